@@ -0,0 +1,380 @@
+// Package archive implements a read only backend which shows the
+// contents of zip and tar archives on another remote as directories
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/cache"
+	"github.com/rclone/rclone/fs/config/configmap"
+	"github.com/rclone/rclone/fs/config/configstruct"
+	"github.com/rclone/rclone/fs/fspath"
+	"github.com/rclone/rclone/fs/hash"
+)
+
+// Globals
+var (
+	errorReadOnly = errors.New("archive remotes are read only")
+	errNotArchive = errors.New("not an archive")
+)
+
+// Register with Fs
+func init() {
+	fs.Register(&fs.RegInfo{
+		Name:        "archive",
+		Description: "Read archives",
+		NewFs:       NewFs,
+		MetadataInfo: &fs.MetadataInfo{
+			System: systemMetadataInfo,
+			Help: `Files inside archives have the metadata stored in the archive,
+other files have any metadata supported by the underlying remote.`,
+		},
+		Options: []fs.Option{{
+			Name: "remote",
+			Help: `Remote containing the archives to read.
+
+Normally should contain a ':' and a path, e.g. "myremote:path/to/dir",
+"myremote:bucket" or maybe "myremote:" (not recommended).
+
+It may also point directly at an archive, e.g. "myremote:path/to/file.zip".`,
+			Required: true,
+		}},
+	})
+}
+
+// Options defines the configuration for this backend
+type Options struct {
+	Remote string `config:"remote"`
+}
+
+// Fs represents a remote showing archives as directories
+type Fs struct {
+	name     string       // name of this remote
+	root     string       // the path we are working on
+	prefix   string       // path of the root in base
+	opt      Options      // parsed config options
+	base     fs.Fs        // the remote containing the archives
+	features *fs.Features // optional features
+
+	archivesMu sync.Mutex
+	archives   map[string]*archive // open archives by path in base
+}
+
+// NewFs constructs an Fs from the path, container:path
+func NewFs(ctx context.Context, name, root string, m configmap.Mapper) (fs.Fs, error) {
+	// Parse config into Options struct
+	opt := new(Options)
+	err := configstruct.Set(m, opt)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(opt.Remote, name+":") {
+		return nil, errors.New("can't point archive remote at itself - check the value of the remote setting")
+	}
+	base, err := cache.Get(ctx, opt.Remote)
+	var prefix string
+	if err == fs.ErrorIsFile {
+		// Pointing at a file which is hopefully an archive so
+		// use it as the prefix of the root
+		_, prefix, _ = fspath.Split(opt.Remote)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to make remote %q to wrap: %w", opt.Remote, err)
+	}
+	f := &Fs{
+		name:     name,
+		root:     strings.Trim(root, "/"),
+		prefix:   prefix,
+		opt:      *opt,
+		base:     base,
+		archives: make(map[string]*archive),
+	}
+	f.features = (&fs.Features{
+		CanHaveEmptyDirectories: true,
+		ReadMetadata:            true,
+	}).Fill(ctx, f)
+	cache.PinUntilFinalized(f.base, f)
+
+	// Check to see if the root points to a file
+	if f.root != "" {
+		_, err := f.NewObject(ctx, "")
+		if err == nil {
+			newRoot := path.Dir(f.root)
+			if newRoot == "." {
+				newRoot = ""
+			}
+			f.root = newRoot
+			// return an error with an fs which points to the parent
+			return f, fs.ErrorIsFile
+		}
+	}
+	return f, nil
+}
+
+// Name of the remote (as passed into NewFs)
+func (f *Fs) Name() string {
+	return f.name
+}
+
+// Root of the remote (as passed into NewFs)
+func (f *Fs) Root() string {
+	return f.root
+}
+
+// String converts this Fs to a string
+func (f *Fs) String() string {
+	return fmt.Sprintf("archive root '%s'", f.root)
+}
+
+// Features returns the optional features of this Fs
+func (f *Fs) Features() *fs.Features {
+	return f.features
+}
+
+// Precision of the remote
+//
+// Zip files store times to 2s
+func (f *Fs) Precision() time.Duration {
+	precision := f.base.Precision()
+	if precision == fs.ModTimeNotSupported || precision < 2*time.Second {
+		precision = 2 * time.Second
+	}
+	return precision
+}
+
+// Hashes returns the supported hash sets.
+//
+// CRC-32 is only available for files in zip archives.
+func (f *Fs) Hashes() hash.Set {
+	hashes := f.base.Hashes()
+	hashes.Add(hash.CRC32)
+	return hashes
+}
+
+// UnWrap returns the Fs that this Fs is wrapping
+func (f *Fs) UnWrap() fs.Fs {
+	return f.base
+}
+
+// basePath returns the path in base of remote
+func (f *Fs) basePath(remote string) string {
+	return path.Join(f.prefix, f.root, remote)
+}
+
+// resolve finds the archive (if any) containing remote. It returns the
+// archive and the path inside it or nil if remote isn't inside an
+// archive.
+func (f *Fs) resolve(ctx context.Context, remote string) (a *archive, inner string, err error) {
+	basePath := f.basePath(remote)
+	if basePath == "" {
+		return nil, "", nil
+	}
+	parts := strings.Split(basePath, "/")
+	for i, part := range parts {
+		if archiveType(part) == typeNone {
+			continue
+		}
+		a, err = f.getArchive(ctx, path.Join(parts[:i+1]...))
+		if err == errNotArchive {
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		return a, path.Join(parts[i+1:]...), nil
+	}
+	return nil, "", nil
+}
+
+// getArchive returns the archive at basePath opening it if needed.
+//
+// It returns errNotArchive if there isn't an archive there.
+func (f *Fs) getArchive(ctx context.Context, basePath string) (*archive, error) {
+	f.archivesMu.Lock()
+	a := f.archives[basePath]
+	f.archivesMu.Unlock()
+	if a != nil && !a.stale() {
+		return a, nil
+	}
+	o, err := f.base.NewObject(ctx, basePath)
+	if err == fs.ErrorObjectNotFound || err == fs.ErrorIsDir || err == fs.ErrorNotAFile {
+		return nil, errNotArchive
+	}
+	if err != nil {
+		return nil, err
+	}
+	if a != nil && a.same(ctx, o) {
+		a.checked()
+		return a, nil
+	}
+	a = newArchive(ctx, o)
+	f.archivesMu.Lock()
+	f.archives[basePath] = a
+	f.archivesMu.Unlock()
+	return a, nil
+}
+
+// List the objects and directories in dir into entries.  The
+// entries can be returned in any order but should be for a
+// complete directory.
+//
+// dir should be "" to list the root, and should not have
+// trailing slashes.
+//
+// This should return ErrDirNotFound if the directory isn't
+// found.
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	a, inner, err := f.resolve(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	if a != nil {
+		return a.list(ctx, f, dir, inner)
+	}
+	baseEntries, err := f.base.List(ctx, f.basePath(dir))
+	if err != nil {
+		return nil, err
+	}
+	entries = make(fs.DirEntries, 0, len(baseEntries))
+	for _, entry := range baseEntries {
+		remote := path.Join(dir, path.Base(entry.Remote()))
+		switch x := entry.(type) {
+		case fs.Object:
+			if archiveType(remote) != typeNone {
+				// Show archives as directories
+				entries = append(entries, fs.NewDir(remote, x.ModTime(ctx)))
+			} else {
+				entries = append(entries, f.wrapObject(x, remote))
+			}
+		case fs.Directory:
+			entries = append(entries, fs.NewDirCopy(ctx, x).SetRemote(remote))
+		default:
+			return nil, fmt.Errorf("unknown object type %T", entry)
+		}
+	}
+	return entries, nil
+}
+
+// NewObject finds the Object at remote.  If it can't be found
+// it returns the error ErrorObjectNotFound.
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	a, inner, err := f.resolve(ctx, remote)
+	if err != nil {
+		return nil, err
+	}
+	if a != nil {
+		if inner == "" {
+			return nil, fs.ErrorIsDir
+		}
+		return a.newObject(ctx, f, remote, inner)
+	}
+	o, err := f.base.NewObject(ctx, f.basePath(remote))
+	if err != nil {
+		return nil, err
+	}
+	return f.wrapObject(o, remote), nil
+}
+
+// Put in to the remote path with the modTime given of the given size
+//
+// The archive backend is read only so this returns an error
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return nil, errorReadOnly
+}
+
+// Mkdir makes the directory (container, bucket)
+//
+// The archive backend is read only so this returns an error
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
+	return errorReadOnly
+}
+
+// Rmdir removes the directory (container, bucket) if empty
+//
+// The archive backend is read only so this returns an error
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
+	return errorReadOnly
+}
+
+// wrappedObject is an object outside any archive on the base remote
+type wrappedObject struct {
+	fs.Object
+	f      *Fs
+	remote string
+}
+
+// wrapObject wraps o so it appears at remote in f
+func (f *Fs) wrapObject(o fs.Object, remote string) *wrappedObject {
+	return &wrappedObject{
+		Object: o,
+		f:      f,
+		remote: remote,
+	}
+}
+
+// Fs returns read only access to the Fs that this object is part of
+func (o *wrappedObject) Fs() fs.Info {
+	return o.f
+}
+
+// String returns a description of the Object
+func (o *wrappedObject) String() string {
+	if o == nil {
+		return "<nil>"
+	}
+	return o.remote
+}
+
+// Remote returns the remote path
+func (o *wrappedObject) Remote() string {
+	return o.remote
+}
+
+// UnWrap returns the wrapped Object
+func (o *wrappedObject) UnWrap() fs.Object {
+	return o.Object
+}
+
+// SetModTime sets the modification time of the Object
+//
+// The archive backend is read only so this returns an error
+func (o *wrappedObject) SetModTime(ctx context.Context, modTime time.Time) error {
+	return errorReadOnly
+}
+
+// Update the Object from in with modTime and size
+//
+// The archive backend is read only so this returns an error
+func (o *wrappedObject) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	return errorReadOnly
+}
+
+// Remove this object
+//
+// The archive backend is read only so this returns an error
+func (o *wrappedObject) Remove(ctx context.Context) error {
+	return errorReadOnly
+}
+
+// Metadata returns metadata for the object
+//
+// It should return nil if there is no Metadata
+func (o *wrappedObject) Metadata(ctx context.Context) (fs.Metadata, error) {
+	return fs.GetMetadata(ctx, o.Object)
+}
+
+// Check the interfaces are satisfied
+var (
+	_ fs.Fs              = (*Fs)(nil)
+	_ fs.UnWrapper       = (*Fs)(nil)
+	_ fs.Object          = (*wrappedObject)(nil)
+	_ fs.ObjectUnWrapper = (*wrappedObject)(nil)
+	_ fs.Metadataer      = (*wrappedObject)(nil)
+)
@@ -0,0 +1,258 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	_ "github.com/rclone/rclone/backend/local"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/configmap"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/walk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testModTime = time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+
+// testFiles are the files put into each test archive
+var testFiles = []struct {
+	name string
+	data string
+}{
+	{"file1.txt", "hello world"},
+	{"dir/file2.txt", "potato"},
+	{"dir/sub/file3.txt", "abcdefghijklmnopqrstuvwxyz"},
+	{"dir/empty.txt", ""},
+}
+
+// makeZip writes a zip of testFiles to name with an explicit empty
+// directory
+func makeZip(t *testing.T, name string) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for i, file := range testFiles {
+		method := zip.Deflate
+		if i%2 == 1 {
+			method = zip.Store
+		}
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   method,
+			Modified: testModTime,
+		})
+		require.NoError(t, err)
+		_, err = io.WriteString(w, file.data)
+		require.NoError(t, err)
+	}
+	_, err := zw.Create("emptydir/")
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	require.NoError(t, os.WriteFile(name, buf.Bytes(), 0666))
+}
+
+// makeTar writes a tar of testFiles to name, compressing it if
+// compress is set
+func makeTar(t *testing.T, name string, compress bool) {
+	var buf bytes.Buffer
+	var out io.Writer = &buf
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(&buf)
+		out = gz
+	}
+	tw := tar.NewWriter(out)
+	require.NoError(t, tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     "emptydir/",
+		Mode:     0755,
+		ModTime:  testModTime,
+	}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeSymlink,
+		Name:     "link",
+		Linkname: "file1.txt",
+		ModTime:  testModTime,
+	}))
+	for _, file := range testFiles {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     file.name,
+			Mode:     0644,
+			Uid:      500,
+			Gid:      501,
+			Size:     int64(len(file.data)),
+			ModTime:  testModTime,
+		}))
+		_, err := io.WriteString(tw, file.data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	if gz != nil {
+		require.NoError(t, gz.Close())
+	}
+	require.NoError(t, os.WriteFile(name, buf.Bytes(), 0666))
+}
+
+// newTestFs makes a directory with test archives in and returns an
+// archive Fs pointing at it
+func newTestFs(t *testing.T, root string) (fs.Fs, string, error) {
+	dir := t.TempDir()
+	makeZip(t, filepath.Join(dir, "test.zip"))
+	makeTar(t, filepath.Join(dir, "test.tar"), false)
+	makeTar(t, filepath.Join(dir, "test.tar.gz"), true)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "plain.txt"), []byte("plain"), 0666))
+	f, err := NewFs(context.Background(), "TestArchive", root, configmap.Simple{"remote": dir})
+	return f, dir, err
+}
+
+// readObject reads the contents of the object at remote
+func readObject(t *testing.T, f fs.Fs, remote string, options ...fs.OpenOption) string {
+	ctx := context.Background()
+	o, err := f.NewObject(ctx, remote)
+	require.NoError(t, err, remote)
+	in, err := o.Open(ctx, options...)
+	require.NoError(t, err, remote)
+	data, err := io.ReadAll(in)
+	require.NoError(t, err, remote)
+	require.NoError(t, in.Close())
+	return string(data)
+}
+
+func TestListAndRead(t *testing.T) {
+	ctx := context.Background()
+	f, _, err := newTestFs(t, "")
+	require.NoError(t, err)
+
+	entries, err := f.List(ctx, "")
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		_, isDir := entry.(fs.Directory)
+		names = append(names, entry.Remote()+map[bool]string{true: "/", false: ""}[isDir])
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"plain.txt", "test.tar.gz/", "test.tar/", "test.zip/"}, names)
+	assert.Equal(t, "plain", readObject(t, f, "plain.txt"))
+
+	for _, archive := range []string{"test.zip", "test.tar", "test.tar.gz"} {
+		t.Run(archive, func(t *testing.T) {
+			var got []string
+			err := walk.ListR(ctx, f, archive, true, -1, walk.ListAll, func(entries fs.DirEntries) error {
+				for _, entry := range entries {
+					got = append(got, entry.Remote())
+				}
+				return nil
+			})
+			require.NoError(t, err)
+			sort.Strings(got)
+			assert.Equal(t, []string{
+				archive + "/dir",
+				archive + "/dir/empty.txt",
+				archive + "/dir/file2.txt",
+				archive + "/dir/sub",
+				archive + "/dir/sub/file3.txt",
+				archive + "/emptydir",
+				archive + "/file1.txt",
+			}, got)
+
+			for _, file := range testFiles {
+				remote := archive + "/" + file.name
+				assert.Equal(t, file.data, readObject(t, f, remote), remote)
+				o, err := f.NewObject(ctx, remote)
+				require.NoError(t, err)
+				assert.Equal(t, int64(len(file.data)), o.Size())
+				assert.True(t, testModTime.Equal(o.ModTime(ctx)), remote)
+			}
+
+			// Ranges and seeks
+			remote := archive + "/dir/sub/file3.txt"
+			assert.Equal(t, "cdef", readObject(t, f, remote, &fs.RangeOption{Start: 2, End: 5}))
+			assert.Equal(t, "xyz", readObject(t, f, remote, &fs.RangeOption{Start: -1, End: 3}))
+			assert.Equal(t, "uvwxyz", readObject(t, f, remote, &fs.SeekOption{Offset: 20}))
+
+			// Metadata
+			o, err := f.NewObject(ctx, remote)
+			require.NoError(t, err)
+			metadata, err := fs.GetMetadata(ctx, o)
+			require.NoError(t, err)
+			assert.Equal(t, testModTime.Format(metadataTimeFormat), metadata["mtime"])
+			if archive != "test.zip" {
+				assert.Equal(t, "100644", metadata["mode"])
+				assert.Equal(t, "500", metadata["uid"])
+				assert.Equal(t, "501", metadata["gid"])
+			}
+
+			// Not found
+			_, err = f.NewObject(ctx, archive+"/potato")
+			assert.Equal(t, fs.ErrorObjectNotFound, err)
+			_, err = f.NewObject(ctx, archive+"/dir")
+			assert.Equal(t, fs.ErrorIsDir, err)
+			_, err = f.List(ctx, archive+"/potato")
+			assert.Equal(t, fs.ErrorDirNotFound, err)
+		})
+	}
+}
+
+func TestZipHash(t *testing.T) {
+	ctx := context.Background()
+	f, _, err := newTestFs(t, "")
+	require.NoError(t, err)
+	o, err := f.NewObject(ctx, "test.zip/file1.txt")
+	require.NoError(t, err)
+	sum, err := o.Hash(ctx, hash.CRC32)
+	require.NoError(t, err)
+	want, err := hash.StreamTypes(bytes.NewBufferString("hello world"), hash.NewHashSet(hash.CRC32))
+	require.NoError(t, err)
+	assert.Equal(t, want[hash.CRC32], sum)
+}
+
+func TestRootInArchive(t *testing.T) {
+	ctx := context.Background()
+	f, _, err := newTestFs(t, "test.tar.gz/dir")
+	require.NoError(t, err)
+	assert.Equal(t, "potato", readObject(t, f, "file2.txt"))
+	entries, err := f.List(ctx, "sub")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(entries))
+	assert.Equal(t, "sub/file3.txt", entries[0].Remote())
+
+	// Pointing at a file in an archive
+	f, _, err = newTestFs(t, "test.zip/dir/file2.txt")
+	assert.Equal(t, fs.ErrorIsFile, err)
+	require.NotNil(t, f)
+	assert.Equal(t, "test.zip/dir", f.Root())
+	assert.Equal(t, "potato", readObject(t, f, "file2.txt"))
+}
+
+func TestRemotePointsAtArchive(t *testing.T) {
+	ctx := context.Background()
+	_, dir, err := newTestFs(t, "")
+	require.NoError(t, err)
+	f, err := NewFs(ctx, "TestArchive", "dir", configmap.Simple{"remote": filepath.Join(dir, "test.zip")})
+	require.NoError(t, err)
+	assert.Equal(t, "potato", readObject(t, f, "file2.txt"))
+}
+
+func TestReadOnly(t *testing.T) {
+	ctx := context.Background()
+	f, _, err := newTestFs(t, "")
+	require.NoError(t, err)
+	assert.Equal(t, errorReadOnly, f.Mkdir(ctx, "potato"))
+	assert.Equal(t, errorReadOnly, f.Rmdir(ctx, "test.zip/emptydir"))
+	o, err := f.NewObject(ctx, "test.zip/file1.txt")
+	require.NoError(t, err)
+	assert.Equal(t, errorReadOnly, o.Remove(ctx))
+	o, err = f.NewObject(ctx, "plain.txt")
+	require.NoError(t, err)
+	assert.Equal(t, errorReadOnly, o.Remove(ctx))
+}
@@ -0,0 +1,433 @@
+package archive
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+)
+
+// how long to use an archive for before checking it hasn't changed
+const archiveCheckInterval = time.Minute
+
+// metadataTimeFormat is the format of times in the metadata
+const metadataTimeFormat = time.RFC3339Nano
+
+// system metadata keys which this backend owns
+//
+// these use the same names as the local backend but are read only
+var systemMetadataInfo = map[string]fs.MetadataHelp{
+	"mode": {
+		Help:     "File type and mode",
+		Type:     "octal, unix style",
+		Example:  "0100664",
+		ReadOnly: true,
+	},
+	"uid": {
+		Help:     "User ID of owner (tar only)",
+		Type:     "decimal number",
+		Example:  "500",
+		ReadOnly: true,
+	},
+	"gid": {
+		Help:     "Group ID of owner (tar only)",
+		Type:     "decimal number",
+		Example:  "500",
+		ReadOnly: true,
+	},
+	"mtime": {
+		Help:     "Time of last modification",
+		Type:     "RFC 3339",
+		Example:  "2006-01-02T15:04:05.999999999Z07:00",
+		ReadOnly: true,
+	},
+}
+
+// archiveKind is the type of the archive
+type archiveKind int
+
+// Types of archive
+const (
+	typeNone archiveKind = iota
+	typeZip
+	typeTar
+	typeTarGz
+)
+
+// archiveType returns the type of archive name is from its extension
+func archiveType(name string) archiveKind {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return typeZip
+	case strings.HasSuffix(lower, ".tar"):
+		return typeTar
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return typeTarGz
+	}
+	return typeNone
+}
+
+// entry is a file or directory inside an archive
+type entry struct {
+	name     string            // leaf name
+	isDir    bool              // set if this is a directory
+	size     int64             // uncompressed size
+	modTime  time.Time         // modification time - may be zero for directories
+	metadata fs.Metadata       // metadata read from the archive
+	crc32    string            // CRC-32 of the data in hex - zip only
+	zf       *zip.File         // the file in the zip - zip only
+	offset   int64             // offset of the data in the uncompressed tar - tar only
+	children map[string]*entry // directory contents - directories only
+}
+
+// newDirEntry makes a new directory entry called name
+func newDirEntry(name string) *entry {
+	return &entry{
+		name:     name,
+		isDir:    true,
+		children: make(map[string]*entry),
+	}
+}
+
+// index is the directory tree of an archive
+type index struct {
+	root  *entry
+	files int // number of files in the index
+}
+
+// newIndex makes a new empty index
+func newIndex() *index {
+	return &index{
+		root: newDirEntry(""),
+	}
+}
+
+// cleanName converts a name in an archive into a relative path
+// which can't refer above the root.
+func cleanName(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	return strings.Trim(path.Clean("/"+name), "/")
+}
+
+// add e to the index with the name given, making any parent
+// directories needed.
+//
+// Later entries with the same name replace earlier ones.
+func (idx *index) add(name string, e *entry) {
+	name = cleanName(name)
+	if name == "" {
+		return
+	}
+	parts := strings.Split(name, "/")
+	dir := idx.root
+	for _, part := range parts[:len(parts)-1] {
+		child := dir.children[part]
+		if child == nil || !child.isDir {
+			child = newDirEntry(part)
+			dir.children[part] = child
+		}
+		dir = child
+	}
+	leaf := parts[len(parts)-1]
+	e.name = leaf
+	if e.isDir {
+		// Keep the contents of any implicit directory already made
+		if existing := dir.children[leaf]; existing != nil && existing.isDir {
+			existing.modTime = e.modTime
+			existing.metadata = e.metadata
+			return
+		}
+		e.children = make(map[string]*entry)
+	} else {
+		idx.files++
+	}
+	dir.children[leaf] = e
+}
+
+// find the entry at name or return nil if not found
+func (idx *index) find(name string) *entry {
+	e := idx.root
+	if name == "" {
+		return e
+	}
+	for _, part := range strings.Split(name, "/") {
+		if !e.isDir {
+			return nil
+		}
+		e = e.children[part]
+		if e == nil {
+			return nil
+		}
+	}
+	return e
+}
+
+// archive is an archive file on the base remote
+type archive struct {
+	o           fs.Object   // the archive file
+	kind        archiveKind // type of the archive
+	fingerprint string      // fingerprint of o when it was opened
+	mu          sync.Mutex
+	lastChecked time.Time    // when o was last checked for changes
+	index       *index       // the contents of the archive or nil if not read yet
+	readerMu    sync.Mutex   // protects reader while reading zip headers
+	reader      *rangeReader // reader used by the zip directory - zip only
+}
+
+// newArchive makes a new archive from o
+//
+// The index isn't read until it is needed
+func newArchive(ctx context.Context, o fs.Object) *archive {
+	return &archive{
+		o:           o,
+		kind:        archiveType(o.Remote()),
+		fingerprint: fs.Fingerprint(ctx, o, true),
+		lastChecked: time.Now(),
+	}
+}
+
+// stale returns true if the archive should be checked for changes
+func (a *archive) stale() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return time.Since(a.lastChecked) > archiveCheckInterval
+}
+
+// checked marks the archive as checked for changes
+func (a *archive) checked() {
+	a.mu.Lock()
+	a.lastChecked = time.Now()
+	a.mu.Unlock()
+}
+
+// same returns true if o is the same as the archive
+func (a *archive) same(ctx context.Context, o fs.Object) bool {
+	return fs.Fingerprint(ctx, o, true) == a.fingerprint
+}
+
+// getIndex returns the index of the archive, reading it if necessary
+func (a *archive) getIndex(ctx context.Context) (idx *index, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.index != nil {
+		return a.index, nil
+	}
+	start := time.Now()
+	switch a.kind {
+	case typeZip:
+		idx, err = a.readZipIndex(ctx)
+	case typeTar, typeTarGz:
+		idx, err = a.readTarIndex(ctx)
+	default:
+		err = errNotArchive
+	}
+	if err != nil {
+		return nil, err
+	}
+	fs.Debugf(a.o, "Read archive index of %d files in %v", idx.files, time.Since(start))
+	a.index = idx
+	return idx, nil
+}
+
+// modTime returns the modification time of e defaulting to that of
+// the archive
+func (a *archive) modTime(ctx context.Context, e *entry) time.Time {
+	if e.modTime.IsZero() {
+		return a.o.ModTime(ctx)
+	}
+	return e.modTime
+}
+
+// list the directory inner in the archive which is at dir in f
+func (a *archive) list(ctx context.Context, f *Fs, dir, inner string) (entries fs.DirEntries, err error) {
+	idx, err := a.getIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	e := idx.find(inner)
+	if e == nil || !e.isDir {
+		return nil, fs.ErrorDirNotFound
+	}
+	entries = make(fs.DirEntries, 0, len(e.children))
+	for _, child := range e.children {
+		remote := path.Join(dir, child.name)
+		if child.isDir {
+			entries = append(entries, fs.NewDir(remote, a.modTime(ctx, child)))
+		} else {
+			entries = append(entries, a.newMember(f, remote, child))
+		}
+	}
+	return entries, nil
+}
+
+// newObject returns the object at inner in the archive which is at
+// remote in f
+func (a *archive) newObject(ctx context.Context, f *Fs, remote, inner string) (fs.Object, error) {
+	idx, err := a.getIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	e := idx.find(inner)
+	if e == nil {
+		return nil, fs.ErrorObjectNotFound
+	}
+	if e.isDir {
+		return nil, fs.ErrorIsDir
+	}
+	return a.newMember(f, remote, e), nil
+}
+
+// Object describes a file inside an archive
+type Object struct {
+	f      *Fs
+	remote string
+	a      *archive
+	e      *entry
+}
+
+// newMember makes an Object for e at remote in f
+func (a *archive) newMember(f *Fs, remote string, e *entry) *Object {
+	return &Object{
+		f:      f,
+		remote: remote,
+		a:      a,
+		e:      e,
+	}
+}
+
+// Fs returns read only access to the Fs that this object is part of
+func (o *Object) Fs() fs.Info {
+	return o.f
+}
+
+// String returns a description of the Object
+func (o *Object) String() string {
+	if o == nil {
+		return "<nil>"
+	}
+	return o.remote
+}
+
+// Remote returns the remote path
+func (o *Object) Remote() string {
+	return o.remote
+}
+
+// ModTime returns the modification date of the file
+func (o *Object) ModTime(ctx context.Context) time.Time {
+	return o.a.modTime(ctx, o.e)
+}
+
+// Size returns the uncompressed size of the file
+func (o *Object) Size() int64 {
+	return o.e.size
+}
+
+// Hash returns the selected checksum of the file
+//
+// Only CRC-32 for files in zip archives is supported
+func (o *Object) Hash(ctx context.Context, ht hash.Type) (string, error) {
+	if ht == hash.CRC32 && o.e.crc32 != "" {
+		return o.e.crc32, nil
+	}
+	return "", hash.ErrUnsupported
+}
+
+// Storable returns a boolean showing whether this object storable
+func (o *Object) Storable() bool {
+	return true
+}
+
+// Metadata returns metadata for the object read from the archive
+//
+// It should return nil if there is no Metadata
+func (o *Object) Metadata(ctx context.Context) (fs.Metadata, error) {
+	if len(o.e.metadata) == 0 {
+		return nil, nil
+	}
+	metadata := make(fs.Metadata, len(o.e.metadata))
+	for k, v := range o.e.metadata {
+		metadata[k] = v
+	}
+	return metadata, nil
+}
+
+// Open an object for read
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.ReadCloser, err error) {
+	offset, limit := openOptions(o, o.e.size, options)
+	if offset > o.e.size {
+		offset = o.e.size
+	}
+	if limit < 0 || offset+limit > o.e.size {
+		limit = o.e.size - offset
+	}
+	switch o.a.kind {
+	case typeZip:
+		return o.a.openZip(ctx, o.e, offset, limit)
+	case typeTar, typeTarGz:
+		return o.a.openTar(ctx, o.e, offset, limit)
+	}
+	return nil, errNotArchive
+}
+
+// SetModTime sets the modification time of the Object
+//
+// The archive backend is read only so this returns an error
+func (o *Object) SetModTime(ctx context.Context, modTime time.Time) error {
+	return errorReadOnly
+}
+
+// Update the Object from in with modTime and size
+//
+// The archive backend is read only so this returns an error
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	return errorReadOnly
+}
+
+// Remove this object
+//
+// The archive backend is read only so this returns an error
+func (o *Object) Remove(ctx context.Context) error {
+	return errorReadOnly
+}
+
+// unixMode converts mode into a unix style mode with the file type
+// bits set
+func unixMode(mode os.FileMode) uint32 {
+	unix := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		unix |= 0o4000
+	}
+	if mode&os.ModeSetgid != 0 {
+		unix |= 0o2000
+	}
+	if mode&os.ModeSticky != 0 {
+		unix |= 0o1000
+	}
+	if mode.IsDir() {
+		unix |= 0o040000
+	} else {
+		unix |= 0o100000
+	}
+	return unix
+}
+
+// formatMode formats mode for the metadata
+func formatMode(mode os.FileMode) string {
+	return fmt.Sprintf("%0o", unixMode(mode))
+}
+
+// Check the interfaces are satisfied
+var (
+	_ fs.Object     = (*Object)(nil)
+	_ fs.Metadataer = (*Object)(nil)
+)
@@ -0,0 +1,197 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/rclone/rclone/fs"
+)
+
+const (
+	minBlockSize = 64 * 1024        // size of the first block read
+	maxBlockSize = 16 * 1024 * 1024 // maximum size of block to read
+)
+
+// rangeReader provides io.ReaderAt and io.ReadSeeker on an fs.Object
+// using ranged reads.
+//
+// It reads in blocks which double in size while the reads are
+// sequential so reading a large central directory or a run of tar
+// headers doesn't need too many requests.
+type rangeReader struct {
+	mu         sync.Mutex
+	ctx        context.Context
+	o          fs.Object
+	size       int64
+	block      []byte // the last block read
+	blockStart int64  // offset of block in the object
+	blockSize  int64  // size of the next block to read
+	pos        int64  // position for Read and Seek
+}
+
+// newRangeReader makes a new rangeReader reading o
+func newRangeReader(ctx context.Context, o fs.Object) *rangeReader {
+	return &rangeReader{
+		ctx:       ctx,
+		o:         o,
+		size:      o.Size(),
+		blockSize: minBlockSize,
+	}
+}
+
+// setContext sets the context used for subsequent reads
+func (r *rangeReader) setContext(ctx context.Context) {
+	r.mu.Lock()
+	r.ctx = ctx
+	r.mu.Unlock()
+}
+
+// fetch reads the block containing off
+//
+// Call with the lock held
+func (r *rangeReader) fetch(off int64) error {
+	if off == r.blockStart+int64(len(r.block)) && len(r.block) > 0 {
+		// Sequential read so read more next time
+		r.blockSize *= 2
+		if r.blockSize > maxBlockSize {
+			r.blockSize = maxBlockSize
+		}
+	} else {
+		r.blockSize = minBlockSize
+	}
+	end := off + r.blockSize
+	if end > r.size {
+		end = r.size
+	}
+	in, err := r.o.Open(r.ctx, &fs.RangeOption{Start: off, End: end - 1})
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	block := make([]byte, end-off)
+	_, err = io.ReadFull(in, block)
+	closeErr := in.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		r.block = nil
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	r.block = block
+	r.blockStart = off
+	return nil
+}
+
+// ReadAt reads len(p) bytes into p starting at offset off
+func (r *rangeReader) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for n < len(p) {
+		if off >= r.size {
+			return n, io.EOF
+		}
+		if off < r.blockStart || off >= r.blockStart+int64(len(r.block)) {
+			err = r.fetch(off)
+			if err != nil {
+				return n, err
+			}
+		}
+		copied := copy(p[n:], r.block[off-r.blockStart:])
+		n += copied
+		off += int64(copied)
+	}
+	return n, nil
+}
+
+// Read reads up to len(p) bytes into p
+func (r *rangeReader) Read(p []byte) (n int, err error) {
+	n, err = r.ReadAt(p, r.pos)
+	r.pos += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+// Seek sets the offset for the next Read
+func (r *rangeReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.pos
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	r.pos = offset
+	return offset, nil
+}
+
+// openOptions decodes the Range and Seek options for an object of size
+// returning the offset to start reading at and the number of bytes to
+// read or -1 to read to the end.
+func openOptions(o fs.Object, size int64, options []fs.OpenOption) (offset, limit int64) {
+	limit = -1
+	for _, option := range options {
+		switch x := option.(type) {
+		case *fs.RangeOption:
+			offset, limit = x.Decode(size)
+		case *fs.SeekOption:
+			offset = x.Offset
+		default:
+			if option.Mandatory() {
+				fs.Logf(o, "Unsupported mandatory option: %v", option)
+			}
+		}
+	}
+	return offset, limit
+}
+
+// skipReadCloser discards the first bytes of the stream then reads
+// limit bytes, or to the end if limit is -1.
+type skipReadCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+// newSkipReadCloser makes an io.ReadCloser which reads in after
+// discarding skip bytes for up to limit bytes. The closers are
+// closed in order when it is closed.
+func newSkipReadCloser(in io.Reader, skip, limit int64, closers ...io.Closer) (io.ReadCloser, error) {
+	rc := &skipReadCloser{
+		Reader:  in,
+		closers: closers,
+	}
+	if skip > 0 {
+		_, err := io.CopyN(io.Discard, in, skip)
+		if err != nil && err != io.EOF {
+			_ = rc.Close()
+			return nil, fmt.Errorf("failed to skip to offset: %w", err)
+		}
+	}
+	if limit >= 0 {
+		rc.Reader = io.LimitReader(in, limit)
+	}
+	return rc, nil
+}
+
+// Close the underlying readers returning the first error
+func (rc *skipReadCloser) Close() (err error) {
+	for _, closer := range rc.closers {
+		closeErr := closer.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
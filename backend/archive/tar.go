@@ -0,0 +1,119 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/lib/readers"
+)
+
+// readTarIndex reads all the headers in the tar to make the index.
+//
+// Uncompressed tar files are read with ranged reads skipping the file
+// data. Compressed tar files have to be read from start to end.
+func (a *archive) readTarIndex(ctx context.Context) (idx *index, err error) {
+	var (
+		in       io.Reader
+		position func() int64 // returns the current offset in the tar
+	)
+	if a.kind == typeTar {
+		r := newRangeReader(ctx, a.o)
+		in = r
+		position = func() int64 { return r.pos }
+	} else {
+		rc, err := a.o.Open(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open archive: %w", err)
+		}
+		defer fs.CheckClose(rc, &err)
+		gz, err := gzip.NewReader(rc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gzip header: %w", err)
+		}
+		cr := readers.NewCountingReader(gz)
+		in = cr
+		position = func() int64 { return int64(cr.BytesRead()) }
+	}
+	idx = newIndex()
+	tr := tar.NewReader(in)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar header: %w", err)
+		}
+		e := &entry{
+			modTime: hdr.ModTime,
+			offset:  position(),
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			e.isDir = true
+		case tar.TypeReg:
+			if isSparse(hdr) {
+				fs.Debugf(a.o, "Ignoring sparse file %q", hdr.Name)
+				continue
+			}
+			e.size = hdr.Size
+		default:
+			fs.Debugf(a.o, "Ignoring %q with unsupported type %q", hdr.Name, hdr.Typeflag)
+			continue
+		}
+		e.metadata = fs.Metadata{
+			"mode":  formatMode(hdr.FileInfo().Mode()),
+			"uid":   strconv.Itoa(hdr.Uid),
+			"gid":   strconv.Itoa(hdr.Gid),
+			"mtime": hdr.ModTime.Format(metadataTimeFormat),
+		}
+		idx.add(hdr.Name, e)
+	}
+	return idx, nil
+}
+
+// isSparse returns true if hdr is a sparse file whose data isn't
+// stored contiguously in the tar
+func isSparse(hdr *tar.Header) bool {
+	if hdr.Typeflag == tar.TypeGNUSparse {
+		return true
+	}
+	for key := range hdr.PAXRecords {
+		if strings.HasPrefix(key, "GNU.sparse.") {
+			return true
+		}
+	}
+	return false
+}
+
+// openTar opens the data of e in the tar reading limit bytes from
+// offset.
+//
+// For uncompressed tar files only the data needed is read, otherwise
+// the archive is decompressed from the start up to the data.
+func (a *archive) openTar(ctx context.Context, e *entry, offset, limit int64) (io.ReadCloser, error) {
+	if limit == 0 {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+	if a.kind == typeTar {
+		start := e.offset + offset
+		return a.o.Open(ctx, &fs.RangeOption{Start: start, End: start + limit - 1})
+	}
+	in, err := a.o.Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		_ = in.Close()
+		return nil, fmt.Errorf("failed to read gzip header: %w", err)
+	}
+	return newSkipReadCloser(gz, e.offset+offset, limit, gz, in)
+}
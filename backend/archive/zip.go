@@ -0,0 +1,84 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rclone/rclone/fs"
+)
+
+// readZipIndex reads the central directory of the zip using ranged
+// reads so the rest of the archive isn't read.
+func (a *archive) readZipIndex(ctx context.Context) (*index, error) {
+	a.reader = newRangeReader(ctx, a.o)
+	zr, err := zip.NewReader(a.reader, a.o.Size())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read zip directory: %w", err)
+	}
+	idx := newIndex()
+	for _, zf := range zr.File {
+		mode := zf.Mode()
+		e := &entry{
+			isDir:   mode.IsDir() || strings.HasSuffix(zf.Name, "/"),
+			modTime: zf.Modified,
+		}
+		if !e.isDir {
+			e.size = int64(zf.UncompressedSize64)
+			e.crc32 = fmt.Sprintf("%08x", zf.CRC32)
+			e.zf = zf
+		}
+		e.metadata = fs.Metadata{
+			"mode": formatMode(mode),
+		}
+		if !e.modTime.IsZero() {
+			e.metadata["mtime"] = e.modTime.Format(metadataTimeFormat)
+		}
+		idx.add(zf.Name, e)
+	}
+	return idx, nil
+}
+
+// openZip opens the data of e in the zip reading limit bytes from
+// offset.
+//
+// Only the compressed data for the file is read from the archive.
+func (a *archive) openZip(ctx context.Context, e *entry, offset, limit int64) (io.ReadCloser, error) {
+	zf := e.zf
+	if zf.Flags&0x1 != 0 {
+		return nil, errors.New("can't read encrypted file from zip")
+	}
+	if zf.Method != zip.Store && zf.Method != zip.Deflate {
+		return nil, fmt.Errorf("can't read file compressed with method %d from zip", zf.Method)
+	}
+	if limit == 0 || zf.CompressedSize64 == 0 {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+
+	// Read the local file header to find the data
+	a.readerMu.Lock()
+	a.reader.setContext(ctx)
+	dataOffset, err := zf.DataOffset()
+	a.readerMu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read zip file header: %w", err)
+	}
+
+	if zf.Method == zip.Store {
+		// Uncompressed so read the range directly
+		start := dataOffset + offset
+		return a.o.Open(ctx, &fs.RangeOption{Start: start, End: start + limit - 1})
+	}
+
+	in, err := a.o.Open(ctx, &fs.RangeOption{Start: dataOffset, End: dataOffset + int64(zf.CompressedSize64) - 1})
+	if err != nil {
+		return nil, err
+	}
+	fr := flate.NewReader(in)
+	return newSkipReadCloser(fr, offset, limit, fr, in)
+}
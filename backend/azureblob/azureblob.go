@@ -6,6 +6,7 @@
 package azureblob
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/hex"
@@ -73,6 +74,12 @@ func init() {
 		Name:        "azureblob",
 		Description: "Microsoft Azure Blob Storage",
 		NewFs:       NewFs,
+		MetadataInfo: &fs.MetadataInfo{
+			System: systemMetadataInfo,
+			Help: `User metadata is stored as x-ms-meta- keys. Azure metadata keys are case insensitive and are always returned in lower case.
+
+Azure requires user metadata keys to be valid C# identifiers, so they may only contain letters, digits and underscores and must not start with a digit.`,
+		},
 		Options: []fs.Option{{
 			Name: "account",
 			Help: "Storage Account Name.\n\nLeave blank to use SAS URL or Emulator.",
@@ -266,6 +273,51 @@ This option controls how often unused buffers will be removed from the pool.`,
 	})
 }
 
+// system metadata keys which this backend owns
+var systemMetadataInfo = map[string]fs.MetadataHelp{
+	"cache-control": {
+		Help:    "Cache-Control header",
+		Type:    "string",
+		Example: "no-cache",
+	},
+	"content-disposition": {
+		Help:    "Content-Disposition header",
+		Type:    "string",
+		Example: "inline",
+	},
+	"content-encoding": {
+		Help:    "Content-Encoding header",
+		Type:    "string",
+		Example: "gzip",
+	},
+	"content-language": {
+		Help:    "Content-Language header",
+		Type:    "string",
+		Example: "en-US",
+	},
+	"content-type": {
+		Help:    "Content-Type header",
+		Type:    "string",
+		Example: "text/plain",
+	},
+	"tier": {
+		Help:    "Access tier of the object",
+		Type:    "string",
+		Example: "Hot",
+	},
+	"mtime": {
+		Help:    "Time of last modification, read from rclone metadata",
+		Type:    "RFC 3339",
+		Example: "2006-01-02T15:04:05.999999999Z07:00",
+	},
+	"btime": {
+		Help:     "Time of file birth (creation) read from the Creation-Time property",
+		Type:     "RFC 3339",
+		Example:  "2006-01-02T15:04:05.999999999Z07:00",
+		ReadOnly: true,
+	},
+}
+
 // Options defines the configuration for this backend
 type Options struct {
 	Account              string               `config:"account"`
@@ -315,14 +367,19 @@ type Fs struct {
 
 // Object describes an azure object
 type Object struct {
-	fs         *Fs                   // what this object is part of
-	remote     string                // The remote path
-	modTime    time.Time             // The modified time of the object if known
-	md5        string                // MD5 hash if known
-	size       int64                 // Size of the object
-	mimeType   string                // Content-Type of the object
-	accessTier azblob.AccessTierType // Blob Access Tier
-	meta       map[string]string     // blob metadata
+	fs                 *Fs                   // what this object is part of
+	remote             string                // The remote path
+	modTime            time.Time             // The modified time of the object if known
+	md5                string                // MD5 hash if known
+	size               int64                 // Size of the object
+	mimeType           string                // Content-Type of the object
+	cacheControl       string                // Cache-Control of the object
+	contentDisposition string                // Content-Disposition of the object
+	contentEncoding    string                // Content-Encoding of the object
+	contentLanguage    string                // Content-Language of the object
+	creationTime       time.Time             // Creation-Time of the object if known
+	accessTier         azblob.AccessTierType // Blob Access Tier
+	meta               map[string]string     // blob metadata
 }
 
 // ------------------------------------------------------------
@@ -378,6 +435,16 @@ func validateAccessTier(tier string) bool {
 		strings.EqualFold(tier, string(azblob.AccessTierArchive))
 }
 
+// canonicalAccessTier returns the tier with the case Azure uses
+func canonicalAccessTier(tier string) string {
+	for _, accessTier := range []azblob.AccessTierType{azblob.AccessTierHot, azblob.AccessTierCool, azblob.AccessTierArchive} {
+		if strings.EqualFold(tier, string(accessTier)) {
+			return string(accessTier)
+		}
+	}
+	return tier
+}
+
 // validatePublicAccess checks if azureblob supports use supplied public access level
 func validatePublicAccess(publicAccess string) bool {
 	switch publicAccess {
@@ -588,6 +655,9 @@ func NewFs(ctx context.Context, name, root string, m configmap.Mapper) (fs.Fs, e
 		BucketBasedRootOK: true,
 		SetTier:           true,
 		GetTier:           true,
+		ReadMetadata:      true,
+		WriteMetadata:     true,
+		UserMetadata:      true,
 	}).Fill(ctx, f)
 
 	var (
@@ -1273,11 +1343,38 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object,
 		return nil, err
 	}
 
+	// If --metadata is in use then set the metadata explicitly
+	// so any from --metadata-set is applied, otherwise the
+	// metadata and headers of the source are copied.
+	ci := fs.GetConfig(ctx)
+	var ui *uploadInfo
+	var metadata azblob.Metadata
+	tier := f.opt.AccessTier
+	if ci.Metadata {
+		var metadataOptions []fs.OpenOption
+		if ci.MetadataSet != nil {
+			metadataOptions = append(metadataOptions, fs.MetadataOption(ci.MetadataSet))
+		}
+		meta, err := fs.GetMetadataOptions(ctx, srcObj, metadataOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read metadata from source object: %w", err)
+		}
+		ui = &uploadInfo{
+			headers: srcObj.httpHeaders(),
+			modTime: srcObj.ModTime(ctx),
+			tier:    tier,
+		}
+		ui.setMetadata(srcObj, meta)
+		ui.meta[modTimeKey] = ui.modTime.Format(timeFormatOut)
+		metadata = ui.meta
+		tier = ui.tier
+	}
+
 	options := azblob.BlobAccessConditions{}
 	var startCopy *azblob.BlobStartCopyFromURLResponse
 
 	err = f.pacer.Call(func() (bool, error) {
-		startCopy, err = dstBlobURL.StartCopyFromURL(ctx, *source, nil, azblob.ModifiedAccessConditions{}, options, azblob.AccessTierType(f.opt.AccessTier), nil)
+		startCopy, err = dstBlobURL.StartCopyFromURL(ctx, *source, metadata, azblob.ModifiedAccessConditions{}, options, azblob.AccessTierType(tier), nil)
 		return f.shouldRetry(ctx, err)
 	})
 	if err != nil {
@@ -1294,6 +1391,18 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object,
 		copyStatus = getMetadata.CopyStatus()
 	}
 
+	// The copy takes the headers from the source so set them if
+	// they were changed by the metadata
+	if ui != nil && !equalHeaders(ui.headers, srcObj.httpHeaders()) {
+		err = f.pacer.Call(func() (bool, error) {
+			_, err := dstBlobURL.SetHTTPHeaders(ctx, ui.headers, options)
+			return f.shouldRetry(ctx, err)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to set headers on copied blob: %w", err)
+		}
+	}
+
 	return f.NewObject(ctx, remote)
 }
 
@@ -1371,6 +1480,11 @@ func (o *Object) decodeMetaDataFromPropertiesResponse(info *azblob.BlobGetProper
 	// this as base64 encoded string.
 	o.md5 = base64.StdEncoding.EncodeToString(info.ContentMD5())
 	o.mimeType = info.ContentType()
+	o.cacheControl = info.CacheControl()
+	o.contentDisposition = info.ContentDisposition()
+	o.contentEncoding = info.ContentEncoding()
+	o.contentLanguage = info.ContentLanguage()
+	o.creationTime = info.CreationTime()
 	o.size = size
 	o.modTime = info.LastModified()
 	o.accessTier = azblob.AccessTierType(info.AccessTier())
@@ -1389,6 +1503,10 @@ func (o *Object) decodeMetaDataFromDownloadResponse(info *azblob.DownloadRespons
 	// this as base64 encoded string.
 	o.md5 = base64.StdEncoding.EncodeToString(info.ContentMD5())
 	o.mimeType = info.ContentType()
+	o.cacheControl = info.CacheControl()
+	o.contentDisposition = info.ContentDisposition()
+	o.contentEncoding = info.ContentEncoding()
+	o.contentLanguage = info.ContentLanguage()
 	o.size = size
 	o.modTime = info.LastModified()
 	o.accessTier = o.AccessTier()
@@ -1422,6 +1540,13 @@ func (o *Object) decodeMetaDataFromBlob(info *azblob.BlobItemInternal) (err erro
 	// this as base64 encoded string.
 	o.md5 = base64.StdEncoding.EncodeToString(info.Properties.ContentMD5)
 	o.mimeType = *info.Properties.ContentType
+	o.cacheControl = stringValue(info.Properties.CacheControl)
+	o.contentDisposition = stringValue(info.Properties.ContentDisposition)
+	o.contentEncoding = stringValue(info.Properties.ContentEncoding)
+	o.contentLanguage = stringValue(info.Properties.ContentLanguage)
+	if info.Properties.CreationTime != nil {
+		o.creationTime = *info.Properties.CreationTime
+	}
 	o.size = size
 	o.modTime = info.Properties.LastModified
 	o.accessTier = info.Properties.AccessTier
@@ -1429,6 +1554,31 @@ func (o *Object) decodeMetaDataFromBlob(info *azblob.BlobItemInternal) (err erro
 	return nil
 }
 
+// httpHeaders returns the content headers of the object
+func (o *Object) httpHeaders() azblob.BlobHTTPHeaders {
+	headers := azblob.BlobHTTPHeaders{
+		ContentType:        o.mimeType,
+		ContentEncoding:    o.contentEncoding,
+		ContentLanguage:    o.contentLanguage,
+		ContentDisposition: o.contentDisposition,
+		CacheControl:       o.cacheControl,
+	}
+	if o.md5 != "" {
+		headers.ContentMD5, _ = base64.StdEncoding.DecodeString(o.md5)
+	}
+	return headers
+}
+
+// equalHeaders returns true if the content headers a and b are the same
+func equalHeaders(a, b azblob.BlobHTTPHeaders) bool {
+	return a.ContentType == b.ContentType &&
+		a.ContentEncoding == b.ContentEncoding &&
+		a.ContentLanguage == b.ContentLanguage &&
+		a.ContentDisposition == b.ContentDisposition &&
+		a.CacheControl == b.CacheControl &&
+		bytes.Equal(a.ContentMD5, b.ContentMD5)
+}
+
 // getBlobReference creates an empty blob reference with no metadata
 func (o *Object) getBlobReference() azblob.BlobURL {
 	container, directory := o.split()
@@ -1653,15 +1803,28 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 		return err
 	}
 
-	// Update Mod time
-	o.updateMetadataWithModTime(src.ModTime(ctx))
+	ui := uploadInfo{
+		modTime: src.ModTime(ctx),
+		tier:    o.fs.opt.AccessTier,
+	}
+	ui.headers.ContentType = fs.MimeType(ctx, src)
+
+	// Fetch metadata if --metadata is in use
+	meta, err := fs.GetMetadataOptions(ctx, src, options)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to read metadata from source object: %w", err)
+	}
+	if meta != nil {
+		// Replace any existing user metadata with that of the source
+		ui.setMetadata(o, meta)
+		o.meta = ui.meta
 	}
 
+	// Update Mod time
+	o.updateMetadataWithModTime(ui.modTime)
+
 	blob := o.getBlobReference()
-	httpHeaders := azblob.BlobHTTPHeaders{}
-	httpHeaders.ContentType = fs.MimeType(ctx, src)
+	httpHeaders := ui.headers
 
 	// Compute the Content-MD5 of the file. As we stream all uploads it
 	// will be set in PutBlockList API call using the 'x-ms-blob-content-md5' header
@@ -1711,12 +1874,12 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 	}
 
 	// If tier is not changed or not specified, do not attempt to invoke `SetBlobTier` operation
-	if o.fs.opt.AccessTier == string(defaultAccessTier) || o.fs.opt.AccessTier == string(o.AccessTier()) {
+	if ui.tier == string(defaultAccessTier) || ui.tier == string(o.AccessTier()) {
 		return nil
 	}
 
-	// Now, set blob tier based on configured access tier
-	return o.SetTier(o.fs.opt.AccessTier)
+	// Now, set blob tier based on configured access tier or metadata
+	return o.SetTier(ui.tier)
 }
 
 // Remove an object
@@ -1775,6 +1938,107 @@ func (o *Object) GetTier() string {
 	return string(o.accessTier)
 }
 
+// stringValue returns the string pointed to by p or "" if p is nil
+func stringValue(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
+
+// Metadata returns metadata for an object
+//
+// It should return nil if there is no Metadata
+func (o *Object) Metadata(ctx context.Context) (metadata fs.Metadata, err error) {
+	err = o.readMetaData()
+	if err != nil {
+		return nil, err
+	}
+	metadata = make(fs.Metadata, len(o.meta)+8)
+	for k, v := range o.meta {
+		k = strings.ToLower(k)
+		switch k {
+		case modTimeKey:
+			if modTime, err := time.Parse(timeFormatIn, v); err == nil {
+				metadata["mtime"] = modTime.Format(time.RFC3339Nano)
+			}
+		default:
+			metadata[k] = v
+		}
+	}
+	if !o.creationTime.IsZero() {
+		metadata["btime"] = o.creationTime.Format(time.RFC3339Nano)
+	}
+
+	// Set system metadata
+	setMetadata := func(k string, v string) {
+		if v == "" {
+			return
+		}
+		metadata[k] = v
+	}
+	setMetadata("content-type", o.mimeType)
+	setMetadata("cache-control", o.cacheControl)
+	setMetadata("content-disposition", o.contentDisposition)
+	setMetadata("content-encoding", o.contentEncoding)
+	setMetadata("content-language", o.contentLanguage)
+	setMetadata("tier", string(o.accessTier))
+
+	return metadata, nil
+}
+
+// uploadInfo is the user metadata, headers, modification time and
+// tier to set on a blob when it is uploaded or copied
+type uploadInfo struct {
+	meta    azblob.Metadata
+	headers azblob.BlobHTTPHeaders
+	modTime time.Time
+	tier    string
+}
+
+// setMetadata merges the rclone metadata passed in into ui
+//
+// System metadata sets the corresponding header, tier or modification
+// time and everything else becomes user metadata.
+func (ui *uploadInfo) setMetadata(o fmt.Stringer, metadata fs.Metadata) {
+	if ui.meta == nil {
+		ui.meta = make(azblob.Metadata, len(metadata)+1)
+	}
+	for k, v := range metadata {
+		k = strings.ToLower(k)
+		switch k {
+		case "cache-control":
+			ui.headers.CacheControl = v
+		case "content-disposition":
+			ui.headers.ContentDisposition = v
+		case "content-encoding":
+			ui.headers.ContentEncoding = v
+		case "content-language":
+			ui.headers.ContentLanguage = v
+		case "content-type":
+			ui.headers.ContentType = v
+		case "tier":
+			if validateAccessTier(v) {
+				ui.tier = canonicalAccessTier(v)
+			} else {
+				fs.Debugf(o, "Ignoring unsupported tier %q in metadata", v)
+			}
+		case "mtime":
+			// mtime in meta overrides source ModTime
+			metaModTime, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				fs.Debugf(o, "failed to parse metadata %s: %q: %v", k, v, err)
+			} else {
+				ui.modTime = metaModTime
+			}
+		case "btime":
+			// can't be set
+		default:
+			ui.meta[k] = v
+		}
+	}
+}
+
 // Check the interfaces are satisfied
 var (
 	_ fs.Fs          = &Fs{}
@@ -1786,4 +2050,5 @@ var (
 	_ fs.MimeTyper   = &Object{}
 	_ fs.GetTierer   = &Object{}
 	_ fs.SetTierer   = &Object{}
+	_ fs.Metadataer  = &Object{}
 )
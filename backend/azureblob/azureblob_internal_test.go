@@ -5,7 +5,10 @@ package azureblob
 
 import (
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/rclone/rclone/fs"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -34,3 +37,39 @@ func TestIncrement(t *testing.T) {
 		assert.Equal(t, test.want, test.in)
 	}
 }
+
+func TestUploadInfoSetMetadata(t *testing.T) {
+	ui := uploadInfo{
+		modTime: time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC),
+		tier:    "",
+	}
+	ui.setMetadata(nil, fs.Metadata{
+		"Cache-Control":       "no-cache",
+		"content-disposition": "inline",
+		"content-encoding":    "gzip",
+		"content-language":    "en-US",
+		"content-type":        "text/plain",
+		"tier":                "cool",
+		"mtime":               "2011-12-25T12:59:58.123456789Z",
+		"btime":               "2011-12-25T12:59:58Z",
+		"Potato":              "King Edward",
+	})
+	assert.Equal(t, azblob.BlobHTTPHeaders{
+		ContentType:        "text/plain",
+		ContentEncoding:    "gzip",
+		ContentLanguage:    "en-US",
+		ContentDisposition: "inline",
+		CacheControl:       "no-cache",
+	}, ui.headers)
+	assert.Equal(t, azblob.Metadata{"potato": "King Edward"}, ui.meta)
+	assert.Equal(t, "Cool", ui.tier)
+	assert.Equal(t, time.Date(2011, 12, 25, 12, 59, 58, 123456789, time.UTC), ui.modTime)
+
+	// Invalid values are ignored
+	ui.setMetadata(nil, fs.Metadata{
+		"tier":  "Potato",
+		"mtime": "yesterday",
+	})
+	assert.Equal(t, "Cool", ui.tier)
+	assert.Equal(t, time.Date(2011, 12, 25, 12, 59, 58, 123456789, time.UTC), ui.modTime)
+}
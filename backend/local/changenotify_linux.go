@@ -0,0 +1,530 @@
+// Change notification using inotify
+
+//go:build linux
+// +build linux
+
+package local
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/rclone/rclone/fs"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// events watched for on each directory
+	inotifyMask = unix.IN_CREATE | unix.IN_DELETE | unix.IN_MODIFY |
+		unix.IN_CLOSE_WRITE | unix.IN_ATTRIB | unix.IN_MOVED_FROM |
+		unix.IN_MOVED_TO | unix.IN_ONLYDIR | unix.IN_DONT_FOLLOW
+
+	// how long to wait for the IN_MOVED_TO which matches an
+	// IN_MOVED_FROM before treating it as a move out of the tree
+	renameTimeout = 100 * time.Millisecond
+)
+
+// inotifyAddWatch adds an inotify watch - it is a variable so it can
+// be overridden in the tests
+var inotifyAddWatch = unix.InotifyAddWatch
+
+// watcherStarted is called when the watches have been added - it is
+// for the tests to wait for
+var watcherStarted = func() {}
+
+// inotifyEvent is a decoded inotify event
+type inotifyEvent struct {
+	wd     int    // watch descriptor of the directory
+	mask   uint32 // what happened
+	cookie uint32 // for pairing IN_MOVED_FROM with IN_MOVED_TO
+	name   string // name of the entry in the directory
+}
+
+// pendingRename is an IN_MOVED_FROM waiting for its IN_MOVED_TO
+type pendingRename struct {
+	remote     string
+	entryType  fs.EntryType
+	generation int
+}
+
+// entryState is the state of an entry in a directory which is
+// scanned rather than watched
+type entryState struct {
+	entryType fs.EntryType
+	size      int64
+	modTime   time.Time
+}
+
+// dirSnapshot is the state of a directory which is scanned rather
+// than watched, indexed by remote
+type dirSnapshot map[string]entryState
+
+// watcher reads changes to the local file system with inotify and
+// reports them to notifyFunc.
+//
+// All the methods apart from read must be called from the same
+// goroutine.
+type watcher struct {
+	f           *Fs
+	notifyFunc  func(string, fs.EntryType)
+	fd          int                      // inotify file descriptor
+	file        *os.File                 // fd wrapped so reads can be interrupted by Close
+	events      chan []inotifyEvent      // batches of events read from file
+	done        chan struct{}            // closed when the watcher is closed
+	wds         map[int]string           // watch descriptor to directory remote
+	dirs        map[string]int           // directory remote to watch descriptor
+	unwatched   map[string]dirSnapshot   // directories which couldn't be watched
+	renames     map[uint32]pendingRename // pending IN_MOVED_FROM by cookie
+	generation  int                      // incremented for each batch of events
+	notified    map[string]struct{}      // remotes notified in this batch
+	warnedLimit bool                     // set if we've warned about the watch limit
+}
+
+// ChangeNotify calls the passed function with a path that has had
+// changes.
+//
+// Changes are read with inotify so they are notified as soon as they
+// happen. Directories which can't be watched because the inotify
+// watch limit has been reached are scanned for changes every
+// pollInterval instead.
+//
+// A pollInterval of 0 stops watching for changes. Closing the
+// pollIntervalChan stops the watcher permanently.
+func (f *Fs) ChangeNotify(ctx context.Context, notifyFunc func(string, fs.EntryType), pollIntervalChan <-chan time.Duration) {
+	go func() {
+		var (
+			w       *watcher
+			ticker  *time.Ticker
+			tickerC <-chan time.Time
+			eventsC <-chan []inotifyEvent
+			renameC <-chan time.Time
+		)
+		stop := func() {
+			if ticker != nil {
+				ticker.Stop()
+				ticker, tickerC = nil, nil
+			}
+			if w != nil {
+				w.close()
+				w, eventsC, renameC = nil, nil, nil
+			}
+		}
+		for {
+			select {
+			case pollInterval, ok := <-pollIntervalChan:
+				if !ok {
+					stop()
+					return
+				}
+				if pollInterval == 0 {
+					stop()
+					continue
+				}
+				if w == nil {
+					var err error
+					w, err = f.newWatcher(notifyFunc)
+					if err != nil {
+						fs.Errorf(f, "Failed to start change notify: %v", err)
+						continue
+					}
+					eventsC = w.events
+					watcherStarted()
+				}
+				if ticker != nil {
+					ticker.Stop()
+				}
+				ticker = time.NewTicker(pollInterval)
+				tickerC = ticker.C
+			case events, ok := <-eventsC:
+				if !ok {
+					fs.Errorf(f, "Change notify stopped reading events - restart with a new poll interval")
+					stop()
+					continue
+				}
+				w.handleEvents(events)
+				renameC = nil
+				if len(w.renames) > 0 {
+					renameC = time.After(renameTimeout)
+				}
+			case <-renameC:
+				renameC = nil
+				w.flushRenames(w.generation + 1)
+			case <-tickerC:
+				w.scan()
+			}
+		}
+	}()
+}
+
+// newWatcher makes a watcher and adds watches for the whole tree
+func (f *Fs) newWatcher(notifyFunc func(string, fs.EntryType)) (*watcher, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	w := &watcher{
+		f:          f,
+		notifyFunc: notifyFunc,
+		fd:         fd,
+		// As fd is non blocking this uses the runtime poller so
+		// Close will interrupt a Read in progress
+		file:      os.NewFile(uintptr(fd), "inotify"),
+		events:    make(chan []inotifyEvent),
+		done:      make(chan struct{}),
+		wds:       make(map[int]string),
+		dirs:      make(map[string]int),
+		unwatched: make(map[string]dirSnapshot),
+		renames:   make(map[uint32]pendingRename),
+	}
+	w.watchTree("")
+	fs.Debugf(f, "Change notify watching %d directories and scanning %d", len(w.dirs), len(w.unwatched))
+	go w.read()
+	return w, nil
+}
+
+// close the watcher, removing all the watches
+func (w *watcher) close() {
+	close(w.done)
+	err := w.file.Close()
+	if err != nil {
+		fs.Debugf(w.f, "Failed to close inotify: %v", err)
+	}
+}
+
+// read batches of events from the inotify file descriptor and send
+// them to w.events until the watcher is closed
+func (w *watcher) read() {
+	defer close(w.events)
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			if !errors.Is(err, os.ErrClosed) {
+				fs.Errorf(w.f, "Failed to read inotify events: %v", err)
+			}
+			return
+		}
+		select {
+		case w.events <- parseInotifyEvents(buf[:n]):
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// parseInotifyEvents decodes the events in buf
+func parseInotifyEvents(buf []byte) (events []inotifyEvent) {
+	for offset := 0; offset+unix.SizeofInotifyEvent <= len(buf); {
+		raw := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+		offset += unix.SizeofInotifyEvent
+		end := offset + int(raw.Len)
+		if end > len(buf) {
+			fs.Debugf(nil, "Truncated inotify event")
+			break
+		}
+		events = append(events, inotifyEvent{
+			wd:     int(raw.Wd),
+			mask:   raw.Mask,
+			cookie: raw.Cookie,
+			name:   strings.TrimRight(string(buf[offset:end]), "\x00"),
+		})
+		offset = end
+	}
+	return events
+}
+
+// addWatch adds a watch for dir if it isn't already watched.
+//
+// If the inotify watch limit has been reached the directory is
+// scanned for changes every poll interval instead.
+func (w *watcher) addWatch(dir string) {
+	if _, ok := w.dirs[dir]; ok {
+		return
+	}
+	if _, ok := w.unwatched[dir]; ok {
+		return
+	}
+	wd, err := inotifyAddWatch(w.fd, w.f.localPath(dir), inotifyMask)
+	if err == unix.ENOSPC {
+		if !w.warnedLimit {
+			fs.Logf(w.f, "Change notify: inotify watch limit reached so scanning unwatched directories every poll interval instead - increase fs.inotify.max_user_watches to fix")
+			w.warnedLimit = true
+		}
+		snapshot, err := w.snapshot(dir)
+		if err != nil {
+			fs.Debugf(dir, "Change notify: failed to read directory: %v", err)
+			return
+		}
+		w.unwatched[dir] = snapshot
+		return
+	} else if err != nil {
+		fs.Debugf(dir, "Change notify: failed to watch directory: %v", err)
+		return
+	}
+	w.wds[wd] = dir
+	w.dirs[dir] = wd
+}
+
+// watchTree adds watches to dir and all the directories under it
+func (w *watcher) watchTree(dir string) {
+	w.addWatch(dir)
+	entries, err := os.ReadDir(w.f.localPath(dir))
+	if err != nil {
+		fs.Debugf(dir, "Change notify: failed to read directory: %v", err)
+		return
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if w.f.opt.OneFileSystem {
+			fi, err := entry.Info()
+			if err != nil || readDevice(fi, true) != w.f.dev {
+				continue
+			}
+		}
+		w.watchTree(w.f.cleanRemote(dir, entry.Name()))
+	}
+}
+
+// forgetTree removes any watches for dir and the directories under it
+func (w *watcher) forgetTree(dir string) {
+	inTree := func(remote string) bool {
+		return dir == "" || remote == dir || strings.HasPrefix(remote, dir+"/")
+	}
+	for remote, wd := range w.dirs {
+		if inTree(remote) {
+			_, _ = unix.InotifyRmWatch(w.fd, uint32(wd))
+			delete(w.dirs, remote)
+			delete(w.wds, wd)
+		}
+	}
+	for remote := range w.unwatched {
+		if inTree(remote) {
+			delete(w.unwatched, remote)
+		}
+	}
+}
+
+// renameTree updates the watches for oldDir and the directories
+// under it to be under newDir
+func (w *watcher) renameTree(oldDir, newDir string) {
+	newRemote := func(remote string) (string, bool) {
+		if remote == oldDir {
+			return newDir, true
+		}
+		if strings.HasPrefix(remote, oldDir+"/") {
+			return newDir + remote[len(oldDir):], true
+		}
+		return "", false
+	}
+	for remote, wd := range w.dirs {
+		if renamed, ok := newRemote(remote); ok {
+			delete(w.dirs, remote)
+			w.dirs[renamed] = wd
+			w.wds[wd] = renamed
+		}
+	}
+	for remote, snapshot := range w.unwatched {
+		if renamed, ok := newRemote(remote); ok {
+			delete(w.unwatched, remote)
+			newSnapshot := make(dirSnapshot, len(snapshot))
+			for entryRemote, state := range snapshot {
+				newSnapshot[renamed+entryRemote[len(remote):]] = state
+			}
+			w.unwatched[renamed] = newSnapshot
+		}
+	}
+}
+
+// notify the change to remote, only once per batch of events
+func (w *watcher) notify(remote string, entryType fs.EntryType) {
+	if _, ok := w.notified[remote]; ok {
+		return
+	}
+	w.notified[remote] = struct{}{}
+	if entryType == fs.EntryObject && w.f.opt.TranslateSymlinks {
+		fi, err := os.Lstat(w.f.localPath(remote))
+		if err != nil {
+			// The object is gone so it could have been a link
+			w.notifyFunc(remote+linkSuffix, entryType)
+		} else if fi.Mode()&os.ModeSymlink != 0 {
+			remote += linkSuffix
+		}
+	}
+	w.notifyFunc(remote, entryType)
+}
+
+// created is called when remote appears in the tree
+func (w *watcher) created(remote string, entryType fs.EntryType) {
+	w.notify(remote, entryType)
+	if entryType == fs.EntryDirectory {
+		// Entries may have been created in the directory
+		// before the watch was added but notifying the
+		// directory covers those.
+		w.watchTree(remote)
+	}
+}
+
+// removed is called when remote disappears from the tree
+func (w *watcher) removed(remote string, entryType fs.EntryType) {
+	w.notify(remote, entryType)
+	if entryType == fs.EntryDirectory {
+		w.forgetTree(remote)
+	}
+}
+
+// renamed is called when oldRemote is renamed to newRemote within
+// the tree
+func (w *watcher) renamed(oldRemote, newRemote string, entryType fs.EntryType) {
+	w.notify(oldRemote, entryType)
+	w.notify(newRemote, entryType)
+	if entryType == fs.EntryDirectory {
+		w.renameTree(oldRemote, newRemote)
+	}
+}
+
+// flushRenames treats any IN_MOVED_FROM from before generation which
+// haven't been paired as moves out of the tree
+func (w *watcher) flushRenames(generation int) {
+	for cookie, rename := range w.renames {
+		if rename.generation < generation {
+			delete(w.renames, cookie)
+			w.removed(rename.remote, rename.entryType)
+		}
+	}
+}
+
+// handleEvents handles a batch of events
+func (w *watcher) handleEvents(events []inotifyEvent) {
+	w.notified = make(map[string]struct{})
+	for _, event := range events {
+		if event.mask&unix.IN_Q_OVERFLOW != 0 {
+			fs.Logf(w.f, "Change notify: inotify event queue overflowed so some changes were missed")
+			w.notify("", fs.EntryDirectory)
+			continue
+		}
+		dir, ok := w.wds[event.wd]
+		if !ok {
+			continue
+		}
+		if event.mask&unix.IN_IGNORED != 0 {
+			// Watch removed because the directory was deleted
+			delete(w.wds, event.wd)
+			if w.dirs[dir] == event.wd {
+				delete(w.dirs, dir)
+			}
+			continue
+		}
+		if event.name == "" {
+			// Events on the directory itself are notified
+			// from its parent
+			continue
+		}
+		remote := w.f.cleanRemote(dir, event.name)
+		entryType := fs.EntryObject
+		if event.mask&unix.IN_ISDIR != 0 {
+			entryType = fs.EntryDirectory
+		}
+		switch {
+		case event.mask&unix.IN_MOVED_FROM != 0:
+			w.renames[event.cookie] = pendingRename{
+				remote:     remote,
+				entryType:  entryType,
+				generation: w.generation,
+			}
+		case event.mask&unix.IN_MOVED_TO != 0:
+			if rename, ok := w.renames[event.cookie]; ok {
+				delete(w.renames, event.cookie)
+				w.renamed(rename.remote, remote, entryType)
+			} else {
+				// Moved in from outside the tree
+				w.created(remote, entryType)
+			}
+		case event.mask&unix.IN_CREATE != 0:
+			w.created(remote, entryType)
+		case event.mask&unix.IN_DELETE != 0:
+			w.removed(remote, entryType)
+		default:
+			w.notify(remote, entryType)
+		}
+	}
+	// The IN_MOVED_TO for a rename is always in the same batch as
+	// the IN_MOVED_FROM or the next one if the read buffer was
+	// full, so flush any older than that.
+	w.flushRenames(w.generation)
+	w.generation++
+}
+
+// snapshot reads the state of dir for scanning
+func (w *watcher) snapshot(dir string) (dirSnapshot, error) {
+	entries, err := os.ReadDir(w.f.localPath(dir))
+	if err != nil {
+		return nil, err
+	}
+	snapshot := make(dirSnapshot, len(entries))
+	for _, entry := range entries {
+		fi, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		state := entryState{
+			entryType: fs.EntryObject,
+			size:      fi.Size(),
+			modTime:   fi.ModTime(),
+		}
+		if fi.IsDir() {
+			if w.f.opt.OneFileSystem && readDevice(fi, true) != w.f.dev {
+				continue
+			}
+			state = entryState{entryType: fs.EntryDirectory}
+		}
+		snapshot[w.f.cleanRemote(dir, entry.Name())] = state
+	}
+	return snapshot, nil
+}
+
+// scan the directories which couldn't be watched for changes
+func (w *watcher) scan() {
+	if len(w.unwatched) == 0 {
+		return
+	}
+	w.notified = make(map[string]struct{})
+	dirs := make([]string, 0, len(w.unwatched))
+	for dir := range w.unwatched {
+		dirs = append(dirs, dir)
+	}
+	for _, dir := range dirs {
+		oldSnapshot, ok := w.unwatched[dir]
+		if !ok {
+			// removed while scanning a parent
+			continue
+		}
+		newSnapshot, err := w.snapshot(dir)
+		if err != nil {
+			w.removed(dir, fs.EntryDirectory)
+			continue
+		}
+		w.unwatched[dir] = newSnapshot
+		for remote, newState := range newSnapshot {
+			oldState, ok := oldSnapshot[remote]
+			if !ok || oldState.entryType != newState.entryType {
+				if ok {
+					w.removed(remote, oldState.entryType)
+				}
+				w.created(remote, newState.entryType)
+			} else if oldState.size != newState.size || !oldState.modTime.Equal(newState.modTime) {
+				w.notify(remote, newState.entryType)
+			}
+		}
+		for remote, oldState := range oldSnapshot {
+			if _, ok := newSnapshot[remote]; !ok {
+				w.removed(remote, oldState.entryType)
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+//go:build linux
+// +build linux
+
+package local
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/configmap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+// changeRecorder records the changes notified by ChangeNotify
+type changeRecorder struct {
+	mu      sync.Mutex
+	changes map[string]fs.EntryType
+}
+
+func (c *changeRecorder) notify(remote string, entryType fs.EntryType) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.changes[remote] = entryType
+}
+
+// forget any changes to remote seen so far
+func (c *changeRecorder) forget(remote string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.changes, remote)
+}
+
+// wait for remote to be notified with entryType
+func (c *changeRecorder) wait(t *testing.T, remote string, entryType fs.EntryType) {
+	assert.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		got, ok := c.changes[remote]
+		return ok && got == entryType
+	}, 5*time.Second, 10*time.Millisecond, "waiting for change to %q", remote)
+}
+
+// startChangeNotify starts ChangeNotify on a new local Fs returning
+// the Fs and the recorder of the changes once the watches are added
+func startChangeNotify(t *testing.T, pollInterval time.Duration) (*Fs, *changeRecorder) {
+	started := make(chan struct{})
+	oldWatcherStarted := watcherStarted
+	watcherStarted = func() { close(started) }
+	t.Cleanup(func() { watcherStarted = oldWatcherStarted })
+	ctx := context.Background()
+	fi, err := NewFs(ctx, "local", t.TempDir(), configmap.Simple{})
+	require.NoError(t, err)
+	f := fi.(*Fs)
+	require.NoError(t, os.MkdirAll(filepath.Join(f.root, "existing", "sub"), 0777))
+	c := &changeRecorder{changes: map[string]fs.EntryType{}}
+	pollIntervalChan := make(chan time.Duration)
+	f.ChangeNotify(ctx, c.notify, pollIntervalChan)
+	pollIntervalChan <- pollInterval
+	t.Cleanup(func() { close(pollIntervalChan) })
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for change notify to start")
+	}
+	return f, c
+}
+
+func TestChangeNotify(t *testing.T) {
+	f, c := startChangeNotify(t, time.Hour)
+	root := f.root
+
+	// New file
+	require.NoError(t, os.WriteFile(filepath.Join(root, "file.txt"), []byte("hello"), 0666))
+	c.wait(t, "file.txt", fs.EntryObject)
+
+	// New file in an existing sub directory
+	require.NoError(t, os.WriteFile(filepath.Join(root, "existing", "sub", "file2.txt"), []byte("hello"), 0666))
+	c.wait(t, "existing/sub/file2.txt", fs.EntryObject)
+
+	// New directory should be watched
+	require.NoError(t, os.Mkdir(filepath.Join(root, "newdir"), 0777))
+	c.wait(t, "newdir", fs.EntryDirectory)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "newdir", "file3.txt"), []byte("hello"), 0666))
+	c.wait(t, "newdir/file3.txt", fs.EntryObject)
+
+	// Rename of a file notifies both names
+	require.NoError(t, os.Rename(filepath.Join(root, "file.txt"), filepath.Join(root, "existing", "renamed.txt")))
+	c.wait(t, "existing/renamed.txt", fs.EntryObject)
+
+	// Renamed directory should still be watched under the new name
+	require.NoError(t, os.Rename(filepath.Join(root, "newdir"), filepath.Join(root, "moveddir")))
+	c.wait(t, "moveddir", fs.EntryDirectory)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "moveddir", "file4.txt"), []byte("hello"), 0666))
+	c.wait(t, "moveddir/file4.txt", fs.EntryObject)
+
+	// Move out of the tree is a delete
+	outside := t.TempDir()
+	c.forget("existing/renamed.txt")
+	require.NoError(t, os.Rename(filepath.Join(root, "existing", "renamed.txt"), filepath.Join(outside, "gone.txt")))
+	c.wait(t, "existing/renamed.txt", fs.EntryObject)
+}
+
+func TestChangeNotifyWatchLimit(t *testing.T) {
+	oldInotifyAddWatch := inotifyAddWatch
+	defer func() { inotifyAddWatch = oldInotifyAddWatch }()
+	inotifyAddWatch = func(fd int, pathname string, mask uint32) (int, error) {
+		if filepath.Base(pathname) == "sub" {
+			return -1, unix.ENOSPC
+		}
+		return oldInotifyAddWatch(fd, pathname, mask)
+	}
+
+	f, c := startChangeNotify(t, 50*time.Millisecond)
+	root := f.root
+
+	// Changes in the watched directory are seen
+	require.NoError(t, os.WriteFile(filepath.Join(root, "existing", "file.txt"), []byte("hello"), 0666))
+	c.wait(t, "existing/file.txt", fs.EntryObject)
+
+	// Changes in the unwatched directory are found by scanning
+	require.NoError(t, os.WriteFile(filepath.Join(root, "existing", "sub", "file2.txt"), []byte("hello"), 0666))
+	c.wait(t, "existing/sub/file2.txt", fs.EntryObject)
+	c.forget("existing/sub/file2.txt")
+	require.NoError(t, os.Remove(filepath.Join(root, "existing", "sub", "file2.txt")))
+	c.wait(t, "existing/sub/file2.txt", fs.EntryObject)
+}
+
+func TestParseInotifyEvents(t *testing.T) {
+	assert.Nil(t, parseInotifyEvents(nil))
+	// Truncated events are ignored
+	assert.Nil(t, parseInotifyEvents(make([]byte, unix.SizeofInotifyEvent-1)))
+}
@@ -205,6 +205,20 @@ when copying to a CIFS mount owned by another user. If this option is
 enabled, rclone will no longer update the modtime after copying a file.`,
 			Default:  false,
 			Advanced: true,
+		}, {
+			Name: "change_notify",
+			Help: `Watch for changes with inotify (Linux only).
+
+If this is set then ` + "`rclone mount`" + ` and ` + "`rclone serve`" + ` will see changes
+made to the files outside of rclone as soon as they happen rather than
+when the directory cache expires.
+
+This walks the whole directory tree when it starts and adds an inotify
+watch for every directory, which may take a long time for large trees.
+The watches count towards the system wide
+fs.inotify.max_user_watches limit which is shared with other programs.`,
+			Default:  false,
+			Advanced: true,
 		}, {
 			Name:     config.ConfigEncoding,
 			Help:     config.ConfigEncodingHelp,
@@ -229,6 +243,7 @@ type Options struct {
 	NoPreAllocate     bool                 `config:"no_preallocate"`
 	NoSparse          bool                 `config:"no_sparse"`
 	NoSetModTime      bool                 `config:"no_set_modtime"`
+	ChangeNotify      bool                 `config:"change_notify"`
 	Enc               encoder.MultiEncoder `config:"encoding"`
 }
 
@@ -295,7 +310,13 @@ func NewFs(ctx context.Context, name, root string, m configmap.Mapper) (fs.Fs, e
 		ReadMetadata:            true,
 		WriteMetadata:           true,
 		UserMetadata:            xattrSupported, // can only R/W general purpose metadata if xattrs are supported
+		ReadDirMetadata:         true,
+		WriteDirMetadata:        true,
+		WriteDirSetModTime:      true,
 	}).Fill(ctx, f)
+	if !opt.ChangeNotify {
+		f.features.ChangeNotify = nil
+	}
 	if opt.FollowSymlinks {
 		f.lstat = os.Stat
 	}
@@ -526,7 +547,7 @@ func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err e
 				// Ignore directories which are symlinks.  These are junction points under windows which
 				// are kind of a souped up symlink. Unix doesn't have directories which are symlinks.
 				if (mode&os.ModeSymlink) == 0 && f.dev == readDevice(fi, f.opt.OneFileSystem) {
-					d := fs.NewDir(newRemote, fi.ModTime())
+					d := f.newDirectory(newRemote, fi)
 					entries = append(entries, d)
 				}
 			} else {
@@ -1366,6 +1387,18 @@ func (o *Object) Metadata(ctx context.Context) (metadata fs.Metadata, err error)
 	return metadata, nil
 }
 
+// SetMetadata sets metadata for an object
+//
+// Only the keys in metadata are changed
+func (o *Object) SetMetadata(ctx context.Context, metadata fs.Metadata) error {
+	err := o.writeMetadata(metadata)
+	if err != nil {
+		return err
+	}
+	// Re-read metadata
+	return o.lstat()
+}
+
 // Write the metadata on the object
 func (o *Object) writeMetadata(metadata fs.Metadata) (err error) {
 	err = o.setXattr(metadata)
@@ -1379,6 +1412,53 @@ func (o *Object) writeMetadata(metadata fs.Metadata) (err error) {
 	return err
 }
 
+// Directory represents a local filesystem directory
+type Directory struct {
+	*fs.Dir
+	o *Object // the directory as an Object for reading and writing metadata
+}
+
+// newDirectory makes a Directory from a remote and its os.FileInfo
+func (f *Fs) newDirectory(remote string, info os.FileInfo) *Directory {
+	o := &Object{
+		fs:     f,
+		remote: remote,
+		path:   f.localPath(remote),
+	}
+	o.setMetadata(info)
+	return &Directory{
+		Dir: fs.NewDir(remote, info.ModTime()),
+		o:   o,
+	}
+}
+
+// ModTime returns the modification time of the directory
+func (d *Directory) ModTime(ctx context.Context) time.Time {
+	return d.o.ModTime(ctx)
+}
+
+// SetModTime sets the modification time of the directory
+func (d *Directory) SetModTime(ctx context.Context, modTime time.Time) error {
+	return d.o.SetModTime(ctx, modTime)
+}
+
+// Metadata returns metadata for the directory
+//
+// It should return nil if there is no Metadata
+func (d *Directory) Metadata(ctx context.Context) (metadata fs.Metadata, err error) {
+	return d.o.Metadata(ctx)
+}
+
+// SetMetadata sets the metadata of the directory
+func (d *Directory) SetMetadata(ctx context.Context, metadata fs.Metadata) error {
+	err := d.o.writeMetadata(metadata)
+	if err != nil {
+		return err
+	}
+	// Re-read metadata
+	return d.o.lstat()
+}
+
 func cleanRootPath(s string, noUNC bool, enc encoder.MultiEncoder) string {
 	if runtime.GOOS == "windows" {
 		if !filepath.IsAbs(s) && !strings.HasPrefix(s, "\\") {
@@ -1419,4 +1499,6 @@ var (
 	_ fs.OpenWriterAter = &Fs{}
 	_ fs.Object         = &Object{}
 	_ fs.Metadataer     = &Object{}
+	_ fs.SetMetadataer  = &Object{}
+	_ fs.FullDirectory  = &Directory{}
 )
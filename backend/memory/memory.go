@@ -125,6 +125,7 @@ func (bi *bucketsInfo) removeObjectData(bucketName, bucketPath string) (removed
 		od := b.objects[bucketPath]
 		if od != nil {
 			delete(b.objects, bucketPath)
+			b.pruneDirs(bucketPath)
 			removed = true
 		}
 		b.mu.Unlock()
@@ -132,15 +133,46 @@ func (bi *bucketsInfo) removeObjectData(bucketName, bucketPath string) (removed
 	return removed
 }
 
+// getDirModTime gets the modification time of the directory at
+// (bucketName, bucketPath) or zero if not set
+func (bi *bucketsInfo) getDirModTime(bucketName, bucketPath string) (modTime time.Time) {
+	b := bi.getBucket(bucketName)
+	if b == nil {
+		return modTime
+	}
+	b.mu.RLock()
+	if dd := b.dirs[bucketPath]; dd != nil {
+		modTime = dd.modTime
+	}
+	b.mu.RUnlock()
+	return modTime
+}
+
+// updateDirData calls update on the directory data for (bucketName,
+// bucketPath) making it if necessary
+func (bi *bucketsInfo) updateDirData(bucketName, bucketPath string, update func(dd *dirData)) {
+	b := bi.makeBucket(bucketName)
+	b.mu.Lock()
+	dd := b.dirs[bucketPath]
+	if dd == nil {
+		dd = &dirData{}
+		b.dirs[bucketPath] = dd
+	}
+	update(dd)
+	b.mu.Unlock()
+}
+
 // bucketInfo holds info about a single bucket
 type bucketInfo struct {
 	mu      sync.RWMutex
 	objects map[string]*objectData
+	dirs    map[string]*dirData
 }
 
 func newBucketInfo() *bucketInfo {
 	return &bucketInfo{
 		objects: make(map[string]*objectData, 16),
+		dirs:    make(map[string]*dirData),
 	}
 }
 
@@ -152,6 +184,33 @@ func (bi *bucketInfo) getObjectData(name string) (od *objectData) {
 	return od
 }
 
+// pruneDirs removes the directory data of the parents of bucketPath
+// which no longer have any objects in.
+//
+// b.mu must be held for writing
+func (bi *bucketInfo) pruneDirs(bucketPath string) {
+	if len(bi.dirs) == 0 {
+		return
+	}
+	for dir := path.Dir(bucketPath); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if _, found := bi.dirs[dir]; !found {
+			continue
+		}
+		prefix := dir + "/"
+		empty := true
+		for absPath := range bi.objects {
+			if strings.HasPrefix(absPath, prefix) {
+				empty = false
+				break
+			}
+		}
+		if !empty {
+			break
+		}
+		delete(bi.dirs, dir)
+	}
+}
+
 // getBucket gets a names bucket or nil
 func (bi *bucketInfo) isEmpty() (empty bool) {
 	bi.mu.RLock()
@@ -168,6 +227,19 @@ type objectData struct {
 	data     []byte
 }
 
+// the directory data which is kept if it has been set
+type dirData struct {
+	modTime  time.Time
+	metadata fs.Metadata
+}
+
+// Directory describes a memory directory
+type Directory struct {
+	*fs.Dir
+	bucket     string // the bucket the directory is in
+	bucketPath string // the path of the directory in the bucket
+}
+
 // Object describes a memory object
 type Object struct {
 	fs     *Fs         // what this object is part of
@@ -236,10 +308,13 @@ func NewFs(ctx context.Context, name, root string, m configmap.Mapper) (fs.Fs, e
 	}
 	f.setRoot(root)
 	f.features = (&fs.Features{
-		ReadMimeType:      true,
-		WriteMimeType:     true,
-		BucketBased:       true,
-		BucketBasedRootOK: true,
+		ReadMimeType:       true,
+		WriteMimeType:      true,
+		BucketBased:        true,
+		BucketBasedRootOK:  true,
+		ReadDirMetadata:    true,
+		WriteDirMetadata:   true,
+		WriteDirSetModTime: true,
 	}).Fill(ctx, f)
 	if f.rootBucket != "" && f.rootDirectory != "" {
 		od := buckets.getObjectData(f.rootBucket, f.rootDirectory)
@@ -304,7 +379,7 @@ func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBuck
 					}
 					_, found := dirs[dir]
 					if !found {
-						err = fn(dir, fs.NewDir(dir, time.Time{}), true)
+						err = fn(dir, f.newDirectory(b, dir, bucket, directory+localPath[:slash]), true)
 						if err != nil {
 							return err
 						}
@@ -326,6 +401,22 @@ func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBuck
 	return nil
 }
 
+// newDirectory makes a Directory for remote which is bucketPath in
+// bucket b called bucketName
+//
+// b.mu must be held for reading
+func (f *Fs) newDirectory(b *bucketInfo, remote, bucketName, bucketPath string) *Directory {
+	var modTime time.Time
+	if dd := b.dirs[bucketPath]; dd != nil {
+		modTime = dd.modTime
+	}
+	return &Directory{
+		Dir:        fs.NewDir(remote, modTime),
+		bucket:     bucketName,
+		bucketPath: bucketPath,
+	}
+}
+
 // listDir lists the bucket to the entries
 func (f *Fs) listDir(ctx context.Context, bucket, directory, prefix string, addBucket bool) (entries fs.DirEntries, err error) {
 	// List the objects and directories
@@ -340,8 +431,10 @@ func (f *Fs) listDir(ctx context.Context, bucket, directory, prefix string, addB
 func (f *Fs) listBuckets(ctx context.Context) (entries fs.DirEntries, err error) {
 	buckets.mu.RLock()
 	defer buckets.mu.RUnlock()
-	for name := range buckets.buckets {
-		entries = append(entries, fs.NewDir(name, time.Time{}))
+	for name, b := range buckets.buckets {
+		b.mu.RLock()
+		entries = append(entries, f.newDirectory(b, name, name, ""))
+		b.mu.RUnlock()
 	}
 	return entries, nil
 }
@@ -418,7 +511,7 @@ func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (
 
 // Put the object into the bucket
 //
-// Copy the reader in to the new object which is returned
+// # Copy the reader in to the new object which is returned
 //
 // The new object may have been created if an error is returned
 func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
@@ -463,9 +556,9 @@ func (f *Fs) Precision() time.Duration {
 
 // Copy src to this remote using server-side copy operations.
 //
-// This is stored with the remote path given
+// # This is stored with the remote path given
 //
-// It returns the destination Object and a possible error
+// # It returns the destination Object and a possible error
 //
 // Will only be called if src.Fs().Name() == f.Name()
 //
@@ -612,12 +705,58 @@ func (o *Object) MimeType(ctx context.Context) string {
 	return o.od.mimeType
 }
 
+// ------------------------------------------------------------
+
+// ModTime returns the modification time of the directory
+func (d *Directory) ModTime(ctx context.Context) time.Time {
+	modTime := buckets.getDirModTime(d.bucket, d.bucketPath)
+	if !modTime.IsZero() {
+		return modTime
+	}
+	return d.Dir.ModTime(ctx)
+}
+
+// SetModTime sets the modification time of the directory
+func (d *Directory) SetModTime(ctx context.Context, modTime time.Time) error {
+	buckets.updateDirData(d.bucket, d.bucketPath, func(dd *dirData) {
+		dd.modTime = modTime
+	})
+	return nil
+}
+
+// Metadata returns metadata for the directory
+//
+// It should return nil if there is no Metadata
+func (d *Directory) Metadata(ctx context.Context) (metadata fs.Metadata, err error) {
+	b := buckets.getBucket(d.bucket)
+	if b == nil {
+		return nil, nil
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	dd := b.dirs[d.bucketPath]
+	if dd == nil {
+		return nil, nil
+	}
+	metadata.Merge(dd.metadata)
+	return metadata, nil
+}
+
+// SetMetadata sets metadata for the directory
+func (d *Directory) SetMetadata(ctx context.Context, metadata fs.Metadata) error {
+	buckets.updateDirData(d.bucket, d.bucketPath, func(dd *dirData) {
+		dd.metadata.Merge(metadata)
+	})
+	return nil
+}
+
 // Check the interfaces are satisfied
 var (
-	_ fs.Fs          = &Fs{}
-	_ fs.Copier      = &Fs{}
-	_ fs.PutStreamer = &Fs{}
-	_ fs.ListRer     = &Fs{}
-	_ fs.Object      = &Object{}
-	_ fs.MimeTyper   = &Object{}
+	_ fs.Fs            = &Fs{}
+	_ fs.Copier        = &Fs{}
+	_ fs.PutStreamer   = &Fs{}
+	_ fs.ListRer       = &Fs{}
+	_ fs.Object        = &Object{}
+	_ fs.MimeTyper     = &Object{}
+	_ fs.FullDirectory = &Directory{}
 )
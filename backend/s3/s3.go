@@ -54,6 +54,7 @@ import (
 	"github.com/rclone/rclone/lib/readers"
 	"github.com/rclone/rclone/lib/rest"
 	"github.com/rclone/rclone/lib/structs"
+	"github.com/rclone/rclone/lib/version"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -61,7 +62,7 @@ import (
 func init() {
 	fs.Register(&fs.RegInfo{
 		Name:        "s3",
-		Description: "Amazon S3 Compliant Storage Providers including AWS, Alibaba, Ceph, China Mobile, Cloudflare, ArvanCloud, Digital Ocean, Dreamhost, Huawei OBS, IBM COS, IDrive e2, Lyve Cloud, Minio, Netease, RackCorp, Scaleway, SeaweedFS, StackPath, Storj, Tencent COS and Wasabi",
+		Description: "Amazon S3 Compliant Storage Providers including AWS, Alibaba, Ceph, China Mobile, Cloudflare, ArvanCloud, Digital Ocean, Dreamhost, Huawei OBS, IBM COS, IDrive e2, Lyve Cloud, Minio, Netease, RackCorp, Rclone, Scaleway, SeaweedFS, StackPath, Storj, Tencent COS and Wasabi",
 		NewFs:       NewFs,
 		CommandHelp: commandHelp,
 		Config: func(ctx context.Context, name string, m configmap.Mapper, config fs.ConfigIn) (*fs.ConfigOut, error) {
@@ -125,6 +126,9 @@ func init() {
 			}, {
 				Value: "RackCorp",
 				Help:  "RackCorp Object Storage",
+			}, {
+				Value: "Rclone",
+				Help:  "Rclone S3 Server",
 			}, {
 				Value: "Scaleway",
 				Help:  "Scaleway Object Storage",
@@ -1982,6 +1986,22 @@ circumstances or for testing.
 `,
 			Default:  false,
 			Advanced: true,
+		}, {
+			Name:     "versions",
+			Help:     "Include old versions in directory listings.\n\nNote that when using this no file write operations are permitted,\nso you can't upload files or delete them.",
+			Default:  false,
+			Advanced: true,
+		}, {
+			Name: "version_at",
+			Help: `Show file versions as they were at the specified time.
+
+The parameter should be a date, "2006-01-02", datetime "2006-01-02
+15:04:05" or a duration for that long ago, eg "100d" or "1h".
+
+Note that when using this no file write operations are permitted,
+so you can't upload files or delete them.`,
+			Default:  fs.Time{},
+			Advanced: true,
 		},
 		}})
 }
@@ -2004,6 +2024,12 @@ const (
 	maxExpireDuration   = fs.Duration(7 * 24 * time.Hour) // max expiry is 1 week
 )
 
+// Globals
+var (
+	errNotWithVersions  = errors.New("can't modify or delete files in --s3-versions mode")
+	errNotWithVersionAt = errors.New("can't modify or delete files in --s3-version-at mode")
+)
+
 // system metadata keys which this backend owns
 var systemMetadataInfo = map[string]fs.MetadataHelp{
 	"cache-control": {
@@ -2099,6 +2125,8 @@ type Options struct {
 	DownloadURL           string               `config:"download_url"`
 	UseMultipartEtag      fs.Tristate          `config:"use_multipart_etag"`
 	UsePresignedRequest   bool                 `config:"use_presigned_request"`
+	Versions              bool                 `config:"versions"`
+	VersionAt             fs.Time              `config:"version_at"`
 }
 
 // Fs represents a remote s3 server
@@ -2134,6 +2162,7 @@ type Object struct {
 	lastModified time.Time         // Last modified
 	meta         map[string]string // The object metadata if known - may be nil - with lower case keys
 	mimeType     string            // MimeType of object - may be ""
+	versionID    *string           // If present this points to an object version
 
 	// Metadata as pointers to strings as they often won't be present
 	storageClass       *string // e.g. GLACIER
@@ -2235,7 +2264,13 @@ func (f *Fs) split(rootRelativePath string) (bucketName, bucketPath string) {
 
 // split returns bucket and bucketPath from the object
 func (o *Object) split() (bucket, bucketPath string) {
-	return o.fs.split(o.remote)
+	bucket, bucketPath = o.fs.split(o.remote)
+	// If there is an object version, then the path may have a
+	// version suffix, if so remove it.
+	if o.versionID != nil && o.fs.opt.Versions {
+		_, bucketPath = version.Remove(bucketPath)
+	}
+	return bucket, bucketPath
 }
 
 // getClient makes an http client according to the options
@@ -2491,6 +2526,8 @@ func setQuirks(opt *Options) {
 	case "RackCorp":
 		// No quirks
 		useMultipartEtag = false // untested
+	case "Rclone":
+		virtualHostStyle = false
 	case "Scaleway":
 		// Scaleway can only have 1000 parts in an upload
 		if opt.MaxUploadParts > 1000 {
@@ -2584,6 +2621,9 @@ func NewFs(ctx context.Context, name, root string, m configmap.Mapper) (fs.Fs, e
 	if opt.BucketACL == "" {
 		opt.BucketACL = opt.ACL
 	}
+	if opt.Versions && opt.VersionAt.IsSet() {
+		return nil, errors.New("s3: can't use --s3-versions and --s3-version-at at the same time")
+	}
 	if opt.SSECustomerKey != "" && opt.SSECustomerKeyMD5 == "" {
 		// calculate CustomerKeyMD5 if not supplied
 		md5sumBinary := md5.Sum([]byte(opt.SSECustomerKey))
@@ -2672,10 +2712,20 @@ func NewFs(ctx context.Context, name, root string, m configmap.Mapper) (fs.Fs, e
 // Return an Object from a path
 //
 //If it can't be found it returns the error ErrorObjectNotFound.
-func (f *Fs) newObjectWithInfo(ctx context.Context, remote string, info *s3.Object) (fs.Object, error) {
+func (f *Fs) newObjectWithInfo(ctx context.Context, remote string, info *s3.Object, versionID *string) (fs.Object, error) {
 	o := &Object{
-		fs:     f,
-		remote: remote,
+		fs:        f,
+		remote:    remote,
+		versionID: versionID,
+	}
+	// If using versions then the version ID needs to be read from
+	// the listing
+	if info == nil && ((f.opt.Versions && version.Match(remote)) || f.opt.VersionAt.IsSet()) {
+		var err error
+		info, o.versionID, err = f.getMetaDataListing(ctx, remote)
+		if err != nil {
+			return nil, err
+		}
 	}
 	if info != nil {
 		// Set info but not meta
@@ -2700,7 +2750,48 @@ func (f *Fs) newObjectWithInfo(ctx context.Context, remote string, info *s3.Obje
 // NewObject finds the Object at remote.  If it can't be found
 // it returns the error fs.ErrorObjectNotFound.
 func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
-	return f.newObjectWithInfo(ctx, remote, nil)
+	return f.newObjectWithInfo(ctx, remote, nil, nil)
+}
+
+// getMetaDataListing reads the info for remote from a listing
+//
+// This is used to find the version ID of remote when using
+// --s3-versions or --s3-version-at.
+func (f *Fs) getMetaDataListing(ctx context.Context, wantRemote string) (info *s3.Object, versionID *string, err error) {
+	bucket, bucketPath := f.split(wantRemote)
+
+	// Strip the version string off if using versions
+	if f.opt.Versions {
+		_, bucketPath = version.Remove(bucketPath)
+	}
+
+	err = f.list(ctx, listOpt{
+		bucket:       bucket,
+		directory:    bucketPath,
+		prefix:       f.rootDirectory,
+		addBucket:    f.rootBucket == "",
+		recurse:      true,
+		withVersions: f.opt.Versions,
+		versionAt:    f.opt.VersionAt,
+		findFile:     true,
+	}, func(gotRemote string, object *s3.Object, objectVersionID *string, isDirectory bool) error {
+		if isDirectory || gotRemote != wantRemote {
+			return nil
+		}
+		info = object
+		versionID = objectVersionID
+		return errEndList // read only 1 item
+	})
+	if err != nil {
+		if err == fs.ErrorDirNotFound {
+			return nil, nil, fs.ErrorObjectNotFound
+		}
+		return nil, nil, err
+	}
+	if info == nil {
+		return nil, nil, fs.ErrorObjectNotFound
+	}
+	return info, versionID, nil
 }
 
 // Gets the bucket location
@@ -2749,24 +2840,47 @@ func (f *Fs) updateRegionForBucket(ctx context.Context, bucket string) error {
 }
 
 // listFn is called from list to handle an object.
-type listFn func(remote string, object *s3.Object, isDirectory bool) error
+//
+// versionID is set if the listing was of object versions.
+type listFn func(remote string, object *s3.Object, versionID *string, isDirectory bool) error
+
+// errEndList is a sentinel used to end the list iteration now.
+// listFn should return it to end the iteration with no errors.
+var errEndList = errors.New("end list")
+
+// listOpt contains options for the list function
+type listOpt struct {
+	bucket       string  // bucket to list
+	directory    string  // directory with bucket
+	prefix       string  // prefix to remove from listing
+	addBucket    bool    // if set, the bucket is added to the start of the remote
+	recurse      bool    // if set, recurse to read sub directories
+	withVersions bool    // if set, old versions are listed with version suffixes
+	versionAt    fs.Time // if set, only show the versions current at this time
+	hidden       bool    // if set, return delete markers as objects with Size == isDeleteMarker
+	findFile     bool    // if set, look for the file called (bucket, directory)
+}
 
 // list lists the objects into the function supplied from
 // the bucket and directory supplied.  The remote has prefix
-// removed from it and if addBucket is set then it adds the
+// removed from it and if opt.addBucket is set then it adds the
 // bucket to the start.
 //
-// Set recurse to read sub directories
-func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBucket bool, recurse bool, fn listFn) error {
+// Set opt.recurse to read sub directories
+//
+// If withVersions or versionAt are set then the object versions are
+// listed and fn is passed the version ID of each object.
+func (f *Fs) list(ctx context.Context, opt listOpt, fn listFn) error {
 	v1 := f.opt.ListVersion == 1
+	bucket, directory, prefix := opt.bucket, opt.directory, opt.prefix
 	if prefix != "" {
 		prefix += "/"
 	}
-	if directory != "" {
+	if directory != "" && !opt.findFile {
 		directory += "/"
 	}
 	delimiter := ""
-	if !recurse {
+	if !opt.recurse {
 		delimiter = "/"
 	}
 	var continuationToken, startAfter *string
@@ -2786,6 +2900,10 @@ func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBuck
 	// So we enable only on providers we know supports it properly, all others can retry when a
 	// XML Syntax error is detected.
 	urlEncodeListings := f.opt.ListURLEncode.Value
+	var versions *versionsList
+	if opt.withVersions || opt.versionAt.IsSet() {
+		versions = f.newVersionsList(opt.hidden, opt.withVersions, opt.versionAt)
+	}
 	for {
 		// FIXME need to implement ALL loop
 		req := s3.ListObjectsV2Input{
@@ -2803,9 +2921,12 @@ func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBuck
 			req.RequestPayer = aws.String(s3.RequestPayerRequester)
 		}
 		var resp *s3.ListObjectsV2Output
+		var infos []versionInfo
 		var err error
 		err = f.pacer.Call(func() (bool, error) {
-			if v1 {
+			if versions != nil {
+				resp, infos, err = versions.List(ctx, &req)
+			} else if v1 {
 				// Convert v2 req into v1 req
 				var reqv1 s3.ListObjectsInput
 				structs.SetFrom(&reqv1, &req)
@@ -2858,7 +2979,7 @@ func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBuck
 			}
 			return err
 		}
-		if !recurse {
+		if !opt.recurse {
 			for _, commonPrefix := range resp.CommonPrefixes {
 				if commonPrefix.Prefix == nil {
 					fs.Logf(f, "Nil common prefix received")
@@ -2878,17 +2999,20 @@ func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBuck
 					continue
 				}
 				remote = remote[len(prefix):]
-				if addBucket {
+				if opt.addBucket {
 					remote = path.Join(bucket, remote)
 				}
 				remote = strings.TrimSuffix(remote, "/")
-				err = fn(remote, &s3.Object{Key: &remote}, true)
+				err = fn(remote, &s3.Object{Key: &remote}, nil, true)
 				if err != nil {
+					if err == errEndList {
+						return nil
+					}
 					return err
 				}
 			}
 		}
-		for _, object := range resp.Contents {
+		for i, object := range resp.Contents {
 			remote := aws.StringValue(object.Key)
 			if urlEncodeListings {
 				remote, err = url.QueryUnescape(remote)
@@ -2904,21 +3028,35 @@ func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBuck
 			}
 			remote = remote[len(prefix):]
 			isDirectory := remote == "" || strings.HasSuffix(remote, "/")
-			if addBucket {
-				remote = path.Join(bucket, remote)
-			}
 			// is this a directory marker?
 			if isDirectory && object.Size != nil && *object.Size == 0 {
 				continue // skip directory marker
 			}
-			err = fn(remote, object, false)
+			var versionID *string
+			if versions != nil {
+				versionID = infos[i].versionID
+				if infos[i].old {
+					remote = version.Add(remote, aws.TimeValue(object.LastModified))
+				}
+			}
+			if opt.addBucket {
+				remote = path.Join(bucket, remote)
+			}
+			err = fn(remote, object, versionID, false)
 			if err != nil {
+				if err == errEndList {
+					return nil
+				}
 				return err
 			}
 		}
 		if !aws.BoolValue(resp.IsTruncated) {
 			break
 		}
+		if versions != nil {
+			// versions keeps track of where the next page starts
+			continue
+		}
 		// Use NextContinuationToken if set, otherwise use last Key for StartAfter
 		if resp.NextContinuationToken == nil || *resp.NextContinuationToken == "" {
 			if len(resp.Contents) == 0 {
@@ -2940,8 +3078,131 @@ func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBuck
 	return nil
 }
 
+// versionsList lists object versions using ListObjectVersions
+// converting the results to look like ListObjectsV2.
+type versionsList struct {
+	f           *Fs
+	req         s3.ListObjectVersionsInput
+	hidden      bool      // set to return delete markers
+	addSuffix   bool      // set to mark old versions to have a version suffix added
+	versionAt   time.Time // if set only return the versions current at this time
+	lastKeySent string    // last Key returned
+}
+
+// versionInfo is the extra info for each object returned by
+// versionsList.List
+type versionInfo struct {
+	versionID *string // version ID of the object
+	old       bool    // set if this isn't the latest version and needs a version suffix
+}
+
+// isDeleteMarker is used as the Size of objects which are delete
+// markers returned by versionsList.List
+var isDeleteMarker = new(int64)
+
+// newVersionsList makes a new versionsList
+func (f *Fs) newVersionsList(hidden bool, addSuffix bool, versionAt fs.Time) *versionsList {
+	return &versionsList{
+		f:         f,
+		hidden:    hidden,
+		addSuffix: addSuffix,
+		versionAt: time.Time(versionAt),
+	}
+}
+
+// versionLess returns true if a should be sorted before b. Versions
+// are sorted by key then newest first.
+func versionLess(a, b *s3.ObjectVersion) bool {
+	aKey, bKey := aws.StringValue(a.Key), aws.StringValue(b.Key)
+	if aKey != bKey {
+		return aKey < bKey
+	}
+	aTime, bTime := aws.TimeValue(a.LastModified), aws.TimeValue(b.LastModified)
+	if !aTime.Equal(bTime) {
+		return aTime.After(bTime)
+	}
+	// Same time so put the latest first
+	return aws.BoolValue(a.IsLatest) && !aws.BoolValue(b.IsLatest)
+}
+
+// mergeDeleteMarkers merges the delete markers into the versions
+// marking them with a Size of isDeleteMarker.
+func mergeDeleteMarkers(versions []*s3.ObjectVersion, deleteMarkers []*s3.DeleteMarkerEntry) []*s3.ObjectVersion {
+	if len(deleteMarkers) == 0 {
+		return versions
+	}
+	merged := make([]*s3.ObjectVersion, 0, len(versions)+len(deleteMarkers))
+	merged = append(merged, versions...)
+	for _, deleteMarker := range deleteMarkers {
+		objectVersion := new(s3.ObjectVersion)
+		structs.SetFrom(objectVersion, deleteMarker)
+		objectVersion.Size = isDeleteMarker
+		merged = append(merged, objectVersion)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return versionLess(merged[i], merged[j])
+	})
+	return merged
+}
+
+// List reads the next page of versions using the parameters in req.
+//
+// It returns the versions as Contents in resp with the extra info for
+// each in infos.
+func (ls *versionsList) List(ctx context.Context, req *s3.ListObjectsV2Input) (resp *s3.ListObjectsV2Output, infos []versionInfo, err error) {
+	structs.SetFrom(&ls.req, req)
+	respVersions, err := ls.f.c.ListObjectVersionsWithContext(ctx, &ls.req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Set up the request for the next page
+	ls.req.KeyMarker = respVersions.NextKeyMarker
+	ls.req.VersionIdMarker = respVersions.NextVersionIdMarker
+	if aws.StringValue(req.EncodingType) == s3.EncodingTypeUrl && ls.req.KeyMarker != nil {
+		keyMarker, err := url.QueryUnescape(*ls.req.KeyMarker)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to URL decode NextKeyMarker %q: %w", *ls.req.KeyMarker, err)
+		}
+		ls.req.KeyMarker = &keyMarker
+	}
+
+	// Convert the versions and delete markers into Contents
+	resp = new(s3.ListObjectsV2Output)
+	structs.SetFrom(resp, respVersions)
+	objectVersions := mergeDeleteMarkers(respVersions.Versions, respVersions.DeleteMarkers)
+	resp.Contents = make([]*s3.Object, 0, len(objectVersions))
+	infos = make([]versionInfo, 0, len(objectVersions))
+	for _, objectVersion := range objectVersions {
+		key := aws.StringValue(objectVersion.Key)
+		if !ls.versionAt.IsZero() {
+			if aws.TimeValue(objectVersion.LastModified).After(ls.versionAt) {
+				// Ignore versions that were created after the specified time
+				continue
+			}
+			if key == ls.lastKeySent {
+				// Ignore versions before the already returned version
+				continue
+			}
+		}
+		ls.lastKeySent = key
+		// Don't return delete markers unless asked for
+		if objectVersion.Size == isDeleteMarker && !ls.hidden {
+			continue
+		}
+		object := new(s3.Object)
+		structs.SetFrom(object, objectVersion)
+		resp.Contents = append(resp.Contents, object)
+		infos = append(infos, versionInfo{
+			versionID: objectVersion.VersionId,
+			old:       ls.addSuffix && !aws.BoolValue(objectVersion.IsLatest),
+		})
+	}
+	return resp, infos, nil
+}
+
 // Convert a list item into a DirEntry
-func (f *Fs) itemToDirEntry(ctx context.Context, remote string, object *s3.Object, isDirectory bool) (fs.DirEntry, error) {
+func (f *Fs) itemToDirEntry(ctx context.Context, remote string, object *s3.Object, versionID *string, isDirectory bool) (fs.DirEntry, error) {
 	if isDirectory {
 		size := int64(0)
 		if object.Size != nil {
@@ -2950,7 +3211,7 @@ func (f *Fs) itemToDirEntry(ctx context.Context, remote string, object *s3.Objec
 		d := fs.NewDir(remote, time.Time{}).SetSize(size)
 		return d, nil
 	}
-	o, err := f.newObjectWithInfo(ctx, remote, object)
+	o, err := f.newObjectWithInfo(ctx, remote, object, versionID)
 	if err != nil {
 		return nil, err
 	}
@@ -2960,8 +3221,15 @@ func (f *Fs) itemToDirEntry(ctx context.Context, remote string, object *s3.Objec
 // listDir lists files and directories to out
 func (f *Fs) listDir(ctx context.Context, bucket, directory, prefix string, addBucket bool) (entries fs.DirEntries, err error) {
 	// List the objects and directories
-	err = f.list(ctx, bucket, directory, prefix, addBucket, false, func(remote string, object *s3.Object, isDirectory bool) error {
-		entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
+	err = f.list(ctx, listOpt{
+		bucket:       bucket,
+		directory:    directory,
+		prefix:       prefix,
+		addBucket:    addBucket,
+		withVersions: f.opt.Versions,
+		versionAt:    f.opt.VersionAt,
+	}, func(remote string, object *s3.Object, versionID *string, isDirectory bool) error {
+		entry, err := f.itemToDirEntry(ctx, remote, object, versionID, isDirectory)
 		if err != nil {
 			return err
 		}
@@ -3038,8 +3306,16 @@ func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (
 	bucket, directory := f.split(dir)
 	list := walk.NewListRHelper(callback)
 	listR := func(bucket, directory, prefix string, addBucket bool) error {
-		return f.list(ctx, bucket, directory, prefix, addBucket, true, func(remote string, object *s3.Object, isDirectory bool) error {
-			entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
+		return f.list(ctx, listOpt{
+			bucket:       bucket,
+			directory:    directory,
+			prefix:       prefix,
+			addBucket:    addBucket,
+			recurse:      true,
+			withVersions: f.opt.Versions,
+			versionAt:    f.opt.VersionAt,
+		}, func(remote string, object *s3.Object, versionID *string, isDirectory bool) error {
+			entry, err := f.itemToDirEntry(ctx, remote, object, versionID, isDirectory)
 			if err != nil {
 				return err
 			}
@@ -3194,6 +3470,9 @@ func (f *Fs) copy(ctx context.Context, req *s3.CopyObjectInput, dstBucket, dstPa
 	req.ACL = &f.opt.ACL
 	req.Key = &dstPath
 	source := pathEscape(path.Join(srcBucket, srcPath))
+	if src.versionID != nil {
+		source += "?versionId=" + url.QueryEscape(*src.versionID)
+	}
 	req.CopySource = &source
 	if f.opt.RequesterPays {
 		req.RequestPayer = aws.String(s3.RequestPayerRequester)
@@ -3344,6 +3623,12 @@ func (f *Fs) copyMultipart(ctx context.Context, copyReq *s3.CopyObjectInput, dst
 //
 // If it isn't possible then return fs.ErrorCantCopy
 func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	if f.opt.Versions {
+		return nil, errNotWithVersions
+	}
+	if !time.Time(f.opt.VersionAt).IsZero() {
+		return nil, errNotWithVersionAt
+	}
 	dstBucket, dstPath := f.split(remote)
 	err := f.makeBucket(ctx, dstBucket)
 	if err != nil {
@@ -3497,6 +3782,72 @@ Durations are parsed as per the rest of rclone, 2h, 7d, 7w etc.
 	Opts: map[string]string{
 		"max-age": "Max age of upload to delete",
 	},
+}, {
+	Name:  "list-versions",
+	Short: "List all the versions of the objects.",
+	Long: `This command lists all the versions of the objects under the path
+passed in JSON format, including delete markers. The bucket must have
+versioning enabled.
+
+    rclone backend list-versions s3:bucket/path/to/dir
+
+Old versions have a version suffix added to their names in the same
+way as the --s3-versions flag. The VersionID can be used with the
+restore-version and delete-version commands.
+
+    [
+        {
+            "Remote": "file.txt",
+            "VersionID": "nQ2rBa_G9fAaW7PJKxzA6Hn7kMB4A.r0",
+            "Size": 6,
+            "ModTime": "2022-08-01T09:31:10.000000000Z",
+            "DeleteMarker": false
+        },
+        {
+            "Remote": "file-v2022-07-31-142403-000.txt",
+            "VersionID": "ZlhYkU4r8KhlMcI.jXxy1GMgb93_DNz5",
+            "Size": 5,
+            "ModTime": "2022-07-31T14:24:03.000000000Z",
+            "DeleteMarker": false
+        }
+    ]
+
+`,
+}, {
+	Name:  "restore-version",
+	Short: "Restore an old version of an object.",
+	Long: `This command restores an old version of an object by copying it over
+the current version, which then becomes an old version.
+
+    rclone backend restore-version s3:bucket path/to/file -o version-id=ID
+
+The version IDs can be found with the list-versions command.
+
+Note that you can use -i/--dry-run with this command to see what it
+would do.
+`,
+	Opts: map[string]string{
+		"version-id": "ID of the version to restore",
+	},
+}, {
+	Name:  "delete-version",
+	Short: "Permanently delete a version of an object.",
+	Long: `This command permanently deletes a version of an object.
+
+    rclone backend delete-version s3:bucket path/to/file -o version-id=ID
+
+If the version ID is that of a delete marker then the delete marker is
+removed, which undeletes the object if the delete marker was the
+current version.
+
+The version IDs can be found with the list-versions command.
+
+Note that you can use -i/--dry-run with this command to see what it
+would do.
+`,
+	Opts: map[string]string{
+		"version-id": "ID of the version to delete",
+	},
 }}
 
 // Command the backend to run a named command
@@ -3586,11 +3937,114 @@ func (f *Fs) Command(ctx context.Context, name string, arg []string, opt map[str
 			}
 		}
 		return nil, f.cleanUp(ctx, maxAge)
+	case "list-versions":
+		return f.listVersions(ctx)
+	case "restore-version", "delete-version":
+		if len(arg) != 1 {
+			return nil, fmt.Errorf("%s needs exactly one path", name)
+		}
+		versionID := opt["version-id"]
+		if versionID == "" {
+			return nil, errors.New("need -o version-id=ID")
+		}
+		if name == "restore-version" {
+			return nil, f.restoreVersion(ctx, arg[0], versionID)
+		}
+		return nil, f.deleteVersion(ctx, arg[0], versionID)
 	default:
 		return nil, fs.ErrorCommandNotFound
 	}
 }
 
+// versionItem is an item returned by the list-versions command
+type versionItem struct {
+	Remote       string
+	VersionID    string
+	Size         int64
+	ModTime      time.Time
+	DeleteMarker bool
+}
+
+// listVersions lists all the versions of the objects under the root
+// including the delete markers
+func (f *Fs) listVersions(ctx context.Context) (out []versionItem, err error) {
+	bucket, directory := f.split("")
+	if bucket == "" {
+		return nil, errors.New("list-versions needs a bucket")
+	}
+	out = []versionItem{}
+	err = f.list(ctx, listOpt{
+		bucket:       bucket,
+		directory:    directory,
+		prefix:       f.rootDirectory,
+		recurse:      true,
+		withVersions: true,
+		hidden:       true,
+	}, func(remote string, object *s3.Object, versionID *string, isDirectory bool) error {
+		if isDirectory {
+			return nil
+		}
+		item := versionItem{
+			Remote:       remote,
+			VersionID:    aws.StringValue(versionID),
+			ModTime:      aws.TimeValue(object.LastModified),
+			DeleteMarker: object.Size == isDeleteMarker,
+		}
+		if !item.DeleteMarker {
+			item.Size = aws.Int64Value(object.Size)
+		}
+		out = append(out, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// restoreVersion restores the version of remote with versionID by
+// copying it over the current version
+func (f *Fs) restoreVersion(ctx context.Context, remote, versionID string) error {
+	o := &Object{
+		fs:        f,
+		remote:    remote,
+		versionID: &versionID,
+	}
+	err := o.readMetaData(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read version %q: %w", versionID, err)
+	}
+	if operations.SkipDestructive(ctx, o, "restore version") {
+		return nil
+	}
+	bucket, bucketPath := o.split()
+	req := s3.CopyObjectInput{
+		MetadataDirective: aws.String(s3.MetadataDirectiveCopy),
+	}
+	return f.copy(ctx, &req, bucket, bucketPath, bucket, bucketPath, o)
+}
+
+// deleteVersion permanently deletes the version of remote with
+// versionID
+func (f *Fs) deleteVersion(ctx context.Context, remote, versionID string) error {
+	if operations.SkipDestructive(ctx, remote, "delete version") {
+		return nil
+	}
+	bucket, bucketPath := f.split(remote)
+	req := s3.DeleteObjectInput{
+		Bucket:    &bucket,
+		Key:       &bucketPath,
+		VersionId: &versionID,
+	}
+	if f.opt.RequesterPays {
+		req.RequestPayer = aws.String(s3.RequestPayerRequester)
+	}
+	return f.pacer.Call(func() (bool, error) {
+		_, err := f.c.DeleteObjectWithContext(ctx, &req)
+		return f.shouldRetry(ctx, err)
+	})
+}
+
 // listMultipartUploads lists all outstanding multipart uploads for (bucket, key)
 //
 // Note that rather lazily we treat key as a prefix so it matches
@@ -3770,8 +4224,9 @@ func (o *Object) Size() int64 {
 func (o *Object) headObject(ctx context.Context) (resp *s3.HeadObjectOutput, err error) {
 	bucket, bucketPath := o.split()
 	req := s3.HeadObjectInput{
-		Bucket: &bucket,
-		Key:    &bucketPath,
+		Bucket:    &bucket,
+		Key:       &bucketPath,
+		VersionId: o.versionID,
 	}
 	if o.fs.opt.RequesterPays {
 		req.RequestPayer = aws.String(s3.RequestPayerRequester)
@@ -3903,6 +4358,12 @@ func (o *Object) ModTime(ctx context.Context) time.Time {
 
 // SetModTime sets the modification time of the local fs object
 func (o *Object) SetModTime(ctx context.Context, modTime time.Time) error {
+	if o.fs.opt.Versions {
+		return errNotWithVersions
+	}
+	if !time.Time(o.fs.opt.VersionAt).IsZero() {
+		return errNotWithVersionAt
+	}
 	err := o.readMetaData(ctx)
 	if err != nil {
 		return err
@@ -3933,11 +4394,14 @@ func (o *Object) Storable() bool {
 }
 
 func (o *Object) downloadFromURL(ctx context.Context, bucketPath string, options ...fs.OpenOption) (in io.ReadCloser, err error) {
-	url := o.fs.opt.DownloadURL + bucketPath
+	rootURL := o.fs.opt.DownloadURL + bucketPath
+	if o.versionID != nil {
+		rootURL += "?versionId=" + url.QueryEscape(*o.versionID)
+	}
 	var resp *http.Response
 	opts := rest.Opts{
 		Method:  "GET",
-		RootURL: url,
+		RootURL: rootURL,
 		Options: options,
 	}
 	err = o.fs.pacer.Call(func() (bool, error) {
@@ -4011,8 +4475,9 @@ func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.Read
 	}
 
 	req := s3.GetObjectInput{
-		Bucket: &bucket,
-		Key:    &bucketPath,
+		Bucket:    &bucket,
+		Key:       &bucketPath,
+		VersionId: o.versionID,
 	}
 	if o.fs.opt.RequesterPays {
 		req.RequestPayer = aws.String(s3.RequestPayerRequester)
@@ -4403,6 +4868,12 @@ func (o *Object) uploadSinglepartPresignedRequest(ctx context.Context, req *s3.P
 
 // Update the Object from in with modTime and size
 func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	if o.fs.opt.Versions {
+		return errNotWithVersions
+	}
+	if !time.Time(o.fs.opt.VersionAt).IsZero() {
+		return errNotWithVersionAt
+	}
 	bucket, bucketPath := o.split()
 	err := o.fs.makeBucket(ctx, bucket)
 	if err != nil {
@@ -4604,6 +5075,12 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 
 // Remove an object
 func (o *Object) Remove(ctx context.Context) error {
+	if o.fs.opt.Versions {
+		return errNotWithVersions
+	}
+	if !time.Time(o.fs.opt.VersionAt).IsZero() {
+		return errNotWithVersionAt
+	}
 	bucket, bucketPath := o.split()
 	req := s3.DeleteObjectInput{
 		Bucket: &bucket,
@@ -4699,17 +5176,102 @@ func (o *Object) Metadata(ctx context.Context) (metadata fs.Metadata, err error)
 	return metadata, nil
 }
 
+// SetMetadata sets metadata for an Object
+//
+// The object is copied to itself to replace the metadata. Only the
+// keys in metadata are changed.
+func (o *Object) SetMetadata(ctx context.Context, metadata fs.Metadata) error {
+	if o.fs.opt.Versions {
+		return errNotWithVersions
+	}
+	if !time.Time(o.fs.opt.VersionAt).IsZero() {
+		return errNotWithVersionAt
+	}
+	err := o.readMetaData(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Can't update metadata here
+	if o.storageClass != nil && (*o.storageClass == "GLACIER" || *o.storageClass == "DEEP_ARCHIVE") {
+		return fmt.Errorf("can't set metadata on object in storage class %q", *o.storageClass)
+	}
+
+	// Start with the existing metadata as it is all replaced
+	bucket, bucketPath := o.split()
+	req := s3.CopyObjectInput{
+		CacheControl:       o.cacheControl,
+		ContentDisposition: o.contentDisposition,
+		ContentEncoding:    o.contentEncoding,
+		ContentLanguage:    o.contentLanguage,
+		StorageClass:       o.storageClass,
+		MetadataDirective:  aws.String(s3.MetadataDirectiveReplace), // replace metadata with that passed in
+	}
+	if o.mimeType != "" {
+		req.ContentType = aws.String(o.mimeType)
+	} else {
+		req.ContentType = aws.String(fs.MimeType(ctx, o)) // Guess the content type
+	}
+	meta := make(map[string]string, len(o.meta)+len(metadata))
+	for k, v := range o.meta {
+		meta[k] = v
+	}
+	for k, v := range metadata {
+		pv := aws.String(v)
+		k = strings.ToLower(k)
+		switch k {
+		case "cache-control":
+			req.CacheControl = pv
+		case "content-disposition":
+			req.ContentDisposition = pv
+		case "content-encoding":
+			req.ContentEncoding = pv
+		case "content-language":
+			req.ContentLanguage = pv
+		case "content-type":
+			req.ContentType = pv
+		case "x-amz-tagging":
+			req.Tagging = pv
+			req.TaggingDirective = aws.String(s3.TaggingDirectiveReplace)
+		case "tier":
+			req.StorageClass = aws.String(strings.ToUpper(v))
+		case "mtime":
+			modTime, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return fmt.Errorf("failed to parse metadata %s: %q: %w", k, v, err)
+			}
+			meta[metaMtime] = swift.TimeToFloatString(modTime)
+		case "btime":
+			// read only
+		default:
+			meta[k] = v
+		}
+	}
+	req.Metadata = mapToS3Metadata(meta)
+	if o.fs.opt.RequesterPays {
+		req.RequestPayer = aws.String(s3.RequestPayerRequester)
+	}
+	err = o.fs.copy(ctx, &req, bucket, bucketPath, bucket, bucketPath, o)
+	if err != nil {
+		return err
+	}
+	// Re-read metadata
+	o.meta = nil
+	return o.readMetaData(ctx)
+}
+
 // Check the interfaces are satisfied
 var (
-	_ fs.Fs          = &Fs{}
-	_ fs.Copier      = &Fs{}
-	_ fs.PutStreamer = &Fs{}
-	_ fs.ListRer     = &Fs{}
-	_ fs.Commander   = &Fs{}
-	_ fs.CleanUpper  = &Fs{}
-	_ fs.Object      = &Object{}
-	_ fs.MimeTyper   = &Object{}
-	_ fs.GetTierer   = &Object{}
-	_ fs.SetTierer   = &Object{}
-	_ fs.Metadataer  = &Object{}
+	_ fs.Fs            = &Fs{}
+	_ fs.Copier        = &Fs{}
+	_ fs.PutStreamer   = &Fs{}
+	_ fs.ListRer       = &Fs{}
+	_ fs.Commander     = &Fs{}
+	_ fs.CleanUpper    = &Fs{}
+	_ fs.Object        = &Object{}
+	_ fs.MimeTyper     = &Object{}
+	_ fs.GetTierer     = &Object{}
+	_ fs.SetTierer     = &Object{}
+	_ fs.Metadataer    = &Object{}
+	_ fs.SetMetadataer = &Object{}
 )
@@ -5,13 +5,20 @@ import (
 	"compress/gzip"
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/configmap"
 	"github.com/rclone/rclone/fstest"
 	"github.com/rclone/rclone/fstest/fstests"
 	"github.com/rclone/rclone/lib/random"
+	"github.com/rclone/rclone/lib/version"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -90,3 +97,253 @@ func (f *Fs) InternalTest(t *testing.T) {
 }
 
 var _ fstests.InternalTester = (*Fs)(nil)
+
+func TestVersionLess(t *testing.T) {
+	t1 := fstest.Time("2022-01-21T12:00:00+01:00")
+	t2 := fstest.Time("2022-01-21T12:00:01+01:00")
+	for n, test := range []struct {
+		a, b *s3.ObjectVersion
+		want bool
+	}{
+		{a: &s3.ObjectVersion{Key: aws.String("a")}, b: &s3.ObjectVersion{Key: aws.String("b")}, want: true},
+		{a: &s3.ObjectVersion{Key: aws.String("b")}, b: &s3.ObjectVersion{Key: aws.String("a")}, want: false},
+		{a: &s3.ObjectVersion{Key: aws.String("a"), LastModified: &t2}, b: &s3.ObjectVersion{Key: aws.String("a"), LastModified: &t1}, want: true},
+		{a: &s3.ObjectVersion{Key: aws.String("a"), LastModified: &t1}, b: &s3.ObjectVersion{Key: aws.String("a"), LastModified: &t2}, want: false},
+		{a: &s3.ObjectVersion{Key: aws.String("a"), LastModified: &t1, IsLatest: aws.Bool(true)}, b: &s3.ObjectVersion{Key: aws.String("a"), LastModified: &t1}, want: true},
+		{a: &s3.ObjectVersion{Key: aws.String("a"), LastModified: &t1}, b: &s3.ObjectVersion{Key: aws.String("a"), LastModified: &t1, IsLatest: aws.Bool(true)}, want: false},
+		{a: &s3.ObjectVersion{Key: aws.String("a"), LastModified: &t1}, b: &s3.ObjectVersion{Key: aws.String("a"), LastModified: &t1}, want: false},
+		{a: &s3.ObjectVersion{Key: aws.String("b"), LastModified: &t2}, b: &s3.ObjectVersion{Key: aws.String("a"), LastModified: &t1}, want: false},
+	} {
+		got := versionLess(test.a, test.b)
+		assert.Equal(t, test.want, got, fmt.Sprintf("%d: %s/%v vs %s/%v", n, aws.StringValue(test.a.Key), aws.TimeValue(test.a.LastModified), aws.StringValue(test.b.Key), aws.TimeValue(test.b.LastModified)))
+	}
+}
+
+func TestMergeDeleteMarkers(t *testing.T) {
+	t1 := fstest.Time("2022-01-21T12:00:00+01:00")
+	t2 := fstest.Time("2022-01-21T12:00:01+01:00")
+	t3 := fstest.Time("2022-01-21T12:00:02+01:00")
+	size := int64(1)
+	for _, test := range []struct {
+		name          string
+		versions      []*s3.ObjectVersion
+		deleteMarkers []*s3.DeleteMarkerEntry
+		want          []string
+	}{
+		{
+			name: "NoDeleteMarkers",
+			versions: []*s3.ObjectVersion{
+				{Key: aws.String("a"), VersionId: aws.String("a2"), LastModified: &t2, IsLatest: aws.Bool(true), Size: &size},
+				{Key: aws.String("a"), VersionId: aws.String("a1"), LastModified: &t1, Size: &size},
+			},
+			want: []string{"a2", "a1"},
+		},
+		{
+			name: "NoVersions",
+			deleteMarkers: []*s3.DeleteMarkerEntry{
+				{Key: aws.String("a"), VersionId: aws.String("d1"), LastModified: &t1, IsLatest: aws.Bool(true)},
+			},
+			want: []string{"d1*"},
+		},
+		{
+			name: "Interleaved",
+			versions: []*s3.ObjectVersion{
+				{Key: aws.String("a"), VersionId: aws.String("a3"), LastModified: &t3, IsLatest: aws.Bool(true), Size: &size},
+				{Key: aws.String("a"), VersionId: aws.String("a1"), LastModified: &t1, Size: &size},
+				{Key: aws.String("b"), VersionId: aws.String("b1"), LastModified: &t1, Size: &size},
+			},
+			deleteMarkers: []*s3.DeleteMarkerEntry{
+				{Key: aws.String("a"), VersionId: aws.String("d2"), LastModified: &t2},
+				{Key: aws.String("b"), VersionId: aws.String("d3"), LastModified: &t3, IsLatest: aws.Bool(true)},
+			},
+			want: []string{"a3", "d2*", "a1", "d3*", "b1"},
+		},
+		{
+			name: "SameTime",
+			versions: []*s3.ObjectVersion{
+				{Key: aws.String("a"), VersionId: aws.String("a1"), LastModified: &t1, Size: &size},
+			},
+			deleteMarkers: []*s3.DeleteMarkerEntry{
+				{Key: aws.String("a"), VersionId: aws.String("d1"), LastModified: &t1, IsLatest: aws.Bool(true)},
+			},
+			want: []string{"d1*", "a1"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, objectVersion := range mergeDeleteMarkers(test.versions, test.deleteMarkers) {
+				id := aws.StringValue(objectVersion.VersionId)
+				if objectVersion.Size == isDeleteMarker {
+					id += "*"
+				}
+				got = append(got, id)
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+// versionsPage is a page of the ListObjectVersions response served by
+// newVersionsServer
+const versionsPage = `<?xml version="1.0" encoding="UTF-8"?>
+<ListVersionsResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+<Name>bucket</Name>
+<IsTruncated>%s</IsTruncated>%s
+</ListVersionsResult>`
+
+// newVersionsServer makes a test S3 server with versions of a, b and
+// c split over two pages of ListObjectVersions. It returns an Fs
+// pointing at the bucket and a log of the requests made.
+func newVersionsServer(t *testing.T) (f *Fs, requests *[]string, tidy func()) {
+	var log []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		_, versions := q["versions"]
+		switch {
+		case r.Method == "GET" && strings.TrimSuffix(r.URL.Path, "/") == "/bucket" && versions:
+			log = append(log, fmt.Sprintf("list key-marker=%q version-id-marker=%q", q.Get("key-marker"), q.Get("version-id-marker")))
+			switch {
+			case q.Get("key-marker") == "" && q.Get("version-id-marker") == "":
+				_, _ = fmt.Fprintf(w, versionsPage, "true", `
+<NextKeyMarker>b</NextKeyMarker>
+<NextVersionIdMarker>d1</NextVersionIdMarker>
+<Version><Key>a</Key><VersionId>a0</VersionId><IsLatest>false</IsLatest><LastModified>2022-01-21T11:00:00.000Z</LastModified><Size>1</Size></Version>
+<Version><Key>a</Key><VersionId>a1</VersionId><IsLatest>true</IsLatest><LastModified>2022-01-21T12:00:00.000Z</LastModified><Size>2</Size></Version>
+<DeleteMarker><Key>b</Key><VersionId>d1</VersionId><IsLatest>true</IsLatest><LastModified>2022-01-21T13:00:00.000Z</LastModified></DeleteMarker>`)
+			case q.Get("key-marker") == "b" && q.Get("version-id-marker") == "d1":
+				_, _ = fmt.Fprintf(w, versionsPage, "false", `
+<Version><Key>b</Key><VersionId>b0</VersionId><IsLatest>false</IsLatest><LastModified>2022-01-21T12:00:00.000Z</LastModified><Size>3</Size></Version>
+<Version><Key>c</Key><VersionId>c0</VersionId><IsLatest>true</IsLatest><LastModified>2022-01-21T12:00:00.000Z</LastModified><Size>4</Size></Version>`)
+			default:
+				http.Error(w, "bad markers", http.StatusBadRequest)
+			}
+		case r.Method == "HEAD" && r.URL.Path == "/bucket/a":
+			log = append(log, fmt.Sprintf("head versionId=%q", q.Get("versionId")))
+			if q.Get("versionId") != "a0" {
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Length", "1")
+			w.Header().Set("Last-Modified", "Fri, 21 Jan 2022 11:00:00 GMT")
+			w.Header().Set("x-amz-version-id", "a0")
+		case r.Method == "PUT" && r.URL.Path == "/bucket/a":
+			log = append(log, fmt.Sprintf("copy source=%q", r.Header.Get("X-Amz-Copy-Source")))
+			_, _ = fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<CopyObjectResult><LastModified>2022-01-21T14:00:00.000Z</LastModified><ETag>"0cc175b9c0f1b6a831c399e269772661"</ETag></CopyObjectResult>`)
+		case r.Method == "DELETE" && r.URL.Path == "/bucket/a":
+			log = append(log, fmt.Sprintf("delete versionId=%q", q.Get("versionId")))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	})
+	ts := httptest.NewServer(handler)
+	m := configmap.Simple{
+		"type":              "s3",
+		"provider":          "Other",
+		"endpoint":          ts.URL,
+		"region":            "us-east-1",
+		"access_key_id":     "key",
+		"secret_access_key": "secret",
+		"force_path_style":  "true",
+	}
+	fsrc, err := NewFs(context.Background(), "s3test", "bucket", m)
+	require.NoError(t, err)
+	return fsrc.(*Fs), &log, ts.Close
+}
+
+func TestVersionsListPaging(t *testing.T) {
+	ctx := context.Background()
+	f, requests, tidy := newVersionsServer(t)
+	defer tidy()
+
+	ls := f.newVersionsList(true, true, fs.Time{})
+	req := s3.ListObjectsV2Input{Bucket: aws.String("bucket")}
+
+	resp, infos, err := ls.List(ctx, &req)
+	require.NoError(t, err)
+	assert.True(t, aws.BoolValue(resp.IsTruncated))
+	require.Len(t, resp.Contents, 3)
+	require.Len(t, infos, 3)
+	assert.Equal(t, "a", aws.StringValue(resp.Contents[0].Key))
+	assert.Equal(t, "a1", aws.StringValue(infos[0].versionID))
+	assert.False(t, infos[0].old)
+	assert.Equal(t, "a0", aws.StringValue(infos[1].versionID))
+	assert.True(t, infos[1].old)
+	assert.Equal(t, "d1", aws.StringValue(infos[2].versionID))
+	assert.True(t, resp.Contents[2].Size == isDeleteMarker)
+	assert.Equal(t, "b", aws.StringValue(ls.req.KeyMarker))
+	assert.Equal(t, "d1", aws.StringValue(ls.req.VersionIdMarker))
+
+	resp, infos, err = ls.List(ctx, &req)
+	require.NoError(t, err)
+	assert.False(t, aws.BoolValue(resp.IsTruncated))
+	require.Len(t, infos, 2)
+	assert.Equal(t, "b0", aws.StringValue(infos[0].versionID))
+	assert.True(t, infos[0].old)
+	assert.Equal(t, "c0", aws.StringValue(infos[1].versionID))
+	assert.False(t, infos[1].old)
+
+	assert.Equal(t, []string{
+		`list key-marker="" version-id-marker=""`,
+		`list key-marker="b" version-id-marker="d1"`,
+	}, *requests)
+
+	// Delete markers are left out unless hidden is set
+	ls = f.newVersionsList(false, true, fs.Time{})
+	resp, infos, err = ls.List(ctx, &req)
+	require.NoError(t, err)
+	assert.Len(t, resp.Contents, 2)
+	assert.Len(t, infos, 2)
+}
+
+func TestVersionCommands(t *testing.T) {
+	ctx := context.Background()
+	f, requests, tidy := newVersionsServer(t)
+	defer tidy()
+
+	t.Run("ListVersions", func(t *testing.T) {
+		out, err := f.Command(ctx, "list-versions", nil, nil)
+		require.NoError(t, err)
+		t11 := fstest.Time("2022-01-21T11:00:00Z")
+		t12 := fstest.Time("2022-01-21T12:00:00Z")
+		t13 := fstest.Time("2022-01-21T13:00:00Z")
+		assert.Equal(t, []versionItem{
+			{Remote: "a", VersionID: "a1", Size: 2, ModTime: t12},
+			{Remote: version.Add("a", t11), VersionID: "a0", Size: 1, ModTime: t11},
+			{Remote: "b", VersionID: "d1", ModTime: t13, DeleteMarker: true},
+			{Remote: version.Add("b", t12), VersionID: "b0", Size: 3, ModTime: t12},
+			{Remote: "c", VersionID: "c0", Size: 4, ModTime: t12},
+		}, out)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		for _, name := range []string{"restore-version", "delete-version"} {
+			_, err := f.Command(ctx, name, nil, map[string]string{"version-id": "a0"})
+			assert.Error(t, err)
+			_, err = f.Command(ctx, name, []string{"a"}, nil)
+			assert.Error(t, err)
+		}
+		_, err := f.Command(ctx, "restore-version", []string{"a"}, map[string]string{"version-id": "missing"})
+		assert.Error(t, err)
+	})
+
+	t.Run("RestoreVersion", func(t *testing.T) {
+		*requests = nil
+		_, err := f.Command(ctx, "restore-version", []string{"a"}, map[string]string{"version-id": "a0"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			`head versionId="a0"`,
+			`copy source="bucket/a?versionId=a0"`,
+		}, *requests)
+	})
+
+	t.Run("DeleteVersion", func(t *testing.T) {
+		*requests = nil
+		_, err := f.Command(ctx, "delete-version", []string{"a"}, map[string]string{"version-id": "a0"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			`delete versionId="a0"`,
+		}, *requests)
+	})
+}
@@ -0,0 +1,183 @@
+//go:build !plan9
+// +build !plan9
+
+package sftp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pkg/sftp"
+	"github.com/rclone/rclone/fs"
+)
+
+const metadataTimeFormat = time.RFC3339Nano
+
+// system metadata keys which this backend owns
+//
+// These use the same names and formats as the local backend so
+// metadata can be copied between them without loss.
+var systemMetadataInfo = map[string]fs.MetadataHelp{
+	"mode": {
+		Help:    "File type and mode",
+		Type:    "octal, unix style",
+		Example: "0100664",
+	},
+	"uid": {
+		Help:    "User ID of owner",
+		Type:    "decimal number",
+		Example: "500",
+	},
+	"gid": {
+		Help:    "Group ID of owner",
+		Type:    "decimal number",
+		Example: "500",
+	},
+	"atime": {
+		Help:    "Time of last access",
+		Type:    "RFC 3339",
+		Example: "2006-01-02T15:04:05Z07:00",
+	},
+	"mtime": {
+		Help:    "Time of last modification",
+		Type:    "RFC 3339",
+		Example: "2006-01-02T15:04:05Z07:00",
+	},
+}
+
+// fileStat returns the SFTP attributes from the stat result passed in
+// or nil if there aren't any
+func fileStat(info os.FileInfo) *sftp.FileStat {
+	stat, _ := info.Sys().(*sftp.FileStat)
+	return stat
+}
+
+// readMetadata reads the metadata from the SFTP attributes passed in
+//
+// It returns nil if the server didn't return the attributes.
+func readMetadata(stat *sftp.FileStat) fs.Metadata {
+	if stat == nil {
+		return nil
+	}
+	return fs.Metadata{
+		"mode":  fmt.Sprintf("%0o", stat.Mode),
+		"uid":   strconv.FormatUint(uint64(stat.UID), 10),
+		"gid":   strconv.FormatUint(uint64(stat.GID), 10),
+		"atime": time.Unix(int64(stat.Atime), 0).Format(metadataTimeFormat),
+		"mtime": time.Unix(int64(stat.Mtime), 0).Format(metadataTimeFormat),
+	}
+}
+
+// parse a time string from metadata with key
+func parseMetadataTime(what interface{}, m fs.Metadata, key string) (t time.Time, ok bool) {
+	value, ok := m[key]
+	if ok {
+		var err error
+		t, err = time.Parse(metadataTimeFormat, value)
+		if err != nil {
+			fs.Debugf(what, "failed to parse metadata %s: %q: %v", key, value, err)
+			ok = false
+		}
+	}
+	return t, ok
+}
+
+// parse an int from metadata with key and base
+func parseMetadataInt(what interface{}, m fs.Metadata, key string, base int) (result int, ok bool) {
+	value, ok := m[key]
+	if ok {
+		result64, err := strconv.ParseInt(value, base, 64)
+		if err != nil {
+			fs.Debugf(what, "failed to parse metadata %s: %q: %v", key, value, err)
+			ok = false
+		}
+		result = int(result64)
+	}
+	return result, ok
+}
+
+// chmodMode converts the unix style mode from the metadata into the
+// os.FileMode which Chmod expects.
+//
+// Only the permission bits can be set - the server works out the file
+// type.
+func chmodMode(mode int) os.FileMode {
+	fileMode := os.FileMode(mode & 0777)
+	if mode&04000 != 0 {
+		fileMode |= os.ModeSetuid
+	}
+	if mode&02000 != 0 {
+		fileMode |= os.ModeSetgid
+	}
+	if mode&01000 != 0 {
+		fileMode |= os.ModeSticky
+	}
+	return fileMode
+}
+
+// writeMetadata sets the metadata m on the file or directory at remote
+// using SETSTAT.
+//
+// stat should be the current attributes of remote if known and is
+// used to fill in the uid or gid if only one of them is being set.
+// Keys which aren't present in m are left unchanged.
+func (f *Fs) writeMetadata(ctx context.Context, remote string, stat *sftp.FileStat, m fs.Metadata) (outErr error) {
+	atime, atimeOK := parseMetadataTime(remote, m, "atime")
+	mtime, mtimeOK := parseMetadataTime(remote, m, "mtime")
+	uid, hasUID := parseMetadataInt(remote, m, "uid", 10)
+	gid, hasGID := parseMetadataInt(remote, m, "gid", 10)
+	mode, hasMode := parseMetadataInt(remote, m, "mode", 8)
+	if !atimeOK && !mtimeOK && !hasUID && !hasGID && !hasMode {
+		return nil
+	}
+	c, err := f.getSftpConnection(ctx)
+	if err != nil {
+		return fmt.Errorf("writeMetadata: %w", err)
+	}
+	defer func() {
+		f.putSftpConnection(&c, outErr)
+	}()
+	absPath := f.remotePath(remote)
+	if (atimeOK || mtimeOK) && f.opt.SetModTime {
+		if atimeOK && !mtimeOK {
+			mtime = atime
+		}
+		if !atimeOK && mtimeOK {
+			atime = mtime
+		}
+		err = c.sftpClient.Chtimes(absPath, atime, mtime)
+		if err != nil {
+			outErr = fmt.Errorf("failed to set times: %w", err)
+		}
+	}
+	if hasUID || hasGID {
+		if !hasUID {
+			if stat == nil {
+				uid = gid
+			} else {
+				uid = int(stat.UID)
+			}
+		}
+		if !hasGID {
+			if stat == nil {
+				gid = uid
+			} else {
+				gid = int(stat.GID)
+			}
+		}
+		err = c.sftpClient.Chown(absPath, uid, gid)
+		if err != nil {
+			outErr = fmt.Errorf("failed to change ownership: %w", err)
+		}
+	}
+	if hasMode {
+		err = c.sftpClient.Chmod(absPath, chmodMode(mode))
+		if err != nil {
+			outErr = fmt.Errorf("failed to change permissions: %w", err)
+		}
+	}
+	return outErr
+}
@@ -59,6 +59,14 @@ func init() {
 		Name:        "sftp",
 		Description: "SSH/SFTP",
 		NewFs:       NewFs,
+		MetadataInfo: &fs.MetadataInfo{
+			System: systemMetadataInfo,
+			Help: `The SFTP protocol (version 3) stores times to the nearest second
+and can only set the permission bits of the mode, not the file type.
+Setting the uid and gid will normally only work if the SFTP server is
+running as root. Times are not set if set_modtime is false.
+`,
+		},
 		Options: []fs.Option{{
 			Name:     "host",
 			Help:     "SSH host to connect to.\n\nE.g. \"example.com\".",
@@ -387,11 +395,12 @@ type Fs struct {
 type Object struct {
 	fs      *Fs
 	remote  string
-	size    int64       // size of the object
-	modTime time.Time   // modification time of the object
-	mode    os.FileMode // mode bits from the file
-	md5sum  *string     // Cached MD5 checksum
-	sha1sum *string     // Cached SHA1 checksum
+	size    int64          // size of the object
+	modTime time.Time      // modification time of the object
+	mode    os.FileMode    // mode bits from the file
+	attrs   *sftp.FileStat // attributes from the server if known
+	md5sum  *string        // Cached MD5 checksum
+	sha1sum *string        // Cached SHA1 checksum
 }
 
 // dial starts a client connection to the given SSH server. It is a
@@ -888,6 +897,11 @@ func NewFsWithConnection(ctx context.Context, f *Fs, name string, root string, m
 	f.features = (&fs.Features{
 		CanHaveEmptyDirectories: true,
 		SlowHash:                true,
+		WriteDirSetModTime:      f.opt.SetModTime,
+		ReadMetadata:            true,
+		WriteMetadata:           true,
+		ReadDirMetadata:         true,
+		WriteDirMetadata:        true,
 	}).Fill(ctx, f)
 	// Make a connection and pool it to return errors early
 	c, err := f.getSftpConnection(ctx)
@@ -1097,7 +1111,7 @@ func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err e
 			}
 		}
 		if info.IsDir() {
-			d := fs.NewDir(remote, info.ModTime())
+			d := f.newDirectory(remote, info)
 			entries = append(entries, d)
 		} else {
 			o := &Object{
@@ -1699,6 +1713,7 @@ func (o *Object) setMetadata(info os.FileInfo) {
 	o.modTime = info.ModTime()
 	o.size = info.Size()
 	o.mode = info.Mode()
+	o.attrs = fileStat(info)
 }
 
 // statRemote stats the file or directory at the remote given
@@ -1752,6 +1767,34 @@ func (o *Object) SetModTime(ctx context.Context, modTime time.Time) error {
 	return nil
 }
 
+// Metadata returns metadata for an object
+//
+// It should return nil if there is no Metadata
+func (o *Object) Metadata(ctx context.Context) (metadata fs.Metadata, err error) {
+	return readMetadata(o.attrs), nil
+}
+
+// SetMetadata sets metadata for an object
+//
+// Only the keys in metadata are changed
+func (o *Object) SetMetadata(ctx context.Context, metadata fs.Metadata) error {
+	return o.writeMetadata(ctx, metadata)
+}
+
+// writeMetadata sets the metadata on the object and reads the
+// attributes back
+func (o *Object) writeMetadata(ctx context.Context, metadata fs.Metadata) error {
+	err := o.fs.writeMetadata(ctx, o.remote, o.attrs, metadata)
+	if err != nil {
+		return err
+	}
+	err = o.stat(ctx)
+	if err != nil {
+		return fmt.Errorf("stat after setting metadata failed: %w", err)
+	}
+	return nil
+}
+
 // Storable returns whether the remote sftp file is a regular file (not a directory, symbolic link, block device, character device, named pipe, etc.)
 func (o *Object) Storable() bool {
 	return o.mode.IsRegular()
@@ -1919,6 +1962,18 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 		}
 	}
 
+	// Set the metadata if --metadata is in use
+	meta, err := fs.GetMetadataOptions(ctx, src, options)
+	if err != nil {
+		return fmt.Errorf("failed to read metadata from source object: %w", err)
+	}
+	if meta != nil {
+		err = o.writeMetadata(ctx, meta)
+		if err != nil {
+			return fmt.Errorf("Update failed to set metadata: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -1933,13 +1988,85 @@ func (o *Object) Remove(ctx context.Context) error {
 	return err
 }
 
+// Directory describes an SFTP directory
+type Directory struct {
+	*fs.Dir
+	fs      *Fs
+	modTime time.Time      // modification time of the directory
+	attrs   *sftp.FileStat // attributes from the server if known
+}
+
+// newDirectory makes a Directory from the remote and its stat result
+func (f *Fs) newDirectory(remote string, info os.FileInfo) *Directory {
+	return &Directory{
+		Dir:     fs.NewDir(remote, info.ModTime()),
+		fs:      f,
+		modTime: info.ModTime(),
+		attrs:   fileStat(info),
+	}
+}
+
+// ModTime returns the modification time of the directory
+func (d *Directory) ModTime(ctx context.Context) time.Time {
+	return d.modTime
+}
+
+// SetModTime sets the modification time of the directory
+func (d *Directory) SetModTime(ctx context.Context, modTime time.Time) error {
+	if !d.fs.opt.SetModTime {
+		return nil
+	}
+	c, err := d.fs.getSftpConnection(ctx)
+	if err != nil {
+		return fmt.Errorf("SetModTime: %w", err)
+	}
+	err = c.sftpClient.Chtimes(d.fs.remotePath(d.Remote()), modTime, modTime)
+	d.fs.putSftpConnection(&c, err)
+	if err != nil {
+		return fmt.Errorf("SetModTime failed: %w", err)
+	}
+	info, err := d.fs.stat(ctx, d.Remote())
+	if err != nil {
+		return fmt.Errorf("SetModTime stat failed: %w", err)
+	}
+	d.modTime = info.ModTime()
+	d.attrs = fileStat(info)
+	return nil
+}
+
+// Metadata returns metadata for the directory
+//
+// It should return nil if there is no Metadata
+func (d *Directory) Metadata(ctx context.Context) (metadata fs.Metadata, err error) {
+	return readMetadata(d.attrs), nil
+}
+
+// SetMetadata sets the metadata of the directory
+func (d *Directory) SetMetadata(ctx context.Context, metadata fs.Metadata) error {
+	err := d.fs.writeMetadata(ctx, d.Remote(), d.attrs, metadata)
+	if err != nil {
+		return err
+	}
+	// Re-read metadata
+	info, err := d.fs.stat(ctx, d.Remote())
+	if err != nil {
+		return fmt.Errorf("SetMetadata stat failed: %w", err)
+	}
+	d.modTime = info.ModTime()
+	d.attrs = fileStat(info)
+	return nil
+}
+
 // Check the interfaces are satisfied
 var (
-	_ fs.Fs          = &Fs{}
-	_ fs.PutStreamer = &Fs{}
-	_ fs.Mover       = &Fs{}
-	_ fs.DirMover    = &Fs{}
-	_ fs.Abouter     = &Fs{}
-	_ fs.Shutdowner  = &Fs{}
-	_ fs.Object      = &Object{}
+	_ fs.Fs            = &Fs{}
+	_ fs.PutStreamer   = &Fs{}
+	_ fs.Mover         = &Fs{}
+	_ fs.DirMover      = &Fs{}
+	_ fs.Abouter       = &Fs{}
+	_ fs.Shutdowner    = &Fs{}
+	_ fs.Object        = &Object{}
+	_ fs.Metadataer    = &Object{}
+	_ fs.SetMetadataer = &Object{}
+	_ fs.FullDirectory = &Directory{}
 )
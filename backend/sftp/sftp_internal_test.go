@@ -4,10 +4,18 @@
 package sftp
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"testing"
+	"time"
 
+	"github.com/pkg/sftp"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fstest"
+	"github.com/rclone/rclone/fstest/fstests"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestShellEscapeUnix(t *testing.T) {
@@ -87,3 +95,93 @@ func TestParseUsage(t *testing.T) {
 		assert.Equal(t, test.usage, [3]int64{gotSpaceTotal, gotSpaceUsed, gotSpaceAvail}, fmt.Sprintf("Test %d sshOutput = %q", i, test.sshOutput))
 	}
 }
+
+func TestReadMetadata(t *testing.T) {
+	assert.Nil(t, readMetadata(nil))
+	m := readMetadata(&sftp.FileStat{
+		Mode:  0102755,
+		UID:   500,
+		GID:   501,
+		Atime: 1000000000,
+		Mtime: 1234567890,
+	})
+	assert.Equal(t, fs.Metadata{
+		"mode":  "102755",
+		"uid":   "500",
+		"gid":   "501",
+		"atime": time.Unix(1000000000, 0).Format(metadataTimeFormat),
+		"mtime": time.Unix(1234567890, 0).Format(metadataTimeFormat),
+	}, m)
+}
+
+func TestChmodMode(t *testing.T) {
+	for i, test := range []struct {
+		mode int
+		want os.FileMode
+	}{
+		{0644, 0644},
+		{0100644, 0644},
+		{040755, 0755},
+		{04755, os.ModeSetuid | 0755},
+		{02750, os.ModeSetgid | 0750},
+		{01777, os.ModeSticky | 0777},
+		{0107777, os.ModeSetuid | os.ModeSetgid | os.ModeSticky | 0777},
+	} {
+		got := chmodMode(test.mode)
+		assert.Equal(t, test.want, got, fmt.Sprintf("Test %d mode = %o", i, test.mode))
+	}
+}
+
+func (f *Fs) InternalTestMetadata(t *testing.T) {
+	ctx := context.Background()
+	item := fstest.NewItem("test-metadata", "hello", fstest.Time("2001-05-06T04:05:06Z"))
+	obj := fstests.PutTestContents(ctx, t, f, &item, "hello", true)
+	defer func() {
+		assert.NoError(t, obj.Remove(ctx))
+	}()
+	o := obj.(*Object)
+	metadata, err := o.Metadata(ctx)
+	require.NoError(t, err)
+	if metadata == nil {
+		t.Skip("server doesn't return attributes")
+	}
+
+	// Setting the uid and gid to their current values should work
+	// without being root
+	mtime := "2009-05-06T04:05:06Z"
+	err = o.SetMetadata(ctx, fs.Metadata{
+		"uid":   metadata["uid"],
+		"gid":   metadata["gid"],
+		"mtime": mtime,
+	})
+	require.NoError(t, err)
+	got, err := o.Metadata(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, metadata["uid"], got["uid"])
+	assert.Equal(t, metadata["gid"], got["gid"])
+	if f.opt.SetModTime {
+		assert.True(t, fstest.Time(mtime).Equal(fstest.Time(got["mtime"])))
+	}
+
+	err = o.SetMetadata(ctx, fs.Metadata{"mode": "0100600"})
+	require.NoError(t, err)
+	got, err = o.Metadata(ctx)
+	require.NoError(t, err)
+	if got["mode"] != "100600" {
+		t.Skipf("server doesn't set the mode: got %q", got["mode"])
+	}
+
+	// The special bits should survive - setgid is allowed on a file
+	// in the owner's group
+	err = o.SetMetadata(ctx, fs.Metadata{"mode": "0102640"})
+	require.NoError(t, err)
+	got, err = o.Metadata(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "102640", got["mode"])
+}
+
+func (f *Fs) InternalTest(t *testing.T) {
+	t.Run("Metadata", f.InternalTestMetadata)
+}
+
+var _ fstests.InternalTester = (*Fs)(nil)
@@ -31,6 +31,7 @@ docs = [
     "alias.md",
     "amazonclouddrive.md",
     "s3.md",
+    "archive.md",
     "b2.md",
     "box.md",
     "cache.md",
@@ -0,0 +1,43 @@
+// Package archive provides the archive command which creates and
+// extracts archives on remotes.
+package archive
+
+import (
+	"errors"
+
+	"github.com/rclone/rclone/cmd"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd.Root.AddCommand(Command)
+	Command.AddCommand(createCommand)
+	Command.AddCommand(extractCommand)
+}
+
+// Command definition for cobra
+var Command = &cobra.Command{
+	Use:   "archive <action> [opts] <source> <dest>",
+	Short: `Create and extract archives on remotes.`,
+	Long: `Create and extract zip and tar archives on remotes without
+staging them on local disk. Requires the use of a subcommand to
+specify the action, e.g.
+
+    rclone archive create remote:dir remote:backup.tar.gz
+    rclone archive extract remote:backup.zip remote:dir
+
+Each subcommand has its own options which you can see in their help.
+
+To read the contents of archives in place use the
+[archive](/archive/) backend.
+`,
+	RunE: func(command *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("archive requires an action, e.g. 'rclone archive create remote:dir remote:file.zip'")
+		}
+		return errors.New("unknown action")
+	},
+}
+
+// formatHelp is the help for the --format flag
+const formatHelp = "Archive format - zip, tar or tar.gz - default is from the file extension"
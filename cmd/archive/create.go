@@ -0,0 +1,70 @@
+package archive
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/fs/archive"
+	"github.com/rclone/rclone/fs/config/flags"
+	"github.com/spf13/cobra"
+)
+
+var (
+	createFormat = ""
+)
+
+func init() {
+	cmdFlags := createCommand.Flags()
+	flags.StringVarP(cmdFlags, &createFormat, "format", "", createFormat, formatHelp)
+}
+
+var createCommand = &cobra.Command{
+	Use:   "create source:path dest:path/file.tar.gz",
+	Short: `Create an archive from the contents of a remote directory.`,
+	Long: `
+rclone archive create packs the contents of the source directory into
+a zip, tar or tar.gz archive which is streamed directly to the
+destination file. Nothing is staged on local disk unless the
+destination remote doesn't support streaming uploads, in which case
+the archive is spooled as described in [rclone rcat](/commands/rclone_rcat/).
+
+    rclone archive create remote:dir remote:backup/dir.tar.gz
+    rclone archive create /home/user/photos s3:bucket/photos.zip
+
+The format is worked out from the extension of the destination unless
+` + "`--format`" + ` is used. Use ` + "`-`" + ` as the destination to write the archive
+to standard output, e.g.
+
+    rclone archive create --format tar remote:dir - | tar tvf -
+
+Filters can be used to choose which files are put in the archive, and
+` + "`--max-depth`" + ` limits how deep the directory tree is read.
+
+Files are stored with their modification times. If ` + "`--metadata`" + `
+is in use the mode, mtime, uid and gid metadata are stored in the
+archive too (uid and gid for tar archives only).
+
+Note that the upload can't be retried as the archive isn't kept
+anywhere.
+`,
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(2, 2, command, args)
+		fsrc := cmd.NewFsSrc(args)
+		format, err := archive.FormatFromName(args[1], createFormat)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if args[1] == "-" {
+			cmd.Run(false, false, command, func() error {
+				return archive.Write(context.Background(), os.Stdout, fsrc, "", format)
+			})
+			return
+		}
+		fdst, dstFileName := cmd.NewFsDstFile(args[1:])
+		cmd.Run(false, true, command, func() error {
+			return archive.Create(context.Background(), fdst, dstFileName, fsrc, "", format)
+		})
+	},
+}
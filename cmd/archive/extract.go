@@ -0,0 +1,74 @@
+package archive
+
+import (
+	"context"
+	"log"
+
+	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/fs/archive"
+	"github.com/rclone/rclone/fs/config/flags"
+	"github.com/spf13/cobra"
+)
+
+var (
+	extractFormat = ""
+)
+
+func init() {
+	cmdFlags := extractCommand.Flags()
+	flags.StringVarP(cmdFlags, &extractFormat, "format", "", extractFormat, formatHelp)
+}
+
+var extractCommand = &cobra.Command{
+	Use:   "extract source:path/file.zip dest:path",
+	Short: `Extract an archive into a remote directory.`,
+	Long: `
+rclone archive extract unpacks the zip, tar or tar.gz archive in the
+source into the destination directory. The archive is read as a
+stream and each file is uploaded as it is read, so nothing is staged
+on local disk.
+
+    rclone archive extract remote:backup/dir.tar.gz remote:dir
+    rclone archive extract s3:bucket/photos.zip /home/user/photos
+
+The format is worked out from the extension of the source unless
+` + "`--format`" + ` is used.
+
+Files which already exist in the destination are skipped if they are
+unchanged as judged by the usual size and modification time checks,
+otherwise they are overwritten. Files are never deleted from the
+destination.
+
+Filters can be used to choose which files are extracted and
+` + "`--max-depth`" + ` limits how deep in the archive to extract.
+` + "`--exclude-if-present`" + ` is ignored.
+
+Files are extracted with their modification times. If
+` + "`--metadata`" + ` is in use then the mode, mtime, uid and gid stored in
+the archive are set as metadata on the extracted files if the
+destination supports it (uid and gid for tar archives only).
+
+Zip archives are read with ranged reads in the order the files are
+stored so the archive is read almost sequentially.
+`,
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(2, 2, command, args)
+		fsrc, srcFileName := cmd.NewFsFile(args[0])
+		if srcFileName == "" {
+			log.Fatalf("%q must point to an archive file", args[0])
+		}
+		format, err := archive.FormatFromName(srcFileName, extractFormat)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fdst := cmd.NewFsDir(args[1:])
+		cmd.Run(true, true, command, func() error {
+			ctx := context.Background()
+			src, err := fsrc.NewObject(ctx, srcFileName)
+			if err != nil {
+				return err
+			}
+			return archive.Extract(ctx, fdst, "", src, format)
+		})
+	},
+}
@@ -631,6 +631,17 @@ func (b *bisyncTest) runBisync(ctx context.Context, args []string) (err error) {
 			require.NoError(b.t, err, "parsing max-delete=%q", val)
 		case "size-only":
 			ci.SizeOnly = true
+		case "conflict-resolve":
+			require.NoError(b.t, opt.ConflictResolve.Set(val), "parsing conflict-resolve=%q", val)
+		case "conflict-loser":
+			require.NoError(b.t, opt.ConflictLoser.Set(val), "parsing conflict-loser=%q", val)
+		case "conflict-suffix":
+			opt.ConflictSuffix = val
+		case "compare":
+			require.NoError(b.t, opt.Compare.Set(val), "parsing compare=%q", val)
+		case "max-lock":
+			opt.MaxLock, err = time.ParseDuration(val)
+			require.NoError(b.t, err, "parsing max-lock=%q", val)
 		case "subdir":
 			fs1 = addSubdir(b.path1, val)
 			fs2 = addSubdir(b.path2, val)
@@ -1223,7 +1234,7 @@ func fileType(fileName string) string {
 		return "log"
 	}
 	switch filepath.Ext(fileName) {
-	case ".lst", ".lst-new", ".lst-err", ".lst-dry", ".lst-dry-new":
+	case ".lst", ".lst-new", ".lst-err", ".lst-dry", ".lst-dry-new", ".lst-chk":
 		return "listing"
 	case ".que":
 		return "queue"
@@ -0,0 +1,164 @@
+package bisync
+
+import (
+	"context"
+	"os"
+
+	"github.com/rclone/rclone/cmd/bisync/bilib"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+)
+
+// checkpoint holds listings of the last state known to be consistent
+// between Path1 and Path2 while changes are being applied.
+//
+// It starts out as the prior listings and is saved again each time a
+// batch of changes completes. If the run is interrupted the next run
+// uses the checkpoint in place of the prior listings, so it only has
+// to redo the changes which didn't complete rather than needing a
+// --resync.
+type checkpoint struct {
+	ctx   context.Context
+	file1 string // checkpoint of Path1 listing
+	file2 string // checkpoint of Path2 listing
+	ls1   *fileList
+	ls2   *fileList
+}
+
+// checkpointFiles returns the names of the checkpoint files
+func (b *bisyncRun) checkpointFiles() (file1, file2 string) {
+	return b.basePath + ".path1.lst-chk", b.basePath + ".path2.lst-chk"
+}
+
+// startCheckpoint saves the prior listings as the checkpoint before
+// any changes are applied. It does nothing in --dry-run mode.
+func (b *bisyncRun) startCheckpoint(ctx context.Context, ds1, ds2 *deltaSet) error {
+	if b.opt.DryRun {
+		return nil
+	}
+	c := &checkpoint{
+		ctx: ctx,
+		ls1: ds1.prior,
+		ls2: ds2.prior,
+	}
+	c.file1, c.file2 = b.checkpointFiles()
+	if err := c.save(); err != nil {
+		return err
+	}
+	b.chk = c
+	return nil
+}
+
+// copied records that files were copied from the path with listing
+// src to the other path
+func (c *checkpoint) copied(files bilib.Names, src *fileList) error {
+	if c == nil {
+		return nil
+	}
+	for file := range files {
+		fi := src.get(file)
+		if fi == nil {
+			// Renamed conflict files aren't in the listing so will
+			// be found as new on both paths by the next run.
+			continue
+		}
+		for _, ls := range []*fileList{c.ls1, c.ls2} {
+			hashVal := ""
+			if ls.hash == src.hash {
+				hashVal = fi.hash
+			}
+			ls.put(file, fi.size, fi.time, hashVal, fi.id)
+		}
+	}
+	return c.save()
+}
+
+// deleted records that files were deleted
+func (c *checkpoint) deleted(files bilib.Names) error {
+	if c == nil {
+		return nil
+	}
+	c.ls1.remove(files)
+	c.ls2.remove(files)
+	return c.save()
+}
+
+// save the checkpoint listings, replacing the old ones atomically so
+// an interruption can't leave a partial checkpoint
+func (c *checkpoint) save() error {
+	for _, item := range []struct {
+		ls   *fileList
+		file string
+	}{{c.ls1, c.file1}, {c.ls2, c.file2}} {
+		tmpFile := item.file + "-tmp"
+		if err := item.ls.save(c.ctx, tmpFile); err != nil {
+			return err
+		}
+		if err := os.Rename(tmpFile, item.file); err != nil {
+			_ = os.Remove(tmpFile)
+			return err
+		}
+	}
+	return nil
+}
+
+// removeCheckpoint removes the checkpoint files
+func (b *bisyncRun) removeCheckpoint() {
+	file1, file2 := b.checkpointFiles()
+	_ = os.Remove(file1)
+	_ = os.Remove(file2)
+	b.chk = nil
+}
+
+// recoverCheckpoint replaces the prior listings with the checkpoint
+// left by an interrupted run, if there is one.
+func (b *bisyncRun) recoverCheckpoint(listing1, listing2 string) error {
+	file1, file2 := b.checkpointFiles()
+	if !bilib.FileExists(file1) && !bilib.FileExists(file2) {
+		return nil
+	}
+	if b.opt.Resync {
+		if !b.opt.DryRun {
+			fs.Infof(nil, "Removing checkpoint of interrupted run")
+			b.removeCheckpoint()
+		}
+		return nil
+	}
+	fs.Logf(nil, "Recovering interrupted run from checkpoint")
+	for _, item := range []struct{ file, listing string }{{file1, listing1}, {file2, listing2}} {
+		if !bilib.FileExists(item.file) {
+			continue
+		}
+		var err error
+		if b.opt.DryRun {
+			err = bilib.CopyFile(item.file, item.listing)
+		} else {
+			err = os.Rename(item.file, item.listing)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	b.recovering = true
+	return nil
+}
+
+// sameFile checks whether file is the same on both paths, as will
+// happen if an interrupted run copied it before it could update the
+// checkpoint.
+func (b *bisyncRun) sameFile(ctx context.Context, ds1, ds2 *deltaSet, file string) bool {
+	info1, info2 := ds1.current.get(file), ds2.current.get(file)
+	if info1 == nil || info2 == nil || info1.size != info2.size {
+		return false
+	}
+	hashType := ds1.current.hash
+	if hashType != hash.None && hashType == ds2.current.hash && info1.hash != "" && info2.hash != "" {
+		return info1.hash == info2.hash
+	}
+	window := fs.GetModifyWindow(ctx, b.fs1, b.fs2)
+	if window == fs.ModTimeNotSupported {
+		return false
+	}
+	dt := info1.time.Sub(info2.time)
+	return dt >= -window && dt <= window
+}
@@ -40,12 +40,19 @@ type Options struct {
 	DryRun          bool
 	NoCleanup       bool
 	SaveQueues      bool // save extra debugging files (test only flag)
+	ConflictResolve ConflictResolveMode
+	ConflictLoser   ConflictLoserMode
+	ConflictSuffix  string
+	ConflictBackup  string        // remote path to move conflict losers to
+	MaxLock         time.Duration // consider lock files older than this stale, 0 to never expire
+	Compare         CompareMode   // file attributes compared to detect changes
 }
 
 // Default values
 const (
-	DefaultMaxDelete     int    = 50
-	DefaultCheckFilename string = "RCLONE_TEST"
+	DefaultMaxDelete      int    = 50
+	DefaultCheckFilename  string = "RCLONE_TEST"
+	DefaultConflictSuffix string = "..path{n}"
 )
 
 // DefaultWorkdir is default working directory
@@ -93,6 +100,146 @@ func (x *CheckSyncMode) Type() string {
 	return "string"
 }
 
+// CompareMode selects the file attributes compared to detect changes
+type CompareMode uint8
+
+// Compare modes which may be combined
+const (
+	CompareSize     CompareMode = 1 << iota // Compare file sizes
+	CompareModTime                          // Compare modification times
+	CompareChecksum                         // Compare hashes where the backend supports them
+)
+
+// DefaultCompare is the default compare mode
+const DefaultCompare = CompareModTime
+
+var compareNames = []string{"size", "modtime", "checksum"}
+
+// has returns true if any of the modes in mode are set
+func (x CompareMode) has(mode CompareMode) bool {
+	return x&mode != 0
+}
+
+func (x CompareMode) String() string {
+	var names []string
+	for i, name := range compareNames {
+		if x.has(1 << i) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return strings.Join(names, ",")
+}
+
+// Set a Compare mode from a comma separated list
+func (x *CompareMode) Set(s string) error {
+	var mode CompareMode
+	for _, item := range strings.Split(s, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		found := false
+		for i, name := range compareNames {
+			if item == name {
+				mode |= 1 << i
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown compare mode for bisync: %q", item)
+		}
+	}
+	*x = mode
+	return nil
+}
+
+// Type of the Compare value
+func (x *CompareMode) Type() string {
+	return "string"
+}
+
+// ConflictResolveMode controls how files changed on both paths are resolved
+type ConflictResolveMode int
+
+// ConflictResolve modes
+const (
+	ConflictResolveKeepBoth ConflictResolveMode = iota // Keep both copies renamed with the conflict suffix (default)
+	ConflictResolveNewer                               // The newer file wins
+	ConflictResolveOlder                               // The older file wins
+	ConflictResolveLarger                              // The larger file wins
+	ConflictResolveSmaller                             // The smaller file wins
+	ConflictResolvePath1                               // The Path1 file wins
+	ConflictResolvePath2                               // The Path2 file wins
+)
+
+var conflictResolveNames = []string{"keep-both", "newer", "older", "larger", "smaller", "path1", "path2"}
+
+func (x ConflictResolveMode) String() string {
+	if x >= 0 && int(x) < len(conflictResolveNames) {
+		return conflictResolveNames[x]
+	}
+	return "unknown"
+}
+
+// Set a ConflictResolve mode from a string
+func (x *ConflictResolveMode) Set(s string) error {
+	for i, name := range conflictResolveNames {
+		if strings.ToLower(s) == name {
+			*x = ConflictResolveMode(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown conflict-resolve mode for bisync: %q", s)
+}
+
+// Type of the ConflictResolve value
+func (x *ConflictResolveMode) Type() string {
+	return "string"
+}
+
+// ConflictLoserMode controls what happens to the losing file of a
+// conflict resolved by ConflictResolveMode
+type ConflictLoserMode int
+
+// ConflictLoser modes
+const (
+	ConflictLoserDelete ConflictLoserMode = iota // Overwrite the loser with the winner (default)
+	ConflictLoserRename                          // Rename the loser with the conflict suffix and keep it on both paths
+	ConflictLoserBackup                          // Move the loser to the conflict backup dir
+)
+
+func (x ConflictLoserMode) String() string {
+	switch x {
+	case ConflictLoserDelete:
+		return "delete"
+	case ConflictLoserRename:
+		return "rename"
+	case ConflictLoserBackup:
+		return "backup"
+	}
+	return "unknown"
+}
+
+// Set a ConflictLoser mode from a string
+func (x *ConflictLoserMode) Set(s string) error {
+	switch strings.ToLower(s) {
+	case "delete":
+		*x = ConflictLoserDelete
+	case "rename":
+		*x = ConflictLoserRename
+	case "backup":
+		*x = ConflictLoserBackup
+	default:
+		return fmt.Errorf("unknown conflict-loser mode for bisync: %q", s)
+	}
+	return nil
+}
+
+// Type of the ConflictLoser value
+func (x *ConflictLoserMode) Type() string {
+	return "string"
+}
+
 // Opt keeps command line options
 var Opt Options
 
@@ -109,6 +256,12 @@ func init() {
 	flags.StringVarP(cmdFlags, &Opt.Workdir, "workdir", "", Opt.Workdir, makeHelp("Use custom working dir - useful for testing. (default: {WORKDIR})"))
 	flags.BoolVarP(cmdFlags, &tzLocal, "localtime", "", tzLocal, "Use local time in listings (default: UTC)")
 	flags.BoolVarP(cmdFlags, &Opt.NoCleanup, "no-cleanup", "", Opt.NoCleanup, "Retain working files (useful for troubleshooting and testing).")
+	flags.FVarP(cmdFlags, &Opt.ConflictResolve, "conflict-resolve", "", "How to resolve files changed on both paths: keep-both|newer|older|larger|smaller|path1|path2 (default: keep-both)")
+	flags.FVarP(cmdFlags, &Opt.ConflictLoser, "conflict-loser", "", "What to do with the losing file of a resolved conflict: delete|rename|backup (default: delete)")
+	flags.StringVarP(cmdFlags, &Opt.ConflictSuffix, "conflict-suffix", "", Opt.ConflictSuffix, makeHelp("Suffix for renamed conflict files, {n} is replaced by the path number and {date} by the date (default: {CONFLICTSUFFIX})"))
+	flags.StringVarP(cmdFlags, &Opt.ConflictBackup, "conflict-backup-dir", "", Opt.ConflictBackup, "Remote path to move losing files to with --conflict-loser backup")
+	flags.FVarP(cmdFlags, &Opt.Compare, "compare", "", "Comma separated list of file attributes to compare to detect changes: size,modtime,checksum (default: modtime)")
+	flags.DurationVarP(cmdFlags, &Opt.MaxLock, "max-lock", "", Opt.MaxLock, "Consider lock files older than this, or left by a dead process, to be stale (default: never)")
 }
 
 // bisync command definition
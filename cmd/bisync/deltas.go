@@ -7,9 +7,13 @@ import (
 	"fmt"
 	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/rclone/rclone/cmd/bisync/bilib"
 	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
 	"github.com/rclone/rclone/fs/operations"
 )
 
@@ -28,7 +32,7 @@ const (
 
 const (
 	deltaModified delta = deltaNewer | deltaOlder | deltaSize | deltaHash | deltaDeleted
-	deltaOther    delta = deltaNew | deltaNewer | deltaOlder
+	deltaOther    delta = deltaNew | deltaNewer | deltaOlder | deltaSize | deltaHash
 )
 
 func (d delta) is(cond delta) bool {
@@ -45,6 +49,8 @@ type deltaSet struct {
 	deleted    int    // number of deleted files (for "excess deletes" check)
 	foundSame  bool   // true if found at least one unchanged file
 	checkFiles bilib.Names
+	current    *fileList // current listing, used to resolve conflicts
+	prior      *fileList // prior listing, used to start the checkpoint
 }
 
 func (ds *deltaSet) empty() bool {
@@ -119,8 +125,11 @@ func (b *bisyncRun) findDeltas(fctx context.Context, f fs.Fs, oldListing, newLis
 		oldCount:   len(old.list),
 		opt:        b.opt,
 		checkFiles: bilib.Names{},
+		current:    now,
+		prior:      old,
 	}
 
+	compare := b.compareMode(f, old, now, msg)
 	for _, file := range old.list {
 		d := deltaZero
 		if !now.has(file) {
@@ -128,7 +137,9 @@ func (b *bisyncRun) findDeltas(fctx context.Context, f fs.Fs, oldListing, newLis
 			ds.deleted++
 			d |= deltaDeleted
 		} else {
-			if old.getTime(file) != now.getTime(file) {
+			oldInfo, nowInfo := old.get(file), now.get(file)
+			switch {
+			case compare.has(CompareModTime) && !oldInfo.time.Equal(nowInfo.time):
 				if old.beforeOther(now, file) {
 					b.indent(msg, file, "File is newer")
 					d |= deltaNewer
@@ -136,8 +147,13 @@ func (b *bisyncRun) findDeltas(fctx context.Context, f fs.Fs, oldListing, newLis
 					b.indent(msg, file, "File is OLDER")
 					d |= deltaOlder
 				}
+			case compare.has(CompareSize) && oldInfo.size != nowInfo.size:
+				b.indent(msg, file, "File size differs")
+				d |= deltaSize
+			case compare.has(CompareChecksum) && oldInfo.hash != "" && nowInfo.hash != "" && oldInfo.hash != nowInfo.hash:
+				b.indent(msg, file, "File checksum differs")
+				d |= deltaHash
 			}
-			// TODO Compare sizes and hashes
 		}
 
 		if d.is(deltaModified) {
@@ -170,6 +186,28 @@ func (b *bisyncRun) findDeltas(fctx context.Context, f fs.Fs, oldListing, newLis
 	return
 }
 
+// compareMode works out which of the file attributes set by --compare
+// can be compared on f given its prior and current listings.
+//
+// If modification times aren't supported or hashes aren't available
+// then the file size is compared instead.
+func (b *bisyncRun) compareMode(f fs.Fs, old, now *fileList, msg string) CompareMode {
+	compare := b.opt.Compare
+	if compare.has(CompareModTime) && f.Precision() == fs.ModTimeNotSupported {
+		fs.Logf(nil, "%s: modification times not supported - comparing size instead", msg)
+		compare = compare&^CompareModTime | CompareSize
+	}
+	if compare.has(CompareChecksum) && (now.hash == hash.None || old.hash != now.hash) {
+		if now.hash == hash.None {
+			fs.Logf(nil, "%s: hashes not available - comparing size instead", msg)
+		} else {
+			fs.Logf(nil, "%s: hash type changed from the prior listing - comparing size instead", msg)
+		}
+		compare = compare&^CompareChecksum | CompareSize
+	}
+	return compare
+}
+
 // applyDeltas
 func (b *bisyncRun) applyDeltas(ctx context.Context, ds1, ds2 *deltaSet) (changes1, changes2 bool, err error) {
 	path1 := bilib.FsPath(b.fs1)
@@ -198,23 +236,42 @@ func (b *bisyncRun) applyDeltas(ctx context.Context, ds1, ds2 *deltaSet) (change
 				copy1to2.Add(file)
 				handled.Add(file)
 			} else if d2.is(deltaOther) {
+				if b.recovering && b.sameFile(ctx, ds1, ds2, file) {
+					b.indent("INFO", file, "Already the same on both paths")
+					handled.Add(file)
+					continue
+				}
 				b.indent("!WARNING", file, "New or changed in both paths")
-				b.indent("!Path1", p1+"..path1", "Renaming Path1 copy")
-				if err = operations.MoveFile(ctxMove, b.fs1, b.fs1, file+"..path1", file); err != nil {
+				winner, reason := b.conflictWinner(ctx, ds1, ds2, file)
+				if winner != 0 {
+					if err = b.resolveConflict(ctxMove, file, winner, reason, copy1to2, copy2to1); err != nil {
+						b.critical = true
+						return
+					}
+					handled.Add(file)
+					continue
+				}
+				if b.opt.ConflictResolve != ConflictResolveKeepBoth {
+					b.indent("!WARNING", file, "Can't resolve - keeping both")
+				}
+				suffix1 := b.conflictSuffix(1)
+				b.indent("!Path1", p1+suffix1, "Renaming Path1 copy")
+				if err = operations.MoveFile(ctxMove, b.fs1, b.fs1, file+suffix1, file); err != nil {
 					err = fmt.Errorf("path1 rename failed for %s: %w", p1, err)
 					b.critical = true
 					return
 				}
-				b.indent("!Path1", p2+"..path1", "Queue copy to Path2")
-				copy1to2.Add(file + "..path1")
+				b.indent("!Path1", p2+suffix1, "Queue copy to Path2")
+				copy1to2.Add(file + suffix1)
 
-				b.indent("!Path2", p2+"..path2", "Renaming Path2 copy")
-				if err = operations.MoveFile(ctxMove, b.fs2, b.fs2, file+"..path2", file); err != nil {
+				suffix2 := b.conflictSuffix(2)
+				b.indent("!Path2", p2+suffix2, "Renaming Path2 copy")
+				if err = operations.MoveFile(ctxMove, b.fs2, b.fs2, file+suffix2, file); err != nil {
 					err = fmt.Errorf("path2 rename failed for %s: %w", file, err)
 					return
 				}
-				b.indent("!Path2", p1+"..path2", "Queue copy to Path1")
-				copy2to1.Add(file + "..path2")
+				b.indent("!Path2", p1+suffix2, "Queue copy to Path1")
+				copy2to1.Add(file + suffix2)
 				handled.Add(file)
 			}
 		} else {
@@ -255,6 +312,9 @@ func (b *bisyncRun) applyDeltas(ctx context.Context, ds1, ds2 *deltaSet) (change
 		changes1 = true
 		b.indent("Path2", "Path1", "Do queued copies to")
 		err = b.fastCopy(ctx, b.fs2, b.fs1, copy2to1, "copy2to1")
+		if err == nil {
+			err = b.chk.copied(copy2to1, ds2.current)
+		}
 		if err != nil {
 			return
 		}
@@ -264,6 +324,9 @@ func (b *bisyncRun) applyDeltas(ctx context.Context, ds1, ds2 *deltaSet) (change
 		changes2 = true
 		b.indent("Path1", "Path2", "Do queued copies to")
 		err = b.fastCopy(ctx, b.fs1, b.fs2, copy1to2, "copy1to2")
+		if err == nil {
+			err = b.chk.copied(copy1to2, ds1.current)
+		}
 		if err != nil {
 			return
 		}
@@ -273,6 +336,9 @@ func (b *bisyncRun) applyDeltas(ctx context.Context, ds1, ds2 *deltaSet) (change
 		changes1 = true
 		b.indent("", "Path1", "Do queued deletes on")
 		err = b.fastDelete(ctx, b.fs1, delete1, "delete1")
+		if err == nil {
+			err = b.chk.deleted(delete1)
+		}
 		if err != nil {
 			return
 		}
@@ -282,6 +348,9 @@ func (b *bisyncRun) applyDeltas(ctx context.Context, ds1, ds2 *deltaSet) (change
 		changes2 = true
 		b.indent("", "Path2", "Do queued deletes on")
 		err = b.fastDelete(ctx, b.fs2, delete2, "delete2")
+		if err == nil {
+			err = b.chk.deleted(delete2)
+		}
 		if err != nil {
 			return
 		}
@@ -290,6 +359,109 @@ func (b *bisyncRun) applyDeltas(ctx context.Context, ds1, ds2 *deltaSet) (change
 	return
 }
 
+// conflict records a file changed on both paths which was resolved
+// by the conflict resolution policy
+type conflict struct {
+	file   string
+	winner int    // number of the winning path
+	reason string // why it won
+}
+
+// conflictSuffix returns the suffix for renaming the conflicting
+// file on path n
+func (b *bisyncRun) conflictSuffix(n int) string {
+	return strings.NewReplacer(
+		"{n}", strconv.Itoa(n),
+		"{date}", time.Now().In(TZ).Format("2006-01-02-150405"),
+	).Replace(b.opt.ConflictSuffix)
+}
+
+// conflictWinner decides which path wins the conflict on file
+// according to the conflict resolution policy.
+//
+// It returns the number of the winning path and why it won, or 0 if
+// the conflict can't be resolved so both copies should be kept.
+func (b *bisyncRun) conflictWinner(ctx context.Context, ds1, ds2 *deltaSet, file string) (winner int, reason string) {
+	info1, info2 := ds1.current.get(file), ds2.current.get(file)
+	if info1 == nil || info2 == nil {
+		return 0, ""
+	}
+	pick := func(path1Wins bool, reason string) (int, string) {
+		if path1Wins {
+			return 1, reason
+		}
+		return 2, reason
+	}
+	switch b.opt.ConflictResolve {
+	case ConflictResolvePath1:
+		return 1, "path1 preferred"
+	case ConflictResolvePath2:
+		return 2, "path2 preferred"
+	case ConflictResolveNewer, ConflictResolveOlder:
+		dt := info1.time.Sub(info2.time)
+		window := fs.GetModifyWindow(ctx, b.fs1, b.fs2)
+		if window == fs.ModTimeNotSupported || (dt >= -window && dt <= window) {
+			return 0, ""
+		}
+		if b.opt.ConflictResolve == ConflictResolveNewer {
+			return pick(dt > 0, "newer")
+		}
+		return pick(dt < 0, "older")
+	case ConflictResolveLarger, ConflictResolveSmaller:
+		if info1.size == info2.size || info1.size < 0 || info2.size < 0 {
+			return 0, ""
+		}
+		if b.opt.ConflictResolve == ConflictResolveLarger {
+			return pick(info1.size > info2.size, "larger")
+		}
+		return pick(info1.size < info2.size, "smaller")
+	}
+	return 0, ""
+}
+
+// resolveConflict resolves the conflict on file in favour of the
+// winning path, dealing with the loser as set by --conflict-loser.
+func (b *bisyncRun) resolveConflict(ctx context.Context, file string, winner int, reason string, copy1to2, copy2to1 bilib.Names) error {
+	loser := 3 - winner
+	fsWin, fsLose := b.fs1, b.fs2
+	copyToLoser, copyToWinner := copy1to2, copy2to1
+	if winner == 2 {
+		fsWin, fsLose = fsLose, fsWin
+		copyToLoser, copyToWinner = copyToWinner, copyToLoser
+	}
+	winTag := "!Path" + strconv.Itoa(winner)
+	loseTag := "!Path" + strconv.Itoa(loser)
+	pWin := bilib.FsPath(fsWin) + file
+	pLose := bilib.FsPath(fsLose) + file
+	b.indentf(winTag, pWin, "Conflict won by Path%d (%s)", winner, reason)
+
+	switch b.opt.ConflictLoser {
+	case ConflictLoserRename:
+		suffix := b.conflictSuffix(loser)
+		loserFile := file + suffix
+		b.indent(loseTag, pLose+suffix, "Renaming losing copy")
+		if err := operations.MoveFile(ctx, fsLose, fsLose, loserFile, file); err != nil {
+			return fmt.Errorf("path%d rename failed for %s: %w", loser, pLose, err)
+		}
+		b.indent(loseTag, bilib.FsPath(fsWin)+loserFile, "Queue copy to Path"+strconv.Itoa(winner))
+		copyToWinner.Add(loserFile)
+	case ConflictLoserBackup:
+		b.indent(loseTag, bilib.FsPath(b.backupFs)+file, "Moving losing copy to backup dir")
+		if err := operations.MoveFile(ctx, b.backupFs, fsLose, file, file); err != nil {
+			return fmt.Errorf("path%d backup failed for %s: %w", loser, pLose, err)
+		}
+	}
+
+	b.indent(winTag, pLose, "Queue copy to Path"+strconv.Itoa(loser))
+	copyToLoser.Add(file)
+	b.conflicts = append(b.conflicts, conflict{
+		file:   file,
+		winner: winner,
+		reason: reason,
+	})
+	return nil
+}
+
 // exccessDeletes checks whether number of deletes is within allowed range
 func (ds *deltaSet) excessDeletes() bool {
 	maxDelete := ds.opt.MaxDelete
@@ -11,6 +11,7 @@ func makeHelp(help string) string {
 		"{MAXDELETE}", strconv.Itoa(DefaultMaxDelete),
 		"{CHECKFILE}", DefaultCheckFilename,
 		"{WORKDIR}", DefaultWorkdir,
+		"{CONFLICTSUFFIX}", DefaultConflictSuffix,
 	)
 	return replacer.Replace(help)
 }
@@ -34,6 +35,19 @@ var rcHelp = makeHelp(`This takes the following parameters
 - filtersFile - read filtering patterns from a file
 - workdir - server directory for history files (default: {WORKDIR})
 - noCleanup - retain working files
+- conflictResolve - how to resolve files changed on both paths:
+                    |keep-both| (default), |newer|, |older|, |larger|,
+                    |smaller|, |path1| or |path2|
+- conflictLoser - what to do with the losing file of a resolved conflict:
+                  |delete| (default), |rename| or |backup|
+- conflictSuffix - suffix for renamed conflict files (default: {CONFLICTSUFFIX})
+- conflictBackupDir - remote path to move losing files to with
+                      conflictLoser |backup|
+- compare - comma separated list of file attributes to compare to
+            detect changes, any of |size|, |modtime| and |checksum|
+            (default: |modtime|)
+- maxLock - consider lock files older than this, or left by a dead
+            process, to be stale (default: never)
 
 See [bisync command help](https://rclone.org/commands/rclone_bisync/)
 and [full bisync description](https://rclone.org/bisync/)
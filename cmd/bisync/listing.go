@@ -14,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/rclone/rclone/cmd/bisync/bilib"
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/hash"
 	"github.com/rclone/rclone/fs/walk"
@@ -81,6 +82,8 @@ func (ls *fileList) put(file string, size int64, time time.Time, hash, id string
 	if fi != nil {
 		fi.size = size
 		fi.time = time
+		fi.hash = hash
+		fi.id = id
 	} else {
 		fi = &fileInfo{
 			size: size,
@@ -93,6 +96,19 @@ func (ls *fileList) put(file string, size int64, time time.Time, hash, id string
 	}
 }
 
+// remove files from the listing
+func (ls *fileList) remove(files bilib.Names) {
+	list := ls.list[:0]
+	for _, file := range ls.list {
+		if files.Has(file) {
+			delete(ls.info, file)
+		} else {
+			list = append(list, file)
+		}
+	}
+	ls.list = list
+}
+
 func (ls *fileList) getTime(file string) time.Time {
 	fi := ls.get(file)
 	if fi == nil {
@@ -255,7 +271,6 @@ func (b *bisyncRun) makeListing(ctx context.Context, f fs.Fs, listing string) (l
 	hashType := hash.None
 	if !ci.IgnoreChecksum {
 		// Currently bisync just honors --ignore-checksum
-		// TODO add full support for checksums and related flags
 		hashType = f.Hashes().GetOne()
 	}
 	ls = newFileList()
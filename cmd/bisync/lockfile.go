@@ -0,0 +1,96 @@
+package bisync
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rclone/rclone/cmd/bisync/bilib"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/lib/process"
+)
+
+// lockStale checks whether the prior lock file can be removed.
+//
+// A lock file is stale if --max-lock is set and either the lock file
+// is older than --max-lock or the process which wrote it is no longer
+// running. Without --max-lock lock files never expire.
+func (b *bisyncRun) lockStale(lockFile string) (stale bool, why string) {
+	if b.opt.MaxLock <= 0 {
+		return false, ""
+	}
+	info, err := os.Stat(lockFile)
+	if err != nil {
+		return false, ""
+	}
+	if age := time.Since(info.ModTime()); age > b.opt.MaxLock {
+		return true, fmt.Sprintf("older than --max-lock %v", b.opt.MaxLock)
+	}
+	buf, err := ioutil.ReadFile(lockFile)
+	if err != nil {
+		return false, ""
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(buf)))
+	if err != nil || pid <= 0 || pid == os.Getpid() {
+		return false, ""
+	}
+	if !process.Exists(pid) {
+		return true, fmt.Sprintf("process %d is not running", pid)
+	}
+	return false, ""
+}
+
+// createLock creates the lock file, replacing a stale one
+func (b *bisyncRun) createLock(lockFile string) error {
+	if bilib.FileExists(lockFile) {
+		stale, why := b.lockStale(lockFile)
+		if !stale {
+			return fmt.Errorf("prior lock file found: %s", lockFile)
+		}
+		fs.Logf(nil, "Removing stale lock file (%s): %s", why, lockFile)
+		if err := os.Remove(lockFile); err != nil {
+			return fmt.Errorf("cannot remove stale lock file: %s: %w", lockFile, err)
+		}
+	}
+
+	pidStr := []byte(strconv.Itoa(os.Getpid()))
+	if err := ioutil.WriteFile(lockFile, pidStr, bilib.PermSecure); err != nil {
+		return fmt.Errorf("cannot create lock file: %s: %w", lockFile, err)
+	}
+	fs.Debugf(nil, "Lock file created: %s", lockFile)
+	return nil
+}
+
+// refreshLock keeps touching the lock file while the run is in
+// progress so a long run doesn't have its lock expired by --max-lock.
+//
+// Call the returned function to stop refreshing.
+func (b *bisyncRun) refreshLock(lockFile string) (stop func()) {
+	if b.opt.MaxLock <= 0 {
+		return func() {}
+	}
+	interval := b.opt.MaxLock / 3
+	if interval < time.Second {
+		interval = time.Second
+	}
+	done := make(chan struct{})
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				now := time.Now()
+				if err := os.Chtimes(lockFile, now, now); err != nil {
+					fs.Errorf(nil, "Failed to refresh lock file %s: %v", lockFile, err)
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
@@ -7,10 +7,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
-	"strconv"
+	"strings"
 	gosync "sync"
 
 	"github.com/rclone/rclone/cmd/bisync/bilib"
@@ -26,13 +25,17 @@ var ErrBisyncAborted = errors.New("bisync aborted")
 
 // bisyncRun keeps bisync runtime state
 type bisyncRun struct {
-	fs1      fs.Fs
-	fs2      fs.Fs
-	abort    bool
-	critical bool
-	basePath string
-	workDir  string
-	opt      *Options
+	fs1        fs.Fs
+	fs2        fs.Fs
+	abort      bool
+	critical   bool
+	basePath   string
+	workDir    string
+	opt        *Options
+	backupFs   fs.Fs       // where to move conflict losers to
+	conflicts  []conflict  // conflicts resolved by policy
+	chk        *checkpoint // checkpoint of changes applied so far
+	recovering bool        // set if recovering an interrupted run
 }
 
 // Bisync handles lock file, performs bisync run and checks exit status
@@ -50,8 +53,30 @@ func Bisync(ctx context.Context, fs1, fs2 fs.Fs, optArg *Options) (err error) {
 	if opt.Workdir == "" {
 		opt.Workdir = DefaultWorkdir
 	}
+	if opt.Compare == 0 {
+		opt.Compare = DefaultCompare
+	}
+	if opt.ConflictSuffix == "" {
+		opt.ConflictSuffix = DefaultConflictSuffix
+	}
+	if !strings.Contains(opt.ConflictSuffix, "{n}") {
+		return errors.New("conflict suffix must contain {n} to tell the Path1 and Path2 copies apart")
+	}
+	if opt.ConflictLoser == ConflictLoserBackup {
+		if opt.ConflictBackup == "" {
+			return errors.New("conflict loser mode backup needs a conflict backup dir")
+		}
+		if b.backupFs, err = fs.NewFs(ctx, opt.ConflictBackup); err != nil {
+			return fmt.Errorf("failed to make conflict backup dir: %w", err)
+		}
+		if operations.OverlappingFilterCheck(ctx, b.backupFs, fs1) || operations.OverlappingFilterCheck(ctx, b.backupFs, fs2) {
+			return errors.New("conflict backup dir can't overlap Path1 or Path2")
+		}
+	}
 
-	if !opt.DryRun && !opt.Force {
+	// Without modification times there is nothing to compare unless
+	// another compare mode is set
+	if !opt.DryRun && !opt.Force && opt.Compare == CompareModTime {
 		if fs1.Precision() == fs.ModTimeNotSupported {
 			return errors.New("modification time support is missing on path1")
 		}
@@ -76,32 +101,28 @@ func Bisync(ctx context.Context, fs1, fs2 fs.Fs, optArg *Options) (err error) {
 	lockFile := ""
 	if !opt.DryRun {
 		lockFile = b.basePath + ".lck"
-		if bilib.FileExists(lockFile) {
-			return fmt.Errorf("prior lock file found: %s", lockFile)
-		}
-
-		pidStr := []byte(strconv.Itoa(os.Getpid()))
-		if err = ioutil.WriteFile(lockFile, pidStr, bilib.PermSecure); err != nil {
-			return fmt.Errorf("cannot create lock file: %s: %w", lockFile, err)
+		if err = b.createLock(lockFile); err != nil {
+			return err
 		}
-		fs.Debugf(nil, "Lock file created: %s", lockFile)
+		stopRefresh := b.refreshLock(lockFile)
+		defer stopRefresh()
 	}
 
 	// Handle SIGINT
+	//
+	// The listings are left alone as they are only replaced once
+	// all changes are applied. If any changes were applied the next
+	// run will recover from the checkpoint.
 	var finaliseOnce gosync.Once
-	markFailed := func(file string) {
-		failFile := file + "-err"
-		if bilib.FileExists(file) {
-			_ = os.Remove(failFile)
-			_ = os.Rename(file, failFile)
-		}
-	}
 	finalise := func() {
 		finaliseOnce.Do(func() {
 			if atexit.Signalled() {
-				fs.Logf(nil, "Bisync interrupted. Must run --resync to recover.")
-				markFailed(listing1)
-				markFailed(listing2)
+				chk1, _ := b.checkpointFiles()
+				if !opt.DryRun && bilib.FileExists(chk1) {
+					fs.Logf(nil, "Bisync interrupted. The next run will recover from the checkpoint.")
+				} else {
+					fs.Logf(nil, "Bisync interrupted.")
+				}
 				_ = os.Remove(lockFile)
 			}
 		})
@@ -124,6 +145,9 @@ func Bisync(ctx context.Context, fs1, fs2 fs.Fs, optArg *Options) (err error) {
 	}
 
 	if b.critical {
+		if !opt.DryRun {
+			b.removeCheckpoint()
+		}
 		if bilib.FileExists(listing1) {
 			_ = os.Rename(listing1, listing1+"-err")
 		}
@@ -138,11 +162,24 @@ func Bisync(ctx context.Context, fs1, fs2 fs.Fs, optArg *Options) (err error) {
 		fs.Logf(nil, "Bisync aborted. Please try again.")
 	}
 	if err == nil {
+		b.logConflicts()
 		fs.Infof(nil, "Bisync successful")
 	}
 	return err
 }
 
+// logConflicts logs a summary of the conflicts resolved by policy
+func (b *bisyncRun) logConflicts() {
+	if len(b.conflicts) == 0 {
+		return
+	}
+	fs.Infof(nil, "Resolved %d conflicts with --conflict-resolve %s --conflict-loser %s",
+		len(b.conflicts), b.opt.ConflictResolve, b.opt.ConflictLoser)
+	for _, c := range b.conflicts {
+		b.indentf("Conflict", c.file, "Path%d won (%s)", c.winner, c.reason)
+	}
+}
+
 // runLocked performs a full bisync run
 func (b *bisyncRun) runLocked(octx context.Context, listing1, listing2 string) (err error) {
 	opt := b.opt
@@ -180,6 +217,12 @@ func (b *bisyncRun) runLocked(octx context.Context, listing1, listing2 string) (
 		return
 	}
 
+	// Pick up where an interrupted run left off
+	if err = b.recoverCheckpoint(listing1, listing2); err != nil {
+		b.critical = true
+		return err
+	}
+
 	// Generate Path1 and Path2 listings and copy any unique Path2 files to Path1
 	if opt.Resync {
 		return b.resync(octx, fctx, listing1, listing2)
@@ -253,6 +296,10 @@ func (b *bisyncRun) runLocked(octx context.Context, listing1, listing2 string) (
 		fs.Infof(nil, "No changes found")
 	} else {
 		fs.Infof(nil, "Applying changes")
+		if err = b.startCheckpoint(octx, ds1, ds2); err != nil {
+			b.critical = true
+			return err
+		}
 		changes1, changes2, err = b.applyDeltas(octx, ds1, ds2)
 		if err != nil {
 			b.critical = true
@@ -286,6 +333,9 @@ func (b *bisyncRun) runLocked(octx context.Context, listing1, listing2 string) (
 		b.critical = true
 		return err
 	}
+	if b.chk != nil {
+		b.removeCheckpoint()
+	}
 
 	if !opt.NoCleanup {
 		_ = os.Remove(newListing1)
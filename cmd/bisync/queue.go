@@ -16,7 +16,15 @@ func (b *bisyncRun) fastCopy(ctx context.Context, fsrc, fdst fs.Fs, files bilib.
 		return err
 	}
 
-	ctxCopy, filterCopy := filter.AddConfig(b.opt.setDryRun(ctx))
+	ctxRun := b.opt.setDryRun(ctx)
+	if b.opt.Compare != CompareModTime || fsrc.Precision() == fs.ModTimeNotSupported || fdst.Precision() == fs.ModTimeNotSupported {
+		// The files have already been found to differ so make sure
+		// the copy doesn't skip those with matching size and time
+		var ci *fs.ConfigInfo
+		ctxRun, ci = fs.AddConfig(ctxRun)
+		ci.IgnoreTimes = true
+	}
+	ctxCopy, filterCopy := filter.AddConfig(ctxRun)
 	for _, file := range files.ToList() {
 		if err := filterCopy.AddFile(file); err != nil {
 			return err
@@ -65,6 +65,38 @@ func rcBisync(ctx context.Context, in rc.Params) (out rc.Params, err error) {
 		return
 	}
 
+	if opt.ConflictSuffix, err = in.GetString("conflictSuffix"); rc.NotErrParamNotFound(err) {
+		return
+	}
+	if opt.ConflictBackup, err = in.GetString("conflictBackupDir"); rc.NotErrParamNotFound(err) {
+		return
+	}
+	if conflictResolve, err := in.GetString("conflictResolve"); err == nil {
+		if err := opt.ConflictResolve.Set(conflictResolve); err != nil {
+			return nil, rc.NewErrParamInvalid(err)
+		}
+	} else if rc.NotErrParamNotFound(err) {
+		return nil, err
+	}
+	if conflictLoser, err := in.GetString("conflictLoser"); err == nil {
+		if err := opt.ConflictLoser.Set(conflictLoser); err != nil {
+			return nil, rc.NewErrParamInvalid(err)
+		}
+	} else if rc.NotErrParamNotFound(err) {
+		return nil, err
+	}
+
+	if compare, err := in.GetString("compare"); err == nil {
+		if err := opt.Compare.Set(compare); err != nil {
+			return nil, rc.NewErrParamInvalid(err)
+		}
+	} else if rc.NotErrParamNotFound(err) {
+		return nil, err
+	}
+	if opt.MaxLock, err = in.GetDuration("maxLock"); rc.NotErrParamNotFound(err) {
+		return
+	}
+
 	checkSync, err := in.GetString("checkSync")
 	if rc.NotErrParamNotFound(err) {
 		return nil, err
@@ -8,6 +8,7 @@ import (
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/config/flags"
 	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/lib/http/share"
 	"github.com/spf13/cobra"
 )
 
@@ -21,6 +22,7 @@ func init() {
 	cmdFlags := commandDefinition.Flags()
 	flags.FVarP(cmdFlags, &expire, "expire", "", "The amount of time that the link will be valid")
 	flags.BoolVarP(cmdFlags, &unlink, "unlink", "", unlink, "Remove existing public link to file/folder")
+	share.AddFlags(cmdFlags)
 }
 
 var commandDefinition = &cobra.Command{
@@ -47,6 +49,20 @@ If successful, the last line of the output will contain the
 link. Exact capabilities depend on the remote, but the link will
 always by default be created with the least constraints – e.g. no
 expiry, no password protection, accessible without account.
+
+If the backend doesn't support public links, for example local, sftp,
+ftp or crypt, then rclone can make a share link served by an rclone
+` + "[serve http](/commands/rclone_serve_http/)" + ` or ` + "[rc](/rc/#rc-share-secret)" + `
+server instead. Set ` + "`--share-url`" + ` to the URL of the server and
+` + "`--share-secret`" + ` to the secret it was started with (or use the
+` + "`RCLONE_SHARE_URL` and `RCLONE_SHARE_SECRET`" + ` environment variables).
+
+    rclone link --share-url https://example.com/ --share-secret XXX --expire 1d remote:path/to/file
+
+The link is signed with the secret and is checked by the server
+without storing anything so it can't be removed with ` + "`--unlink`" + `.
+Use ` + "`--share-max-downloads`" + ` to limit the number of times it can be
+downloaded and ` + "`--share-password`" + ` to make it need a password.
 `,
 	Run: func(command *cobra.Command, args []string) {
 		cmd.CheckArgs(1, 1, command, args)
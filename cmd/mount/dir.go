@@ -129,6 +129,8 @@ func (d *Dir) ReadDirAll(ctx context.Context) (dirents []fuse.Dirent, err error)
 		}
 		if node.IsDir() {
 			dirent.Type = fuse.DT_Dir
+		} else if node.Mode()&os.ModeSymlink != 0 {
+			dirent.Type = fuse.DT_Link
 		}
 		dirents = append(dirents, dirent)
 	}
@@ -217,6 +219,22 @@ func (d *Dir) Rename(ctx context.Context, req *fuse.RenameRequest, newDir fusefs
 	return nil
 }
 
+// Check interface satisfied
+var _ fusefs.NodeSymlinker = (*Dir)(nil)
+
+// Symlink creates a new symbolic link in the receiver, which must
+// be a directory.
+func (d *Dir) Symlink(ctx context.Context, req *fuse.SymlinkRequest) (node fusefs.Node, err error) {
+	defer log.Trace(d, "newName=%q, target=%q", req.NewName, req.Target)("node=%v, err=%v", &node, &err)
+	file, err := d.Dir.Symlink(req.Target, req.NewName)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	node = &File{file, d.fsys}
+	file.SetSys(node) // cache the FUSE node for later
+	return node, nil
+}
+
 // Check interface satisfied
 var _ fusefs.NodeFsyncer = (*Dir)(nil)
 
@@ -10,6 +10,7 @@ import (
 
 	"bazil.org/fuse"
 	fusefs "bazil.org/fuse/fs"
+	"github.com/rclone/rclone/cmd/mountlib"
 	"github.com/rclone/rclone/fs/log"
 	"github.com/rclone/rclone/vfs"
 )
@@ -33,6 +34,9 @@ func (f *File) Attr(ctx context.Context, a *fuse.Attr) (err error) {
 	a.Gid = f.VFS().Opt.GID
 	a.Uid = f.VFS().Opt.UID
 	a.Mode = f.VFS().Opt.FilePerms
+	if f.File.IsSymlink() {
+		a.Mode = f.File.Mode()
+	}
 	a.Size = Size
 	a.Atime = modTime
 	a.Mtime = modTime
@@ -41,6 +45,19 @@ func (f *File) Attr(ctx context.Context, a *fuse.Attr) (err error) {
 	return nil
 }
 
+// Check interface satisfied
+var _ fusefs.NodeReadlinker = (*File)(nil)
+
+// Readlink reads the target of a symlink
+func (f *File) Readlink(ctx context.Context, req *fuse.ReadlinkRequest) (target string, err error) {
+	defer log.Trace(f, "")("target=%q, err=%v", &target, &err)
+	target, err = f.File.Readlink()
+	if err != nil {
+		return "", translateError(err)
+	}
+	return target, nil
+}
+
 // Check interface satisfied
 var _ fusefs.NodeSetattrer = (*File)(nil)
 
@@ -97,23 +114,45 @@ func (f *File) Fsync(ctx context.Context, req *fuse.FsyncRequest) (err error) {
 // node.
 //
 // If there is no xattr by that name, returns fuse.ErrNoXattr.
-func (f *File) Getxattr(ctx context.Context, req *fuse.GetxattrRequest, resp *fuse.GetxattrResponse) error {
-	return syscall.ENOSYS // we never implement this
+func (f *File) Getxattr(ctx context.Context, req *fuse.GetxattrRequest, resp *fuse.GetxattrResponse) (err error) {
+	if !f.fsys.opt.Xattr {
+		return syscall.ENOSYS
+	}
+	defer log.Trace(f, "name=%q", req.Name)("value=%q, err=%v", &resp.Xattr, &err)
+	value, err := mountlib.Getxattr(f.File, req.Name)
+	if err != nil {
+		return translateError(err)
+	}
+	resp.Xattr = value
+	return nil
 }
 
 var _ fusefs.NodeGetxattrer = (*File)(nil)
 
 // Listxattr lists the extended attributes recorded for the node.
-func (f *File) Listxattr(ctx context.Context, req *fuse.ListxattrRequest, resp *fuse.ListxattrResponse) error {
-	return syscall.ENOSYS // we never implement this
+func (f *File) Listxattr(ctx context.Context, req *fuse.ListxattrRequest, resp *fuse.ListxattrResponse) (err error) {
+	if !f.fsys.opt.Xattr {
+		return syscall.ENOSYS
+	}
+	defer log.Trace(f, "")("err=%v", &err)
+	names, err := mountlib.Listxattr(f.File)
+	if err != nil {
+		return translateError(err)
+	}
+	resp.Append(names...)
+	return nil
 }
 
 var _ fusefs.NodeListxattrer = (*File)(nil)
 
 // Setxattr sets an extended attribute with the given name and
 // value for the node.
-func (f *File) Setxattr(ctx context.Context, req *fuse.SetxattrRequest) error {
-	return syscall.ENOSYS // we never implement this
+func (f *File) Setxattr(ctx context.Context, req *fuse.SetxattrRequest) (err error) {
+	if !f.fsys.opt.Xattr {
+		return syscall.ENOSYS
+	}
+	defer log.Trace(f, "name=%q, value=%q", req.Name, req.Xattr)("err=%v", &err)
+	return translateError(mountlib.Setxattr(f.File, req.Name, req.Xattr))
 }
 
 var _ fusefs.NodeSetxattrer = (*File)(nil)
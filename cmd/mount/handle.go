@@ -6,6 +6,7 @@ package mount
 import (
 	"context"
 	"io"
+	"syscall"
 
 	"bazil.org/fuse"
 	fusefs "bazil.org/fuse/fs"
@@ -18,6 +19,11 @@ type FileHandle struct {
 	vfs.Handle
 }
 
+// file returns the File the handle is open on
+func (fh *FileHandle) file() *vfs.File {
+	return fh.Handle.Node().(*vfs.File)
+}
+
 // Check interface satisfied
 var _ fusefs.HandleReader = (*FileHandle)(nil)
 
@@ -70,6 +76,11 @@ var _ fusefs.HandleFlusher = (*FileHandle)(nil)
 // some writes, or that if will be called at all.
 func (fh *FileHandle) Flush(ctx context.Context, req *fuse.FlushRequest) (err error) {
 	defer log.Trace(fh, "")("err=%v", &err)
+	// Closing any file descriptor releases the POSIX locks of the owner
+	err = fh.file().ReleaseLocks(uint64(req.LockOwner), false)
+	if err != nil {
+		return translateError(err)
+	}
 	return translateError(fh.Handle.Flush())
 }
 
@@ -81,5 +92,88 @@ var _ fusefs.HandleReleaser = (*FileHandle)(nil)
 // the kernel
 func (fh *FileHandle) Release(ctx context.Context, req *fuse.ReleaseRequest) (err error) {
 	defer log.Trace(fh, "")("err=%v", &err)
+	if req.ReleaseFlags&fuse.ReleaseFlockUnlock != 0 {
+		_ = fh.file().ReleaseLocks(uint64(req.LockOwner), true)
+	}
 	return translateError(fh.Handle.Release())
 }
+
+// toLock converts a FUSE lock request into a vfs.Lock
+func toLock(owner fuse.LockOwner, lk fuse.FileLock, flags fuse.LockFlags) vfs.Lock {
+	lock := vfs.Lock{
+		Owner: uint64(owner),
+		PID:   uint32(lk.PID),
+		Start: lk.Start,
+		End:   lk.End,
+		Flock: flags&fuse.LockFlock != 0,
+	}
+	switch lk.Type {
+	case fuse.LockRead:
+		lock.Type = vfs.LockRead
+	case fuse.LockWrite:
+		lock.Type = vfs.LockWrite
+	default:
+		lock.Type = vfs.LockUnlock
+	}
+	return lock
+}
+
+// setLock sets the lock described by req on the file
+func (fh *FileHandle) setLock(ctx context.Context, req *fuse.LockRequest, wait bool) error {
+	lock := toLock(req.LockOwner, req.Lock, req.LockFlags)
+	return translateError(fh.file().SetLock(ctx, lock, wait))
+}
+
+// Check interface satisfied
+var (
+	_ fusefs.HandleLocker      = (*FileHandle)(nil)
+	_ fusefs.HandleFlockLocker = (*FileHandle)(nil)
+	_ fusefs.HandlePOSIXLocker = (*FileHandle)(nil)
+)
+
+// Lock tries to acquire a lock on a byte range of the node. If a
+// conflicting lock is already held, returns syscall.EAGAIN.
+func (fh *FileHandle) Lock(ctx context.Context, req *fuse.LockRequest) (err error) {
+	defer log.Trace(fh, "req=%v", req)("err=%v", &err)
+	return fh.setLock(ctx, req, false)
+}
+
+// LockWait acquires a lock on a byte range of the node, waiting
+// until the lock can be obtained (or context is canceled).
+func (fh *FileHandle) LockWait(ctx context.Context, req *fuse.LockWaitRequest) (err error) {
+	defer log.Trace(fh, "req=%v", req)("err=%v", &err)
+	err = fh.setLock(ctx, (*fuse.LockRequest)(req), true)
+	if err == context.Canceled {
+		err = syscall.EINTR
+	}
+	return err
+}
+
+// Unlock releases the lock on a byte range of the node.
+func (fh *FileHandle) Unlock(ctx context.Context, req *fuse.UnlockRequest) (err error) {
+	defer log.Trace(fh, "req=%v", req)("err=%v", &err)
+	return fh.setLock(ctx, (*fuse.LockRequest)(req), false)
+}
+
+// QueryLock returns the current state of locks held for the byte
+// range of the node.
+func (fh *FileHandle) QueryLock(ctx context.Context, req *fuse.QueryLockRequest, resp *fuse.QueryLockResponse) (err error) {
+	defer log.Trace(fh, "req=%v", req)("err=%v", &err)
+	lock, err := fh.file().GetLock(toLock(req.LockOwner, req.Lock, req.LockFlags))
+	if err != nil {
+		return translateError(err)
+	}
+	if lock.Type == vfs.LockUnlock {
+		return nil
+	}
+	resp.Lock = fuse.FileLock{
+		Start: lock.Start,
+		End:   lock.End,
+		Type:  fuse.LockRead,
+		PID:   int32(lock.PID),
+	}
+	if lock.Type == vfs.LockWrite {
+		resp.Lock.Type = fuse.LockWrite
+	}
+	return nil
+}
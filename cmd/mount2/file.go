@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sync"
 	"syscall"
 
 	fusefs "github.com/hanwen/go-fuse/v2/fs"
@@ -36,6 +37,15 @@ import (
 type FileHandle struct {
 	h    vfs.Handle
 	fsys *FS
+
+	mu     sync.Mutex
+	owners map[lockOwner]struct{} // lock owners which set locks through this handle
+}
+
+// lockOwner identifies the owner of POSIX or BSD locks
+type lockOwner struct {
+	owner uint64
+	flock bool
 }
 
 // Create a new FileHandle
@@ -96,6 +106,15 @@ var _ fusefs.FileFlusher = (*FileHandle)(nil)
 // so any cleanup that requires specific synchronization or
 // could fail with I/O errors should happen in Flush instead.
 func (f *FileHandle) Release(ctx context.Context) syscall.Errno {
+	// The lock owner isn't passed on release so release the locks
+	// of the owners which used this handle in case the kernel
+	// didn't unlock them.
+	f.mu.Lock()
+	for o := range f.owners {
+		_ = f.file().ReleaseLocks(o.owner, o.flock)
+	}
+	f.owners = nil
+	f.mu.Unlock()
 	return translateError(f.h.Release())
 }
 
@@ -150,3 +169,89 @@ func (f *FileHandle) Setattr(ctx context.Context, in *fuse.SetAttrIn, out *fuse.
 }
 
 var _ fusefs.FileSetattrer = (*FileHandle)(nil)
+
+// file returns the File the handle is open on
+func (f *FileHandle) file() *vfs.File {
+	return f.h.Node().(*vfs.File)
+}
+
+// toLock converts a FUSE lock into a vfs.Lock
+func toLock(owner uint64, lk *fuse.FileLock, flags uint32) vfs.Lock {
+	lock := vfs.Lock{
+		Owner: owner,
+		PID:   lk.Pid,
+		Start: lk.Start,
+		End:   lk.End,
+		Flock: flags&fuse.FUSE_LK_FLOCK != 0,
+	}
+	switch lk.Typ {
+	case syscall.F_RDLCK:
+		lock.Type = vfs.LockRead
+	case syscall.F_WRLCK:
+		lock.Type = vfs.LockWrite
+	default:
+		lock.Type = vfs.LockUnlock
+	}
+	return lock
+}
+
+// setLock sets the lock on the file, remembering the owner
+func (f *FileHandle) setLock(ctx context.Context, owner uint64, lk *fuse.FileLock, flags uint32, wait bool) syscall.Errno {
+	lock := toLock(owner, lk, flags)
+	err := f.file().SetLock(ctx, lock, wait)
+	if err == context.Canceled {
+		return syscall.EINTR
+	}
+	if err == nil && lock.Type != vfs.LockUnlock {
+		f.mu.Lock()
+		if f.owners == nil {
+			f.owners = make(map[lockOwner]struct{})
+		}
+		f.owners[lockOwner{owner: owner, flock: lock.Flock}] = struct{}{}
+		f.mu.Unlock()
+	}
+	return translateError(err)
+}
+
+// Getlk returns locks that would conflict with the given input
+// lock. If no locks conflict, the output has type L_UNLCK. See
+// fcntl(2) for more information.
+func (f *FileHandle) Getlk(ctx context.Context, owner uint64, lk *fuse.FileLock, flags uint32, out *fuse.FileLock) (errno syscall.Errno) {
+	defer log.Trace(f, "owner=%d, lk=%v, flags=%d", owner, lk, flags)("out=%v, errno=%v", &out, &errno)
+	lock, err := f.file().GetLock(toLock(owner, lk, flags))
+	if err != nil {
+		return translateError(err)
+	}
+	out.Start = lock.Start
+	out.End = lock.End
+	out.Pid = lock.PID
+	switch lock.Type {
+	case vfs.LockRead:
+		out.Typ = syscall.F_RDLCK
+	case vfs.LockWrite:
+		out.Typ = syscall.F_WRLCK
+	default:
+		out.Typ = syscall.F_UNLCK
+	}
+	return 0
+}
+
+var _ fusefs.FileGetlker = (*FileHandle)(nil)
+
+// Setlk obtains a lock on a file, or fail if the lock could not
+// obtained. See fcntl(2) for more information.
+func (f *FileHandle) Setlk(ctx context.Context, owner uint64, lk *fuse.FileLock, flags uint32) (errno syscall.Errno) {
+	defer log.Trace(f, "owner=%d, lk=%v, flags=%d", owner, lk, flags)("errno=%v", &errno)
+	return f.setLock(ctx, owner, lk, flags, false)
+}
+
+var _ fusefs.FileSetlker = (*FileHandle)(nil)
+
+// Setlkw obtains a lock on a file, waiting if necessary. See fcntl(2)
+// for more information.
+func (f *FileHandle) Setlkw(ctx context.Context, owner uint64, lk *fuse.FileLock, flags uint32) (errno syscall.Errno) {
+	defer log.Trace(f, "owner=%d, lk=%v, flags=%d", owner, lk, flags)("errno=%v", &errno)
+	return f.setLock(ctx, owner, lk, flags, true)
+}
+
+var _ fusefs.FileSetlkwer = (*FileHandle)(nil)
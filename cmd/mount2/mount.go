@@ -30,9 +30,10 @@ func mountOptions(fsys *FS, f fs.Fs, opt *mountlib.Options) (mountOpts *fuse.Mou
 		AllowOther:    fsys.opt.AllowOther,
 		FsName:        opt.DeviceName,
 		Name:          "rclone",
-		DisableXAttrs: true,
+		DisableXAttrs: !opt.Xattr,
 		Debug:         fsys.opt.DebugFUSE,
 		MaxReadAhead:  int(fsys.opt.MaxReadAhead),
+		EnableLocks:   true,
 
 		// RememberInodes: true,
 		// SingleThreaded: true,
@@ -405,3 +405,96 @@ func (n *Node) Rename(ctx context.Context, oldName string, newParent fusefs.Inod
 }
 
 var _ = (fusefs.NodeRenamer)((*Node)(nil))
+
+// Readlink reads the content of a symlink.
+func (n *Node) Readlink(ctx context.Context) (target []byte, errno syscall.Errno) {
+	defer log.Trace(n, "")("target=%q, errno=%v", &target, &errno)
+	file, ok := n.node.(*vfs.File)
+	if !ok {
+		return nil, syscall.EINVAL
+	}
+	link, err := file.Readlink()
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return []byte(link), 0
+}
+
+var _ = (fusefs.NodeReadlinker)((*Node)(nil))
+
+// Symlink is similar to Mknod but creates a symbolic link to target
+// called name.
+func (n *Node) Symlink(ctx context.Context, target, name string, out *fuse.EntryOut) (inode *fusefs.Inode, errno syscall.Errno) {
+	defer log.Trace(n, "target=%q, name=%q", target, name)("inode=%v, errno=%v", &inode, &errno)
+	dir, ok := n.node.(*vfs.Dir)
+	if !ok {
+		return nil, syscall.ENOTDIR
+	}
+	file, err := dir.Symlink(target, name)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	newNode := newNode(n.fsys, file)
+	n.fsys.setEntryOut(newNode.node, out)
+	newInode := n.NewInode(ctx, newNode, fusefs.StableAttr{Mode: out.Attr.Mode})
+	return newInode, 0
+}
+
+var _ = (fusefs.NodeSymlinker)((*Node)(nil))
+
+// Getxattr should read data for the given attribute into
+// `dest` and return the number of bytes. If `dest` is too
+// small, it should return ERANGE and the size of the attribute.
+func (n *Node) Getxattr(ctx context.Context, attr string, dest []byte) (size uint32, errno syscall.Errno) {
+	if !n.fsys.opt.Xattr {
+		return 0, syscall.ENOSYS
+	}
+	defer log.Trace(n, "attr=%q", attr)("size=%d, errno=%v", &size, &errno)
+	value, err := mountlib.Getxattr(n.node, attr)
+	if err != nil {
+		return 0, translateError(err)
+	}
+	if len(value) > len(dest) {
+		return uint32(len(value)), syscall.ERANGE
+	}
+	return uint32(copy(dest, value)), 0
+}
+
+var _ = (fusefs.NodeGetxattrer)((*Node)(nil))
+
+// Setxattr should store data for the given attribute.  See
+// setxattr(2) for information about flags.
+func (n *Node) Setxattr(ctx context.Context, attr string, data []byte, flags uint32) (errno syscall.Errno) {
+	if !n.fsys.opt.Xattr {
+		return syscall.ENOSYS
+	}
+	defer log.Trace(n, "attr=%q, data=%q, flags=%d", attr, data, flags)("errno=%v", &errno)
+	return translateError(mountlib.Setxattr(n.node, attr, data))
+}
+
+var _ = (fusefs.NodeSetxattrer)((*Node)(nil))
+
+// Listxattr should read all attributes (null terminated) into
+// `dest`. If the `dest` buffer is too small, it should return ERANGE
+// and the correct size.
+func (n *Node) Listxattr(ctx context.Context, dest []byte) (size uint32, errno syscall.Errno) {
+	if !n.fsys.opt.Xattr {
+		return 0, 0
+	}
+	defer log.Trace(n, "")("size=%d, errno=%v", &size, &errno)
+	names, err := mountlib.Listxattr(n.node)
+	if err != nil {
+		return 0, translateError(err)
+	}
+	var buf []byte
+	for _, name := range names {
+		buf = append(buf, name...)
+		buf = append(buf, 0)
+	}
+	if len(buf) > len(dest) {
+		return uint32(len(buf)), syscall.ERANGE
+	}
+	return uint32(copy(dest, buf)), 0
+}
+
+var _ = (fusefs.NodeListxattrer)((*Node)(nil))
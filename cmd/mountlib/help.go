@@ -295,6 +295,77 @@ This is the same as setting the attr_timeout option in mount.fuse.
 Note that all the rclone filters can be used to select a subset of the
 files to be visible in the mount.
 
+### Prefetching
+
+With |--vfs-cache-mode full| rclone @ can download files into the VFS
+cache as soon as it is mounted, so they can be read without waiting
+for the remote when users arrive. Pass the files and directories to
+prefetch with |--prefetch| - directories are prefetched recursively.
+
+The files in them can be chosen with |--prefetch-filter|, which takes
+filter rules in the same format as |--filter|, and |--prefetch-max-size|.
+Only part of each file can be prefetched with |--prefetch-offset| and
+|--prefetch-count|.
+
+For example to prefetch all the files under |projects/current| smaller
+than 100 MiB
+
+    rclone @ remote: /mnt/remote --vfs-cache-mode full --prefetch projects/current --prefetch-max-size 100M
+
+or to prefetch the first 4 MiB of every video
+
+    rclone @ remote: /mnt/remote --vfs-cache-mode full --prefetch / --prefetch-filter "+ *.{mp4,mkv}" --prefetch-filter "- **" --prefetch-count 4M
+
+Files can also be prefetched while mounted with the |vfs/prefetch|
+remote control command.
+
+The prefetch runs in the background. The files being prefetched and
+the data being downloaded are shown in the stats. Prefetched files are
+removed from the cache as usual so set |--vfs-cache-max-age| and
+|--vfs-cache-max-size| large enough to keep them.
+
+### Extended attributes
+
+If the |--xattr| flag is set, the metadata of files can be read and
+written as extended attributes, for example with |getfattr| and
+|setfattr|. Each metadata key is shown as an extended attribute with
+|user.rclone.| on the front, so the |content-type| of a file on S3 is
+|user.rclone.content-type|.
+
+    $ getfattr -d /mnt/remote/file.txt
+    # file: mnt/remote/file.txt
+    user.rclone.content-type="text/plain; charset=utf-8"
+    user.rclone.mtime="2022-07-01T12:00:00.123456789Z"
+    $ setfattr -n user.rclone.project -v potato /mnt/remote/file.txt
+
+What metadata is available, and which keys can be written, depends on
+the backend - see the [metadata](/docs/#metadata) docs and the docs
+for the backend. Setting an attribute needs a backend which can update
+the metadata of an existing object; S3 does this by copying the object
+onto itself. Attributes can't be removed.
+
+Reading the attributes may need a call to the remote for each file, so
+this is off by default. Attributes can't be read or written on Windows.
+
+### File locking
+
+Advisory file locks taken with |flock| (BSD locks) and |fcntl| (POSIX
+locks) are passed to rclone rather than being handled by the kernel,
+so programs such as SQLite can use them to coordinate writes to files
+in the mount. The locks are held in the VFS and are released when the
+file is closed or the mount is stopped.
+
+The locks are normally only seen by the processes using this mount.
+Set the |--vfs-shared-locks| flag to share them with the other rclone
+processes on this machine using the same remote and cache directory.
+The locks are kept in a database in the cache directory, and locks
+held by an rclone process which has stopped running are ignored.
+
+The locks are only advisory - they don't stop reads or writes by
+programs which don't take them - and they are not seen by other
+machines or by programs using the remote directly. File locking isn't
+supported by the |cmount| implementation used on Windows and macOS.
+
 ### systemd
 
 When running rclone @ as a systemd service, it is possible
@@ -14,6 +14,7 @@ import (
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/config"
 	"github.com/rclone/rclone/fs/config/flags"
+	"github.com/rclone/rclone/fs/filter"
 	"github.com/rclone/rclone/fs/rc"
 	"github.com/rclone/rclone/lib/atexit"
 	"github.com/rclone/rclone/lib/daemonize"
@@ -46,16 +47,24 @@ type Options struct {
 	NoAppleXattr       bool
 	DaemonTimeout      time.Duration // OSXFUSE only
 	AsyncRead          bool
-	NetworkMode        bool // Windows only
+	NetworkMode        bool          // Windows only
+	Xattr              bool          // map extended attributes to metadata
+	Prefetch           []string      // paths to prefetch into the VFS cache after mounting
+	PrefetchFilter     []string      // filter rules to choose the files to prefetch
+	PrefetchMaxSize    fs.SizeSuffix // only prefetch files smaller than this
+	PrefetchOffset     fs.SizeSuffix // offset in each file to prefetch from
+	PrefetchCount      fs.SizeSuffix // number of bytes to prefetch from each file
 }
 
 // DefaultOpt is the default values for creating the mount
 var DefaultOpt = Options{
-	MaxReadAhead:  128 * 1024,
-	AttrTimeout:   1 * time.Second, // how long the kernel caches attribute for
-	NoAppleDouble: true,            // use noappledouble by default
-	NoAppleXattr:  false,           // do not use noapplexattr by default
-	AsyncRead:     true,            // do async reads by default
+	MaxReadAhead:    128 * 1024,
+	AttrTimeout:     1 * time.Second, // how long the kernel caches attribute for
+	NoAppleDouble:   true,            // use noappledouble by default
+	NoAppleXattr:    false,           // do not use noapplexattr by default
+	AsyncRead:       true,            // do async reads by default
+	PrefetchMaxSize: -1,
+	PrefetchCount:   -1,
 }
 
 type (
@@ -76,6 +85,8 @@ type MountPoint struct {
 	MountFn    MountFn
 	UnmountFn  UnmountFn
 	ErrChan    <-chan error
+
+	cancelPrefetch context.CancelFunc // stops the prefetch if running
 }
 
 // NewMountPoint makes a new mounting structure
@@ -147,6 +158,13 @@ func AddFlags(flagSet *pflag.FlagSet) {
 	flags.BoolVarP(flagSet, &Opt.NetworkMode, "network-mode", "", Opt.NetworkMode, "Mount as remote network drive, instead of fixed disk drive (supported on Windows only)")
 	// Unix only
 	flags.DurationVarP(flagSet, &Opt.DaemonWait, "daemon-wait", "", Opt.DaemonWait, "Time to wait for ready mount from daemon (maximum time on Linux, constant sleep time on OSX/BSD) (not supported on Windows)")
+	flags.BoolVarP(flagSet, &Opt.Xattr, "xattr", "", Opt.Xattr, "Read and write the metadata of files as \""+XattrPrefix+"*\" extended attributes (not supported on Windows)")
+	// Prefetching
+	flags.StringArrayVarP(flagSet, &Opt.Prefetch, "prefetch", "", []string{}, "Prefetch this file or directory into the VFS cache after mounting (repeat if required)")
+	flags.StringArrayVarP(flagSet, &Opt.PrefetchFilter, "prefetch-filter", "", []string{}, "Filter rule to choose the files to prefetch (repeat if required)")
+	flags.FVarP(flagSet, &Opt.PrefetchMaxSize, "prefetch-max-size", "", "Only prefetch files smaller than this in KiB or suffix B|K|M|G|T|P")
+	flags.FVarP(flagSet, &Opt.PrefetchOffset, "prefetch-offset", "", "Offset in each file to prefetch from")
+	flags.FVarP(flagSet, &Opt.PrefetchCount, "prefetch-count", "", "Number of bytes to prefetch from each file (default all)")
 }
 
 // NewMountCommand makes a mount command with the given name and Mount function
@@ -258,9 +276,43 @@ func (m *MountPoint) Mount() (daemon *os.Process, err error) {
 		return nil, fmt.Errorf("failed to mount FUSE fs: %w", err)
 	}
 	m.MountedOn = time.Now()
+	if len(m.MountOpt.Prefetch) > 0 {
+		err = m.prefetch()
+		if err != nil {
+			fs.Errorf(m.Fs, "Not prefetching into the VFS cache: %v", err)
+		}
+	}
 	return nil, nil
 }
 
+// prefetch the files in --prefetch into the VFS cache in the
+// background
+func (m *MountPoint) prefetch() error {
+	filterOpt := filter.DefaultOpt
+	filterOpt.FilterRule = m.MountOpt.PrefetchFilter
+	filterOpt.MaxSize = m.MountOpt.PrefetchMaxSize
+	fi, err := filter.NewFilter(&filterOpt)
+	if err != nil {
+		return fmt.Errorf("bad --prefetch-filter: %w", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = filter.ReplaceConfig(ctx, fi)
+	m.cancelPrefetch = cancel
+	opt := vfs.PrefetchOpt{
+		Paths:  m.MountOpt.Prefetch,
+		Offset: int64(m.MountOpt.PrefetchOffset),
+		Count:  int64(m.MountOpt.PrefetchCount),
+	}
+	go func() {
+		defer cancel()
+		_, err := m.VFS.Prefetch(ctx, opt)
+		if err != nil && ctx.Err() == nil {
+			fs.Errorf(m.Fs, "Failed to prefetch into the VFS cache: %v", err)
+		}
+	}()
+	return nil
+}
+
 // Wait for mount end
 func (m *MountPoint) Wait() error {
 	// Unmount on exit
@@ -320,5 +372,8 @@ func (m *MountPoint) Wait() error {
 
 // Unmount the specified mountpoint
 func (m *MountPoint) Unmount() (err error) {
+	if m.cancelPrefetch != nil {
+		m.cancelPrefetch()
+	}
 	return m.UnmountFn()
 }
@@ -0,0 +1,74 @@
+package mountlib
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/vfs"
+)
+
+// XattrPrefix is the prefix of the extended attributes which are
+// mapped onto the metadata of files with --xattr
+//
+// The "user." namespace is the only one unprivileged users can write
+// to on Linux.
+const XattrPrefix = "user.rclone."
+
+// Getxattr returns the value of the extended attribute name on node
+//
+// It returns vfs.ENOATTR if the attribute isn't found.
+func Getxattr(node vfs.Node, name string) ([]byte, error) {
+	// Don't read the metadata for attributes we don't provide -
+	// the kernel asks for security.capability on every write
+	if !strings.HasPrefix(name, XattrPrefix) {
+		return nil, vfs.ENOATTR
+	}
+	file, ok := node.(*vfs.File)
+	if !ok {
+		return nil, vfs.ENOATTR
+	}
+	metadata, err := file.Metadata()
+	if err != nil {
+		return nil, err
+	}
+	value, found := metadata[name[len(XattrPrefix):]]
+	if !found {
+		return nil, vfs.ENOATTR
+	}
+	return []byte(value), nil
+}
+
+// Listxattr returns the names of the extended attributes on node
+func Listxattr(node vfs.Node) (names []string, err error) {
+	file, ok := node.(*vfs.File)
+	if !ok {
+		return nil, nil
+	}
+	metadata, err := file.Metadata()
+	if err != nil {
+		return nil, err
+	}
+	for k := range metadata {
+		names = append(names, XattrPrefix+k)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Setxattr sets the extended attribute name on node to value
+//
+// This sets the metadata of the file, so it returns vfs.ENOSYS if
+// the backend can't set metadata.
+func Setxattr(node vfs.Node, name string, value []byte) error {
+	if !strings.HasPrefix(name, XattrPrefix) || len(name) == len(XattrPrefix) {
+		return vfs.EPERM
+	}
+	file, ok := node.(*vfs.File)
+	if !ok {
+		return vfs.EPERM
+	}
+	return file.SetMetadata(fs.Metadata{
+		name[len(XattrPrefix):]: string(value),
+	})
+}
@@ -0,0 +1,65 @@
+package mountlib_test
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/rclone/rclone/backend/local"
+	"github.com/rclone/rclone/cmd/mountlib"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/vfs"
+	"github.com/rclone/rclone/vfs/vfscommon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXattr(t *testing.T) {
+	ctx := context.Background()
+	localDir := t.TempDir()
+	err := ioutil.WriteFile(filepath.Join(localDir, "file.txt"), []byte("hello"), 0666)
+	require.NoError(t, err)
+	f, err := fs.NewFs(ctx, localDir)
+	require.NoError(t, err)
+	opt := vfscommon.DefaultOpt
+	v := vfs.New(f, &opt)
+	defer v.Shutdown()
+
+	node, err := v.Stat("file.txt")
+	require.NoError(t, err)
+	dir, err := v.Stat("")
+	require.NoError(t, err)
+
+	// Attributes not in our namespace aren't looked up
+	_, err = mountlib.Getxattr(node, "security.capability")
+	assert.Equal(t, vfs.ENOATTR, err)
+	_, err = mountlib.Getxattr(node, mountlib.XattrPrefix+"potato")
+	assert.Equal(t, vfs.ENOATTR, err)
+
+	names, err := mountlib.Listxattr(node)
+	require.NoError(t, err)
+	assert.Contains(t, names, mountlib.XattrPrefix+"mtime")
+
+	// Set the mtime via an xattr
+	mtime := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC).Format(time.RFC3339Nano)
+	err = mountlib.Setxattr(node, mountlib.XattrPrefix+"mtime", []byte(mtime))
+	require.NoError(t, err)
+	value, err := mountlib.Getxattr(node, mountlib.XattrPrefix+"mtime")
+	require.NoError(t, err)
+	assert.Equal(t, mtime, string(value))
+
+	// Only our namespace can be set
+	err = mountlib.Setxattr(node, "user.potato", []byte("hello"))
+	assert.Equal(t, vfs.EPERM, err)
+	err = mountlib.Setxattr(node, mountlib.XattrPrefix, []byte("hello"))
+	assert.Equal(t, vfs.EPERM, err)
+
+	// Directories don't have any
+	names, err = mountlib.Listxattr(dir)
+	require.NoError(t, err)
+	assert.Empty(t, names)
+	_, err = mountlib.Getxattr(dir, mountlib.XattrPrefix+"mtime")
+	assert.Equal(t, vfs.ENOATTR, err)
+}
@@ -22,14 +22,14 @@ var Assets = func() http.FileSystem {
 	fs := vfsgen۰FS{
 		"/": &vfsgen۰DirInfo{
 			name:    "/",
-			modTime: time.Date(2020, 5, 5, 16, 40, 6, 115915195, time.UTC),
+			modTime: time.Date(2026, 10, 16, 12, 50, 7, 143572566, time.UTC),
 		},
 		"/index.html": &vfsgen۰CompressedFileInfo{
 			name:             "index.html",
-			modTime:          time.Date(2020, 5, 5, 16, 40, 5, 919909715, time.UTC),
-			uncompressedSize: 15424,
+			modTime:          time.Date(2026, 10, 16, 12, 51, 44, 908274121, time.UTC),
+			uncompressedSize: 16676,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbc\x7b\x6d\x73\xdb\x46\xf2\xe7\x6b\xe9\x53\x4c\x98\xcd\x8a\x4a\xc0\xe1\x3c\x3f\x48\xa4\xf6\x6c\xc6\x59\xbb\x56\x71\x52\xb1\x9d\xad\x6c\x2a\x2f\x20\x62\x48\xe2\x0c\x02\x0c\x00\xea\xc1\x3a\x55\xdd\x87\xb8\x4f\x78\x9f\xe4\xaa\x67\x00\x12\x90\x28\x27\x7b\xf5\xdf\xbf\xec\xa2\x80\x9e\x99\x9e\x7e\xf8\x75\x4f\x37\x08\x4d\xbe\x18\x8d\xd0\xf1\x78\x8c\x66\xc5\xe6\xae\x4c\x97\xab\x1a\x31\x42\x25\xfa\x3e\xae\xeb\x95\xbb\x41\xaf\x8b\xac\x46\x71\x9e\xa0\xf7\x2b\x87\x66\x71\x92\xdc\xa1\x17\xdb\x7a\x55\x94\xd5\xf1\x78\x0c\xeb\x2e\xd3\xb9\xcb\x2b\x97\xa0\x6d\x9e\xb8\x12\xd5\x2b\x87\x5e\x6c\xe2\xf9\xca\xb5\x23\x11\xfa\xd9\x95\x55\x5a\xe4\x88\x61\x82\x86\x30\x61\xd0\x0c\x0d\x4e\xcf\x81\xc5\x5d\xb1\x45\xeb\xf8\x0e\xe5\x45\x8d\xb6\x95\x43\xf5\x2a\xad\xd0\x22\xcd\x1c\x72\xb7\x73\xb7\xa9\x51\x9a\xa3\x79\xb1\xde\x64\x69\x9c\xcf\x1d\xba\x49\xeb\x95\xdf\xa7\xe1\x82\x81\xc7\x2f\x0d\x8f\xe2\xaa\x8e\xd3\x1c\xc5\x68\x5e\x6c\xee\x50\xb1\xe8\x4e\x44\x71\xdd\x08\x0d\x3f\xab\xba\xde\x9c\x8d\xc7\x37\x37\x37\x38\xf6\x02\xe3\xa2\x5c\x8e\xb3\x30\xb5\x1a\x5f\xbe\x99\xbd\x7a\xfb\xee\xd5\x88\x61\xd2\x2c\xfa\x90\x67\xae\xaa\x50\xe9\x7e\xdf\xa6\xa5\x4b\xd0\xd5\x1d\x8a\x37\x9b\x2c\x9d\xc7\x57\x99\x43\x59\x7c\x83\x8a\x12\xc5\xcb\xd2\xb9\x04\xd5\x05\x08\x7d\x53\xa6\x75\x9a\x2f\x23\x54\x15\x8b\xfa\x26\x2e\x1d\xb0\x49\xd2\xaa\x2e\xd3\xab\x6d\xdd\xb3\x59\x2b\x62\x5a\xf5\x26\x14\x39\x8a\x73\x34\x78\xf1\x0e\xbd\x79\x37\x40\x2f\x5f\xbc\x7b\xf3\x2e\x02\x26\xff\x7c\xf3\xfe\xf5\x0f\x1f\xde\xa3\x7f\xbe\xf8\xe9\xa7\x17\x6f\xdf\xbf\x79\xf5\x0e\xfd\xf0\x13\x9a\xfd\xf0\xf6\xdb\x37\xef\xdf\xfc\xf0\xf6\x1d\xfa\xe1\x3b\xf4\xe2\xed\x2f\xe8\x1f\x6f\xde\x7e\x1b\x21\x97\xd6\x2b\x57\x22\x77\xbb\x29\x41\x83\xa2\x44\x29\x58\xd3\x25\xde\x74\xef\x9c\xeb\x89\xb0\x28\x82\x48\xd5\xc6\xcd\xd3\x45\x3a\x47\x59\x9c\x2f\xb7\xf1\xd2\xa1\x65\x71\xed\xca\x3c\xcd\x97\x68\xe3\xca\x75\x5a\x81\x57\x2b\x40\x07\xb0\xc9\xd2\x75\x5a\xc7\xb5\x27\x3d\xd1\x0b\xa3\xe3\xef\x8b\x04\xb8\x85\x19\x67\x08\xbd\x48\xe2\x4d\x1d\x4c\x55\xce\xb3\x22\x77\x68\x1d\x97\x1f\xb7\x1b\x34\x1a\x5d\x1c\x1f\x4f\xbe\xf8\xf6\x87\xd9\xfb\x5f\x7e\x7c\x85\x56\xf5\x3a\xbb\x38\x9e\x84\x5f\x47\x93\x95\x8b\x93\x8b\xe3\xa3\xa3\x49\x9d\xd6\x99\xbb\xb8\xbf\x87\x01\x84\xdf\xc6\x6b\xf7\xf0\x30\x19\x07\x2a\x8c\xaf\x5d\x1d\xa3\xf9\x2a\x2e\x2b\x57\x4f\x07\xdb\x7a\x31\x32\x83\xfd\x40\x1e\xaf\xdd\x74\x70\x9d\xba\x9b\x4d\x51\xd6\x03\x34\x2f\xf2\xda\xe5\xf5\x74\x70\x93\x26\xf5\x6a\x9a\xb8\xeb\x74\xee\x46\xfe\x26\x42\x69\x9e\xd6\x69\x9c\x8d\xaa\x79\x9c\xb9\x29\xc5\xe4\x09\xa3\x65\x51\x2c\x33\xd7\x61\x93\x17\x75\x19\xe7\x55\x16\xd7\x6e\x70\x71\x3c\xa9\xea\x3b\x10\xeb\x6b\x74\x8f\x36\x71\x92\xa4\xf9\xf2\x0c\x91\x73\xd0\x78\x99\xe6\xfe\xf2\xe1\xf8\xaa\x48\xee\xd0\xfd\xf1\xd1\xa2\xc8\xeb\xd1\x22\x5e\xa7\xd9\xdd\x19\xaa\xe2\xbc\x1a\x55\xae\x4c\x17\xe7\xc7\x47\xb5\xbb\xad\x47\xa5\x03\xe3\x7a\x0e\xc5\xa6\x4e\xd7\xe9\x27\x57\x6d\x9c\x4b\xce\x8f\x8f\xae\xe2\xf9\xc7\x65\x59\x6c\xf3\x64\x34\x2f\xb2\xa2\x3c\x43\x5f\x2e\xfc\xcf\xf9\xf1\xc3\x71\x0c\xbc\x5b\x32\x21\xca\x25\xbc\x65\x99\xb8\x79\x51\x7a\xc7\x9c\xa1\xbc\xc8\x9d\x9f\x7e\xb6\x02\x6f\x47\xc7\x2b\x8a\x9a\xeb\x2e\x03\x4e\xed\x3c\xf0\x05\x87\xc0\xbc\x2f\xab\xed\x7a\x1d\x97\x5e\x85\x46\xc7\x51\xe6\x16\xf5\x19\x92\x5f\x9d\xef\x49\x3e\xc7\x04\xda\xc3\x71\xbd\x3a\x5b\xa4\x65\x55\x8f\xe6\xab\x34\x4b\xa2\xe3\x3a\xe9\xde\x03\x27\xef\x81\x33\x44\xbf\x3a\x47\xe3\xaf\x51\x0d\x8b\x5d\xe9\x21\xba\x2e\xae\x20\x45\x7c\x3d\x0e\x7c\xb2\xb8\xc7\x66\x7f\xfb\xe7\xb9\x04\x4d\xba\xf2\xd7\xc5\xe6\x0c\x31\xb9\xb9\xed\x28\x70\x55\xd4\x75\xb1\x3e\x43\x34\x90\x0f\xd9\x9c\xc1\x3f\x6f\x1b\xba\x73\x68\x95\x7e\x72\x67\x88\x11\xbf\xc8\x53\x6e\x5c\x30\x45\x5e\x94\xeb\x38\x3b\x3f\x3e\xba\x59\xa5\xb5\x1b\x55\x9b\x78\xee\x80\x7a\x53\xc6\x9b\xf3\xe3\x23\xb0\xfc\x22\x2b\x6e\x46\xb7\x67\x68\x95\x26\x89\xcb\x5b\xb7\xb5\x23\x67\xc8\x65\x59\xba\xa9\xd2\xea\x7c\xef\x20\x6b\x6d\x23\xc1\x23\xc7\x93\xf3\xe3\xa3\x1d\xee\x90\x00\x79\x1e\x1e\x39\xf9\x09\x28\x7c\x3c\x67\x69\x40\x86\x9f\xfb\xc8\x4d\x7b\x20\x1f\x3f\x1c\xaf\x21\x03\xdf\x1f\x1f\x25\x69\xb5\xc9\xe2\xbb\x33\x74\x95\x15\xf3\x8f\x30\x82\x7d\xc8\xf4\x4d\x42\xd9\xde\x24\x2d\xea\x7f\x76\x65\x12\xe7\x71\xd4\x87\xff\x55\x51\x26\xae\xdc\x3b\x60\x73\x8b\xaa\x22\x4b\x13\xf4\xa5\x9d\xc1\xbf\xf3\x47\x8e\xa3\xe4\xb0\xe3\x48\xd0\xd9\x0b\x33\x4a\x6b\xb7\xde\x6b\xd0\xc2\x93\xba\x35\x4c\xf9\x72\x91\x66\x75\x0f\x12\x67\xc1\x62\x8d\x2c\x3d\x21\x66\xb3\x99\xc7\xb4\x3f\x0e\x3a\xa0\x23\xe4\xab\xbd\xf0\xf3\x22\xcb\xe2\x4d\xe5\xce\x50\x7b\xe5\xd7\xf8\x2d\x0e\xe8\x97\xc4\xd5\xca\x25\xe8\xcb\x24\x86\x7f\x7e\xaa\x4f\x13\x75\xb9\xf7\xd6\x33\x51\xef\xe6\x21\xc2\x20\x1c\x76\x4e\x8d\xb3\x74\x99\x9f\x21\x88\xcb\xf3\x8e\x4e\x60\x12\x04\x7e\x80\xf0\x98\xad\xe2\x7c\xe9\x12\xb4\x28\x8b\x35\x22\x90\x9f\x59\x1b\x65\x4f\x62\xe3\xb3\x26\xee\x79\x59\x79\xca\x41\x88\x7b\xce\x5d\x94\x5e\x65\x71\xc0\x4b\xbd\x42\xd5\xf5\x12\x46\xae\x5d\x59\xa7\xf3\x38\x6b\x35\x58\xa7\x49\x92\x05\xdb\x85\x08\x3f\x18\x3b\x5d\x01\x1a\xa4\xd7\xc9\x59\x5e\xaf\x02\x72\x87\xec\xb4\xe3\x28\x43\xbe\x7a\x32\x81\x9f\xf6\x7c\x4f\x7c\x00\x37\xbf\x9a\x04\xb6\x9f\x2c\x4e\xa3\xfe\x6a\x71\xfa\xd8\xf0\x1e\x5e\x87\xc4\x68\xd4\xdc\x14\x55\x1a\x42\x2e\xbe\xaa\x8a\x6c\x5b\xb7\x2a\x62\x38\x67\xbc\x2b\xf1\xb2\xd8\x6e\x3a\x88\x0d\x39\x96\x62\x2d\x01\xb3\x47\x37\x45\x99\x8c\xae\x4a\x17\x7f\x3c\x43\xfe\xd7\x28\xce\xb2\x6e\x1a\x01\xd3\xb4\x43\x30\xf9\xb1\x57\x36\xa5\x1b\xb5\x7e\xc1\xe9\xbc\xc8\x9f\x46\x87\x6c\x02\x08\x46\x71\x55\x94\x75\x2f\xda\xd3\x1c\x32\xc5\xa8\x09\xfa\x5d\x18\x78\xe9\x56\xae\x13\x5f\x1d\x6d\x4b\x97\xc5\x75\x7a\xed\x20\xb5\x01\xae\x30\x0b\x01\xd8\xd9\x02\xd7\xc5\xe6\x39\x13\x1d\x05\x23\x90\x76\xf9\x88\x3e\x91\x10\x07\x6c\x3e\xcb\xa1\x85\x6e\x58\xba\x67\xf8\x70\xbc\x28\x8a\x27\x39\xc0\xc7\xcb\x53\x90\x87\x54\xd6\x75\xf8\xdc\xe5\xb5\x2b\x81\xcd\xff\x58\xbb\x24\x8d\xd1\x70\x1d\xdf\x8e\x1a\x9b\x28\x42\x36\xb7\x80\x91\xf1\xd7\x47\x78\x95\x26\xae\x4d\x1d\x7b\x63\x86\xe3\xf8\xe8\x01\x95\x6e\x5d\x5c\x87\x72\xe9\xa3\x73\x1b\x94\xc4\xb5\xab\xa0\x3e\xdc\x9f\x60\x47\x07\xb0\xdd\x9a\x3f\xde\xd6\x05\xf0\x39\x3e\xea\x41\x96\x9f\x46\x8f\x96\x05\xc4\x1f\x3a\xae\x8f\x0e\x21\x19\x38\x86\x53\xee\xd1\x11\x13\xe8\x3e\xaa\xbb\xa7\x03\xd0\x3b\x59\xf5\xa8\x63\x0d\x4a\x82\x41\x1f\x8e\x1f\x8e\x27\xe3\xa6\x62\x3a\x9a\x8c\x9b\x8a\x6f\xe2\x13\x5f\x91\x67\x45\x9c\x4c\x4f\x02\x8b\xe1\xe9\x79\x5d\x2c\x97\x99\x1b\x0e\x7c\xee\x1c\x9c\x9e\xcf\x7d\xf6\x7a\x97\x7e\x72\xc3\xd3\x13\x5f\xa6\x41\x68\x5d\x87\x16\x64\x3a\xa0\x98\x0e\xd0\xed\x3a\xcb\xab\xe9\xa0\xd3\x01\xdc\x70\x5f\xfd\x33\x42\xc8\xb8\xba\x5e\x36\x53\xce\x6e\xb3\x34\xff\x78\x68\x22\xb5\xd6\x8e\xfd\xe8\x00\x05\x4c\x4f\x07\x64\x80\x42\xf1\x08\x57\x5e\xfc\xe9\xe0\x00\xd4\x7c\xed\x78\x34\x49\xdc\xa2\xf2\x57\x47\xbe\x05\xfb\xae\xc8\xa0\xf6\x80\xda\xd7\xd3\x96\x28\x4d\xa6\x83\x85\xa7\x0e\xa0\x19\xca\x46\xe5\x16\x38\xe6\x45\xfe\xc9\x95\x45\xa0\xf9\x5b\x17\x38\x1e\x1d\x4d\x36\x71\xbd\x42\xc9\x74\xf0\x3d\x33\x12\x33\x86\xb8\xc6\x52\xae\x46\x54\x30\xac\x2e\x29\x25\xd8\x22\xf2\x9a\x53\xac\x67\x54\x60\x26\x11\x41\x04\x51\x05\xd4\x30\xf5\x5a\x4b\x4c\x57\x1c\x48\xec\x67\xb8\x9e\x93\x11\x23\x58\xc9\x11\xcc\x57\x23\x3f\x69\x04\x0c\xc2\xe5\xa7\x56\x8a\x2f\xbf\xfb\xee\x05\x21\x64\x30\x7e\x56\x12\xd5\xdd\x97\x2b\x44\x90\x24\x98\x19\x44\x90\xd2\x58\x8b\x6b\x2a\x0d\xd6\x73\x82\xa8\xc6\x42\x23\xbf\x1d\x82\x15\xd2\x7f\x86\xcb\xd7\x9e\xd9\x1c\xa6\x08\x10\x19\xe4\xa0\x02\xf3\x70\xe5\xa7\xfc\x0c\xdc\xe4\x9c\x8c\x3c\x9f\x56\x6c\x18\x19\xed\x27\x75\xc5\x9e\xbd\x60\xa6\x15\x7b\x32\x5e\x1e\xb0\xfe\xa8\x5a\x15\x65\x3d\xdf\xd6\xe0\xd4\xb2\xf8\xe8\x1a\xa3\x37\x77\xa3\xc6\xe7\xb4\xe7\x91\xae\xc7\xdc\xb5\xcb\x8b\x24\xd9\x79\xe9\x20\xf3\x11\x9c\xe0\x9b\x83\x9e\x6e\xd6\x3d\xb7\xb0\x5a\xc5\x9b\x1d\x04\x9e\x9a\x5e\x18\xad\x22\xf0\x96\x30\xca\x12\x86\x2e\x3d\x1a\x28\x13\xdc\xf4\xc9\x00\x0f\x46\xb4\x91\x11\x41\x97\x9c\x62\x65\xa9\x92\xcc\x46\x04\x79\xaf\x35\x4b\x08\x22\x11\x55\xd8\x58\x65\x29\x51\x88\xf4\x78\x90\x88\x52\x86\x95\x50\x44\x53\xe0\xa1\x70\xc3\xe3\x19\xb2\x96\x98\x58\xcd\x0d\x91\x68\xd6\x21\x4b\x81\x85\x90\x8a\x10\x83\x38\x61\x58\x49\xc9\x8c\xec\x6e\x74\x58\xb3\x7f\x0d\xbc\x7d\xde\x79\x7b\x3c\x02\xe6\xc5\x64\x0c\x76\xf9\x03\x2b\xa9\x9e\xe2\x5c\xf5\x34\x07\xd0\x46\x1e\xb4\xdc\x48\x65\x10\x89\x3c\x72\xa9\x25\xdc\x82\xea\x8c\x29\x2c\x24\x15\x4c\xa0\x19\x89\x98\xe0\xd8\x12\x2b\x34\x45\x1d\x1e\x4c\x1a\x4c\x2d\xe7\xcc\xa0\xce\x46\x1d\xea\x65\x47\x9c\x0e\x79\xd6\xb1\x43\x8f\xc7\xce\x66\x9d\xfd\xba\xd4\xbd\x4c\x5d\xbb\x77\x04\xef\xd9\x7d\xaf\x5c\xd7\xee\x0a\xf5\x6d\xf4\x8c\x9d\x7d\x24\x3d\xb2\xf3\x2e\xa2\xba\x16\xa7\x4c\x61\x2a\x05\xe5\x22\x62\x92\x60\x29\x2d\x35\x02\xcd\x80\x6c\x24\xb1\x1a\xc8\x14\x1b\xc3\x95\xe6\x88\x32\x8d\x39\x21\x52\x80\x99\x38\x26\x44\x51\xc6\x3c\x55\x6b\xa6\x08\x8b\x98\x14\x98\x06\xea\x8c\x32\x83\x85\xb2\x42\x00\x59\x62\xd6\x4e\x36\xd8\x52\x4b\x28\x98\x54\x61\x4a\x04\x31\x40\xb5\x58\x71\xc3\x39\x58\x54\x63\x42\x18\x11\x14\xcd\x28\xf7\x12\x59\xc5\xbc\xa1\x39\x53\x92\x53\x44\x21\x6f\x30\x63\x24\x4c\xb6\x88\x72\x8e\x29\x21\x44\x6a\x7f\x3b\xa3\x5c\x60\x61\xb9\xe6\xba\x19\x96\x58\x50\xc9\x95\xf0\x3c\xa4\xa4\x84\x21\xca\x15\xa6\x94\x31\x22\xfc\x7e\x4a\x4b\xe9\xb7\x53\xd8\x10\x4b\x84\xe8\x4a\x41\xb9\xc6\x4c\x1a\x45\xad\xd7\xc3\x1e\xa0\x0a\x2c\x75\xcb\xa2\x43\x06\x10\x04\xf5\xba\x54\x86\xcd\x9e\x4a\x38\x37\x9c\x79\x1b\x0b\xa9\xa9\xe0\x41\x0a\x6d\x94\x54\x2a\x62\xc2\x62\x4b\x0c\x55\xdc\x4b\x2c\x15\xd5\xda\x7a\x2a\xf1\xb6\xe8\x53\x0d\x96\xc1\x4d\x9e\x05\x31\x56\x33\x60\xc1\xb0\xa1\x82\x19\xe5\x2d\x61\x94\xb0\xdc\x46\x8c\x6b\xc8\x2f\x82\x98\x3e\x95\x63\xa6\xb9\x50\xde\x8a\x7b\x32\x93\x98\x04\xe1\xba\xb2\x51\x8d\x65\xcb\xd8\x60\x6a\x08\x13\x40\x25\xd8\x08\x65\xb9\x67\x61\xb1\xb6\x46\x53\x1e\x31\x22\x30\x6b\x0c\x27\x00\x4e\x96\x71\x11\x51\x6b\xb0\x82\xed\x04\xa2\x42\x60\xc1\x2c\x67\x26\xa2\x96\x63\xad\x40\x3f\x88\x78\x8d\x19\x55\xca\xd8\x88\x1a\x83\x8d\xb2\xdc\x18\x44\x25\xc1\x4a\x1b\x41\x69\x44\x8d\xc0\x26\x88\x4c\xa5\xc0\x86\x2b\xab\x79\x44\x0d\x6d\xc1\x32\x83\xb3\xcc\x5a\x29\xb9\x8c\xa8\x06\xa0\x5a\x69\x19\xa2\x8a\x63\xc5\x14\x15\x36\xa2\x5a\xec\xf0\xad\x0c\x16\x86\x4a\xc9\x22\xaa\x19\x56\x80\x58\x08\x06\xcd\x31\xe7\xca\x4a\x11\x51\x4d\xb0\xe0\x46\x6b\x85\xa8\xb6\x98\x52\x6e\x0d\x8f\x60\x9d\x52\x92\x13\x85\xa8\x91\x58\x1a\x6d\x80\x85\xd2\x98\x0b\x70\x1f\x9a\x51\xcb\x30\x51\x54\x33\x20\x2b\xcc\x28\x6c\x88\xc0\x00\x5a\x11\xae\x4d\x44\x95\xc4\x5c\x30\x23\x35\x62\x44\x7a\x29\xa8\x88\xa8\x12\x58\x05\xa5\x67\x8c\x32\xb0\x32\xd5\x9e\xca\x82\xf7\x18\xb5\x58\x5a\x43\x85\x8a\x40\x25\x6b\x21\x7e\x11\x63\x06\x53\xc5\xbc\xce\x7b\xea\x25\x13\x0a\x13\x09\x21\xfe\x2c\xd9\xca\xd6\xa9\xb3\x1e\x59\x63\x0d\x16\x92\x08\xa8\x5a\x32\xc1\x81\x6a\x31\x44\x13\x11\x08\xc0\xc7\x35\x31\xd6\x46\x8c\x50\x4c\x9a\x2c\x02\x19\x85\x51\x88\xbe\x88\x41\x0e\x0b\x50\x86\x10\x20\xda\x1a\xa3\x22\x46\x38\x96\x21\x31\x40\x14\x71\xcd\x34\x95\x5d\xea\x0c\x92\x84\x50\x9c\xf1\x47\x93\x0d\x96\x9c\x32\xad\x7b\x8c\x15\xc1\x60\x62\xc6\xbb\x52\x5c\x72\x48\x71\x84\x31\x00\x11\xd7\x58\x5b\x80\x00\x9a\x71\x48\x5b\x8c\x68\xa9\x23\x80\x35\x13\xc6\x1a\xc4\x99\xc1\x4a\x30\x6e\x44\xe4\xf3\x88\x8f\xea\x1e\x91\x61\x06\x8e\xa6\xc0\xa0\x43\x26\x98\x00\x95\xa1\x2e\x5b\x66\x30\x0b\x81\xd3\x95\x81\x29\xac\x83\xc0\x97\x1d\x89\x15\xc7\x42\xb4\xae\xf6\x89\x8a\x6b\xa9\x22\x45\xb1\xb1\x01\xb3\x1d\x53\x28\x1a\xec\x65\x25\xb5\x02\xee\x66\x1d\xa3\xfa\x41\x82\x19\x57\x8a\xb3\x1e\x03\xf0\x92\xe5\x5c\xeb\xfe\x6e\xe0\x52\x2d\x2c\x8d\x94\x68\x40\x21\xbc\x9f\x89\x36\x44\x47\x4a\x61\x0d\x33\xb5\xe9\x12\x21\xa8\x02\x88\x2f\xf7\x54\x4a\x48\x8b\x88\xcb\x2e\x06\xf7\xe4\x19\xa0\xdf\x28\x4e\xc0\x68\x7b\x32\xe4\x7f\xaa\x14\x33\x2c\xa2\x54\x63\xaa\x34\x87\xc2\x93\x4a\xcc\xb4\x10\x5c\x47\x10\xf2\xa2\x3d\x9b\x28\xc1\x4a\x29\x4e\x00\xc6\x14\x2a\x0e\x6b\x10\x25\x06\x73\x49\xac\xe5\x11\xd5\x12\xf3\x86\x6f\x87\x6a\x29\xd6\x21\xc0\x66\x1d\x32\x04\x1b\x6b\x72\x02\x85\x84\x1d\xa0\xc6\x38\xb6\x10\xfc\x12\x51\x26\x20\x46\x85\x14\x11\x13\xba\x0d\xfe\x19\x85\xa4\x48\xb4\x66\x3e\xf1\xd2\x76\xae\x84\x34\xce\xac\x08\x49\xba\x55\xee\xd0\x11\xfb\xcc\xc1\x0d\x3f\x03\xe4\x1f\x57\x2f\x8a\x72\x3d\x1d\xec\x9e\x5c\x0f\x19\x35\x58\x58\x9f\x0c\x11\x55\x04\x13\xff\x73\x8a\xfc\x83\xf0\xe1\x88\x46\x88\x9e\xa2\xfd\xf4\x51\x77\xfe\xa8\xbb\xe0\x51\x61\xb0\xaf\xb4\x77\x17\xbe\x09\x82\x46\xf6\x71\x0b\x94\x66\x6e\x5f\x79\x43\x73\xf9\xb8\xf2\x66\xb2\xab\xcc\xc1\xd2\xbb\x5d\x91\xa5\xb9\x9b\xc7\x9b\xe9\xc0\x3f\x2e\xeb\x91\xff\x67\x91\xe6\x2d\xfd\x49\x17\x43\x39\x62\x02\x53\x76\xcd\x34\xb8\x66\x4e\x90\xc2\x54\x21\x89\x0d\x40\x06\x53\x38\x59\x31\x6d\xae\x5f\x33\x6e\xe7\x1a\x73\x68\xae\x80\x3a\x12\xd8\xaa\xe6\xd2\x4f\xf8\xd9\xd7\x02\xf2\x1d\x44\x36\x0c\xf8\x0a\x85\x6b\x44\xf9\x6b\xf0\x9b\x9e\x51\xe3\x19\x73\xff\x5f\x87\xd5\x41\x80\x4f\x07\x5a\x2c\x40\xb2\x5f\x7d\x49\x99\x0d\x90\x82\x46\x8a\x60\x69\x90\x86\x3e\x8a\x5a\x4c\xa1\xcf\x63\xda\x5f\xbe\x66\xc2\x5e\xee\x16\x7d\x7a\xb6\xfb\x49\x33\xf7\x9f\xea\x7d\xba\xac\xdb\xce\xe7\x20\x00\x29\x6f\x20\x14\xa1\xdd\xe5\xe9\x93\x8e\xa8\xc7\x2e\xf4\x43\x3d\xc4\xfc\x21\x68\x3c\x6e\xfe\xbf\x30\xd2\x75\x04\xb4\x3f\x98\x32\x2a\x8c\x51\xbe\x23\x30\x00\x10\x23\xb4\xf6\x1d\x01\x9c\xc7\xdc\x5a\x26\x3c\x6e\x84\x35\xbe\xc8\xb7\x0a\x5b\x6b\xad\xe1\x01\x21\xcc\x70\xcd\xbb\xd4\x4b\xa8\x85\xac\xd5\x70\xe8\x77\xc8\x33\x5f\x39\x59\xe9\xcb\xe5\x3d\x99\x71\x8b\xa9\x26\x86\xed\xb6\x13\xac\x4b\xdc\x4b\x74\xb9\xa7\x52\xc6\x31\x15\x32\x64\xe6\x43\x54\x0a\x47\xbe\x91\x82\x46\x0c\x1b\xc1\xa8\x26\x56\xb8\x11\x15\x3e\x5d\x72\x65\x05\x1c\x7f\xfd\x91\xcb\x46\x1b\x09\x3a\xf6\x87\x66\x20\x83\x24\xc4\x12\x1d\x8d\x28\xd6\x54\x68\x6b\x0d\x73\x23\x22\x11\x89\x20\x58\x08\x63\xd6\xc2\x4d\xc7\x9e\x4d\xf2\x2a\xdd\xbc\xa6\x54\xd3\xcf\x74\x74\x94\x2a\xa8\x0c\x88\x6f\x64\x29\x55\x3e\xeb\x5b\x22\xac\xf2\x99\x5c\x45\x94\x52\x2c\x0c\x9c\x55\x08\x94\x64\xd2\x10\x62\x22\xca\x20\x5e\x19\x94\xa3\x70\x5c\xc1\xed\x25\x24\x66\x7f\xf1\x98\xe7\xee\xa6\x2b\x96\xb6\xe2\x4f\x35\x40\x42\x63\x43\x38\x05\x73\x5a\x81\x49\x38\xe8\x66\x02\x52\xa7\xb5\x86\x02\x59\x62\x1a\xca\x7b\x61\xb0\x15\x56\x4a\x19\xbc\x4f\x42\x01\x25\x2c\x16\x8c\x2a\xb2\xc3\x84\xa7\xce\x24\xc1\x94\x1a\x21\x0c\x60\x42\x63\x13\xc8\x70\x00\x28\x43\x18\xd3\x11\xf3\xe5\xaf\x2f\x1a\x24\xc5\x0c\xca\x58\x28\x7e\xac\xc5\x3c\xd4\x92\x33\xc9\x30\x23\xc6\x2a\x63\x22\x4e\x08\x16\xfe\xa4\x93\x1c\x73\xad\x7d\x33\xc1\x09\x45\x52\x60\x2d\x2c\x51\x5c\xf8\xdb\x19\x34\x55\x82\x69\xc1\x55\x18\xd6\x98\x28\xc1\x35\xb1\x9e\x85\x0a\x7d\x83\xd4\x58\x2b\xca\xbc\x76\x16\x3a\x4e\xce\x34\x9a\x49\x83\x85\x34\x44\x36\xe4\x46\x0a\xa8\x9f\x89\x56\x0c\x0e\x40\x0b\x2d\xdd\x53\xaa\xc6\x1c\xea\x19\xee\x59\xec\xc9\x0a\x9b\x46\xbd\x2e\x55\x62\xbb\xa3\x2a\x03\x3d\x2e\xf3\xa6\x37\x80\x4f\x1a\xa4\xe0\x52\x6a\x06\x93\x39\xb4\x37\x50\x84\x4b\x83\x19\x25\xbe\xae\x86\x60\x32\x8d\x2d\xfa\x54\xd1\x74\x61\xa0\x1e\x37\x9a\x43\x24\x18\x8d\x75\xa8\xc1\x24\x34\x2c\xdc\x0a\x49\x23\x66\x38\xd6\xa1\x8c\xeb\x52\xb5\xc5\xd6\xf7\x87\xb3\x1e\x95\x63\x16\x64\xeb\x8a\xa6\x74\xdb\x14\x49\x8b\x0d\xb3\x4c\x42\xb7\xa5\x28\x56\x4d\xf3\xaa\x28\x16\xc2\x17\x08\xe0\x92\x60\x35\xc5\xb1\xe4\x86\x09\xc2\x7d\xcb\xa7\x42\x81\xa0\x7c\xfd\xc4\x39\xb4\xd5\x42\x63\xc5\x84\xb0\x68\xa6\xa0\xdf\x91\xbe\xeb\x60\x02\xba\x15\x5f\xf0\x6b\x86\x39\xd3\x82\xfa\xc2\x83\x60\xee\xc5\xd5\x0a\x0b\x23\xad\xb6\xcc\x77\x76\xc1\x36\x33\x43\xb0\x12\x42\x0a\x0a\x54\x81\x65\x68\xcb\x0c\xd4\x3b\x92\x4a\x45\x23\xc6\x59\x8b\x6c\x4b\x30\xe5\x44\x4a\xf0\x05\x07\xb6\xa1\xd4\xb2\x02\x5b\x23\xad\x02\x79\x99\xc1\x32\x74\x33\x10\xc2\x5a\x31\x28\xf6\x99\xf6\x8d\x26\x1c\x8a\x44\x43\xc9\x69\x64\x78\xd0\x41\xda\xa7\x00\x94\x63\x4d\x89\x66\x26\xf4\x91\x06\xf6\x43\x94\x11\x2c\x20\xd6\x64\xc4\x18\xd4\xfd\x54\xc0\x69\xc9\xb4\x97\x82\xf9\xfa\xcb\x04\x85\x67\xd0\xdf\x1b\x66\x29\xd4\xfa\x8c\x63\x11\xdc\x06\x6d\x24\x13\x9a\x36\x93\x59\xd3\x19\x0a\x8b\x0d\xa5\x52\xf4\xa8\x97\xd0\x89\x69\x22\xa4\x35\xcf\x92\xa1\x5c\x6b\x1b\xf0\x0e\x59\x12\x48\x8f\x92\x86\xd6\x90\xd0\xf0\xdc\x88\xed\x1e\x45\x68\x82\x09\xb5\x96\x48\xdf\xee\xcb\x26\x7b\x50\x4d\xa1\xc8\xf5\xcf\x38\x04\x36\x01\xc1\xd0\x45\x6a\x66\x0c\x14\x9d\x52\x62\x19\xf2\x01\xd5\x0a\x13\xe6\x1b\xc3\x0e\x75\x46\x75\x53\x54\xf6\xc8\xd4\x10\xdf\x68\x43\x4a\xe9\x30\x36\x14\x1b\x46\x01\xec\x7b\x19\x2e\x01\x49\x5a\x52\x66\x95\x6f\x86\x4c\xe8\xb3\x67\xa0\x28\x34\xc9\x0a\x4a\x5f\xc8\x45\xbe\xd2\xf6\xfd\x82\xa5\xdc\x86\xae\x8e\x86\x84\xd0\xa3\x6a\xcc\x9a\xc6\xa9\x47\x96\x58\xb4\xcd\xc5\x8e\x31\x85\x44\x1a\x22\xa6\x23\x05\xb4\xc0\x3a\x48\x7c\xb9\x17\x19\xfc\xb8\x7b\xdc\x63\x08\x66\x84\x79\x16\xdc\x62\xdd\x3c\x1a\xd8\x9b\x82\x72\x1b\x0c\x26\x04\x83\xea\xdf\x3f\x65\xd8\x9b\x35\x0c\x53\x6c\x8c\x54\xdc\xf6\x79\x10\x4c\x9a\x56\xad\xbb\x21\x38\x95\x71\x0b\x3d\xb5\x60\x3b\x10\x81\xff\x99\x26\x70\xee\x08\x60\x1e\x9e\x93\x74\xa9\x12\xc2\x18\xfa\x80\xcb\x2e\x59\xef\x9e\x3a\x5c\x76\x80\xd8\x21\xcf\x8c\xc1\x92\x32\x62\xe1\x84\xdb\x93\x01\x64\x54\x32\xe8\xdd\xa8\x11\xd8\x86\x67\x54\x5c\x61\xcb\xb8\xef\x7e\x7c\xeb\xdf\x60\x8b\x33\xcc\xa9\xe4\x44\x43\xe8\x50\xcc\x82\x03\x39\xf1\xd1\x2c\x03\x43\xb8\x13\x12\xdb\x10\x56\x33\xb8\x85\x73\x20\x24\x00\x2e\xb1\x94\x60\x4e\x16\x31\xcd\xc0\x93\x86\x6b\x24\x14\x04\xa4\x50\x04\xce\x25\xda\x46\xfa\x4c\x28\xac\xa4\xd2\x4c\xa9\x50\xc3\x34\x93\x35\xa6\x44\x71\x42\x6c\x48\xc6\x61\xd7\x83\x27\x69\xb7\xcd\x19\xcd\x8a\xcd\xdd\xae\xd2\x6b\x4b\xc1\x43\x5f\xa7\x1c\x2e\x3f\x05\x81\x1a\x48\x59\x19\x21\xc6\xfe\xb8\xff\xe9\xce\x1f\x75\x17\xfc\xb9\xfe\xe7\xc3\x06\xc5\x65\x59\xdc\x3c\xee\x81\xb6\x9b\x91\xa7\x3f\x23\xe5\x08\x4e\x11\xc6\xd0\x88\x11\x83\x29\x3b\xed\xf7\x2f\x9d\x25\xeb\xb8\x2e\xd3\xdb\x21\x66\x4c\x50\xee\xbf\xfd\xc1\x14\x4e\x7b\xc4\xb9\xc4\x4a\x23\xaa\x04\xe6\xf2\xf4\x71\xad\x4c\x06\x50\xb6\xac\x47\x10\x64\x54\x23\x41\x19\x26\x74\x35\x62\x06\x1b\xa6\x9b\x5f\x19\x15\x58\x50\x31\x62\x50\xbf\x49\x74\xe8\x0e\x85\xbb\x03\x0d\x07\xe8\xfe\x6d\x71\x93\x1f\xd6\x3e\x29\x6e\xf2\xff\x94\xfe\xa3\xbe\x01\x00\xb3\x96\xff\x77\x1b\x60\x32\x6e\xbf\x0c\x9c\x8c\xab\x6b\x4f\x9b\x84\x77\x91\xc2\xf0\x8a\x86\xf9\xf7\xf7\x65\x9c\x2f\x1d\xfa\x4b\x1a\xa1\xbf\xcc\xcb\xed\xfa\x0a\x9d\x4d\x11\x7e\x59\xba\x38\xf1\xb7\x0f\x0f\x93\x18\xad\x4a\xb7\x98\x0e\x9a\xf7\xe2\xc2\x34\x7c\x99\xe6\x1f\x1f\x1e\x06\x17\x7d\xea\x7b\x77\x5b\x3f\x3c\x4c\xc6\xf1\xc5\xfd\x7d\xba\x40\x39\x70\x46\xe4\xe1\x61\x7c\x7f\xef\xf2\xe4\xe1\xa1\xf9\x15\x44\x0c\x42\x84\x6f\x63\x83\x60\x93\x75\x9c\xe6\xcd\x97\x99\xe9\x35\x9a\x67\x71\x55\x4d\x07\x6b\x57\xc7\x8d\x03\x3c\x19\x3c\xd8\xbc\x19\xb6\xf3\x4b\xb5\x89\xf3\xee\x7c\xff\x12\xce\xe0\x62\x92\xe6\x9b\x6d\x8d\xea\xbb\x8d\x9b\x0e\x6a\x77\x5b\x0f\xd0\x26\x8b\xe7\x6e\xe5\xbf\xf1\xf2\x7d\x5e\xed\xca\x41\xdb\xf3\xf9\xeb\x22\xff\xe8\xee\xb6\x9b\xfd\x17\xc2\x27\x17\x93\x31\xf0\x6f\x4d\x9c\xa4\xd7\xad\x91\xdb\xab\x8e\xb4\x59\x5a\xd5\x69\xbe\x6c\x05\x0e\xef\xee\xc4\x65\x1a\x8f\x12\x57\xcd\xcb\xf4\xca\x25\x57\x77\x4f\x15\xa8\xdb\xb7\x10\xfd\x4d\xb9\x2b\xf1\xeb\xd5\xc5\x64\xdc\xa9\xfe\xbb\xfd\x49\xeb\x99\xbf\x55\x45\x59\x4f\xf3\x78\xed\x92\xb4\xf4\xaf\x51\xfd\xd5\x7f\x77\x3d\x8d\xab\xf9\xa0\x95\xcb\xbf\x77\xe1\xdf\x5b\x08\xdf\x6b\x5f\xf8\x6f\xb1\x9b\xc1\xba\xd8\xec\xbe\x6a\xa6\x6e\xbd\xff\x06\x1a\x4b\xb8\xeb\x7f\xd7\x7d\x9d\xba\x9b\x97\xc5\xed\x74\xe0\xbf\xec\x65\xd8\x32\x46\xad\x40\x0a\x13\x2e\x8d\xb1\x76\x70\x31\xd9\x56\x0e\xf9\xef\xb2\xcf\x82\x84\x5f\xee\xf2\xcd\xc5\x64\xbc\xad\xdc\x45\x80\x65\x57\x84\xf0\xb6\xc4\x7f\x56\x8a\x4e\xdc\xf7\xe5\x18\xc7\x3b\xab\x7e\xc6\xba\x07\xac\xda\xd8\xf2\x6d\xbc\x76\x1d\x26\x7f\xd2\x63\x55\xfa\xe9\x33\x3c\xdf\xa5\x9f\x3e\xc3\xb3\x9d\xdc\xbe\xe3\x31\x78\x6e\x93\x3a\xfd\x9c\xe0\xe1\x15\x5a\x97\xfc\x3b\x1b\x75\x26\x4c\xc6\x3b\xa8\x02\xb5\x0b\xe1\xab\x22\xb9\x3b\x84\xe7\x04\xd6\x27\xdd\xfb\x27\x82\x63\xbc\xd7\xa6\x1f\xda\xcb\x62\xbb\x19\x5c\xfc\xbd\x40\xdb\x4d\x37\x26\xfd\xf6\x5d\x05\x7a\xfc\xff\xba\x4e\xe2\x6a\x75\xfe\x88\xfc\x54\xaf\x3f\x3b\xaf\x33\xa1\xa3\xff\xfd\xfd\x08\x85\x64\x8a\x5f\xe5\x75\x99\xba\x2a\xe4\x39\xaf\x7e\xcb\xc4\x3f\x7a\x3c\xa0\xfb\x73\x26\x01\xa6\xe9\x02\xe1\x37\xd5\xb7\x69\xd9\xf2\x6b\x5e\x40\x69\x03\x25\x04\x47\x1b\x2a\xf4\x0f\x22\x85\x53\x38\x93\x0e\x46\x47\xf3\x6a\x48\x2f\x32\xba\x82\xb8\xac\x72\xff\x25\x32\x30\x25\x11\x67\xfc\xa0\x0c\x69\xb0\xf0\x33\x12\xb4\x87\xc7\x13\x60\x40\x78\x0e\x2e\x1e\x9f\x55\xf8\xc3\x4f\x97\x9d\x43\x0a\x5f\xba\x78\x11\x8e\xa7\x3e\x7a\xba\xe6\x3f\x6c\x72\x00\x42\x12\xd7\xf1\x28\x44\xd2\x60\x44\x0f\xe2\xe5\x89\x99\x1e\xaf\xbb\xbf\xc7\x10\xd7\x20\xd4\x04\xc2\xff\x62\x47\x98\x8c\xfd\xfd\x13\x6e\x1d\x95\x5b\xd1\xbe\x2f\x92\xf7\xe9\xda\xa1\xff\x85\xe2\x45\xed\xca\x57\x9b\x62\xbe\x42\xbd\x2d\x9f\x62\x16\xd2\x80\x7f\xc3\x0b\x2e\xbc\x1c\x2d\x97\x60\xa0\xce\xed\x64\x0c\x73\x9e\x4a\xf2\x58\xaf\x27\x9b\xfc\xdf\xff\xfd\x7f\x3e\x27\xfe\xbf\x19\x4c\x9d\xa5\x93\x71\x27\x9d\x4c\xc6\xfe\x4c\xed\x1f\xc1\x93\x71\x5b\x3a\x4c\xe0\x90\xdd\xd4\x7e\xf8\x3a\x2e\x51\x38\xc5\x5f\x65\x68\x8a\x92\x62\xbe\x5d\xbb\xbc\xc6\x4b\x57\xbf\xca\x1c\x5c\xbe\xbc\x7b\x93\x0c\x9b\x93\xfe\xe4\xf4\x1c\x16\xb5\x0b\xf0\xa2\x98\x6f\xab\x61\x43\xdc\xe6\xf3\x3a\x2d\x72\xd4\x16\x05\xfe\x55\xb3\xb0\xc3\xef\x68\xba\xdb\x05\x5f\xc7\xd9\xd6\xe1\xba\x4c\xd7\xc3\x53\x5c\x17\x97\xc5\x8d\x2b\x67\x71\xe5\x1a\x3e\x7e\x81\xcb\xdc\xba\xea\xca\xf3\xfb\xd6\x95\x77\xef\x5c\xe6\xe6\x75\x51\xbe\xc8\xb2\xe1\x49\x5d\x62\x08\x85\x46\xa4\x23\xbf\x02\x2f\x8a\xf2\x55\x3c\x5f\x0d\x5b\x61\x86\x2e\x6b\xe5\x38\x4a\x17\x68\xf8\xc5\xef\xbb\xdb\x23\x97\x61\xff\xc2\x18\x6e\xde\xfb\x43\x53\x74\x72\x72\xde\x0c\x96\xae\xde\x96\x79\x73\xd7\xd8\x18\x04\x83\x28\xf2\x96\x72\x59\x5f\xa6\xe1\x89\x7f\x5b\xb4\x15\x67\x37\xf9\xe7\x18\x66\x87\x65\x18\xea\xab\x59\xf8\x6b\x85\xcf\x18\xc0\x4b\xda\xac\xc5\x69\x9e\xb8\xdb\x1f\x16\xc3\xdf\x4f\xd1\x17\xd3\x29\x1a\xd1\x3f\xa7\xc0\x83\x07\xe3\x67\xa7\xe6\x45\xee\x4e\x7a\x1a\x3e\x04\x01\x1e\x7a\xee\xcc\x8a\x79\x9c\xa5\x9f\xdc\xb7\x4d\x64\x0c\x5d\x84\xbc\x50\x11\x8a\xcb\x56\x18\x90\xd8\x75\xd5\x43\xd3\xe9\xd4\xbf\xc1\xbe\x48\x73\x97\xec\x64\xee\x9a\xf5\x61\xe7\xed\x04\x2c\xe4\x6e\x10\x6c\x31\x74\x80\xbd\x17\x75\xf3\xd7\x38\xc3\x93\x36\x22\x4f\x4e\x1b\xf3\xc0\x5e\x69\xf5\x36\x7e\x3b\x4c\x4e\x77\x8c\x1f\xb1\xe8\x48\xd2\x35\xea\x93\x65\x87\xfc\x1c\x3e\x1f\x69\x83\x12\xef\x29\x68\x41\xdf\xd5\x65\x9a\x2f\x87\xbf\xfe\x16\xa1\xfb\x24\xbe\x3b\x43\x03\x36\x4a\xd2\x65\x5a\x0f\x22\xb4\x2e\xf2\x7a\xd5\xa3\xdc\xb9\xb8\x3c\x43\x83\x7c\xbb\x76\x65\x3a\x1f\x44\x68\x55\x6c\xcb\xfe\x9a\x34\xdf\xd6\xae\x47\xaa\xdc\xbc\xc8\x93\x0e\xa9\xeb\x19\xb0\x18\x18\xe4\x32\xad\x40\xb0\x17\x65\x19\xdf\xe1\x4d\x59\xd4\x05\x14\xf1\xb8\xca\xd2\xb9\xc3\xf3\x38\xcb\x86\x07\xa2\xb9\x7a\x79\xf7\x3e\x5e\x42\x31\x36\x1c\x00\x93\x41\x63\xd5\x96\xe1\x2e\x82\x1e\xbb\xfd\xf4\xfc\xb8\xdd\x7c\xe9\xea\x0f\x65\xf6\x63\x5c\xc6\x6b\x57\xbb\x12\x62\xbb\x05\xcb\xa3\xa1\x61\xe5\x2f\xbb\xa9\xa0\xfa\x31\x5e\xba\x0f\x3f\x5d\xa2\x29\xba\x49\xf3\xa4\xb8\xc1\xb0\x13\x2c\xc6\x95\x8b\xcb\xf9\x0a\x57\xdb\xab\x2a\x98\x98\x9e\x46\x7e\x5d\xf5\xe1\xa7\xcb\x9f\xa1\x41\xb8\xca\x1c\x64\x85\x96\x07\xae\x36\x59\x5a\x0f\x4f\xfe\x7a\xd2\x4e\xdc\xed\xfc\xd6\xbf\xb9\xed\xfd\x1e\x04\x3f\x5a\x14\x25\x1a\xa6\x68\x8a\xc8\x39\x4a\xd1\x04\xf5\x98\xe2\xcc\xe5\xcb\x7a\x75\x8e\xd2\x6f\xbe\xd9\x81\xa3\xcf\x0d\xf6\xed\x2e\xf9\x35\xfd\xad\xdd\x7f\x7a\xd2\x58\x27\xa0\xac\xbf\xee\x57\xf2\x9b\x0f\x86\xbe\x29\x5a\xe4\xa1\x47\x93\xe9\x6f\xfd\xc8\x41\x7f\x43\x75\xb9\x75\xe8\x0c\x25\x6e\x5e\x24\xee\xc3\x4f\x6f\x66\xc5\x7a\x53\xe4\x2e\xaf\x1f\x6f\x44\x7f\x3b\x7d\x0a\xe4\x87\x7e\x72\x6e\xde\xdc\xf5\x87\x0c\x2c\x3a\xdd\x7b\xc6\x9f\xbf\x68\xfa\xc4\x87\x27\x7e\xe0\xe4\x51\x76\x06\x2c\x1d\x3e\x30\xaa\x97\x77\xb3\x96\x7d\x67\xa3\xf3\xd6\x0b\x43\x60\xe1\x1d\x11\xa1\x60\x76\x9f\x4e\xc3\xda\xbd\x23\xd0\x04\x1d\x72\x0a\x2c\x9e\x6f\xcb\xf2\x75\xe9\x16\x9d\x75\xe0\x0d\x28\x6c\x76\xc1\x3e\x0c\xe5\xc4\xf4\x04\x7a\xca\x93\xd3\x7b\xd4\x98\xdd\xaf\x5f\x2d\xd1\x74\xc7\x05\x97\xce\x77\xbc\xc3\x30\x35\x42\x27\x31\xac\x38\xdf\xa5\xce\xfe\x0e\xb0\x72\xb5\xec\x9f\x0c\x9d\xed\xe2\x3f\xbd\x5b\x1c\x36\x0b\xf2\xfd\x1b\xbb\x1d\x72\x6b\xe9\xe2\x04\x50\xf9\x5d\x9a\x85\xd7\xb0\xa1\x52\xea\x86\xdd\x36\x4f\xbd\xbf\x7e\x3d\x79\x09\x9b\xfe\xc3\x7f\x7e\xef\x3f\xff\xee\x3f\xdf\xfb\xcf\x1f\xfd\xe7\x2b\xff\xf9\x2f\xff\xf9\xcb\xcb\x93\xdf\xf6\x9e\x0f\xf1\xe3\x6f\x6f\x56\x69\x16\xf6\x41\x17\x53\x44\x09\x13\xfb\xc0\x01\xe2\x38\x10\x1b\xd1\xbf\xf9\x26\xed\x66\xfd\x06\xfc\x9b\xb8\xac\xdc\x77\x59\x11\xd7\x41\x60\x5c\x17\xdf\xa5\xb7\xce\xbf\x47\xff\x0d\x3a\x41\x27\xe8\x9b\x20\xf9\xaf\xe9\x6f\x4d\x02\xec\xa9\xdd\x7d\xef\xbc\x9b\x63\xd2\x4f\xee\x79\x70\xee\xf2\x1f\x4c\x1b\x9c\x76\xd3\xc3\x5e\xc5\x90\x22\x80\xcf\xc1\xd4\xb0\xda\xae\xe3\x1c\xf6\x45\xd3\xc3\xb6\xf7\x0e\x4c\xf3\xdc\x95\xaf\xdf\x7f\x7f\xd9\xba\xf7\xe9\x08\x9a\xa2\x1d\xaf\x8e\x77\xc3\x63\xa9\xb6\x4c\x9b\x8c\x43\x6d\x37\x19\x87\x3f\xc8\xfc\x7f\x01\x00\x00\xff\xff\xd1\xe7\x7f\xef\x40\x3c\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xbc\x7b\xeb\x72\xdb\x46\xd6\xe0\x6f\xf2\x29\x7a\x98\x99\x11\x35\x01\x9b\x7d\xbf\x48\xa4\x66\x1d\xc6\xf9\xec\xfa\x14\x67\x2a\xb6\x33\x35\x5f\x2a\x3f\x20\xa2\x29\x62\x0d\x02\x0c\x00\xea\x62\xad\xaa\xf6\x21\xf6\x09\xf7\x49\xb6\x4e\x37\x40\x02\x12\xe5\x24\x5b\x3b\xab\xa4\x4c\xe0\xe0\xf4\xe9\x73\xef\x73\x1a\x8d\xd9\x9f\x26\x13\x34\x9c\x4e\xd1\xa2\xd8\xde\x97\xe9\xf5\xba\x46\x8c\x50\x89\xbe\x8f\xeb\x7a\xed\x6e\xd1\x9b\x22\xab\x51\x9c\x27\xe8\xc3\xda\xa1\x45\x9c\x24\xf7\xe8\xd5\xae\x5e\x17\x65\x35\x9c\x4e\x61\xdc\x65\xba\x74\x79\xe5\x12\xb4\xcb\x13\x57\xa2\x7a\xed\xd0\xab\x6d\xbc\x5c\xbb\xf6\x49\x84\x7e\x72\x65\x95\x16\x39\x62\x98\xa0\x31\x20\x8c\x9a\x47\xa3\xd3\x73\x20\x71\x5f\xec\xd0\x26\xbe\x47\x79\x51\xa3\x5d\xe5\x50\xbd\x4e\x2b\xb4\x4a\x33\x87\xdc\xdd\xd2\x6d\x6b\x94\xe6\x68\x59\x6c\xb6\x59\x1a\xe7\x4b\x87\x6e\xd3\x7a\xed\xe7\x69\xa8\x60\xa0\xf1\xaf\x86\x46\x71\x55\xc7\x69\x8e\x62\xb4\x2c\xb6\xf7\xa8\x58\x75\x11\x51\x5c\x37\x4c\xc3\xdf\xba\xae\xb7\x67\xd3\xe9\xed\xed\x2d\x8e\x3d\xc3\xb8\x28\xaf\xa7\x59\x40\xad\xa6\x97\x6f\x17\xaf\xdf\xbd\x7f\x3d\x61\x98\x34\x83\x3e\xe6\x99\xab\x2a\x54\xba\x5f\x77\x69\xe9\x12\x74\x75\x8f\xe2\xed\x36\x4b\x97\xf1\x55\xe6\x50\x16\xdf\xa2\xa2\x44\xf1\x75\xe9\x5c\x82\xea\x02\x98\xbe\x2d\xd3\x3a\xcd\xaf\x23\x54\x15\xab\xfa\x36\x2e\x1d\x90\x49\xd2\xaa\x2e\xd3\xab\x5d\xdd\xd3\x59\xcb\x62\x5a\xf5\x10\x8a\x1c\xc5\x39\x1a\xbd\x7a\x8f\xde\xbe\x1f\xa1\x6f\x5e\xbd\x7f\xfb\x3e\x02\x22\xff\x7c\xfb\xe1\xcd\x0f\x1f\x3f\xa0\x7f\xbe\xfa\xf1\xc7\x57\xef\x3e\xbc\x7d\xfd\x1e\xfd\xf0\x23\x5a\xfc\xf0\xee\xdb\xb7\x1f\xde\xfe\xf0\xee\x3d\xfa\xe1\x3b\xf4\xea\xdd\xbf\xd0\x7f\xbe\x7d\xf7\x6d\x84\x5c\x5a\xaf\x5d\x89\xdc\xdd\xb6\x04\x09\x8a\x12\xa5\xa0\x4d\x97\x78\xd5\xbd\x77\xae\xc7\xc2\xaa\x08\x2c\x55\x5b\xb7\x4c\x57\xe9\x12\x65\x71\x7e\xbd\x8b\xaf\x1d\xba\x2e\x6e\x5c\x99\xa7\xf9\x35\xda\xba\x72\x93\x56\x60\xd5\x0a\xbc\x03\xc8\x64\xe9\x26\xad\xe3\xda\x83\x9e\xc9\x85\xd1\xf0\xfb\x22\x01\x6a\x01\xe3\x0c\xa1\x57\x49\xbc\xad\x83\xaa\xca\x65\x56\xe4\x0e\x6d\xe2\xf2\xd3\x6e\x8b\x26\x93\x8b\xe1\x70\xf6\xa7\x6f\x7f\x58\x7c\xf8\xd7\x3f\x5e\xa3\x75\xbd\xc9\x2e\x86\xb3\xf0\x33\x98\xad\x5d\x9c\x5c\x0c\x07\x83\x59\x9d\xd6\x99\xbb\x78\x78\x80\x07\x08\xbf\x8b\x37\xee\xf1\x71\x36\x0d\x50\x78\xbe\x71\x75\x8c\x96\xeb\xb8\xac\x5c\x3d\x1f\xed\xea\xd5\xc4\x8c\x0e\x0f\xf2\x78\xe3\xe6\xa3\x9b\xd4\xdd\x6e\x8b\xb2\x1e\xa1\x65\x91\xd7\x2e\xaf\xe7\xa3\xdb\x34\xa9\xd7\xf3\xc4\xdd\xa4\x4b\x37\xf1\x37\x11\x4a\xf3\xb4\x4e\xe3\x6c\x52\x2d\xe3\xcc\xcd\x29\x26\xcf\x08\x5d\x17\xc5\x75\xe6\x3a\x64\xf2\xa2\x2e\xe3\xbc\xca\xe2\xda\x8d\x2e\x86\xb3\xaa\xbe\x07\xb6\xfe\x86\x1e\xd0\x36\x4e\x92\x34\xbf\x3e\x43\xe4\x1c\x24\xbe\x4e\x73\x7f\xf9\x38\xbc\x2a\x92\x7b\xf4\x30\x1c\xac\x8a\xbc\x9e\xac\xe2\x4d\x9a\xdd\x9f\xa1\x2a\xce\xab\x49\xe5\xca\x74\x75\x3e\x1c\xd4\xee\xae\x9e\x94\x0e\x94\xeb\x29\x14\xdb\x3a\xdd\xa4\x9f\x5d\xb5\x75\x2e\x39\x1f\x0e\xae\xe2\xe5\xa7\xeb\xb2\xd8\xe5\xc9\x64\x59\x64\x45\x79\x86\xbe\x5a\xf9\xbf\xf3\xe1\xe3\x30\x06\xda\x2d\x98\x10\xe5\x12\xde\x92\x4c\xdc\xb2\x28\xbd\x61\xce\x50\x5e\xe4\xce\xa3\x9f\xad\xc1\xda\xd1\x70\x4d\x51\x73\xdd\x25\xc0\xa9\x5d\x06\xba\x60\x10\xc0\xfb\xaa\xda\x6d\x36\x71\x79\x1f\x0d\xbf\xda\x6d\xb3\x22\x4e\x00\xbd\x11\x76\x92\xb9\x55\x7d\x86\xe4\x5f\xce\x0f\x20\x9f\x6c\x02\xec\x71\x58\xaf\xcf\x56\x69\x59\xd5\x93\xe5\x3a\xcd\x92\x68\x58\x27\xdd\x7b\xa0\xe4\x4d\x71\x86\xe8\x5f\xce\xd1\xf4\x6f\xa8\x86\xc1\xae\xf4\xbe\xba\x29\xae\x20\x57\xfc\x6d\x1a\xe8\x64\x71\x8f\x4c\x16\xff\x71\x2a\x41\xa4\x2e\xff\x75\xb1\x3d\x43\x4c\x6e\xef\x3a\x02\x5c\x15\x75\x5d\x6c\xce\x10\x0d\xe0\x63\xca\x67\xf0\x9f\x57\x12\xdd\x5b\xb6\x4a\x3f\xbb\x33\xc4\x88\x1f\xe4\x21\xb7\x2e\xa8\x22\x2f\xca\x4d\x9c\x9d\x0f\x07\xb7\xeb\xb4\x76\x93\x6a\x1b\x2f\x1d\x40\x6f\xcb\x78\x7b\x3e\x1c\x80\x09\x56\x59\x71\x3b\xb9\x3b\x43\xeb\x34\x49\x5c\xde\xda\xaf\x7d\x72\x86\x5c\x96\xa5\xdb\x2a\xad\xce\x0f\x96\xb2\xd6\x36\x1c\x3c\xf1\x00\x72\x3e\x1c\xec\x1d\x10\x89\xed\x5d\x8b\x76\xb0\xf6\x33\xef\xf0\x81\x9d\xa5\xb9\xdb\xe3\x3e\x31\xd3\xc1\xa3\x87\x8f\xc3\x0d\xa4\xe2\x87\xe1\x20\x49\xab\x6d\x16\xdf\x9f\xa1\xab\xac\x58\x7e\x82\x27\xd8\xc7\x4e\x5f\x25\x94\x1d\x54\xd2\xba\xff\x4f\xae\x4c\xe2\x3c\x8e\xfa\x71\x70\x55\x94\x89\x2b\x0f\x06\xd8\xde\xa1\xaa\xc8\xd2\x04\x7d\x65\x17\xf0\xdf\xf9\x13\xc3\x51\x72\xdc\x70\x64\x7b\xb7\x67\x66\x92\xd6\x6e\x73\x90\xa0\x75\x4f\xea\x36\x80\xf2\xd5\x2a\xcd\xea\x9e\x4b\x9c\x05\x8d\x35\xbc\xf4\x98\x58\x2c\x16\x7e\x4c\x13\x06\xab\xa2\xdc\xf4\xb4\x90\xe6\xa0\xc1\xc9\x5e\x19\xb5\x5f\x40\x3a\xde\x49\xc8\x5f\x0e\x52\x2e\x8b\x2c\x8b\xb7\x95\x3b\x43\xed\x95\x1f\xe3\x79\x39\xa2\x88\x24\xae\xd6\x2e\x41\x5f\x25\x31\xfc\xe7\x51\x7d\x62\xa9\xcb\x83\x59\x5f\xc8\x13\x6e\x19\x42\x11\xe2\x66\x6f\xfd\x38\x4b\xaf\xf3\x33\x04\x01\x7c\xde\x11\x1e\x74\x87\xc0\x60\x10\x47\x8b\x75\x9c\x5f\xbb\x04\xad\xca\x62\x83\x08\x64\x74\xd6\x86\xe3\xb3\x20\xfa\xa2\x2d\x7a\xee\xa0\xb6\x77\x2f\xc5\x82\xa7\xdc\x75\xe7\xab\x2c\x6e\x74\xb9\x46\xd5\xcd\x35\x3c\xb9\x71\x65\x9d\x2e\xe3\xac\x95\x60\x93\x26\x49\x16\x74\x17\x52\xc1\xd1\x20\xeb\x32\xd0\x84\x44\x9d\x9c\xe5\xf5\x3a\xb8\xf8\x98\x9d\x76\x0c\x65\xc8\x5f\x9e\x21\xf0\xd3\x9e\x93\x10\x1f\xe9\xcd\x4f\x93\xe9\x0e\xc8\xe2\x34\xea\x8f\x16\xa7\x4f\x15\xef\xfd\xf0\x18\x1b\x8d\x98\xdb\xa2\x4a\x43\x6c\xc6\x57\x55\x91\xed\xea\x56\x44\x0c\x2b\x93\x37\x25\xbe\x2e\x76\xdb\x8e\x6b\x87\x64\x4c\xb1\x96\xe0\xdc\x83\xdb\xa2\x4c\x26\x57\xa5\x8b\x3f\x9d\x21\xff\x33\x89\xb3\xac\x9b\x6f\x40\x35\xed\x23\x40\x7e\x6a\x95\x6d\xe9\x26\xad\x5d\x70\xba\x2c\xf2\xe7\x61\x24\xb7\x77\xfb\xa7\xb8\x2a\xca\xfa\x0b\x01\xb1\x0f\x03\xcf\xdd\xda\x75\x02\xb1\x23\x6d\xe9\xb2\xb8\x4e\x6f\x1c\xe4\x40\xf0\x2b\xcc\xdc\xe6\xc9\x14\xb8\x2e\xb6\x2f\xa9\x68\x10\x94\x40\xda\xe1\x13\xfa\x8c\x43\x1c\x7c\xf3\x45\x0a\xad\xeb\x86\xa1\x07\x82\x8f\xc3\x55\x51\x3c\x4b\x16\x3e\x5e\x9e\x3b\x79\xc8\x79\x5d\x83\x2f\x5d\x5e\xbb\x12\xc8\xfc\xb7\x8d\x4b\xd2\x18\x8d\x37\xf1\xdd\xa4\xd1\x89\x22\x64\x7b\x07\x3e\x32\xfd\xdb\x00\xaf\xd3\xc4\xb5\xa9\xe3\xa0\xcc\xb0\x80\x0f\x1e\x51\xe9\x36\xc5\x4d\x28\xb0\x3e\x39\xb7\x45\x49\x5c\xbb\x0a\x2a\xca\xc3\x52\x37\x38\xe2\xdb\xad\xfa\xe3\x5d\x5d\x00\x9d\xe1\xa0\xe7\xb2\xfc\x34\x1a\x0e\x8e\x78\xfc\xb1\x75\x7d\x70\xcc\x93\x81\x62\x58\x0e\x9f\xac\x45\x01\xee\xa3\xba\xbb\x8c\x00\xbc\x93\x7e\x07\x1d\x6d\x50\x12\x14\xfa\x38\x7c\x1c\xce\xa6\x4d\x8d\x35\x98\x4d\x9b\x1a\x71\xe6\x13\x5f\x91\x43\x16\x9e\x9f\x04\x12\xe3\xd3\xf3\xba\xb8\xbe\xce\xdc\x78\xe4\x73\xe7\xe8\xf4\x7c\xe9\xb3\xd7\xfb\xf4\xb3\x1b\x9f\x9e\xf8\xc2\x0e\x42\xeb\x26\x34\x2d\xf3\x11\xc5\x74\x84\xee\x36\x59\x5e\xcd\x47\x9d\x9e\xe1\x96\xfb\x7e\x81\x11\x42\xa6\xd5\xcd\x75\x83\x72\x76\x97\xa5\xf9\xa7\x63\x88\xd4\x5a\x3b\xf5\x4f\x47\x28\xf8\xf4\x7c\x44\x46\x28\x94\x9b\x70\xe5\xd9\x9f\x8f\x8e\xb8\x9a\xaf\x36\x07\xb3\xc4\xad\x2a\x7f\x35\xf0\x4d\xdb\x77\x45\x06\x45\xca\x64\xd2\xc0\xae\x51\x9a\xcc\x47\x2b\x0f\x1d\x41\xfb\x94\x4d\xca\x1d\x50\xcc\x8b\xfc\xb3\x2b\x8b\x00\xf3\xb7\x2e\x50\x1c\x0c\x66\xdb\xb8\x5e\xa3\x64\x3e\xfa\x9e\x19\x89\x19\x43\x5c\x63\x29\xd7\x13\x2a\x18\x56\x97\x94\x12\x6c\x11\x79\xc3\x29\xd6\x0b\x2a\x30\x93\x88\x20\x82\xa8\x02\x68\x40\xbd\xd1\x12\xd3\x35\x07\x10\xfb\x09\xae\x97\x64\xc2\x08\x56\x72\x02\xf8\x6a\xe2\x91\x26\x40\x20\x5c\x7e\x6e\xb9\xf8\xea\xbb\xef\x5e\x11\x42\x46\xd3\x17\x39\x51\xdd\x79\xb9\x42\x04\x49\x82\x99\x41\x04\x29\x8d\xb5\xb8\xa1\xd2\x60\xbd\x24\x88\x6a\x2c\x34\xf2\xd3\x21\x18\x21\xfd\xbf\xe1\xf2\x8d\x27\xb6\x04\x14\x01\x2c\x03\x1f\x54\x60\x1e\xae\x3c\xca\x4f\x40\x4d\x2e\xc9\xc4\xd3\x69\xd9\x86\x27\x93\x03\x52\x97\xed\xc5\x2b\x66\x5a\xb6\x67\xd3\xeb\x23\xda\x9f\x54\xeb\xa2\xac\x97\xbb\x1a\x8c\x5a\x16\x9f\x5c\xa3\xf4\xe6\x6e\xd2\xd8\x9c\xf6\x2c\xd2\xb5\x98\xbb\x71\x79\x91\x24\x7b\x2b\x1d\x25\x3e\x81\x15\x7c\x7b\xd4\xd2\xcd\xb8\x97\x06\x56\xeb\x78\xbb\x77\x81\xe7\xaa\x17\x46\xab\x08\xac\x25\x8c\xb2\x84\xa1\x4b\xef\x0d\x94\x09\x6e\xfa\x60\x70\x0f\x46\xb4\x91\x11\x41\x97\x9c\x62\x65\xa9\x92\xcc\x46\x04\x79\xab\x35\x43\x08\x22\x11\x55\xd8\x58\x65\x29\x51\x88\xf4\x68\x90\x88\x52\x86\x95\x50\x44\x53\xa0\xa1\x70\x43\xe3\x05\xb0\x96\x98\x58\xcd\x0d\x91\x68\xd1\x01\x4b\x81\x85\x90\x8a\x10\x83\x38\x61\x58\x49\xc9\x8c\xec\x4e\x74\x5c\xb2\xff\x1a\x79\xfd\xbc\xf7\xfa\x78\xe2\x98\x17\xb3\x29\xe8\xe5\x37\xb4\xa4\x7a\x82\x73\xd5\x93\x1c\x9c\x36\xf2\x4e\xcb\x8d\x54\x06\x91\xc8\x7b\x2e\xb5\x84\x5b\x10\x9d\x31\x85\x85\xa4\x82\x09\xb4\x20\x11\x13\x1c\x5b\x62\x85\xa6\xa8\x43\x83\x49\x83\xa9\xe5\x9c\x19\xd4\x99\xa8\x03\xbd\xec\xb0\xd3\x01\x2f\x3a\x7a\xe8\xd1\xd8\xeb\xac\x33\x5f\x17\x7a\xe0\xa9\xab\xf7\x0e\xe3\x3d\xbd\x1f\x84\xeb\xea\x5d\xa1\xbe\x8e\x5e\xd0\xb3\x8f\xa4\x27\x7a\xde\x47\x54\x57\xe3\x94\x29\x4c\xa5\xa0\x5c\x44\x4c\x12\x2c\xa5\xa5\x46\xa0\x05\x80\x8d\x24\x56\x03\x98\x62\x63\xb8\xd2\x1c\x51\xa6\x31\x27\x44\x0a\x50\x13\xc7\x84\x28\xca\x98\x87\x6a\xcd\x14\x61\x11\x93\x02\xd3\x00\x5d\x50\x66\xb0\x50\x56\x08\x00\x4b\xcc\x5a\x64\x83\x2d\xb5\x84\x82\x4a\x15\xa6\x44\x10\x03\x50\x8b\x15\x37\x9c\x83\x46\x35\x26\x84\x11\x41\xd1\x82\x72\xcf\x91\x55\xcc\x2b\x9a\x33\x25\x39\x45\x14\xf2\x06\x33\x46\x02\xb2\x45\x94\x73\x4c\x09\x21\x52\xfb\xdb\x05\xe5\x02\x0b\xcb\x35\xd7\xcd\x63\x89\x05\x95\x5c\x09\x4f\x43\x4a\x4a\x18\xa2\x5c\x61\x4a\x19\x23\xc2\xcf\xa7\xb4\x94\x7e\x3a\x85\x0d\xb1\x44\x88\x2e\x17\x94\x6b\xcc\xa4\x51\xd4\x7a\x39\xec\x11\xa8\xc0\x52\xb7\x24\x3a\x60\x70\x82\x20\x5e\x17\xca\xb0\x39\x40\x09\xe7\x86\x33\xaf\x63\x21\x35\x15\x3c\x70\xa1\x8d\x92\x4a\x45\x4c\x58\x6c\x89\xa1\x8a\x7b\x8e\xa5\xa2\x5a\x5b\x0f\x25\x5e\x17\x7d\xa8\xc1\x32\x98\xc9\x93\x20\xc6\x6a\x06\x24\x18\x36\x54\x30\xa3\xbc\x26\x8c\x12\x96\xdb\x88\x71\x0d\xf9\x45\x10\xd3\x87\x72\xcc\x34\x17\xca\x6b\xf1\x00\x66\x12\x93\xc0\x5c\x97\x37\xaa\xb1\x6c\x09\x1b\x4c\x0d\x61\x02\xa0\x04\x1b\xa1\x2c\xf7\x24\x2c\xd6\xd6\x68\xca\x23\x46\x04\x66\x8d\xe2\x04\xb8\x93\x65\x5c\x44\xd4\x1a\xac\x60\x3a\x81\xa8\x10\x58\x30\xcb\x99\x89\xa8\xe5\x58\x2b\x90\x0f\x22\x5e\x63\x46\x95\x32\x36\xa2\xc6\x60\xa3\x2c\x37\x06\x51\x49\xb0\xd2\x46\x50\x1a\x51\x23\xb0\x09\x2c\x53\x29\xb0\xe1\xca\x6a\x1e\x51\x43\x5b\x67\x59\xc0\x5a\x66\xad\x94\x5c\x46\x54\x83\xa3\x5a\x69\x19\xa2\x8a\x63\xc5\x14\x15\x36\xa2\x5a\xec\xfd\x5b\x19\x2c\x0c\x95\x92\x45\x54\x33\xac\xc0\x63\x21\x18\x34\xc7\x9c\x2b\x2b\x45\x44\x35\xc1\x82\x1b\xad\x15\xa2\xda\x62\x4a\xb9\x35\x3c\x82\x71\x4a\x49\x4e\x14\xa2\x46\x62\x69\xb4\x01\x12\x4a\x63\x2e\xc0\x7c\x68\x41\x2d\xc3\x44\x51\xcd\x00\xac\x30\xa3\x30\x21\x02\x05\x68\x45\xb8\x36\x11\x55\x12\x73\xc1\x8c\xd4\x88\x11\xe9\xb9\xa0\x22\xa2\x4a\x60\x15\x84\x5e\x30\xca\x40\xcb\x54\x7b\x28\x0b\xd6\x63\xd4\x62\x69\x0d\x15\x2a\x02\x91\xac\x85\xf8\x45\x8c\x19\x4c\x15\xf3\x32\x1f\xa0\x97\x4c\x28\x4c\x24\x84\xf8\x8b\x60\x2b\x5b\xa3\x2e\x7a\x60\x8d\x35\x68\x48\x22\x80\x6a\xc9\x04\x07\xa8\xc5\x10\x4d\x44\x20\x70\x3e\xae\x89\xb1\x36\x62\x84\x62\xd2\x64\x11\xc8\x28\x8c\x42\xf4\x45\x0c\x72\x58\x70\x65\x08\x01\xa2\xad\x31\x2a\x62\x84\x63\x19\x12\x03\x44\x11\xd7\x4c\x53\xd9\x85\x2e\x20\x49\x08\xc5\x19\x7f\x82\x6c\xb0\xe4\x94\x69\xdd\x23\xac\x08\x06\x15\x33\xde\xe5\xe2\x92\x43\x8a\x23\x8c\x81\x13\x71\x8d\xb5\x05\x17\x40\x0b\x0e\x69\x8b\x11\x2d\x75\x04\x6e\xcd\x84\xb1\x06\x71\x66\xb0\x12\x8c\x1b\x11\xf9\x3c\xe2\xa3\xba\x07\x64\x98\x81\xa1\x29\x5a\xf4\xc0\x04\x13\x80\x32\xd4\x25\xcb\x0c\x66\x21\x70\xba\x3c\x30\x85\x75\x60\xf8\xb2\xc3\xb1\xe2\x58\x88\xd6\xd4\x3e\x51\x71\x2d\x55\xa4\x28\x36\x36\xf8\x6c\x47\x15\x8a\x06\x7d\x59\x49\xad\x80\xbb\x45\x47\xa9\xfe\x21\xc1\x8c\x2b\xc5\x59\x8f\x00\x58\xc9\x72\xae\x75\x7f\x36\x30\xa9\x16\x96\x46\x4a\x34\x4e\x21\xbc\x9d\x89\x36\x44\x47\x4a\x61\x0d\x98\xda\x74\x81\x10\x54\xc1\x89\x2f\x0f\x50\x4a\x48\xeb\x11\x97\x5d\x1f\x3c\x80\x17\xe0\xfd\x46\x71\x62\x44\x17\x0c\xf9\x9f\x2a\xc5\x0c\x8b\x28\xd5\x98\x2a\xcd\xa1\xf0\xa4\x12\x33\x2d\x04\xd7\x11\x84\xbc\x68\xd7\x26\x4a\xb0\x52\x8a\x13\x70\x63\x0a\x15\x87\x35\x88\x12\x83\xb9\x24\xd6\xf2\x88\x6a\x89\x79\x43\xb7\x03\xb5\x14\xeb\x10\x60\x8b\x0e\x18\x82\x8d\x35\x39\x81\x42\xc2\x0e\xae\xc6\x38\xb6\x10\xfc\x12\x51\x26\x20\x46\x85\x14\x11\x13\xba\x0d\xfe\x05\x85\xa4\x48\xb4\x66\x3e\xf1\xd2\x16\x57\x42\x1a\x67\x56\x84\x24\xdd\x0a\x77\x6c\x89\x7d\x61\xe1\x86\xbf\x11\xf2\x1b\xdc\xb0\xb9\x35\x1f\xed\xf7\xba\xc7\x8c\x1a\x2c\xac\x4f\x86\x88\x2a\x82\x89\xff\x3b\x45\x7e\xeb\x7c\x3c\xa1\x11\xa2\xa7\xe8\x80\x3e\xe9\xe2\x4f\xba\x03\x9e\x14\x06\x87\x4a\x7b\x7a\xdd\x6d\x82\xa0\x91\x7d\xda\x02\xa5\x99\x3b\x54\xde\xd0\x5c\x3e\xad\xbc\x99\xec\x0a\x73\xb4\xf4\x6e\x47\xc0\xc6\xc4\x32\xde\xce\x47\x7e\xbb\xac\x07\xfe\xef\x45\x9a\xb7\xf0\x67\x5d\x0c\xe5\x88\x09\x4c\xd9\x0d\xd3\x60\x9a\x25\x41\x0a\x53\x85\x24\x36\xe0\x32\x98\xc2\xca\x8a\x69\x73\xfd\x86\x71\xbb\xd4\x98\x23\x12\xa0\x13\x81\xad\x6a\x2e\x3d\xc2\x4f\xbe\x16\x90\xef\x21\xb2\xe1\x81\xaf\x50\xb8\x46\x94\xbf\x01\xbb\xe9\x05\x35\x9e\x30\xf7\xff\xeb\x30\x3a\x30\xf0\xf9\x48\x8b\x05\x9e\xec\x47\x5f\x52\x66\x83\x4b\x41\x23\x45\xb0\x34\x48\x43\x1f\x45\x2d\xa6\xd0\xe7\x31\xed\x2f\xdf\x30\x61\x2f\xf7\x83\x3e\xbf\xd8\xfd\xa4\x99\xfb\x77\xf5\x3e\x5d\xd2\x6d\xe7\x73\xd4\x01\x29\x6f\x5c\x28\x42\xfb\xcb\xd3\x67\x1d\x51\x8f\x5c\xe8\x87\x7a\x1e\xf3\x9b\x4e\xe3\xfd\xe6\xff\xca\x47\xba\x86\x80\xf6\x07\x53\x46\x85\x31\xca\x77\x04\x06\x1c\xc4\x08\xad\x7d\x47\x00\xeb\x31\xb7\x96\x09\xef\x37\xc2\x1a\x5f\xe4\x5b\x85\xad\xb5\xd6\xf0\xe0\x21\xcc\x70\xcd\xbb\xd0\x4b\xa8\x85\xac\xd5\xd6\xf4\xc0\x0b\x5f\x39\x59\xe9\xcb\xe5\x03\x98\x71\x8b\xa9\x26\x86\xed\xa7\x13\xac\x0b\x3c\x70\x74\x79\x80\x52\xc6\x31\x15\x32\x64\xe6\x63\x50\x0a\x4b\xbe\x91\x82\x46\x0c\x1b\xc1\xa8\x26\x56\xb8\x09\x15\x3e\x5d\x72\x65\x05\xe3\x4f\x9f\x5c\x36\xd2\x48\xad\x9e\x3e\x5a\x00\x0f\x92\x10\x4b\x74\x34\xa1\x58\x53\xa1\xad\x35\xcc\x4d\x88\x44\x24\x82\x60\x21\x8c\x59\x2b\x51\x4f\x9f\x4d\xf2\x2a\xdd\xb2\xa6\x54\xd3\x2f\x74\x74\x94\x2a\xa8\x0c\x88\x6f\x64\x29\x55\x3e\xeb\x5b\x22\xac\xf2\x99\x5c\x45\x94\x52\x2c\x0c\xac\x55\x08\x84\x64\xd2\x10\x62\x22\xca\x20\x5e\x19\x94\xa3\xb0\x5c\xc1\xed\x25\x24\x66\x7f\xf1\x94\xe6\xfe\xa6\xcb\x96\xb6\xe2\x77\x35\x40\x42\x63\x43\x38\x05\x75\x5a\x81\x49\x58\xe8\x16\x02\x52\xa7\xb5\x86\x02\x58\x62\x1a\xca\x7b\x61\xb0\x15\x56\x4a\x19\xac\x4f\x42\x01\x25\x2c\x16\x8c\x2a\xb2\xf7\x09\x0f\x5d\x48\x82\x29\x35\x42\x18\xf0\x09\x8d\x4d\x00\xc3\x02\xa0\x0c\x61\x4c\x47\xcc\x97\xbf\xbe\x68\x90\x14\x33\x28\x63\xa1\xf8\xb1\x16\xf3\x50\x4b\x2e\x24\xc3\x8c\x18\xab\x8c\x89\x38\x21\x58\xf8\x95\x4e\x72\xcc\xb5\xf6\xcd\x04\x27\x14\x49\x81\xb5\xb0\x44\x71\xe1\x6f\x17\xd0\x54\x09\xa6\x05\x57\xe1\xb1\xc6\x44\x09\xae\x89\xf5\x24\x54\xe8\x1b\xa4\xc6\x5a\x51\xe6\xa5\xb3\xd0\x71\x72\xa6\xd1\x42\x1a\x2c\xa4\x21\x92\xd2\x2e\x17\x50\x3f\x13\xad\x18\x2c\x80\x16\x5a\xba\xe7\x50\x8d\x39\xd4\x33\x1c\x2d\x7a\x60\x85\x4d\x23\x5e\x17\x2a\xb1\xdd\x43\x95\x81\x1e\x97\x79\xd5\x1b\xf0\x4f\x1a\xb8\xe0\x52\x6a\x06\xc8\x1c\xcb\x50\x84\x4b\x83\x19\x25\xbe\xae\x86\x60\x32\x8d\x2e\xfa\x50\xd1\x74\x61\x20\x1e\x37\x9a\x43\x24\x18\xd8\x83\xf2\x35\x98\x84\x86\x85\x5b\x21\x69\xc4\x0c\xc7\x3a\x94\x71\x5d\xa8\xb6\xd8\xfa\xfe\x70\xd1\x83\x72\xcc\x02\x6f\x5d\xd6\x94\x6e\x9b\x22\x69\xb1\x61\x96\x49\xe8\xb6\x14\xc5\xaa\x69\x5e\x15\xc5\x42\xf8\x02\x01\x4c\x12\xb4\xa6\x38\x96\xdc\x30\x41\xb8\x6f\xf9\x54\x28\x10\x94\xaf\x9f\x38\x87\xb6\x5a\x68\xac\x98\x10\x16\x2d\x14\xf4\x3b\xd2\x77\x1d\xb0\x9f\xa0\x42\xc1\xaf\x19\xe6\x4c\x0b\x0a\x74\x05\xc1\xdc\xb3\xab\x15\x16\x46\x5a\x6d\x99\xef\xec\x82\x6e\x16\x86\x60\x25\x84\x14\x14\xa0\x02\xcb\xd0\x96\xc1\xee\x81\x96\x54\x2a\x1a\x31\xce\x5a\xcf\xb6\x04\x53\x4e\xa4\x04\x5b\x70\x20\x1b\x4a\x2d\x2b\xb0\x35\xd2\x2a\xe0\x97\x19\x2c\x43\x37\x03\x21\xac\x15\x83\x62\x9f\x69\xdf\x68\xc2\xa2\x48\x34\x94\x9c\x46\x86\x8d\x0e\xd2\xee\x02\x50\x8e\x35\x25\x9a\x99\xd0\x47\x1a\x98\x0f\x51\x46\xb0\x80\x58\x93\x11\x63\x50\xf7\x53\x01\xab\x25\xd3\x9e\x0b\xe6\xeb\x2f\x13\x04\x5e\x40\x7f\x6f\x98\xa5\x50\xeb\x33\x8e\x45\x30\x1b\xb4\x91\x4c\x68\xda\x20\xb3\xa6\x33\x14\x16\x1b\x4a\xa5\xe8\x41\x2f\xa1\x13\xd3\x44\x48\x6b\x5e\x04\x0b\xdb\x9a\x73\xd1\x05\x4b\x02\xe9\x51\xd2\xd0\x1a\x12\x1a\xf6\x8d\xd8\x7e\x2b\x42\x13\x4c\xa8\xb5\x44\xfa\x76\x5f\x36\xd9\x83\x6a\x0a\x45\xae\xdf\xe3\x10\xd8\x04\x0f\x86\x2e\x12\xb6\x2d\xa0\xe8\x94\x12\xcb\x90\x0f\xa8\x56\x98\x30\xdf\x18\x76\xa0\x0b\xaa\x9b\xa2\xb2\x07\xa6\x86\xf8\x46\xdb\x88\x1e\x61\x43\xb1\x61\x94\xf1\x2e\x0f\x97\xe0\x49\x5a\x52\x66\x95\x6f\x86\x4c\xe8\xb3\x17\x20\x28\x34\xc9\x0a\x4a\x5f\xc8\x45\xbe\xd2\xf6\xfd\x82\xa5\xdc\x86\xae\x8e\x86\x84\xd0\x83\x6a\xcc\x9a\xc6\xa9\x07\x96\x58\xb4\xcd\xc5\x9e\x30\x85\x44\x1a\x22\xa6\xc3\x05\xb4\xc0\x3a\x70\x7c\x79\x60\x19\xec\xb8\xdf\xee\x31\x04\x76\x09\x3c\x09\xd8\x3b\x08\x2c\x77\x54\x41\xb9\x0d\x0a\x13\x82\x41\xf5\xef\x77\x19\x0e\x6a\x0d\x8f\x61\x7b\x41\x2a\x6e\xfb\x34\x08\x26\x4d\xab\xd6\x9d\x10\x8c\xca\xb8\x85\x9e\x5a\xb0\xbd\x13\x81\xfd\x99\x26\xb0\xee\x08\x20\x1e\xf6\x49\xba\x50\x89\x65\xe8\x03\x2e\xbb\x60\xbd\xdf\x75\xb8\xec\x38\x62\x07\xbc\x30\x06\x4b\xca\x88\x25\xa6\x0b\x06\x27\xa3\x92\x41\xef\x06\xfb\x19\x36\xec\x51\x71\xd8\xf8\xe7\xbe\xfb\xf1\xad\x7f\xe3\x5b\x9c\x61\x4e\x25\x27\x1a\x42\x87\x62\x16\x0c\xc8\x89\x8f\x66\x19\x08\xc2\x9d\x90\xd8\x86\xb0\x5a\xc0\x2d\xac\x03\x21\x01\x70\x89\xa5\x04\x75\xb2\x88\x69\x06\x96\x34\x5c\x23\xa1\x20\x20\x61\x57\x38\x62\x96\xb6\x91\xbe\x10\x0a\x2b\xa9\x34\x53\x2a\xd4\x30\x0d\xb2\x86\x5d\x3e\x4e\x88\xf5\x50\x13\x66\x3d\xba\x92\x76\xdb\x9c\x09\x1c\x84\xdb\x57\x7a\x6d\x29\x78\xec\x75\xca\xf1\xf2\x53\x10\xa8\x81\x94\x95\x11\x62\xec\xb7\xfb\x9f\x2e\xfe\xa4\x3b\xe0\xf7\xf5\x3f\x1f\xb7\x28\x2e\xcb\xe2\xf6\x69\x0f\xb4\xdb\x4e\x3c\xfc\x05\x2e\x27\xb0\x8a\x30\x86\x26\x8c\x18\x4c\xd9\x69\xbf\x7f\xe9\x0c\xd9\xc4\x75\x99\xde\x8d\x61\x2f\x97\x72\xff\xf6\x07\x53\x58\xed\x11\xe7\x12\xc3\xe6\x90\x12\x98\xcb\xd3\xa7\xb5\x32\x19\x41\xd9\xb2\x99\x40\x90\x51\x8d\x04\x65\x98\xd0\xf5\x84\x19\x6c\x98\x6e\x7e\x32\x2a\xb0\xa0\x62\xc2\xa0\x7e\x93\xe8\xd8\x1d\x0a\x77\x47\x1a\x0e\x90\xfd\xdb\xe2\x36\x3f\x2e\x7d\x52\xdc\xe6\xff\x2e\xf9\x27\x7d\x05\x80\xcf\x5a\xfe\xff\x5b\x01\xb3\x69\xfb\x32\x70\x06\x2f\x1f\xfd\x45\x38\xb4\x14\x1e\xaf\x69\xc0\x7f\x78\x28\xe1\xdd\x26\xfa\x73\x1a\xa1\x3f\x2f\xcb\xdd\xe6\x0a\x9d\xcd\x11\xfe\xa6\x74\x71\xe2\x6f\x1f\x1f\x67\x31\x5a\x97\x6e\x35\x1f\x35\x27\xe9\x02\x1a\xbe\x4c\xf3\x4f\x8f\x8f\xa3\x8b\x3e\xf4\x83\xbb\xab\xe1\x94\x5d\x7c\xf1\xf0\x90\xae\x50\x0e\x94\x11\x79\x7c\x9c\x3e\x3c\xb8\x3c\x79\x7c\x6c\x7e\x02\x8b\x81\x89\xd9\xf4\xc0\xd8\x0c\x0e\x04\x35\x2f\x33\xd3\x1b\xb4\xcc\xe2\xaa\x9a\x8f\xe0\xf4\x4d\x63\x00\x0f\x06\x0b\x36\x67\xc9\xf6\x76\xa9\xb6\x71\xde\xc5\xf7\xa7\x75\x46\x17\xb3\x34\xdf\xee\x6a\x54\xdf\x6f\xdd\x7c\x04\xef\x9a\x47\x68\x9b\xc5\x4b\xb7\xf6\x6f\xbc\x7c\x9f\x57\xc3\xdb\xd0\x34\x39\x5c\x17\xf9\x27\x77\xbf\xdb\x1e\x5e\x08\x9f\x5c\xcc\xa6\x40\xbf\x99\xeb\xe1\x61\x82\xd2\x15\xc2\xe0\x5e\xf0\xe6\x18\xde\xb0\x3f\x3e\x7e\x99\x8f\x16\x17\xc5\x15\xda\x2b\xf4\xef\x49\x03\x9d\x7f\x4e\xb7\xa3\x8b\xcf\xe9\x16\x14\x87\x8a\xf2\x18\x4a\x1d\x97\xf8\xfa\xf3\xe8\x22\xfc\x02\xe2\x33\xa6\xf6\xaa\x05\xf3\xa7\x37\x17\xc3\xde\x55\xcb\xf6\x3f\xcb\xb4\x3e\xb0\xfc\x5b\x7a\x0e\x47\x94\xf6\x6a\x06\xa7\x47\x1b\x57\xaf\x8b\xc4\xbf\x89\xae\x47\xc8\xe5\xcb\xa0\xde\xcd\x2e\xab\xd3\x6d\x5c\xd6\x53\xc0\x9a\x24\x71\x1d\x8f\x8e\xa8\xa2\xed\x5d\xba\x96\x09\x3b\x32\xe1\xa4\x64\xb8\x0e\xc4\x32\xb7\x3f\x4d\x7b\x74\x5c\xb5\xbb\xda\xa4\xf5\x08\xdd\xc4\xd9\xce\xcd\x47\x1f\xfb\xcc\x7a\x3e\xbe\xc0\xf9\xef\xe3\x2d\x9c\xa4\x6b\xb9\x8b\x97\xf0\xee\x7d\x3f\xe3\xe6\x53\x92\x96\xc7\xc7\x05\x6f\x0b\xa3\xe0\xdf\x27\x9e\x97\xbb\x5b\xd4\xbe\x8b\xff\x23\x32\x2e\x4a\x17\xd7\xae\x1d\x7a\x44\xd4\x97\x6c\x7f\x08\xbc\x8e\xc9\xb3\xb4\x82\x03\xc8\xad\xd5\xbd\x63\xa0\xb8\x4c\xe3\x49\xe2\xaa\x65\x99\x5e\xb9\xe4\xea\xfe\x79\xb4\xd5\xed\x21\x5b\x7f\x53\xee\xf9\xae\xd7\x17\xb3\x69\xa7\x55\xed\x36\xd3\x7b\x97\x86\xb3\x33\x73\xd0\x48\x92\x96\xfe\x70\xe0\x5f\xfd\x41\x8b\x79\x5c\x2d\xf7\x36\xf1\x87\x84\x00\x11\xf9\x67\xa3\x0b\x7f\xe4\xa2\x79\x58\x17\xdb\xfd\xb9\x08\xea\x36\x87\xe3\x12\x58\xc2\x5d\xff\x60\x06\x9c\xe0\xfd\xa6\xb8\x9b\x8f\xfc\xc9\x04\x86\x2d\x63\xd4\x0a\xa4\x30\xe1\xd2\x18\x6b\x47\x17\x33\x38\x52\xee\x0f\x5e\x9c\x05\x0e\xbf\xda\x2f\x8e\x17\xb3\xe9\xae\x72\x17\x21\x87\x76\x59\x08\x47\x7b\xfe\xbd\x5c\x74\x16\xa9\x3e\x1f\xd3\x78\xaf\xd5\x2f\x68\xf7\x88\x56\x1b\x5d\xc2\x51\xe8\x0e\x91\xdf\x69\x31\x38\x8f\xf4\x32\x4d\x38\x1d\xf3\x32\xcd\x16\xb9\x3d\x90\x34\x7a\x69\x92\x3a\xfd\x12\xe3\xe1\x84\xb8\x4b\xfe\xc8\x44\x1d\x84\xd9\x74\xef\xaa\xb3\x69\xdf\x85\xe1\x20\xd0\x31\x7f\x4e\x60\x7c\xd2\xbd\x7f\xc6\x38\xc6\x07\x69\xfa\xf9\x1f\x4e\xd5\x8d\x2e\xfe\xa3\x40\xbb\x6d\x2f\x57\x0f\x06\x7d\x01\x7a\xf4\xff\xba\x81\x03\x9a\xe7\x4f\xc0\xcf\xe5\xfa\xbd\x78\x1d\x84\x8e\xfc\x90\x10\xc2\xca\x8f\x5f\xe7\x75\x99\xba\x6a\xbf\x82\xd5\x65\x4b\xc4\x67\xe2\x23\xb2\xbf\xa4\x92\x76\x85\x79\x5b\x7d\x9b\x96\x2d\xbd\xe6\xb4\x54\x1b\x28\x58\x76\x43\x85\xfe\x46\xa4\x70\x0a\x05\xd4\xd1\xe8\x68\x13\x60\x37\x32\xba\x8c\xb8\xac\x72\xff\x4f\x78\x80\x57\xb8\x9c\xf1\xa3\x3c\xa4\x99\xfb\x02\x07\x79\xd2\x65\xa0\xe3\x18\x7e\x39\xb8\x78\x5a\x58\xe1\x8f\x3f\x5e\x76\x2a\x2a\x7c\xe9\xe2\x55\xa8\xa5\xfa\xde\xd3\x55\xff\x71\x95\x83\x23\xc0\xf2\x3b\x09\x91\x34\x9a\xd0\xa3\xfe\xf2\x4c\x4d\x4f\xc7\x3d\x3c\x60\x88\x6b\x60\x6a\x06\xe1\x7f\xb1\x07\xcc\xa6\xfe\xfe\x19\xb5\x8e\xc8\x2d\x6b\xdf\x17\xc9\x87\x74\xe3\xd0\xff\x40\xf1\xaa\x76\xe5\xeb\x6d\xb1\x5c\xa3\xde\x94\xcf\x7d\x16\xd2\x00\x70\xe2\xe0\xc2\xf3\xd1\x52\x09\x0a\xea\xdc\xc2\x17\x1d\x1b\x77\xf1\x9b\x72\x3d\x9b\xe4\x7f\xff\xcf\xff\xf5\x3b\xd8\xff\xf3\x93\x7a\xe9\x45\x96\x8f\x1b\x13\x15\xf9\x32\x4b\x97\x9f\xe6\x27\xa5\xab\x77\x65\xde\x9c\xb9\x1c\xc3\x17\x4c\xa7\x27\x17\xdf\xba\xcc\xd5\x2e\x98\xf8\x8f\xf2\xff\x05\xe6\x9f\x04\x7a\xff\xc9\x21\xd5\xcd\xa6\x5e\xb0\x7e\xad\x30\x9b\xb6\x35\xf8\x0c\x0a\x80\x6d\xed\x1f\xdf\xc4\x25\x0a\xe5\xf0\xeb\x0c\xcd\x51\x52\x2c\x77\x1b\x97\xd7\xf8\xda\xd5\xaf\x33\x07\x97\xdf\xdc\xbf\x4d\xc6\x4d\xc9\x7c\x72\x0a\x47\x3b\x07\xed\x00\xbc\x2a\x96\xbb\x6a\xdc\x00\x77\xb9\x2f\xa1\x50\x5b\x5d\xfb\x33\x9b\x61\x86\x5f\xd1\x7c\x3f\x0b\xf6\xf5\x0e\xae\xcb\x74\x33\x3e\xc5\x75\x71\x59\xdc\xba\x72\x11\x57\xae\xa1\xe3\x07\xb8\xcc\x6d\xaa\x2e\x3f\xbf\xee\x5c\x79\xff\xde\x65\x6e\x59\x17\xe5\xab\x2c\x1b\x9f\xd4\x25\x86\x30\x6d\x58\x1a\xf8\x11\x78\x55\x94\xaf\xe3\xe5\x7a\xdc\x32\x33\x76\x59\xcb\xc7\x20\x5d\xa1\xf1\x9f\x7e\xdd\xdf\x0e\x5c\x86\xfd\xc9\x4b\xdc\x1c\xa0\x45\x73\x74\x72\x72\xde\x3c\x0c\x56\x6d\xee\x1a\x1d\x03\x63\x10\xe1\x5e\x53\x2e\xeb\xf3\x34\x3e\xf1\xc7\xae\x5b\x76\xf6\xc8\x3f\xc5\x80\x1d\x86\x61\x28\x1d\x17\xe1\x43\xa1\x2f\x28\xc0\x73\xda\x8c\xc5\x69\x9e\xb8\xbb\x1f\x56\xe3\x5f\x4f\xd1\x9f\xe6\x73\x34\xa1\xbf\x4f\x80\x47\xef\x68\x5f\x44\x85\x17\x70\x27\x3d\x09\x1f\x03\x03\x8f\x3d\x73\x36\x8e\x7d\x50\x64\x2b\x59\x50\xc2\x36\x2e\x5d\x5e\xbf\x2b\x12\xd7\xbd\x3c\xae\x9a\x23\xf2\x9f\x0f\x5b\x81\x97\x45\xbe\x4a\xcb\xcd\xf8\x24\xc4\x0e\x3a\x41\x5f\x87\x69\xbe\x46\x27\x7f\x3f\x39\xdd\x8b\xbd\x72\xf5\x72\x3d\x76\x19\x86\xb0\x8c\xd0\x43\x28\xfd\xcf\xd0\xc9\xb7\xaf\x2f\x5f\x7f\x78\x7d\xf2\x78\x8a\xeb\xb5\xcb\x0f\x2e\x50\xba\x6a\x7b\x50\x1a\x4c\x05\x10\x5c\x7c\x3a\x00\x07\x59\x11\x3e\x4f\xc3\xa5\x83\x4e\x63\x6f\x89\xa7\x7a\x1c\xf8\xa1\x20\xc6\xb8\x99\x26\xce\x5c\x59\x1f\xd0\x1b\x75\x36\x80\xc7\xe1\xc1\x97\xd0\x2a\xce\xe0\x0b\x8e\x67\x0a\x86\xb9\xb3\xf4\xb3\xfb\xb6\x49\x8b\x63\x07\xdf\x9d\x25\xee\x2e\x42\x71\xd9\xf2\x08\x6c\xbb\xae\xfe\xd0\x7c\x3e\xf7\x1f\xe5\xac\xd2\xdc\x25\x7b\x51\xba\x7e\xfb\xb8\x37\x57\x02\x2e\xe8\x6e\x11\x4c\x31\x76\x10\xdc\xaf\xea\xe6\x4b\xc3\xf1\x49\x9b\x8e\x4f\x4e\x3b\xd6\x48\xab\x77\xf1\xbb\x71\x72\x50\xfb\x13\x12\x1d\x4e\xba\x5e\xfb\x6c\xd8\xb1\x40\x0a\xff\x3e\x91\x06\x25\x3e\x14\x60\xb3\xec\x7d\x0d\x1f\xb9\x8d\x7f\xfe\x25\x42\x0f\x09\x1c\x6c\x1f\xb1\x49\x92\x5e\xa7\xf5\x28\x42\x9b\x22\xaf\xd7\x3d\xc8\xbd\x8b\xcb\x33\x34\xca\x77\x1b\x57\xa6\xcb\x51\x84\xd6\xc5\xae\xec\x8f\x49\xf3\x5d\xed\x7a\xa0\xca\x2d\x8b\x3c\xe9\x80\xba\xae\x0f\x1a\x03\x85\x5c\xa6\x15\x30\xf6\xaa\x2c\xe3\x7b\xbc\x2d\x8b\xba\x80\xc6\x0d\x57\xf0\xa5\x28\x5e\xc6\x59\x36\x3e\x92\x2e\xab\x6f\xee\x3f\xc4\xd7\x50\x89\x8f\x47\x40\x64\xd4\x68\xb5\x25\xb8\x4f\x51\x4f\xcd\x7e\x7a\x3e\x6c\x27\xbf\x76\xf5\xc7\x32\xfb\x47\x5c\xc6\x1b\x57\xbb\x12\x92\x67\xeb\x2c\x4f\x1e\x8d\x2b\x7f\xd9\x0d\xcd\xea\x1f\xf1\xb5\xfb\xf8\xe3\x25\x9a\xa3\xdb\x34\x4f\x8a\x5b\xbc\x77\xee\xca\xc5\xe5\x72\x8d\xab\xdd\x55\x15\x54\x4c\xe1\x48\xff\x60\x30\xa8\x3e\xfe\x78\xf9\x13\x74\x87\x57\x99\x83\xb4\xdb\xd2\xc0\xd5\x36\x4b\xeb\xf1\xc9\x5f\x4f\x5a\xc4\xfd\xcc\xef\xfc\x37\x26\xde\xee\x81\xf1\x01\x7c\x4b\x37\x4e\xd1\x1c\xbe\x69\x4c\xd1\x0c\xf5\x88\xe2\xcc\xe5\xd7\xf5\xfa\x1c\xa5\x5f\x7f\xbd\x77\x8e\x3e\x35\x34\xef\x0f\xf9\x39\xfd\xa5\x9d\x7f\x7e\xd2\x68\x27\x78\x59\x7f\xdc\xcf\xe4\x17\x1f\x0c\x7d\x55\xec\xc3\xee\x09\x32\xfd\xa5\x1f\x39\xe8\xef\xa8\x2e\x77\x0e\x9d\x21\xf8\xda\x2d\x71\x1f\x7f\x7c\xbb\x28\x36\xdb\x22\x77\x79\x3d\x7e\x36\xf6\xf4\xb9\x23\x3f\xf6\x57\xbf\xe6\x1b\x03\xbf\xaa\xc3\xa0\xd3\x83\x65\x7c\xf1\x85\xe6\xcf\x6c\x78\xe2\x1f\x9c\x3c\x59\xfe\xc0\x97\x8e\xaf\xc8\xd5\x37\xf7\x8b\x96\x7c\x67\xa2\xf3\xd6\x0a\x63\x20\xe1\x0d\x11\xa1\xa0\x76\x9f\xaa\xc3\xd8\x83\x21\xd0\x0c\x1d\x33\x0a\x0c\x5e\xee\xca\xf2\x4d\xe9\x56\x9d\x71\x60\x0d\xc8\xb8\xfb\x60\x1f\x87\x5a\x72\x7e\x02\x1b\x0a\x27\xa7\x0f\x68\x38\x38\x8c\x5f\x5f\xa3\xf9\x9e\x0a\x2e\x9d\xdf\x21\x19\x07\xd4\x08\x9d\xc4\x30\xe2\x7c\xbf\x36\xf5\x67\x80\x91\xeb\xeb\xfe\xd2\xdb\x99\x2e\xfe\xdd\xb3\xc5\x61\xb2\xc0\xdf\x1f\x98\xed\x98\x59\x61\xdf\x14\xbc\x12\x8e\x1d\xf9\x0f\x46\xa0\x4c\xee\x86\xdd\x2e\x4f\xbd\xbd\x7e\x3e\xf9\x06\x26\xfd\xcf\xd4\xff\x7c\x1f\x7e\xfe\x23\xfc\x7c\x08\x3f\xff\x08\x3f\xaf\xc3\xcf\x7f\x85\x9f\x7f\xa5\xdf\x9c\xfc\x72\xf0\x80\x10\x47\xfe\xf6\x76\x9d\x66\x61\x3e\x74\x31\x47\x94\x30\x71\x08\x20\x00\x4e\x03\xb0\x11\xe1\xeb\xaf\xd3\x23\x6b\xcf\x16\x3e\x86\xfe\x2e\x2b\xe2\x3a\x30\x8e\xeb\xe2\xbb\xf4\xce\xf9\x2f\x7f\xbe\x46\x27\x7e\xb1\xf5\x12\xfc\x9c\xfe\xd2\x24\xc2\x9e\xf8\xdd\x2f\x65\xba\xb9\x06\x3e\x41\x7e\xd1\x49\xf7\x79\x10\xd0\x46\xa7\xdd\x34\x71\x10\x31\xa4\x0a\xa0\x73\x34\x45\xac\x77\x9b\x38\x87\x79\xd1\xfc\xb8\x0d\xbc\x21\xd3\x3c\x77\xe5\x9b\x0f\xdf\x5f\xb6\x66\x7e\xfe\x04\xcd\xd1\x9e\x56\xc7\xca\x61\x23\xbd\xad\x87\x67\xd3\x50\x44\xcf\xa6\xe1\xa3\xf3\xff\x33\x00\xc9\xef\xee\x59\x24\x41\x00\x00"),
 		},
 	}
 	fs["/"].(*vfsgen۰DirInfo).entries = []os.FileInfo{
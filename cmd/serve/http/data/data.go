@@ -32,6 +32,8 @@ to be used within the template to server pages:
 | .Order      | The current ordering used.  This is changeable via ?order= parameter |
 |             | Order Options: asc,desc (default asc) |
 | .Query      | Currently unused. |
+| .Writable   | Set if files can be uploaded and deleted. |
+| .Downloadable | Set if the directory can be downloaded as an archive. |
 | .Breadcrumb | Allows for creating a relative navigation |
 |-- .Link     | The relative to the root link of the Text. |
 |-- .Text     | The Name of the directory. |
@@ -17,9 +17,11 @@ import (
 	"github.com/rclone/rclone/cmd/serve/http/data"
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fs/config/flags"
 	httplib "github.com/rclone/rclone/lib/http"
 	"github.com/rclone/rclone/lib/http/auth"
 	"github.com/rclone/rclone/lib/http/serve"
+	"github.com/rclone/rclone/lib/http/share"
 	"github.com/rclone/rclone/vfs"
 	"github.com/rclone/rclone/vfs/vfsflags"
 	"github.com/spf13/cobra"
@@ -28,6 +30,8 @@ import (
 // Options required for http server
 type Options struct {
 	data.Options
+	Writable bool          // allow files to be uploaded and deleted and directories made
+	Share    share.Options // options for share links
 }
 
 // DefaultOpt is the default values used for Options
@@ -38,6 +42,8 @@ var Opt = DefaultOpt
 
 func init() {
 	data.AddFlags(Command.Flags(), "", &Opt.Options)
+	flags.BoolVarP(Command.Flags(), &Opt.Writable, "writable", "", Opt.Writable, "Allow files to be uploaded and deleted and directories made")
+	share.AddFlagsPrefix(Command.Flags(), "", &Opt.Share)
 	httplib.AddFlags(Command.Flags())
 	auth.AddFlags(Command.Flags())
 	vfsflags.AddFlags(Command.Flags())
@@ -58,12 +64,88 @@ The server will log errors.  Use ` + "`-v`" + ` to see access logs.
 
 ` + "`--bwlimit`" + ` will be respected for file transfers.  Use ` + "`--stats`" + ` to
 control the stats printing.
-` + httplib.Help + data.Help + auth.Help + vfs.Help,
+
+#### JSON listings
+
+Directory listings are returned as JSON instead of HTML if the request
+has an ` + "`Accept: application/json`" + ` header or a ` + "`?format=json`" + `
+query parameter. If the ` + "`Accept`" + ` header lists ` + "`text/html`" + ` too
+then JSON is only returned if its q weight is at least as high. This
+is a list of the entries in the same format as
+[lsjson](/commands/rclone_lsjson/) with the paths relative to the
+directory. Add ` + "`hash=true`" + ` to the query to
+include all the supported hashes of the files, or ` + "`hash-type=MD5`" + `
+to include just the named ones, for example
+
+    curl 'http://localhost:8080/dir/?format=json&hash-type=MD5'
+
+#### Downloading directories
+
+A directory can be downloaded as an archive by adding
+` + "`?download=zip`, `?download=tar` or `?download=tar.gz`" + ` to its URL,
+for example
+
+    curl -OJ 'http://localhost:8080/dir/?download=zip'
+
+The archive is made as it is sent so nothing is stored on disk. The
+filter flags control which files are included. The default template
+shows links to download each directory.
+
+The archive is read straight from the remote rather than through the
+VFS, so it doesn't use the directory cache or the file cache. This
+means files written to the server which are waiting to be uploaded
+from the ` + "`--vfs-cache-mode`" + ` cache aren't in it until they have been
+uploaded.
+
+#### Uploads
+
+By default the server is read only. If ` + "`--writable`" + ` is set then
+files can be changed too:
+
+- ` + "`PUT`" + ` to a file URL uploads the request body to that file.
+- ` + "`POST`" + ` a ` + "`multipart/form-data`" + ` form to a directory URL uploads
+  each file in the form into that directory.
+- ` + "`POST`" + ` a form with ` + "`action=mkdir`" + ` and ` + "`name=NAME`" + ` to a
+  directory URL makes the directory NAME inside it.
+- ` + "`DELETE`" + ` a file URL, or the URL of an empty directory, removes it.
+
+The default template shows an upload form, a form to make directories
+and delete links so files can be managed from a web browser. Use the
+authentication flags below to control who can do this. Nothing can be
+changed if ` + "`--read-only`" + ` is set.
+
+To stop other web sites using a logged in browser to change files,
+requests to change files which a browser says come from another site,
+using the ` + "`Sec-Fetch-Site`" + ` or ` + "`Origin`" + ` headers, are refused. If the
+server is behind a proxy which changes the ` + "`Host`" + ` header then it
+must be set back to the host the browser used.
+
+For example to upload a file with curl
+
+    curl -T file.txt http://localhost:8080/dir/file.txt
+
+#### Making share links
+
+If ` + "`--share-secret`" + ` is set then a share link to a file or directory
+can be made by adding ` + "`?share`" + ` to its URL. This needs the same
+authentication as the rest of the server and returns the link as
+text. Add ` + "`expire=1h`" + ` to the query to make the link expire,
+` + "`max-downloads=N`" + ` to limit the number of downloads and
+` + "`password=XXX`" + ` to make the link need a password. These default to
+the ` + "`--share-max-downloads` and `--share-password`" + ` flags.
+
+    curl 'http://localhost:8080/dir/file.txt?share&expire=1d'
+
+The host name of the request is used in the link unless
+` + "`--share-url`" + ` is set. This should be set if the server is behind a
+proxy. Files called ` + "`.share`" + ` in the root can't be served if share
+links are enabled.
+` + share.Help + httplib.Help + data.Help + auth.Help + vfs.Help,
 	Run: func(command *cobra.Command, args []string) {
 		cmd.CheckArgs(1, 1, command, args)
 		f := cmd.NewFsSrc(args)
 		cmd.Run(false, true, command, func() error {
-			s := newServer(f, Opt.Template)
+			s := newServer(f, &Opt)
 			router, err := httplib.Router()
 			if err != nil {
 				return err
@@ -79,47 +161,70 @@ control the stats printing.
 type server struct {
 	f            fs.Fs
 	vfs          *vfs.VFS
+	opt          *Options
+	share        *share.Server      // checks share links if they are enabled
 	HTMLTemplate *template.Template // HTML template for web interface
 }
 
-func newServer(f fs.Fs, templatePath string) *server {
-	htmlTemplate, templateErr := data.GetTemplate(templatePath)
+func newServer(f fs.Fs, opt *Options) *server {
+	htmlTemplate, templateErr := data.GetTemplate(opt.Template)
 	if templateErr != nil {
 		log.Fatalf(templateErr.Error())
 	}
 	s := &server{
 		f:            f,
 		vfs:          vfs.New(f, &vfsflags.Opt),
+		opt:          opt,
 		HTMLTemplate: htmlTemplate,
 	}
+	if opt.Share.Enabled() {
+		s.share = share.NewServer(opt.Share.Secret)
+	}
 	return s
 }
 
 func (s *server) Bind(router chi.Router) {
-	if m := auth.Auth(auth.Opt); m != nil {
-		router.Use(m)
-	}
 	router.Use(
 		middleware.SetHeader("Accept-Ranges", "bytes"),
 		middleware.SetHeader("Server", "rclone/"+fs.Version),
 	)
-	router.Get("/*", s.handler)
-	router.Head("/*", s.handler)
+	if s.share != nil {
+		// Share links don't need authentication
+		router.Get(share.Prefix+"*", s.handleShare)
+		router.Head(share.Prefix+"*", s.handleShare)
+	}
+	router.Group(func(router chi.Router) {
+		if m := auth.Auth(auth.Opt); m != nil {
+			router.Use(m)
+		}
+		router.Get("/*", s.handler)
+		router.Head("/*", s.handler)
+		if s.opt.Writable {
+			router := router.With(checkSameOrigin)
+			router.Put("/*", s.handlePut)
+			router.Post("/*", s.handlePost)
+			router.Delete("/*", s.handleDelete)
+		}
+	})
 }
 
 // handler reads incoming requests and dispatches them
 func (s *server) handler(w http.ResponseWriter, r *http.Request) {
 	isDir := strings.HasSuffix(r.URL.Path, "/")
 	remote := strings.Trim(r.URL.Path, "/")
-	if isDir {
-		s.serveDir(w, r, remote)
+	if _, found := r.URL.Query()["share"]; found && s.share != nil {
+		s.makeShareLink(w, r, remote)
+	} else if isDir {
+		s.serveDir(w, r, remote, s.opt.Writable && !s.vfs.Opt.ReadOnly)
 	} else {
 		s.serveFile(w, r, remote)
 	}
 }
 
 // serveDir serves a directory index at dirRemote
-func (s *server) serveDir(w http.ResponseWriter, r *http.Request, dirRemote string) {
+//
+// If writable is set the index has the forms to change the directory.
+func (s *server) serveDir(w http.ResponseWriter, r *http.Request, dirRemote string, writable bool) {
 	// List the directory
 	node, err := s.vfs.Stat(dirRemote)
 	if err == vfs.ENOENT {
@@ -134,6 +239,19 @@ func (s *server) serveDir(w http.ResponseWriter, r *http.Request, dirRemote stri
 		return
 	}
 	dir := node.(*vfs.Dir)
+
+	// Download the directory as an archive if requested
+	format, err := serve.ArchiveFormat(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if format != "" {
+		// This reads the remote directly, not through the VFS
+		serve.Archive(w, r, s.f, dirRemote, format)
+		return
+	}
+
 	dirEntries, err := dir.ReadDirAll()
 	if err != nil {
 		serve.Error(dirRemote, w, "Failed to list directory", err)
@@ -142,11 +260,14 @@ func (s *server) serveDir(w http.ResponseWriter, r *http.Request, dirRemote stri
 
 	// Make the entries for display
 	directory := serve.NewDirectory(dirRemote, s.HTMLTemplate)
+	directory.Writable = writable
+	directory.Downloadable = true
 	for _, node := range dirEntries {
+		o, _ := node.DirEntry().(fs.Object)
 		if vfsflags.Opt.NoModTime {
-			directory.AddHTMLEntry(node.Path(), node.IsDir(), node.Size(), time.Time{})
+			directory.AddObjectEntry(node.Path(), node.IsDir(), node.Size(), time.Time{}, o)
 		} else {
-			directory.AddHTMLEntry(node.Path(), node.IsDir(), node.Size(), node.ModTime().UTC())
+			directory.AddObjectEntry(node.Path(), node.IsDir(), node.Size(), node.ModTime().UTC(), o)
 		}
 	}
 
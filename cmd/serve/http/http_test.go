@@ -1,19 +1,32 @@
 package http
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/json"
 	"flag"
+	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	_ "github.com/rclone/rclone/backend/local"
+	"github.com/rclone/rclone/cmd/serve/http/data"
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/config/configfile"
 	"github.com/rclone/rclone/fs/filter"
 	httplib "github.com/rclone/rclone/lib/http"
+	"github.com/rclone/rclone/lib/http/auth"
+	"github.com/rclone/rclone/lib/http/share"
+	"github.com/rclone/rclone/lib/readers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -32,7 +45,7 @@ const (
 func startServer(t *testing.T, f fs.Fs) {
 	opt := httplib.DefaultOpt
 	opt.ListenAddr = testBindAddress
-	httpServer = newServer(f, testTemplate)
+	httpServer = newServer(f, &Options{Options: data.Options{Template: testTemplate}})
 	router, err := httplib.Router()
 	if err != nil {
 		t.Fatal(err.Error())
@@ -229,6 +242,312 @@ func TestGET(t *testing.T) {
 	}
 }
 
+func TestWritable(t *testing.T) {
+	f, err := fs.NewFs(context.Background(), t.TempDir())
+	require.NoError(t, err)
+	s := newServer(f, &Options{Writable: true})
+	router := chi.NewRouter()
+	s.Bind(router)
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	do := func(method, target, contentType string, body io.Reader) (int, string) {
+		req, err := http.NewRequest(method, ts.URL+target, body)
+		require.NoError(t, err)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		out, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode, string(out)
+	}
+
+	// The listing shows the upload form
+	status, body := do("GET", "/", "", nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `enctype="multipart/form-data"`)
+
+	// PUT a file, then replace it
+	status, _ = do("PUT", "/file.txt", "", strings.NewReader("hello"))
+	assert.Equal(t, http.StatusCreated, status)
+	status, _ = do("PUT", "/file.txt", "", strings.NewReader("hello world"))
+	assert.Equal(t, http.StatusNoContent, status)
+	status, body = do("GET", "/file.txt", "", nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "hello world", body)
+
+	// Make a directory
+	form := url.Values{"action": {"mkdir"}, "name": {"dir"}}
+	status, _ = do("POST", "/", "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	assert.Equal(t, http.StatusSeeOther, status)
+	status, _ = do("POST", "/", "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	assert.Equal(t, http.StatusConflict, status)
+
+	// Forms posted from other sites are refused
+	for _, header := range []http.Header{
+		{"Origin": {"http://evil.example.com"}},
+		{"Origin": {"null"}},
+		{"Sec-Fetch-Site": {"cross-site"}},
+		{"Sec-Fetch-Site": {"same-site"}, "Origin": {ts.URL}},
+	} {
+		req, err := http.NewRequest("POST", ts.URL+"/", strings.NewReader(url.Values{"action": {"mkdir"}, "name": {"evil"}}.Encode()))
+		require.NoError(t, err)
+		req.Header = header
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, header)
+	}
+	_, err = f.List(context.Background(), "evil")
+	assert.Equal(t, fs.ErrorDirNotFound, err)
+
+	// Forms posted from the server itself are allowed
+	for _, header := range []http.Header{
+		{"Origin": {ts.URL}},
+		{"Sec-Fetch-Site": {"same-origin"}, "Origin": {ts.URL}},
+	} {
+		req, err := http.NewRequest("POST", ts.URL+"/", strings.NewReader(url.Values{"action": {"mkdir"}, "name": {"same"}}.Encode()))
+		require.NoError(t, err)
+		req.Header = header
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusSeeOther, resp.StatusCode, header)
+		status, _ = do("DELETE", "/same/", "", nil)
+		assert.Equal(t, http.StatusNoContent, status)
+	}
+
+	// Upload files into it with a form
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for _, name := range []string{"a.txt", "b.txt"} {
+		w, err := mw.CreateFormFile("file", name)
+		require.NoError(t, err)
+		_, err = io.WriteString(w, "contents of "+name)
+		require.NoError(t, err)
+	}
+	require.NoError(t, mw.Close())
+	status, _ = do("POST", "/dir/", mw.FormDataContentType(), &buf)
+	assert.Equal(t, http.StatusSeeOther, status)
+	status, body = do("GET", "/dir/b.txt", "", nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "contents of b.txt", body)
+
+	// Delete things
+	status, _ = do("DELETE", "/dir/", "", nil)
+	assert.Equal(t, http.StatusConflict, status)
+	for _, name := range []string{"/dir/a.txt", "/dir/b.txt", "/dir/", "/file.txt"} {
+		status, _ = do("DELETE", name, "", nil)
+		assert.Equal(t, http.StatusNoContent, status, name)
+	}
+	status, _ = do("DELETE", "/file.txt", "", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+	entries, err := f.List(context.Background(), "")
+	require.NoError(t, err)
+	assert.Len(t, entries, 0)
+}
+
+func TestWritableAbort(t *testing.T) {
+	dir := t.TempDir()
+	f, err := fs.NewFs(context.Background(), dir)
+	require.NoError(t, err)
+	s := newServer(f, &Options{Writable: true})
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file.txt"), []byte("original"), 0666))
+	check := func() {
+		data, err := ioutil.ReadFile(filepath.Join(dir, "file.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "original", string(data))
+		entries, err := f.List(context.Background(), "")
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, entries)
+	}
+	aborted := io.MultiReader(strings.NewReader("new contents"), readers.ErrorReader{Err: io.ErrUnexpectedEOF})
+
+	// Check an aborted PUT over an existing file keeps it
+	w := httptest.NewRecorder()
+	s.handlePut(w, httptest.NewRequest("PUT", "/file.txt", aborted))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	check()
+
+	// Check an aborted upload in a form over an existing file keeps it
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fw, err := mw.CreateFormFile("file", "file.txt")
+	require.NoError(t, err)
+	_, err = io.WriteString(fw, "new contents which are cut off")
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+	r := httptest.NewRequest("POST", "/", bytes.NewReader(buf.Bytes()[:buf.Len()/2]))
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	w = httptest.NewRecorder()
+	s.handlePost(w, r)
+	assert.NotEqual(t, http.StatusSeeOther, w.Code)
+	check()
+}
+
+func TestJSONListing(t *testing.T) {
+	req, err := http.NewRequest("GET", testURL+"?hash-type=MD5", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	type item struct {
+		Path     string
+		Name     string
+		MimeType string
+		ModTime  time.Time
+		IsDir    bool
+		Hashes   map[string]string
+	}
+	var items []item
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&items))
+	byName := map[string]item{}
+	for _, item := range items {
+		byName[item.Name] = item
+	}
+
+	// Files excluded by the filters aren't listed
+	assert.NotContains(t, byName, "hidden.txt")
+	assert.True(t, byName["three"].IsDir)
+	two := byName[datedObject]
+	assert.Equal(t, datedObject, two.Path)
+	assert.Equal(t, "text/plain; charset=utf-8", two.MimeType)
+	assert.True(t, expectedTime.Equal(two.ModTime), two.ModTime)
+	assert.Equal(t, map[string]string{"md5": "3749f52bb326ae96782b42dc0a97b4c1"}, two.Hashes)
+}
+
+func TestDownloadArchive(t *testing.T) {
+	download := func(target string) (names []string) {
+		resp, err := http.Get(testURL + target)
+		require.NoError(t, err)
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/zip", resp.Header.Get("Content-Type"))
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+		require.NoError(t, err)
+		for _, file := range zr.File {
+			names = append(names, file.Name)
+		}
+		return names
+	}
+
+	names := download("three/?download=zip")
+	assert.ElementsMatch(t, []string{"a.txt", "b.txt"}, names)
+
+	// Files excluded by the filters aren't in the archive
+	names = download("?download=zip")
+	assert.Contains(t, names, "three/a.txt")
+	assert.NotContains(t, names, "hidden.txt")
+	assert.NotContains(t, names, "hidden/file.txt")
+
+	resp, err := http.Head(testURL + "three/?download=zip")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, "attachment; filename=three.zip", resp.Header.Get("Content-Disposition"))
+
+	// Unknown formats are rejected
+	for _, format := range []string{"rar", "bogus"} {
+		resp, err = http.Get(testURL + "three/?download=" + format)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, format)
+		assert.Empty(t, resp.Header.Get("Content-Disposition"), format)
+	}
+}
+
+func TestShareLinks(t *testing.T) {
+	oldAuth := auth.Opt
+	defer func() {
+		auth.Opt = oldAuth
+	}()
+	auth.Opt.BasicUser = "user"
+	auth.Opt.BasicPass = "pass"
+	f, err := fs.NewFs(context.Background(), "testdata/files")
+	require.NoError(t, err)
+	s := newServer(f, &Options{Share: share.Options{Secret: "secret"}})
+	router := chi.NewRouter()
+	s.Bind(router)
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+	do := func(target, user, pass string) (int, string) {
+		if !strings.HasPrefix(target, "http") {
+			target = ts.URL + target
+		}
+		req, err := http.NewRequest("GET", target, nil)
+		require.NoError(t, err)
+		if user != "" {
+			req.SetBasicAuth(user, pass)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		out, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode, strings.TrimSpace(string(out))
+	}
+
+	// Making links needs authentication
+	status, _ := do("/two.txt?share", "", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, link := do("/two.txt?share&max-downloads=1", "user", "pass")
+	require.Equal(t, http.StatusOK, status)
+	assert.True(t, strings.HasPrefix(link, ts.URL+share.Prefix), link)
+	assert.True(t, strings.HasSuffix(link, "/two.txt"), link)
+
+	// Using them doesn't, until the downloads run out
+	status, body := do(link, "", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "0123456789", body)
+	status, _ = do(link, "", "")
+	assert.Equal(t, http.StatusGone, status)
+
+	// Links to directories can be browsed and may need a password
+	status, link = do("/three/?share&password=potato&expire=1h", "user", "pass")
+	require.Equal(t, http.StatusOK, status)
+	assert.True(t, strings.HasSuffix(link, "/"), link)
+	status, _ = do(link+"a.txt", "", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, _ = do(link+"a.txt", "anyone", "potato")
+	assert.Equal(t, http.StatusOK, status)
+	status, body = do(link, "anyone", "potato")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "b.txt")
+	status, _ = do(link+"../two.txt", "anyone", "potato")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	// Bad links are rejected
+	status, _ = do(share.Prefix+"rubbish/two.txt", "", "")
+	assert.Equal(t, http.StatusNotFound, status)
+	l := &share.Link{ID: "id", Fs: "other:", Path: "two.txt"}
+	token, err := l.Sign("secret")
+	require.NoError(t, err)
+	status, _ = do(l.URL(ts.URL, token), "", "")
+	assert.Equal(t, http.StatusNotFound, status)
+	l = &share.Link{ID: "id", Fs: fs.ConfigString(f), Path: "two.txt", Expires: time.Now().Add(-time.Minute).Unix()}
+	token, err = l.Sign("secret")
+	require.NoError(t, err)
+	status, _ = do(l.URL(ts.URL, token), "", "")
+	assert.Equal(t, http.StatusGone, status)
+}
+
 func TestFinalise(t *testing.T) {
 	_ = httplib.Shutdown()
 }
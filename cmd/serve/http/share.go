@@ -0,0 +1,89 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rclone/rclone/fs"
+	httplib "github.com/rclone/rclone/lib/http"
+	"github.com/rclone/rclone/lib/http/serve"
+	"github.com/rclone/rclone/lib/http/share"
+)
+
+// baseURL returns the URL of the root of the server as seen by the
+// client making r
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	u := scheme + "://" + r.Host
+	if base := strings.Trim(httplib.GetOptions().BaseURL, "/"); base != "" {
+		u += "/" + base
+	}
+	return u + "/"
+}
+
+// makeShareLink sends the client a share link for remote
+func (s *server) makeShareLink(w http.ResponseWriter, r *http.Request, remote string) {
+	q := r.URL.Query()
+	opt := s.opt.Share
+	expire := fs.DurationOff
+	if value := q.Get("expire"); value != "" {
+		if err := expire.Set(value); err != nil {
+			http.Error(w, "Bad expire: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if value := q.Get("max-downloads"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			http.Error(w, "Bad max-downloads", http.StatusBadRequest)
+			return
+		}
+		opt.MaxDownloads = n
+	}
+	if _, found := q["password"]; found {
+		opt.Password = q.Get("password")
+	}
+	if opt.URL == "" {
+		opt.URL = baseURL(r)
+	}
+	link, err := opt.MakeLink(r.Context(), s.f, remote, expire)
+	if errors.Is(err, fs.ErrorDirNotFound) || errors.Is(err, fs.ErrorObjectNotFound) {
+		http.Error(w, "File not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		serve.Error(remote, w, "Failed to make share link", err)
+		return
+	}
+	fs.Infof(remote, "%s: Made share link", r.RemoteAddr)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = fmt.Fprintln(w, link)
+}
+
+// handleShare serves the file or directory of a share link
+func (s *server) handleShare(w http.ResponseWriter, r *http.Request) {
+	l, remote, ok := s.share.Check(w, r, strings.TrimPrefix(r.URL.Path, share.Prefix))
+	if !ok {
+		return
+	}
+	remote, ok = l.Rel(fs.ConfigString(s.f), remote)
+	if !ok {
+		fs.Infof(l.Fs, "%s: Share link isn't for this server", r.RemoteAddr)
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	isDir := l.Dir && strings.HasSuffix(r.URL.Path, "/")
+	if r.Method == "GET" && (!isDir || r.URL.Query().Get("download") != "") && !s.share.Download(w, l) {
+		return
+	}
+	if isDir {
+		s.serveDir(w, r, remote, false)
+	} else {
+		s.serveFile(w, r, remote)
+	}
+}
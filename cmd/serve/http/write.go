@@ -0,0 +1,249 @@
+package http
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/lib/http/serve"
+	"github.com/rclone/rclone/lib/random"
+	"github.com/rclone/rclone/vfs"
+)
+
+// writeError reports err from changing remote to the client
+func writeError(remote string, w http.ResponseWriter, text string, err error) {
+	switch err {
+	case vfs.ENOENT:
+		http.Error(w, text+": not found", http.StatusNotFound)
+	case vfs.EEXIST, vfs.ENOTEMPTY:
+		http.Error(w, text+": "+err.Error(), http.StatusConflict)
+	case vfs.EROFS, vfs.EPERM:
+		http.Error(w, text+": "+err.Error(), http.StatusForbidden)
+	default:
+		serve.Error(remote, w, text, err)
+	}
+}
+
+// checkLeaf returns the leaf name from name, or false if it isn't
+// a valid name for a file or directory.
+func checkLeaf(name string) (leaf string, ok bool) {
+	// Some browsers send the full path of the file being uploaded
+	leaf = path.Base(strings.Replace(name, "\\", "/", -1))
+	if leaf == "" || leaf == "." || leaf == ".." || leaf == "/" {
+		return "", false
+	}
+	return leaf, true
+}
+
+// sameOrigin returns false if a browser says r comes from another
+// site, which might be a cross-site request forgery.
+//
+// Requests without the headers browsers add, such as those from curl,
+// are allowed.
+func sameOrigin(r *http.Request) bool {
+	switch r.Header.Get("Sec-Fetch-Site") {
+	case "":
+	case "same-origin", "none":
+		return true
+	default:
+		return false
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// checkSameOrigin is middleware which refuses requests from other
+// sites
+func checkSameOrigin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !sameOrigin(r) {
+			fs.Infof(r.URL.Path, "%s: Refused %s from another site %q", r.RemoteAddr, r.Method, r.Header.Get("Origin"))
+			http.Error(w, "Cross-site request refused", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeFile writes the contents of in to the file at remote,
+// replacing any existing file.
+//
+// An existing file is replaced by writing a temporary file and
+// renaming it over the top when it is complete, if the remote can
+// rename files, so it is kept if the write fails. If the write fails
+// the partially written file is removed unless it was the existing
+// file.
+func (s *server) writeFile(remote string, in io.Reader) (err error) {
+	_, err = s.vfs.Stat(remote)
+	existed := err == nil
+	features := s.vfs.Fs().Features()
+	target := remote
+	if existed && (features.Move != nil || features.Copy != nil) {
+		target = remote + "." + random.String(8) + ".partial"
+	}
+	fd, err := s.vfs.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(fd, in)
+	closeErr := fd.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil && target != remote {
+		err = s.vfs.Rename(target, remote)
+	}
+	if err != nil && (target != remote || !existed) {
+		if removeErr := s.vfs.Remove(target); removeErr != nil {
+			fs.Debugf(target, "Failed to remove partial upload: %v", removeErr)
+		}
+	}
+	return err
+}
+
+// handlePut uploads the request body to a file
+func (s *server) handlePut(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/") {
+		http.Error(w, "Can't upload to a directory", http.StatusMethodNotAllowed)
+		return
+	}
+	remote := strings.Trim(r.URL.Path, "/")
+	_, err := s.vfs.Stat(remote)
+	existed := err == nil
+	err = s.writeFile(remote, r.Body)
+	if err != nil {
+		writeError(remote, w, "Failed to upload file", err)
+		return
+	}
+	fs.Infof(remote, "%s: Uploaded file", r.RemoteAddr)
+	if existed {
+		w.WriteHeader(http.StatusNoContent)
+	} else {
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+// handlePost uploads files in a multipart form to a directory or
+// carries out the action in a form on it.
+func (s *server) handlePost(w http.ResponseWriter, r *http.Request) {
+	dirRemote := strings.Trim(r.URL.Path, "/")
+	node, err := s.vfs.Stat(dirRemote)
+	if err != nil {
+		writeError(dirRemote, w, "Failed to find directory", err)
+		return
+	}
+	if !node.IsDir() {
+		http.Error(w, "Not a directory", http.StatusNotFound)
+		return
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		if !s.upload(w, r, dirRemote) {
+			return
+		}
+	} else {
+		switch action := r.PostFormValue("action"); action {
+		case "mkdir":
+			if !s.mkdir(w, r, dirRemote) {
+				return
+			}
+		default:
+			http.Error(w, "Unknown action "+action, http.StatusBadRequest)
+			return
+		}
+	}
+	// Show the directory again
+	http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+}
+
+// upload the files in the multipart form in r to dirRemote
+//
+// This streams the files so they aren't buffered in memory or on
+// disk. It returns false if it wrote an error to w.
+func (s *server) upload(w http.ResponseWriter, r *http.Request, dirRemote string) bool {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "Bad upload: "+err.Error(), http.StatusBadRequest)
+		return false
+	}
+	files := 0
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			http.Error(w, "Bad upload: "+err.Error(), http.StatusBadRequest)
+			return false
+		}
+		if part.FileName() == "" {
+			// Not a file
+			continue
+		}
+		leaf, ok := checkLeaf(part.FileName())
+		if !ok {
+			http.Error(w, "Bad file name", http.StatusBadRequest)
+			return false
+		}
+		remote := path.Join(dirRemote, leaf)
+		err = s.writeFile(remote, part)
+		if err != nil {
+			writeError(remote, w, "Failed to upload file", err)
+			return false
+		}
+		fs.Infof(remote, "%s: Uploaded file", r.RemoteAddr)
+		files++
+	}
+	if files == 0 {
+		http.Error(w, "No files uploaded", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// mkdir makes the directory named in the form in r in dirRemote
+//
+// It returns false if it wrote an error to w.
+func (s *server) mkdir(w http.ResponseWriter, r *http.Request, dirRemote string) bool {
+	leaf, ok := checkLeaf(r.PostFormValue("name"))
+	if !ok {
+		http.Error(w, "Bad directory name", http.StatusBadRequest)
+		return false
+	}
+	remote := path.Join(dirRemote, leaf)
+	if _, err := s.vfs.Stat(remote); err == nil {
+		writeError(remote, w, "Failed to make directory", vfs.EEXIST)
+		return false
+	}
+	err := s.vfs.Mkdir(remote, 0777)
+	if err != nil {
+		writeError(remote, w, "Failed to make directory", err)
+		return false
+	}
+	fs.Infof(remote, "%s: Made directory", r.RemoteAddr)
+	return true
+}
+
+// handleDelete removes a file or an empty directory
+func (s *server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	remote := strings.Trim(r.URL.Path, "/")
+	if remote == "" {
+		http.Error(w, "Can't delete the root", http.StatusForbidden)
+		return
+	}
+	err := s.vfs.Remove(remote)
+	if err != nil {
+		writeError(remote, w, "Failed to delete", err)
+		return
+	}
+	fs.Infof(remote, "%s: Deleted", r.RemoteAddr)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -125,6 +125,7 @@ type Options struct {
 	BasicUser          string        // single username for basic auth if not using Htpasswd
 	BasicPass          string        // password for BasicUser
 	Auth               AuthFn        `json:"-"` // custom Auth (not set by command line flags)
+	PublicPrefix       string        `json:"-"` // URL path prefix which doesn't need auth (not set by command line flags)
 	Template           string        // User specified template
 }
 
@@ -174,6 +175,14 @@ func (s *Server) singleUserProvider(user, realm string) string {
 	return ""
 }
 
+// isPublic returns true if r is for a path which doesn't need auth
+func (s *Server) isPublic(r *http.Request) bool {
+	if s.Opt.PublicPrefix == "" || (r.Method != "GET" && r.Method != "HEAD") {
+		return false
+	}
+	return strings.HasPrefix(r.URL.Path, s.Opt.BaseURL+s.Opt.PublicPrefix)
+}
+
 // parseAuthorization parses the Authorization header into user, pass
 // it returns a boolean as to whether the parse was successful
 func parseAuthorization(r *http.Request) (user, pass string, ok bool) {
@@ -226,8 +235,8 @@ func NewServer(handler http.Handler, opt *Options) *Server {
 		}
 		oldHandler := handler
 		handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// No auth wanted for OPTIONS method
-			if r.Method == "OPTIONS" {
+			// No auth wanted for OPTIONS method or public paths
+			if r.Method == "OPTIONS" || s.isPublic(r) {
 				oldHandler.ServeHTTP(w, r)
 				return
 			}
@@ -0,0 +1,196 @@
+//go:build !plan9
+// +build !plan9
+
+package nfs
+
+// NFS has no open or close calls so we keep the VFS handles open
+// between READ and WRITE calls and close them when they have been
+// idle for a while, when the client COMMITs or when the file is
+// removed or renamed.
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/vfs"
+	"github.com/rclone/rclone/vfs/vfscommon"
+)
+
+const (
+	openFileIdleTime  = 5 * time.Second // close handles unused for this long
+	openFileCheckTime = 1 * time.Second // how often to look for idle handles
+)
+
+// openFileKey identifies an open file
+type openFileKey struct {
+	entry handleEntry
+	write bool // set if the handle was opened for writing
+}
+
+// openFile is a VFS handle being kept open
+type openFile struct {
+	handle   vfs.Handle
+	users    int       // number of calls using the handle
+	lastUsed time.Time // when the handle was last released
+}
+
+// openFiles is a cache of open VFS handles
+type openFiles struct {
+	mu    sync.Mutex
+	files map[openFileKey]*openFile
+	timer *time.Timer // to close idle handles or nil if not running
+}
+
+// newOpenFiles makes a new open file cache
+func newOpenFiles() *openFiles {
+	return &openFiles{
+		files: make(map[openFileKey]*openFile),
+	}
+}
+
+// readWrite returns true if the VFS can use a single handle for
+// reading and writing.
+func readWrite(VFS *vfs.VFS) bool {
+	return VFS.Opt.CacheMode >= vfscommon.CacheModeWrites && !VFS.Opt.ReadOnly
+}
+
+// openFlags returns the key and the flags to open a file with
+func openFlags(VFS *vfs.VFS, e handleEntry, write bool) (key openFileKey, flags int) {
+	switch {
+	case readWrite(VFS):
+		return openFileKey{entry: e, write: true}, os.O_RDWR
+	case write:
+		return openFileKey{entry: e, write: true}, os.O_WRONLY
+	default:
+		return openFileKey{entry: e}, os.O_RDONLY
+	}
+}
+
+// get returns an open handle for the file, opening it with extra
+// flags if necessary. The handle must be returned with put.
+func (o *openFiles) get(VFS *vfs.VFS, e handleEntry, write bool, extraFlags int) (key openFileKey, handle vfs.Handle, err error) {
+	key, flags := openFlags(VFS, e, write)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	file, found := o.files[key]
+	if !found {
+		handle, err = VFS.OpenFile(e.path, flags|extraFlags, 0666)
+		if err != nil {
+			return key, nil, err
+		}
+		file = &openFile{handle: handle}
+		o.files[key] = file
+		if o.timer == nil {
+			o.timer = time.AfterFunc(openFileCheckTime, o.closeIdle)
+		}
+	}
+	file.users++
+	return key, file.handle, nil
+}
+
+// isOpen returns true if the file is open for writing
+func (o *openFiles) isOpen(VFS *vfs.VFS, e handleEntry) bool {
+	key, _ := openFlags(VFS, e, true)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	_, found := o.files[key]
+	return found
+}
+
+// put returns a handle got with get
+func (o *openFiles) put(key openFileKey) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	file, found := o.files[key]
+	if !found {
+		return
+	}
+	file.users--
+	file.lastUsed = time.Now()
+}
+
+// closeHandle closes the file, logging any errors
+func closeHandle(e handleEntry, handle vfs.Handle) error {
+	err := handle.Close()
+	if err != nil && err != vfs.ECLOSED {
+		fs.Errorf(e.path, "Failed to close file: %v", err)
+		return err
+	}
+	return nil
+}
+
+// closeIdle closes handles which haven't been used recently
+func (o *openFiles) closeIdle() {
+	o.mu.Lock()
+	var toClose []openFileKey
+	now := time.Now()
+	for key, file := range o.files {
+		if file.users <= 0 && now.Sub(file.lastUsed) >= openFileIdleTime {
+			toClose = append(toClose, key)
+		}
+	}
+	var handles []vfs.Handle
+	for _, key := range toClose {
+		handles = append(handles, o.files[key].handle)
+		delete(o.files, key)
+	}
+	if len(o.files) > 0 && o.timer != nil {
+		o.timer.Reset(openFileCheckTime)
+	} else {
+		o.timer = nil
+	}
+	o.mu.Unlock()
+	for i, handle := range handles {
+		_ = closeHandle(toClose[i].entry, handle)
+	}
+}
+
+// closeFile closes any handles open on the file returning the first
+// error from closing a write handle.
+func (o *openFiles) closeFile(e handleEntry) (err error) {
+	return o.closeMatching(func(key openFileKey) bool {
+		return key.entry == e
+	})
+}
+
+// closeInside closes any handles open on the path or inside it
+func (o *openFiles) closeInside(export, path string) {
+	_ = o.closeMatching(func(key openFileKey) bool {
+		return key.entry.export == export && isInside(key.entry.path, path)
+	})
+}
+
+// closeMatching closes all the handles for which match is true
+func (o *openFiles) closeMatching(match func(key openFileKey) bool) (err error) {
+	o.mu.Lock()
+	var handles []vfs.Handle
+	var keys []openFileKey
+	for key, file := range o.files {
+		if match(key) {
+			keys = append(keys, key)
+			handles = append(handles, file.handle)
+			delete(o.files, key)
+		}
+	}
+	o.mu.Unlock()
+	for i, handle := range handles {
+		closeErr := closeHandle(keys[i].entry, handle)
+		if closeErr != nil && keys[i].write && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// close all the open handles
+func (o *openFiles) close() {
+	o.mu.Lock()
+	if o.timer != nil {
+		o.timer.Stop()
+		o.timer = nil
+	}
+	o.mu.Unlock()
+	_ = o.closeMatching(func(openFileKey) bool { return true })
+}
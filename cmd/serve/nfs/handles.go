@@ -0,0 +1,303 @@
+//go:build !plan9
+// +build !plan9
+
+package nfs
+
+// NFS file handles
+//
+// NFS clients identify files by an opaque handle rather than by
+// path, and expect the handle to stay the same for the lifetime of
+// the file, including across renames and server restarts. The VFS
+// has no persistent inode numbers so the handles are derived from a
+// hash of the path and the mapping from handle to path is kept here,
+// optionally persisted in the key-value database so that clients
+// don't see stale handles when the server restarts.
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/lib/kv"
+)
+
+const (
+	handleSize      = 16              // size of the handles we issue
+	maxHandleSize   = 64              // NFS3_FHSIZE
+	handleFlushTime = 1 * time.Second // how often to persist new handles
+	kvFacility      = "serve-nfs"     // kv facility for persisted handles
+)
+
+// fileHandle is a handle issued to a client
+type fileHandle [handleSize]byte
+
+// fileID returns the unique file number for the handle
+func (h fileHandle) fileID() uint64 {
+	return binary.BigEndian.Uint64(h[:8])
+}
+
+// handleEntry is what the handle refers to
+type handleEntry struct {
+	export string // name of the export - the user when using the auth proxy
+	path   string // path of the file within the export
+}
+
+// encode the entry for storage
+func (e handleEntry) encode() []byte {
+	return []byte(e.export + "\x00" + e.path)
+}
+
+// decodeHandleEntry decodes an entry encoded with encode
+func decodeHandleEntry(data []byte) (e handleEntry, ok bool) {
+	i := bytes.IndexByte(data, 0)
+	if i < 0 {
+		return e, false
+	}
+	return handleEntry{export: string(data[:i]), path: string(data[i+1:])}, true
+}
+
+// handleMap maps handles to paths and back
+type handleMap struct {
+	mu       sync.Mutex
+	byHandle map[fileHandle]handleEntry
+	byEntry  map[handleEntry]fileHandle
+	db       *kv.DB                      // database to persist handles in or nil
+	pending  map[fileHandle]*handleEntry // changes not yet persisted - nil to delete
+	timer    *time.Timer                 // to flush pending changes
+}
+
+// handleFacility returns the name of the key-value database for the
+// handles of f. The database is named after the remote so a hash of
+// the root is added as the same handle is a different file under a
+// different root.
+func handleFacility(f fs.Fs) string {
+	sum := sha256.Sum256([]byte(f.Root()))
+	return kvFacility + "-" + hex.EncodeToString(sum[:8])
+}
+
+// newHandleMap makes a new handle map, persisting it in the
+// key-value database if persist is set and it is supported.
+func newHandleMap(ctx context.Context, f fs.Fs, persist bool) *handleMap {
+	m := &handleMap{
+		byHandle: make(map[fileHandle]handleEntry),
+		byEntry:  make(map[handleEntry]fileHandle),
+		pending:  make(map[fileHandle]*handleEntry),
+	}
+	if !persist {
+		return m
+	}
+	if !kv.Supported() {
+		fs.Logf(nil, "serve nfs: file handles can't be persisted on this OS")
+		return m
+	}
+	db, err := kv.Start(ctx, handleFacility(f), f)
+	if err != nil {
+		fs.Errorf(nil, "serve nfs: failed to open file handle database - handles won't be persisted: %v", err)
+		return m
+	}
+	m.db = db
+	err = db.Do(false, &kvLoadHandles{m: m})
+	if err != nil {
+		fs.Errorf(nil, "serve nfs: failed to load file handles: %v", err)
+	}
+	fs.Debugf(nil, "serve nfs: loaded %d file handles from %q", len(m.byHandle), db.Path())
+	return m
+}
+
+// makeHandle works out the handle for an entry which must not be in
+// use for a different entry.
+//
+// Call with the lock held.
+func (m *handleMap) makeHandle(e handleEntry) (h fileHandle) {
+	key := string(e.encode())
+	for i := 0; ; i++ {
+		sum := sha256.Sum256([]byte(key))
+		copy(h[:], sum[:])
+		if _, found := m.byHandle[h]; !found {
+			return h
+		}
+		// Collision, most likely with a file which was renamed
+		// from this path, so try again with a different key
+		key = string(e.encode()) + "\x00" + strings.Repeat("+", i+1)
+	}
+}
+
+// toHandle returns the handle for the path in the export, issuing a
+// new one if necessary.
+func (m *handleMap) toHandle(export, path string) fileHandle {
+	e := handleEntry{export: export, path: path}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if h, found := m.byEntry[e]; found {
+		return h
+	}
+	h := m.makeHandle(e)
+	m.set(h, e)
+	return h
+}
+
+// fromHandle returns the entry for the handle if known
+func (m *handleMap) fromHandle(h fileHandle) (e handleEntry, found bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, found = m.byHandle[h]
+	return e, found
+}
+
+// set the handle to point to e
+//
+// Call with the lock held.
+func (m *handleMap) set(h fileHandle, e handleEntry) {
+	m.byHandle[h] = e
+	m.byEntry[e] = h
+	m.changed(h, &e)
+}
+
+// del removes the handle
+//
+// Call with the lock held.
+func (m *handleMap) del(h fileHandle) {
+	e, found := m.byHandle[h]
+	if !found {
+		return
+	}
+	delete(m.byHandle, h)
+	delete(m.byEntry, e)
+	m.changed(h, nil)
+}
+
+// changed marks a handle as needing persisting
+//
+// Call with the lock held.
+func (m *handleMap) changed(h fileHandle, e *handleEntry) {
+	if m.db == nil {
+		return
+	}
+	m.pending[h] = e
+	if m.timer == nil {
+		m.timer = time.AfterFunc(handleFlushTime, m.flush)
+	}
+}
+
+// isInside returns true if path is dir or inside dir
+func isInside(path, dir string) bool {
+	return dir == "" || path == dir || strings.HasPrefix(path, dir+"/")
+}
+
+// remove the handles for the path and anything inside it
+func (m *handleMap) remove(export, path string) {
+	if path == "" {
+		return // never forget the root
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for h, e := range m.byHandle {
+		if e.export == export && isInside(e.path, path) {
+			m.del(h)
+		}
+	}
+}
+
+// rename moves the handles for oldPath and anything inside it to
+// newPath keeping the handles the same.
+func (m *handleMap) rename(export, oldPath, newPath string) {
+	if oldPath == newPath {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Anything already at the destination has been overwritten
+	for h, e := range m.byHandle {
+		if e.export == export && isInside(e.path, newPath) {
+			m.del(h)
+		}
+	}
+	for h, e := range m.byHandle {
+		if e.export == export && isInside(e.path, oldPath) {
+			delete(m.byEntry, e)
+			e.path = newPath + e.path[len(oldPath):]
+			m.set(h, e)
+		}
+	}
+}
+
+// flush writes any pending changes to the database
+func (m *handleMap) flush() {
+	m.mu.Lock()
+	pending := m.pending
+	m.pending = make(map[fileHandle]*handleEntry)
+	m.timer = nil
+	m.mu.Unlock()
+	if len(pending) == 0 || m.db == nil {
+		return
+	}
+	err := m.db.Do(true, &kvSaveHandles{pending: pending})
+	if err != nil {
+		fs.Errorf(nil, "serve nfs: failed to persist %d file handles: %v", len(pending), err)
+	}
+}
+
+// close flushes the handles and closes the database
+func (m *handleMap) close() {
+	m.mu.Lock()
+	if m.timer != nil {
+		m.timer.Stop()
+	}
+	m.mu.Unlock()
+	m.flush()
+	if m.db != nil {
+		_ = m.db.Stop(false)
+		m.db = nil
+	}
+}
+
+// kvLoadHandles reads all the handles from the database
+type kvLoadHandles struct {
+	m *handleMap
+}
+
+// Do the load - the caller holds no locks as this is called in newHandleMap
+func (op *kvLoadHandles) Do(ctx context.Context, b kv.Bucket) error {
+	return b.ForEach(func(bkey, data []byte) error {
+		var h fileHandle
+		if len(bkey) != handleSize {
+			return nil
+		}
+		copy(h[:], bkey)
+		e, ok := decodeHandleEntry(data)
+		if !ok {
+			return nil
+		}
+		op.m.byHandle[h] = e
+		op.m.byEntry[e] = h
+		return nil
+	})
+}
+
+// kvSaveHandles writes or deletes handles in the database
+type kvSaveHandles struct {
+	pending map[fileHandle]*handleEntry
+}
+
+// Do the save
+func (op *kvSaveHandles) Do(ctx context.Context, b kv.Bucket) error {
+	for h, e := range op.pending {
+		h := h
+		var err error
+		if e == nil {
+			err = b.Delete(h[:])
+		} else {
+			err = b.Put(h[:], e.encode())
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+//go:build !plan9
+// +build !plan9
+
+package nfs
+
+// The MOUNT protocol version 3 as described in RFC 1813 Appendix I
+
+import (
+	"path"
+	"strings"
+
+	"github.com/rclone/rclone/fs"
+)
+
+// MOUNT program constants
+const (
+	mountProgram = 100005
+	mountVersion = 3
+
+	mountProcNull    = 0
+	mountProcMnt     = 1
+	mountProcDump    = 2
+	mountProcUmnt    = 3
+	mountProcUmntAll = 4
+	mountProcExport  = 5
+
+	mntPathLen = 1024
+
+	mnt3OK             = 0
+	mnt3ErrPerm        = 1
+	mnt3ErrNoEnt       = 2
+	mnt3ErrIO          = 5
+	mnt3ErrAccess      = 13
+	mnt3ErrNotDir      = 20
+	mnt3ErrInval       = 22
+	mnt3ErrNameTooLong = 63
+	mnt3ErrNotSupp     = 10004
+	mnt3ErrServerFault = 10006
+)
+
+// mountProcNames are the names of the MOUNT procedures for logging
+var mountProcNames = []string{"NULL", "MNT", "DUMP", "UMNT", "UMNTALL", "EXPORT"}
+
+// mountProg implements the MOUNT program
+type mountProg struct {
+	s *server
+}
+
+// versions returns the lowest and highest versions supported
+func (p mountProg) versions() (low, high uint32) {
+	return mountVersion, mountVersion
+}
+
+// call runs the MOUNT procedure
+func (p mountProg) call(c *rpcCall, w *xdrWriter) error {
+	switch c.proc {
+	case mountProcNull, mountProcUmntAll:
+		return nil
+	case mountProcMnt:
+		return p.mnt(c, w)
+	case mountProcDump:
+		// We don't keep a list of mounts
+		w.bool(false)
+		return nil
+	case mountProcUmnt:
+		dirPath := c.args.string(mntPathLen)
+		fs.Debugf(c.conn.what, "MOUNT UMNT %q", dirPath)
+		return nil
+	case mountProcExport:
+		// One export which anyone can mount
+		w.bool(true)
+		w.string("/")
+		w.bool(false) // no groups
+		w.bool(false) // end of list
+		return nil
+	}
+	return errProcUnavail
+}
+
+// splitMountPath splits the path the client asked to mount into the
+// export name and the path within it.
+//
+// Without the auth proxy there is a single export and the whole path
+// is a directory in it. With the auth proxy the first component of the
+// path is the user name passed to the proxy.
+func (s *server) splitMountPath(dirPath string) (export, dir string) {
+	dirPath = strings.Trim(path.Clean("/"+dirPath), "/")
+	if s.proxy == nil {
+		return "", dirPath
+	}
+	if i := strings.IndexByte(dirPath, '/'); i >= 0 {
+		return dirPath[:i], dirPath[i+1:]
+	}
+	return dirPath, ""
+}
+
+// mnt mounts a directory returning its file handle
+func (p mountProg) mnt(c *rpcCall, w *xdrWriter) error {
+	dirPath := c.args.string(mntPathLen)
+	if c.args.err != nil {
+		return errGarbageArgs
+	}
+	what := c.conn.what
+	export, dir := p.s.splitMountPath(dirPath)
+	fs.Debugf(what, "MOUNT MNT %q", dirPath)
+	if p.s.proxy != nil && export == "" {
+		fs.Infof(what, "MOUNT of %q refused: the first component of the path must be the user name when using --auth-proxy", dirPath)
+		w.uint32(mnt3ErrAccess)
+		return nil
+	}
+	VFS, err := p.s.getVFS(export)
+	if err != nil {
+		fs.Infof(what, "MOUNT of %q refused: %v", dirPath, err)
+		w.uint32(mnt3ErrAccess)
+		return nil
+	}
+	node, err := VFS.Stat(dir)
+	if err != nil {
+		fs.Infof(what, "MOUNT of %q failed: %v", dirPath, err)
+		w.uint32(mountStatus(err))
+		return nil
+	}
+	if !node.IsDir() {
+		w.uint32(mnt3ErrNotDir)
+		return nil
+	}
+	fs.Infof(what, "Mounted %q", dirPath)
+	h := p.s.handles.toHandle(export, dir)
+	w.uint32(mnt3OK)
+	w.opaque(h[:])
+	// Auth flavors we accept
+	w.uint32(2)
+	w.uint32(authSys)
+	w.uint32(authNone)
+	return nil
+}
+
+// mountStatus converts a VFS error into a mountstat3
+func mountStatus(err error) uint32 {
+	switch nfsStatus(err) {
+	case nfs3ErrNoEnt:
+		return mnt3ErrNoEnt
+	case nfs3ErrAccess:
+		return mnt3ErrAccess
+	case nfs3ErrNotDir:
+		return mnt3ErrNotDir
+	case nfs3ErrInval:
+		return mnt3ErrInval
+	case nfs3ErrNameTooLong:
+		return mnt3ErrNameTooLong
+	case nfs3ErrPerm:
+		return mnt3ErrPerm
+	}
+	return mnt3ErrIO
+}
+
+// Check interfaces
+var _ rpcProgram = mountProg{}
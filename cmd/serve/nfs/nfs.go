@@ -0,0 +1,141 @@
+//go:build !plan9
+// +build !plan9
+
+// Package nfs implements an NFSv3 server to serve an rclone VFS
+package nfs
+
+import (
+	"context"
+
+	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/cmd/serve/proxy"
+	"github.com/rclone/rclone/cmd/serve/proxy/proxyflags"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/flags"
+	"github.com/rclone/rclone/fs/rc"
+	"github.com/rclone/rclone/vfs"
+	"github.com/rclone/rclone/vfs/vfsflags"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Values for --handle-cache
+const (
+	handleCacheDisk   = "disk"
+	handleCacheMemory = "memory"
+)
+
+// Options contains options for the NFS Server
+type Options struct {
+	ListenAddr  string // Port to listen on
+	HandleCache string // where to keep the file handles - disk or memory
+}
+
+// DefaultOpt is the default values used for Options
+var DefaultOpt = Options{
+	ListenAddr:  "localhost:2049",
+	HandleCache: handleCacheDisk,
+}
+
+// Opt is options set by command line flags
+var Opt = DefaultOpt
+
+// AddFlags adds flags for the nfs
+func AddFlags(flagSet *pflag.FlagSet, Opt *Options) {
+	rc.AddOption("nfs", &Opt)
+	flags.StringVarP(flagSet, &Opt.ListenAddr, "addr", "", Opt.ListenAddr, "IPaddress:Port or :Port to bind server to")
+	flags.StringVarP(flagSet, &Opt.HandleCache, "handle-cache", "", Opt.HandleCache, "Where to keep the NFS file handles: disk or memory")
+}
+
+func init() {
+	vfsflags.AddFlags(Command.Flags())
+	proxyflags.AddFlags(Command.Flags())
+	AddFlags(Command.Flags(), &Opt)
+}
+
+// Command definition for cobra
+var Command = &cobra.Command{
+	Use:   "nfs remote:path",
+	Short: `Serve the remote as an NFS mount.`,
+	Long: `Run an NFSv3 server to serve a remote over NFS. This can be used
+to mount a remote with the kernel NFS client on machines where FUSE
+isn't available or isn't allowed.
+
+You can use the filter flags (e.g. ` + "`--include`, `--exclude`" + `) to control what
+is served.
+
+The server will log errors.  Use ` + "`-v`" + ` to see access logs.
+
+` + "`--bwlimit`" + ` will be respected for file transfers.
+Use ` + "`--stats`" + ` to control the stats printing.
+
+By default the server binds to localhost:2049 - if you want it to be
+reachable externally then supply ` + "`--addr :2049`" + ` for example.
+The server runs the NFS and MOUNT protocols on the same port and
+doesn't register with the portmapper, so the port must be given to
+the client when mounting, for example on Linux
+
+    mount -t nfs -o port=2049,mountport=2049,tcp,nfsvers=3,nolock localhost:/ /mnt/remote
+
+and on macOS
+
+    mount -t nfs -o port=2049,mountport=2049,tcp,vers=3,nolocks localhost:/ /mnt/remote
+
+Subdirectories of the remote may be mounted directly by giving their
+path instead of ` + "`/`" + `. Locking isn't supported so ` + "`nolock`" + ` must be used.
+
+The NFS protocol has no open or close operations so the server keeps
+files open between reads and writes and closes them when the client
+commits the data or when they have been idle for a few seconds.
+
+It is strongly recommended to use ` + "`--vfs-cache-mode writes`" + ` or
+` + "`--vfs-cache-mode full`" + ` as NFS clients write files out of order
+and in parts. Without the VFS cache files can only be written
+sequentially from the start and updating existing files isn't
+possible.
+
+### File handles
+
+NFS clients refer to files by file handles which must stay the same
+while the file exists, even across renames and server restarts. By
+default rclone keeps the map of file handles to paths in a database
+in rclone's cache directory (see ` + "`rclone help flags cache-dir`" + `)
+so clients don't see "Stale file handle" errors when the server is
+restarted. Use ` + "`--handle-cache memory`" + ` to keep the map in
+memory only.
+
+### Security
+
+NFSv3 has no authentication beyond trusting the user ID sent by the
+client so access to the server should be restricted with a firewall
+or by only binding to localhost. All files are presented as owned by
+the ` + "`--uid`" + ` and ` + "`--gid`" + ` given to rclone.
+
+If ` + "`--auth-proxy`" + ` is used then the first component of the path
+mounted is used as the user name passed to the proxy, with an empty
+password, and the remainder is the path within the backend the proxy
+returns, for example ` + "`localhost:/user/dir`" + `.
+
+` + vfs.Help + proxy.Help,
+	Run: func(command *cobra.Command, args []string) {
+		var f fs.Fs
+		if proxyflags.Opt.AuthProxy == "" {
+			cmd.CheckArgs(1, 1, command, args)
+			f = cmd.NewFsSrc(args)
+		} else {
+			cmd.CheckArgs(0, 0, command, args)
+		}
+		cmd.Run(false, true, command, func() error {
+			s, err := newServer(context.Background(), f, &Opt)
+			if err != nil {
+				return err
+			}
+			err = s.serve()
+			if err != nil {
+				return err
+			}
+			s.Wait()
+			return nil
+		})
+	},
+}
@@ -0,0 +1,1111 @@
+//go:build !plan9
+// +build !plan9
+
+package nfs
+
+// The NFS protocol version 3 as described in RFC 1813
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/fserrors"
+	"github.com/rclone/rclone/vfs"
+)
+
+// NFS program constants
+const (
+	nfsProgram = 100003
+	nfsVersion = 3
+
+	nfsProcNull        = 0
+	nfsProcGetAttr     = 1
+	nfsProcSetAttr     = 2
+	nfsProcLookup      = 3
+	nfsProcAccess      = 4
+	nfsProcReadLink    = 5
+	nfsProcRead        = 6
+	nfsProcWrite       = 7
+	nfsProcCreate      = 8
+	nfsProcMkdir       = 9
+	nfsProcSymlink     = 10
+	nfsProcMknod       = 11
+	nfsProcRemove      = 12
+	nfsProcRmdir       = 13
+	nfsProcRename      = 14
+	nfsProcLink        = 15
+	nfsProcReadDir     = 16
+	nfsProcReadDirPlus = 17
+	nfsProcFSStat      = 18
+	nfsProcFSInfo      = 19
+	nfsProcPathConf    = 20
+	nfsProcCommit      = 21
+
+	nfs3OK             = 0
+	nfs3ErrPerm        = 1
+	nfs3ErrNoEnt       = 2
+	nfs3ErrIO          = 5
+	nfs3ErrAccess      = 13
+	nfs3ErrExist       = 17
+	nfs3ErrNotDir      = 20
+	nfs3ErrIsDir       = 21
+	nfs3ErrInval       = 22
+	nfs3ErrFBig        = 27
+	nfs3ErrNoSpc       = 28
+	nfs3ErrRoFS        = 30
+	nfs3ErrNameTooLong = 63
+	nfs3ErrNotEmpty    = 66
+	nfs3ErrStale       = 70
+	nfs3ErrBadHandle   = 10001
+	nfs3ErrNotSync     = 10002
+	nfs3ErrBadCookie   = 10003
+	nfs3ErrNotSupp     = 10004
+	nfs3ErrTooSmall    = 10005
+	nfs3ErrServerFault = 10006
+
+	nf3Reg = 1
+	nf3Dir = 2
+
+	access3Read    = 0x0001
+	access3Lookup  = 0x0002
+	access3Modify  = 0x0004
+	access3Extend  = 0x0008
+	access3Delete  = 0x0010
+	access3Execute = 0x0020
+
+	stableUnstable = 0
+	stableFileSync = 2
+
+	createUnchecked = 0
+	createGuarded   = 1
+	createExclusive = 2
+
+	timeDontChange   = 0
+	timeServerTime   = 1
+	timeClientTime   = 2
+	fsf3Homogeneous  = 0x0008
+	fsf3CanSetTime   = 0x0010
+	maxNameLen       = 255
+	maxPathLen       = 1024
+	maxReadWriteSize = 1024 * 1024
+	preferredDirSize = 64 * 1024
+)
+
+// nfsProcNames are the names of the NFS procedures for logging
+var nfsProcNames = []string{
+	"NULL", "GETATTR", "SETATTR", "LOOKUP", "ACCESS", "READLINK", "READ",
+	"WRITE", "CREATE", "MKDIR", "SYMLINK", "MKNOD", "REMOVE", "RMDIR",
+	"RENAME", "LINK", "READDIR", "READDIRPLUS", "FSSTAT", "FSINFO",
+	"PATHCONF", "COMMIT",
+}
+
+// nfsStatus converts an error from the VFS into an nfsstat3
+func nfsStatus(err error) uint32 {
+	if err == nil {
+		return nfs3OK
+	}
+	_, uErr := fserrors.Cause(err)
+	switch uErr {
+	case vfs.OK:
+		return nfs3OK
+	case vfs.ENOENT, fs.ErrorDirNotFound, fs.ErrorObjectNotFound:
+		return nfs3ErrNoEnt
+	case vfs.EEXIST, fs.ErrorDirExists:
+		return nfs3ErrExist
+	case vfs.EPERM, fs.ErrorPermissionDenied:
+		return nfs3ErrPerm
+	case vfs.ENOTEMPTY:
+		return nfs3ErrNotEmpty
+	case vfs.EROFS:
+		return nfs3ErrRoFS
+	case vfs.ENOSYS, fs.ErrorNotImplemented:
+		return nfs3ErrNotSupp
+	case vfs.EINVAL:
+		return nfs3ErrInval
+	}
+	fs.Errorf(nil, "IO error: %v", err)
+	return nfs3ErrIO
+}
+
+// nfsProg implements the NFS program
+type nfsProg struct {
+	s *server
+}
+
+// versions returns the lowest and highest versions supported
+func (p nfsProg) versions() (low, high uint32) {
+	return nfsVersion, nfsVersion
+}
+
+// call runs the NFS procedure
+func (p nfsProg) call(c *rpcCall, w *xdrWriter) error {
+	switch c.proc {
+	case nfsProcNull:
+		return nil
+	case nfsProcGetAttr:
+		return p.getAttr(c, w)
+	case nfsProcSetAttr:
+		return p.setAttr(c, w)
+	case nfsProcLookup:
+		return p.lookup(c, w)
+	case nfsProcAccess:
+		return p.access(c, w)
+	case nfsProcReadLink:
+		w.uint32(nfs3ErrNotSupp)
+		w.bool(false) // no attributes
+		return nil
+	case nfsProcRead:
+		return p.read(c, w)
+	case nfsProcWrite:
+		return p.write(c, w)
+	case nfsProcCreate:
+		return p.create(c, w)
+	case nfsProcMkdir:
+		return p.mkdir(c, w)
+	case nfsProcSymlink, nfsProcMknod:
+		w.uint32(nfs3ErrNotSupp)
+		writeEmptyWcc(w)
+		return nil
+	case nfsProcRemove:
+		return p.remove(c, w, false)
+	case nfsProcRmdir:
+		return p.remove(c, w, true)
+	case nfsProcRename:
+		return p.rename(c, w)
+	case nfsProcLink:
+		w.uint32(nfs3ErrNotSupp)
+		w.bool(false) // no file attributes
+		writeEmptyWcc(w)
+		return nil
+	case nfsProcReadDir:
+		return p.readDir(c, w, false)
+	case nfsProcReadDirPlus:
+		return p.readDir(c, w, true)
+	case nfsProcFSStat:
+		return p.fsStat(c, w)
+	case nfsProcFSInfo:
+		return p.fsInfo(c, w)
+	case nfsProcPathConf:
+		return p.pathConf(c, w)
+	case nfsProcCommit:
+		return p.commit(c, w)
+	}
+	return errProcUnavail
+}
+
+// file is a file or directory referred to by a handle
+type file struct {
+	h     fileHandle
+	entry handleEntry
+	vfs   *vfs.VFS
+	node  vfs.Node
+}
+
+// readHandle reads a file handle from the arguments
+func readHandle(c *rpcCall) (h fileHandle, status uint32) {
+	data := c.args.opaque(maxHandleSize)
+	if c.args.err != nil {
+		return h, nfs3ErrServerFault
+	}
+	if len(data) != handleSize {
+		return h, nfs3ErrBadHandle
+	}
+	copy(h[:], data)
+	return h, nfs3OK
+}
+
+// resolve finds the file a handle refers to
+func (p nfsProg) resolve(h fileHandle) (f file, status uint32) {
+	f.h = h
+	e, found := p.s.handles.fromHandle(h)
+	if !found {
+		return f, nfs3ErrStale
+	}
+	f.entry = e
+	VFS, err := p.s.getVFS(e.export)
+	if err != nil {
+		fs.Debugf(nil, "Failed to find VFS for export %q: %v", e.export, err)
+		return f, nfs3ErrStale
+	}
+	f.vfs = VFS
+	f.node, err = VFS.Stat(e.path)
+	if err == vfs.ENOENT {
+		return f, nfs3ErrStale
+	} else if err != nil {
+		return f, nfsStatus(err)
+	}
+	return f, nfs3OK
+}
+
+// readFile reads a handle from the arguments and resolves it
+func (p nfsProg) readFile(c *rpcCall) (f file, status uint32) {
+	h, status := readHandle(c)
+	if status != nfs3OK {
+		return f, status
+	}
+	return p.resolve(h)
+}
+
+// child returns the handle entry for a name in the directory
+func (f *file) child(name string) handleEntry {
+	return handleEntry{export: f.entry.export, path: path.Join(f.entry.path, name)}
+}
+
+// dir returns the node as a directory if it is one
+func (f *file) dir() (*vfs.Dir, uint32) {
+	dir, ok := f.node.(*vfs.Dir)
+	if !ok {
+		return nil, nfs3ErrNotDir
+	}
+	return dir, nfs3OK
+}
+
+// checkName checks a name sent by the client is a valid leaf name
+func checkName(name string) uint32 {
+	switch {
+	case len(name) > maxNameLen:
+		return nfs3ErrNameTooLong
+	case name == "" || name == "." || name == ".." || strings.ContainsRune(name, '/'):
+		return nfs3ErrInval
+	}
+	return nfs3OK
+}
+
+// readDirOp reads a diropargs3 resolving the directory
+func (p nfsProg) readDirOp(c *rpcCall) (dir file, name string, status uint32) {
+	h, status := readHandle(c)
+	name = c.args.string(maxPathLen)
+	if status != nfs3OK {
+		return dir, name, status
+	}
+	if c.args.err != nil {
+		return dir, name, nfs3ErrServerFault
+	}
+	dir, status = p.resolve(h)
+	if status != nfs3OK {
+		return dir, name, status
+	}
+	if _, status = dir.dir(); status != nfs3OK {
+		return dir, name, status
+	}
+	return dir, name, nfs3OK
+}
+
+// writeTime writes an nfstime3
+func writeTime(w *xdrWriter, t time.Time) {
+	secs := t.Unix()
+	if secs < 0 {
+		secs = 0
+	} else if secs > math.MaxUint32 {
+		secs = math.MaxUint32
+	}
+	w.uint32(uint32(secs))
+	w.uint32(uint32(t.Nanosecond()))
+}
+
+// readTime reads an nfstime3
+func readTime(r *xdrReader) time.Time {
+	secs := r.uint32()
+	nsecs := r.uint32()
+	return time.Unix(int64(secs), int64(nsecs))
+}
+
+// fsid returns the file system ID for the export
+func fsid(export string) uint64 {
+	sum := sha256.Sum256([]byte("fsid\x00" + export))
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// writeAttr writes the fattr3 for the file
+func writeAttr(w *xdrWriter, f *file) {
+	node := f.node
+	if node.IsDir() {
+		w.uint32(nf3Dir)
+	} else {
+		w.uint32(nf3Reg)
+	}
+	w.uint32(uint32(node.Mode().Perm()))
+	if node.IsDir() {
+		w.uint32(2)
+	} else {
+		w.uint32(1)
+	}
+	w.uint32(f.vfs.Opt.UID)
+	w.uint32(f.vfs.Opt.GID)
+	size := node.Size()
+	if size < 0 {
+		size = 0
+	}
+	w.uint64(uint64(size)) // size
+	w.uint64(uint64(size)) // used
+	w.uint32(0)            // rdev major
+	w.uint32(0)            // rdev minor
+	w.uint64(fsid(f.entry.export))
+	w.uint64(f.h.fileID())
+	modTime := node.ModTime()
+	writeTime(w, modTime) // atime
+	writeTime(w, modTime) // mtime
+	writeTime(w, modTime) // ctime
+}
+
+// writePostOpAttr writes the post_op_attr for the file if it is valid
+func writePostOpAttr(w *xdrWriter, f *file) {
+	if f.node == nil {
+		w.bool(false)
+		return
+	}
+	w.bool(true)
+	writeAttr(w, f)
+}
+
+// writeEmptyWcc writes wcc_data with no attributes
+func writeEmptyWcc(w *xdrWriter) {
+	w.bool(false) // before
+	w.bool(false) // after
+}
+
+// writeWcc writes wcc_data with no pre operation attributes and the
+// current attributes of the file.
+func writeWcc(w *xdrWriter, f *file) {
+	w.bool(false)
+	f.refresh()
+	writePostOpAttr(w, f)
+}
+
+// refresh reads the node again so the attributes are up to date
+func (f *file) refresh() {
+	if f.vfs == nil {
+		return
+	}
+	node, err := f.vfs.Stat(f.entry.path)
+	if err != nil {
+		f.node = nil
+		return
+	}
+	f.node = node
+}
+
+// writePostOpFh writes a post_op_fh3 and post_op_attr for the entry
+func (p nfsProg) writePostOpFhAttr(w *xdrWriter, f *file, e handleEntry) {
+	node, err := f.vfs.Stat(e.path)
+	if err != nil {
+		w.bool(false) // no handle
+		w.bool(false) // no attributes
+		return
+	}
+	h := p.s.handles.toHandle(e.export, e.path)
+	w.bool(true)
+	w.opaque(h[:])
+	writePostOpAttr(w, &file{h: h, entry: e, vfs: f.vfs, node: node})
+}
+
+// sattr is the decoded sattr3
+type sattr struct {
+	setSize  bool
+	size     uint64
+	setMtime bool
+	mtime    time.Time
+}
+
+// readSattr reads the sattr3 ignoring the mode, uid, gid and atime
+// which can't be set in the VFS.
+func readSattr(r *xdrReader) (a sattr) {
+	if r.bool() {
+		_ = r.uint32() // mode
+	}
+	if r.bool() {
+		_ = r.uint32() // uid
+	}
+	if r.bool() {
+		_ = r.uint32() // gid
+	}
+	if a.setSize = r.bool(); a.setSize {
+		a.size = r.uint64()
+	}
+	if r.uint32() == timeClientTime {
+		_ = readTime(r) // atime
+	}
+	switch r.uint32() {
+	case timeServerTime:
+		a.setMtime = true
+		a.mtime = time.Now()
+	case timeClientTime:
+		a.setMtime = true
+		a.mtime = readTime(r)
+	}
+	return a
+}
+
+// apply the attributes to the file
+func (a sattr) apply(f *file) uint32 {
+	if a.setSize {
+		if f.node.IsDir() {
+			return nfs3ErrIsDir
+		}
+		if a.size > math.MaxInt64 {
+			return nfs3ErrFBig
+		}
+		if err := f.node.Truncate(int64(a.size)); err != nil {
+			return nfsStatus(err)
+		}
+	}
+	if a.setMtime {
+		if err := f.node.SetModTime(a.mtime); err != nil {
+			return nfsStatus(err)
+		}
+	}
+	return nfs3OK
+}
+
+// getAttr implements GETATTR
+func (p nfsProg) getAttr(c *rpcCall, w *xdrWriter) error {
+	f, status := p.readFile(c)
+	w.uint32(status)
+	if status == nfs3OK {
+		writeAttr(w, &f)
+	}
+	return nil
+}
+
+// setAttr implements SETATTR
+func (p nfsProg) setAttr(c *rpcCall, w *xdrWriter) error {
+	h, status := readHandle(c)
+	a := readSattr(c.args)
+	checkCtime := c.args.bool()
+	var ctime time.Time
+	if checkCtime {
+		ctime = readTime(c.args)
+	}
+	if c.args.err != nil {
+		return errGarbageArgs
+	}
+	var f file
+	if status == nfs3OK {
+		f, status = p.resolve(h)
+	}
+	if status == nfs3OK && checkCtime && !f.node.ModTime().Truncate(time.Second).Equal(ctime.Truncate(time.Second)) {
+		status = nfs3ErrNotSync
+	}
+	if status == nfs3OK {
+		status = a.apply(&f)
+	}
+	w.uint32(status)
+	writeWcc(w, &f)
+	return nil
+}
+
+// lookup implements LOOKUP
+func (p nfsProg) lookup(c *rpcCall, w *xdrWriter) error {
+	dir, name, status := p.readDirOp(c)
+	if status == nfs3OK {
+		switch {
+		case name == "." || name == "":
+			// the directory itself
+		case name == "..":
+			name = path.Dir(dir.entry.path)
+			if name == "." {
+				name = ""
+			}
+		case len(name) > maxNameLen:
+			status = nfs3ErrNameTooLong
+		case strings.ContainsRune(name, '/'):
+			status = nfs3ErrNoEnt
+		}
+	}
+	var child file
+	if status == nfs3OK {
+		child = dir
+		switch name {
+		case ".", "":
+		case "..":
+			child.entry.path = name
+		default:
+			child.entry = dir.child(name)
+		}
+		node, err := dir.vfs.Stat(child.entry.path)
+		if err != nil {
+			status = nfsStatus(err)
+		} else {
+			child.node = node
+			child.h = p.s.handles.toHandle(child.entry.export, child.entry.path)
+		}
+	}
+	w.uint32(status)
+	if status == nfs3OK {
+		w.opaque(child.h[:])
+		writePostOpAttr(w, &child)
+	}
+	writePostOpAttr(w, &dir)
+	return nil
+}
+
+// access implements ACCESS
+func (p nfsProg) access(c *rpcCall, w *xdrWriter) error {
+	f, status := p.readFile(c)
+	want := c.args.uint32()
+	w.uint32(status)
+	writePostOpAttr(w, &f)
+	if status != nfs3OK {
+		return nil
+	}
+	var allowed uint32 = access3Read
+	if f.node.IsDir() {
+		allowed |= access3Lookup
+	} else if f.node.Mode()&0111 != 0 {
+		allowed |= access3Execute
+	}
+	if !f.vfs.Opt.ReadOnly {
+		allowed |= access3Modify | access3Extend | access3Delete
+	}
+	w.uint32(want & allowed)
+	return nil
+}
+
+// read implements READ
+func (p nfsProg) read(c *rpcCall, w *xdrWriter) error {
+	f, status := p.readFile(c)
+	offset := c.args.uint64()
+	count := c.args.uint32()
+	if c.args.err != nil {
+		return errGarbageArgs
+	}
+	if status == nfs3OK && f.node.IsDir() {
+		status = nfs3ErrIsDir
+	}
+	if status == nfs3OK && offset > math.MaxInt64 {
+		status = nfs3ErrInval
+	}
+	var data []byte
+	var eof bool
+	if status == nfs3OK {
+		if count > maxReadWriteSize {
+			count = maxReadWriteSize
+		}
+		data, eof, status = p.readData(&f, int64(offset), int(count))
+	}
+	w.uint32(status)
+	f.refresh()
+	writePostOpAttr(w, &f)
+	if status != nfs3OK {
+		return nil
+	}
+	w.uint32(uint32(len(data)))
+	w.bool(eof)
+	w.opaque(data)
+	return nil
+}
+
+// readData reads count bytes from offset in the file
+func (p nfsProg) readData(f *file, offset int64, count int) (data []byte, eof bool, status uint32) {
+	if !readWrite(f.vfs) && p.s.files.isOpen(f.vfs, f.entry) {
+		// Without the VFS cache the file can't be read until
+		// the write handle has been closed and it has uploaded.
+		if err := p.s.files.closeFile(f.entry); err != nil {
+			return nil, false, nfsStatus(err)
+		}
+	}
+	size := f.node.Size()
+	if offset >= size {
+		return nil, true, nfs3OK
+	}
+	key, handle, err := p.s.files.get(f.vfs, f.entry, false, 0)
+	if err != nil {
+		return nil, false, nfsStatus(err)
+	}
+	defer p.s.files.put(key)
+	data = make([]byte, count)
+	n, err := handle.ReadAt(data, offset)
+	if err == io.EOF {
+		eof = true
+	} else if err != nil {
+		return nil, false, nfsStatus(err)
+	}
+	data = data[:n]
+	if offset+int64(n) >= f.node.Size() {
+		eof = true
+	}
+	return data, eof, nfs3OK
+}
+
+// write implements WRITE
+func (p nfsProg) write(c *rpcCall, w *xdrWriter) error {
+	f, status := p.readFile(c)
+	offset := c.args.uint64()
+	_ = c.args.uint32() // count - the same as the length of data
+	stable := c.args.uint32()
+	data := c.args.opaque(maxReadWriteSize)
+	if c.args.err != nil {
+		return errGarbageArgs
+	}
+	if status == nfs3OK && f.node.IsDir() {
+		status = nfs3ErrIsDir
+	}
+	if status == nfs3OK && offset > math.MaxInt64 {
+		status = nfs3ErrFBig
+	}
+	var n int
+	if status == nfs3OK {
+		n, status = p.writeData(&f, int64(offset), data)
+	}
+	if status == nfs3OK && stable != stableUnstable && !readWrite(f.vfs) {
+		// Without the VFS cache the only way of getting the data
+		// to stable storage is to close the file which uploads it.
+		status = nfsStatus(p.s.files.closeFile(f.entry))
+	}
+	w.uint32(status)
+	writeWcc(w, &f)
+	if status != nfs3OK {
+		return nil
+	}
+	w.uint32(uint32(n))
+	if stable == stableUnstable {
+		w.uint32(stableUnstable)
+	} else {
+		w.uint32(stableFileSync)
+	}
+	w.fixedOpaque(p.s.verifier[:])
+	return nil
+}
+
+// writeData writes data at offset in the file
+func (p nfsProg) writeData(f *file, offset int64, data []byte) (n int, status uint32) {
+	var extraFlags int
+	if !readWrite(f.vfs) {
+		// Without the VFS cache files can only be written
+		// sequentially from the start.
+		if p.s.files.isOpen(f.vfs, f.entry) {
+			// carry on writing to the open handle
+		} else if offset != 0 {
+			fs.Errorf(f.entry.path, "Can't write at offset %d without --vfs-cache-mode writes or full", offset)
+			return 0, nfs3ErrIO
+		} else {
+			extraFlags = os.O_TRUNC
+		}
+	}
+	key, handle, err := p.s.files.get(f.vfs, f.entry, true, extraFlags)
+	if err != nil {
+		return 0, nfsStatus(err)
+	}
+	defer p.s.files.put(key)
+	n, err = handle.WriteAt(data, offset)
+	if err != nil {
+		return n, nfsStatus(err)
+	}
+	return n, nfs3OK
+}
+
+// create implements CREATE
+func (p nfsProg) create(c *rpcCall, w *xdrWriter) error {
+	dir, name, status := p.readDirOp(c)
+	how := c.args.uint32()
+	var a sattr
+	switch how {
+	case createUnchecked, createGuarded:
+		a = readSattr(c.args)
+	case createExclusive:
+		_ = c.args.fixedOpaque(8) // verifier
+	default:
+		return errGarbageArgs
+	}
+	if c.args.err != nil {
+		return errGarbageArgs
+	}
+	if status == nfs3OK {
+		status = checkName(name)
+	}
+	var e handleEntry
+	if status == nfs3OK {
+		e = dir.child(name)
+		status = p.createFile(&dir, e, how, a)
+	}
+	w.uint32(status)
+	if status == nfs3OK {
+		p.writePostOpFhAttr(w, &dir, e)
+	}
+	writeWcc(w, &dir)
+	return nil
+}
+
+// createFile creates the file described by e
+func (p nfsProg) createFile(dir *file, e handleEntry, how uint32, a sattr) uint32 {
+	node, err := dir.vfs.Stat(e.path)
+	exists := err == nil
+	if err != nil && err != vfs.ENOENT {
+		return nfsStatus(err)
+	}
+	if exists {
+		if how != createUnchecked {
+			return nfs3ErrExist
+		}
+		if node.IsDir() {
+			return nfs3ErrIsDir
+		}
+	}
+	truncate := a.setSize && a.size == 0
+	if !exists || truncate {
+		// Open the file to create it and keep the handle open
+		// for the writes which will follow.
+		_ = p.s.files.closeFile(e)
+		key, _, err := p.s.files.get(dir.vfs, e, true, os.O_CREATE|os.O_TRUNC)
+		if err != nil {
+			return nfsStatus(err)
+		}
+		p.s.files.put(key)
+		node, err = dir.vfs.Stat(e.path)
+		if err != nil {
+			return nfsStatus(err)
+		}
+	}
+	a.setSize = false
+	f := file{entry: e, vfs: dir.vfs, node: node}
+	return a.apply(&f)
+}
+
+// mkdir implements MKDIR
+func (p nfsProg) mkdir(c *rpcCall, w *xdrWriter) error {
+	dir, name, status := p.readDirOp(c)
+	a := readSattr(c.args)
+	if c.args.err != nil {
+		return errGarbageArgs
+	}
+	if status == nfs3OK {
+		status = checkName(name)
+	}
+	var e handleEntry
+	if status == nfs3OK {
+		e = dir.child(name)
+		if _, err := dir.vfs.Stat(e.path); err == nil {
+			status = nfs3ErrExist
+		} else if err != vfs.ENOENT {
+			status = nfsStatus(err)
+		}
+	}
+	if status == nfs3OK {
+		parent, _ := dir.dir()
+		newDir, err := parent.Mkdir(name)
+		status = nfsStatus(err)
+		if status == nfs3OK && a.setMtime {
+			_ = newDir.SetModTime(a.mtime)
+		}
+	}
+	w.uint32(status)
+	if status == nfs3OK {
+		p.writePostOpFhAttr(w, &dir, e)
+	}
+	writeWcc(w, &dir)
+	return nil
+}
+
+// remove implements REMOVE and RMDIR
+func (p nfsProg) remove(c *rpcCall, w *xdrWriter, isDir bool) error {
+	dir, name, status := p.readDirOp(c)
+	if status == nfs3OK {
+		status = checkName(name)
+	}
+	var e handleEntry
+	var node vfs.Node
+	if status == nfs3OK {
+		e = dir.child(name)
+		var err error
+		node, err = dir.vfs.Stat(e.path)
+		status = nfsStatus(err)
+	}
+	if status == nfs3OK {
+		switch {
+		case isDir && !node.IsDir():
+			status = nfs3ErrNotDir
+		case !isDir && node.IsDir():
+			status = nfs3ErrIsDir
+		}
+	}
+	if status == nfs3OK {
+		p.s.files.closeInside(e.export, e.path)
+		status = nfsStatus(node.Remove())
+	}
+	if status == nfs3OK {
+		p.s.handles.remove(e.export, e.path)
+	}
+	w.uint32(status)
+	writeWcc(w, &dir)
+	return nil
+}
+
+// rename implements RENAME
+func (p nfsProg) rename(c *rpcCall, w *xdrWriter) error {
+	fromDir, fromName, status := p.readDirOp(c)
+	toDir, toName, toStatus := p.readDirOp(c)
+	if c.args.err != nil {
+		return errGarbageArgs
+	}
+	if status == nfs3OK {
+		status = toStatus
+	}
+	if status == nfs3OK {
+		status = checkName(fromName)
+	}
+	if status == nfs3OK {
+		status = checkName(toName)
+	}
+	if status == nfs3OK && fromDir.entry.export != toDir.entry.export {
+		status = nfs3ErrInval // XDEV would be better but that isn't allowed in RENAME
+	}
+	var from, to handleEntry
+	if status == nfs3OK {
+		from, to = fromDir.child(fromName), toDir.child(toName)
+		status = p.renameFile(&fromDir, from, to)
+	}
+	w.uint32(status)
+	writeWcc(w, &fromDir)
+	writeWcc(w, &toDir)
+	return nil
+}
+
+// renameFile renames from to to which are in the same export
+func (p nfsProg) renameFile(dir *file, from, to handleEntry) uint32 {
+	if from.path == to.path {
+		return nfs3OK
+	}
+	if isInside(to.path, from.path) {
+		return nfs3ErrInval
+	}
+	fromNode, err := dir.vfs.Stat(from.path)
+	if err != nil {
+		return nfsStatus(err)
+	}
+	toNode, err := dir.vfs.Stat(to.path)
+	if err == nil {
+		// Overwriting an existing entry
+		switch {
+		case fromNode.IsDir() && !toNode.IsDir():
+			return nfs3ErrNotDir
+		case !fromNode.IsDir() && toNode.IsDir():
+			return nfs3ErrIsDir
+		case toNode.IsDir():
+			// Directories can only be replaced if empty
+			p.s.files.closeInside(to.export, to.path)
+			if err := toNode.Remove(); err != nil {
+				return nfsStatus(err)
+			}
+		default:
+			p.s.files.closeInside(to.export, to.path)
+		}
+	} else if err != vfs.ENOENT {
+		return nfsStatus(err)
+	}
+	p.s.files.closeInside(from.export, from.path)
+	if err := dir.vfs.Rename(from.path, to.path); err != nil {
+		return nfsStatus(err)
+	}
+	p.s.handles.rename(from.export, from.path, to.path)
+	return nfs3OK
+}
+
+// dirEntry is an entry returned by READDIR or READDIRPLUS
+type dirEntry struct {
+	name  string
+	entry handleEntry
+	node  vfs.Node
+}
+
+// readDir implements READDIR and READDIRPLUS
+func (p nfsProg) readDir(c *rpcCall, w *xdrWriter, plus bool) error {
+	f, status := p.readFile(c)
+	cookie := c.args.uint64()
+	_ = c.args.fixedOpaque(8) // cookie verifier
+	dirCount := c.args.uint32()
+	maxCount := dirCount
+	if plus {
+		maxCount = c.args.uint32()
+	}
+	if c.args.err != nil {
+		return errGarbageArgs
+	}
+	var dir *vfs.Dir
+	if status == nfs3OK {
+		dir, status = f.dir()
+	}
+	var entries []dirEntry
+	if status == nfs3OK {
+		var err error
+		entries, err = p.listDir(&f, dir)
+		status = nfsStatus(err)
+	}
+	if status == nfs3OK && cookie > uint64(len(entries)) {
+		status = nfs3ErrBadCookie
+	}
+	w.uint32(status)
+	writePostOpAttr(w, &f)
+	if status != nfs3OK {
+		return nil
+	}
+	var cookieVerifier [8]byte
+	w.fixedOpaque(cookieVerifier[:])
+
+	// Encode the entries into a separate buffer so we can keep
+	// the reply within the limits the client asked for.
+	const overhead = 4 + 4 + 84 + 8 + 4 + 4 // status, post_op_attr, verifier, eof
+	var out xdrWriter
+	dirSize := 0
+	n := 0
+	for i := int(cookie); i < len(entries); i++ {
+		e := &entries[i]
+		var entry xdrWriter
+		h := p.s.handles.toHandle(e.entry.export, e.entry.path)
+		entry.bool(true) // value follows
+		entry.uint64(h.fileID())
+		entry.string(e.name)
+		entry.uint64(uint64(i + 1))
+		dirSize += entry.Len()
+		if plus {
+			writePostOpAttr(&entry, &file{h: h, entry: e.entry, vfs: f.vfs, node: e.node})
+			entry.bool(true)
+			entry.opaque(h[:])
+		}
+		if dirSize > int(dirCount) || overhead+out.Len()+entry.Len() > int(maxCount) {
+			break
+		}
+		_, _ = out.Write(entry.Bytes())
+		n++
+	}
+	if n == 0 && int(cookie) < len(entries) {
+		// No entries would fit in the space provided - return
+		// the error after the attributes already written.
+		fail := &xdrWriter{}
+		fail.uint32(nfs3ErrTooSmall)
+		writePostOpAttr(fail, &f)
+		w.Reset()
+		_, _ = w.Write(fail.Bytes())
+		return nil
+	}
+	_, _ = w.Write(out.Bytes())
+	w.bool(false) // no more entries
+	w.bool(int(cookie)+n >= len(entries))
+	return nil
+}
+
+// listDir lists the directory including "." and ".."
+func (p nfsProg) listDir(f *file, dir *vfs.Dir) (entries []dirEntry, err error) {
+	nodes, err := dir.ReadDirAll()
+	if err != nil {
+		return nil, err
+	}
+	entries = make([]dirEntry, 0, len(nodes)+2)
+	entries = append(entries, dirEntry{name: ".", entry: f.entry, node: f.node})
+	parent := f.entry
+	if parent.path != "" {
+		parent.path = path.Dir(parent.path)
+		if parent.path == "." {
+			parent.path = ""
+		}
+	}
+	parentNode, err := f.vfs.Stat(parent.path)
+	if err != nil {
+		return nil, err
+	}
+	entries = append(entries, dirEntry{name: "..", entry: parent, node: parentNode})
+	for _, node := range nodes {
+		entries = append(entries, dirEntry{
+			name:  node.Name(),
+			entry: f.child(node.Name()),
+			node:  node,
+		})
+	}
+	return entries, nil
+}
+
+// fsStat implements FSSTAT
+func (p nfsProg) fsStat(c *rpcCall, w *xdrWriter) error {
+	f, status := p.readFile(c)
+	w.uint32(status)
+	writePostOpAttr(w, &f)
+	if status != nfs3OK {
+		return nil
+	}
+	total, _, free := f.vfs.Statfs()
+	w.uint64(uint64(total))
+	w.uint64(uint64(free))
+	w.uint64(uint64(free))
+	// rclone doesn't have a limit on the number of files
+	const files = 1 << 50
+	w.uint64(files)
+	w.uint64(files)
+	w.uint64(files)
+	w.uint32(uint32(f.vfs.Opt.DirCacheTime / time.Second)) // invarsec
+	return nil
+}
+
+// fsInfo implements FSINFO
+func (p nfsProg) fsInfo(c *rpcCall, w *xdrWriter) error {
+	f, status := p.readFile(c)
+	w.uint32(status)
+	writePostOpAttr(w, &f)
+	if status != nfs3OK {
+		return nil
+	}
+	w.uint32(maxReadWriteSize) // rtmax
+	w.uint32(maxReadWriteSize) // rtpref
+	w.uint32(4096)             // rtmult
+	w.uint32(maxReadWriteSize) // wtmax
+	w.uint32(maxReadWriteSize) // wtpref
+	w.uint32(4096)             // wtmult
+	w.uint32(preferredDirSize) // dtpref
+	w.uint64(math.MaxInt64)    // maxfilesize
+	precision := f.vfs.Fs().Precision()
+	if precision == fs.ModTimeNotSupported || precision <= 0 {
+		precision = time.Second
+	}
+	w.uint32(uint32(precision / time.Second))
+	w.uint32(uint32(precision % time.Second))
+	w.uint32(fsf3Homogeneous | fsf3CanSetTime)
+	return nil
+}
+
+// pathConf implements PATHCONF
+func (p nfsProg) pathConf(c *rpcCall, w *xdrWriter) error {
+	f, status := p.readFile(c)
+	w.uint32(status)
+	writePostOpAttr(w, &f)
+	if status != nfs3OK {
+		return nil
+	}
+	w.uint32(1)          // linkmax
+	w.uint32(maxNameLen) // name_max
+	w.bool(true)         // no_trunc
+	w.bool(true)         // chown_restricted
+	w.bool(false)        // case_insensitive
+	w.bool(true)         // case_preserving
+	return nil
+}
+
+// commit implements COMMIT
+func (p nfsProg) commit(c *rpcCall, w *xdrWriter) error {
+	f, status := p.readFile(c)
+	_ = c.args.uint64() // offset
+	_ = c.args.uint32() // count
+	if c.args.err != nil {
+		return errGarbageArgs
+	}
+	if status == nfs3OK && readWrite(f.vfs) {
+		// Closing the handle writes the file into the VFS cache
+		// from where it will be uploaded. Without the cache the
+		// file is uploaded when the handle becomes idle, as the
+		// client may commit part way through writing a file.
+		status = nfsStatus(p.s.files.closeFile(f.entry))
+	}
+	w.uint32(status)
+	writeWcc(w, &f)
+	if status == nfs3OK {
+		w.fixedOpaque(p.s.verifier[:])
+	}
+	return nil
+}
+
+// Check interfaces
+var _ rpcProgram = nfsProg{}
@@ -0,0 +1,421 @@
+//go:build !plan9
+// +build !plan9
+
+package nfs
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "github.com/rclone/rclone/backend/local"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fstest/mockfs"
+	"github.com/rclone/rclone/vfs/vfscommon"
+	"github.com/rclone/rclone/vfs/vfsflags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXDR(t *testing.T) {
+	var w xdrWriter
+	w.uint32(1)
+	w.uint64(1 << 40)
+	w.bool(true)
+	w.string("hello")
+	w.opaque([]byte{1, 2, 3, 4})
+	w.fixedOpaque([]byte{5})
+	assert.Equal(t, 4+8+4+4+8+4+4+4, w.Len())
+
+	r := newXDRReader(w.Bytes())
+	assert.Equal(t, uint32(1), r.uint32())
+	assert.Equal(t, uint64(1<<40), r.uint64())
+	assert.Equal(t, true, r.bool())
+	assert.Equal(t, "hello", r.string(10))
+	assert.Equal(t, []byte{1, 2, 3, 4}, r.opaque(10))
+	assert.Equal(t, []byte{5}, r.fixedOpaque(1))
+	require.NoError(t, r.err)
+	assert.Equal(t, 0, len(r.buf))
+
+	// Reading past the end is an error
+	assert.Equal(t, uint32(0), r.uint32())
+	assert.Equal(t, errGarbageArgs, r.err)
+
+	// Over long strings are an error
+	r = newXDRReader(w.Bytes()[4+8+4:])
+	assert.Equal(t, "", r.string(4))
+	assert.Equal(t, errGarbageArgs, r.err)
+}
+
+func TestHandleMap(t *testing.T) {
+	m := newHandleMap(context.Background(), nil, false)
+	root := m.toHandle("", "")
+	dir := m.toHandle("", "dir")
+	file := m.toHandle("", "dir/file")
+	assert.Equal(t, root, m.toHandle("", ""))
+	assert.NotEqual(t, root, dir)
+	assert.NotEqual(t, dir, file)
+
+	// Renames keep the handles
+	m.rename("", "dir", "newdir")
+	e, found := m.fromHandle(file)
+	require.True(t, found)
+	assert.Equal(t, "newdir/file", e.path)
+	assert.Equal(t, file, m.toHandle("", "newdir/file"))
+
+	// A new file in the old place gets a new handle
+	assert.NotEqual(t, file, m.toHandle("", "dir/file"))
+
+	// Removes forget the handles
+	m.remove("", "newdir")
+	_, found = m.fromHandle(dir)
+	assert.False(t, found)
+	_, found = m.fromHandle(file)
+	assert.False(t, found)
+	_, found = m.fromHandle(root)
+	assert.True(t, found)
+}
+
+func TestHandleFacility(t *testing.T) {
+	ctx := context.Background()
+	a := handleFacility(mockfs.NewFs(ctx, "remote", "a"))
+	b := handleFacility(mockfs.NewFs(ctx, "remote", "b"))
+	assert.True(t, strings.HasPrefix(a, kvFacility+"-"))
+	assert.NotEqual(t, a, b)
+	assert.Equal(t, a, handleFacility(mockfs.NewFs(ctx, "other", "a")))
+}
+
+// testClient is a minimal NFS client
+type testClient struct {
+	t    *testing.T
+	conn net.Conn
+	xid  uint32
+}
+
+// call the procedure returning a reader for the results
+func (c *testClient) call(prog, proc uint32, args func(w *xdrWriter)) *xdrReader {
+	c.xid++
+	var w xdrWriter
+	w.uint32(c.xid)
+	w.uint32(msgCall)
+	w.uint32(rpcVersion)
+	w.uint32(prog)
+	if prog == nfsProgram {
+		w.uint32(nfsVersion)
+	} else {
+		w.uint32(mountVersion)
+	}
+	w.uint32(proc)
+	var cred xdrWriter
+	cred.uint32(0)
+	cred.string("test")
+	cred.uint32(1000)
+	cred.uint32(1000)
+	cred.uint32(0)
+	w.uint32(authSys)
+	w.opaque(cred.Bytes())
+	w.uint32(authNone)
+	w.opaque(nil)
+	if args != nil {
+		args(&w)
+	}
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(w.Len())|lastFragment)
+	_, err := c.conn.Write(append(header[:], w.Bytes()...))
+	require.NoError(c.t, err)
+
+	record, err := readRecord(c.conn)
+	require.NoError(c.t, err)
+	r := newXDRReader(record)
+	assert.Equal(c.t, c.xid, r.uint32())
+	assert.Equal(c.t, uint32(msgReply), r.uint32())
+	assert.Equal(c.t, uint32(msgAccepted), r.uint32())
+	_ = r.uint32()
+	_ = r.opaque(maxAuthBody)
+	require.Equal(c.t, uint32(acceptSuccess), r.uint32())
+	return r
+}
+
+// skipAttr skips a post_op_attr
+func skipAttr(r *xdrReader) {
+	if r.bool() {
+		r.next(84)
+	}
+}
+
+// skipWcc skips a wcc_data
+func skipWcc(r *xdrReader) {
+	if r.bool() {
+		r.next(24)
+	}
+	skipAttr(r)
+}
+
+// dirOp writes diropargs3
+func dirOp(h []byte, name string) func(w *xdrWriter) {
+	return func(w *xdrWriter) {
+		w.opaque(h)
+		w.string(name)
+	}
+}
+
+func (c *testClient) lookup(dir []byte, name string) (status uint32, h []byte) {
+	r := c.call(nfsProgram, nfsProcLookup, dirOp(dir, name))
+	status = r.uint32()
+	if status == nfs3OK {
+		h = r.opaque(maxHandleSize)
+	}
+	return status, h
+}
+
+func (c *testClient) create(dir []byte, name string) []byte {
+	r := c.call(nfsProgram, nfsProcCreate, func(w *xdrWriter) {
+		dirOp(dir, name)(w)
+		w.uint32(createGuarded)
+		w.bool(false)
+		w.bool(false)
+		w.bool(false)
+		w.bool(true)
+		w.uint64(0)
+		w.uint32(timeDontChange)
+		w.uint32(timeDontChange)
+	})
+	require.Equal(c.t, uint32(nfs3OK), r.uint32())
+	require.True(c.t, r.bool())
+	return r.opaque(maxHandleSize)
+}
+
+func (c *testClient) write(h []byte, offset uint64, data string, stable uint32) {
+	r := c.call(nfsProgram, nfsProcWrite, func(w *xdrWriter) {
+		w.opaque(h)
+		w.uint64(offset)
+		w.uint32(uint32(len(data)))
+		w.uint32(stable)
+		w.string(data)
+	})
+	require.Equal(c.t, uint32(nfs3OK), r.uint32())
+	skipWcc(r)
+	assert.Equal(c.t, uint32(len(data)), r.uint32())
+}
+
+func (c *testClient) read(h []byte, offset uint64, count uint32) (string, bool) {
+	r := c.call(nfsProgram, nfsProcRead, func(w *xdrWriter) {
+		w.opaque(h)
+		w.uint64(offset)
+		w.uint32(count)
+	})
+	require.Equal(c.t, uint32(nfs3OK), r.uint32())
+	skipAttr(r)
+	n := r.uint32()
+	eof := r.bool()
+	data := r.string(int(count))
+	assert.Equal(c.t, int(n), len(data))
+	return data, eof
+}
+
+func (c *testClient) commit(h []byte) {
+	r := c.call(nfsProgram, nfsProcCommit, func(w *xdrWriter) {
+		w.opaque(h)
+		w.uint64(0)
+		w.uint32(0)
+	})
+	require.Equal(c.t, uint32(nfs3OK), r.uint32())
+}
+
+func (c *testClient) getAttr(h []byte) (status uint32, fileType uint32, size uint64) {
+	r := c.call(nfsProgram, nfsProcGetAttr, func(w *xdrWriter) {
+		w.opaque(h)
+	})
+	status = r.uint32()
+	if status == nfs3OK {
+		fileType = r.uint32()
+		r.next(16)
+		size = r.uint64()
+	}
+	return status, fileType, size
+}
+
+func (c *testClient) readDirPlus(h []byte) (names []string) {
+	r := c.call(nfsProgram, nfsProcReadDirPlus, func(w *xdrWriter) {
+		w.opaque(h)
+		w.uint64(0)
+		w.fixedOpaque(make([]byte, 8))
+		w.uint32(4096)
+		w.uint32(32768)
+	})
+	require.Equal(c.t, uint32(nfs3OK), r.uint32())
+	skipAttr(r)
+	r.next(8)
+	for r.bool() {
+		_ = r.uint64()
+		names = append(names, r.string(maxNameLen))
+		_ = r.uint64()
+		skipAttr(r)
+		if r.bool() {
+			_ = r.opaque(maxHandleSize)
+		}
+	}
+	assert.True(c.t, r.bool())
+	require.NoError(c.t, r.err)
+	return names
+}
+
+func testNFS(t *testing.T, cacheMode vfscommon.CacheMode) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	f, err := fs.NewFs(ctx, dir)
+	require.NoError(t, err)
+
+	oldOpt := vfsflags.Opt
+	vfsflags.Opt.CacheMode = cacheMode
+	vfsflags.Opt.WriteBack = 0
+	defer func() {
+		vfsflags.Opt = oldOpt
+	}()
+
+	opt := DefaultOpt
+	opt.ListenAddr = "localhost:0"
+	opt.HandleCache = handleCacheMemory
+	s, err := newServer(ctx, f, &opt)
+	require.NoError(t, err)
+	require.NoError(t, s.serve())
+	defer func() {
+		require.NoError(t, s.Close())
+		s.vfs.Shutdown()
+	}()
+
+	conn, err := net.Dial("tcp", s.Addr().String())
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close()
+	}()
+	c := &testClient{t: t, conn: conn}
+
+	// NULL on both programs
+	c.call(mountProgram, mountProcNull, nil)
+	c.call(nfsProgram, nfsProcNull, nil)
+
+	// Mount the root
+	r := c.call(mountProgram, mountProcMnt, func(w *xdrWriter) {
+		w.string("/")
+	})
+	require.Equal(t, uint32(mnt3OK), r.uint32())
+	root := r.opaque(maxHandleSize)
+	require.Len(t, root, handleSize)
+
+	// Mounting something which doesn't exist fails
+	r = c.call(mountProgram, mountProcMnt, func(w *xdrWriter) {
+		w.string("/notfound")
+	})
+	assert.Equal(t, uint32(mnt3ErrNoEnt), r.uint32())
+
+	status, fileType, _ := c.getAttr(root)
+	require.Equal(t, uint32(nfs3OK), status)
+	assert.Equal(t, uint32(nf3Dir), fileType)
+
+	// Bad handles
+	status, _, _ = c.getAttr([]byte{1, 2, 3})
+	assert.Equal(t, uint32(nfs3ErrBadHandle), status)
+	status, _, _ = c.getAttr(make([]byte, handleSize))
+	assert.Equal(t, uint32(nfs3ErrStale), status)
+
+	// Create and write a file
+	file := c.create(root, "hello.txt")
+	c.write(file, 0, "hello ", stableUnstable)
+	c.write(file, 6, "world", stableUnstable)
+	c.commit(file)
+
+	status, fileType, size := c.getAttr(file)
+	require.Equal(t, uint32(nfs3OK), status)
+	assert.Equal(t, uint32(nf3Reg), fileType)
+	assert.Equal(t, uint64(11), size)
+
+	// Creating it again with GUARDED fails
+	r = c.call(nfsProgram, nfsProcCreate, func(w *xdrWriter) {
+		dirOp(root, "hello.txt")(w)
+		w.uint32(createGuarded)
+		for i := 0; i < 4; i++ {
+			w.bool(false)
+		}
+		w.uint32(timeDontChange)
+		w.uint32(timeDontChange)
+	})
+	assert.Equal(t, uint32(nfs3ErrExist), r.uint32())
+
+	// Read it back
+	data, eof := c.read(file, 0, 100)
+	assert.Equal(t, "hello world", data)
+	assert.True(t, eof)
+	data, eof = c.read(file, 6, 3)
+	assert.Equal(t, "wor", data)
+	assert.False(t, eof)
+
+	// Lookup
+	status, h := c.lookup(root, "hello.txt")
+	require.Equal(t, uint32(nfs3OK), status)
+	assert.Equal(t, file, h)
+	status, _ = c.lookup(root, "potato")
+	assert.Equal(t, uint32(nfs3ErrNoEnt), status)
+
+	// Make a directory
+	r = c.call(nfsProgram, nfsProcMkdir, func(w *xdrWriter) {
+		dirOp(root, "dir")(w)
+		for i := 0; i < 4; i++ {
+			w.bool(false)
+		}
+		w.uint32(timeDontChange)
+		w.uint32(timeDontChange)
+	})
+	require.Equal(t, uint32(nfs3OK), r.uint32())
+	require.True(t, r.bool())
+	subDir := r.opaque(maxHandleSize)
+
+	// Rename the file into it - the handle should stay the same
+	r = c.call(nfsProgram, nfsProcRename, func(w *xdrWriter) {
+		dirOp(root, "hello.txt")(w)
+		dirOp(subDir, "moved.txt")(w)
+	})
+	require.Equal(t, uint32(nfs3OK), r.uint32())
+	status, h = c.lookup(subDir, "moved.txt")
+	require.Equal(t, uint32(nfs3OK), status)
+	assert.Equal(t, file, h)
+	data, _ = c.read(file, 0, 100)
+	assert.Equal(t, "hello world", data)
+
+	assert.Equal(t, []string{".", "..", "moved.txt"}, c.readDirPlus(subDir))
+	assert.Equal(t, []string{".", "..", "dir"}, c.readDirPlus(root))
+
+	// Check the file arrived on disk
+	got, err := os.ReadFile(filepath.Join(dir, "dir", "moved.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(got))
+
+	// Directory not empty
+	r = c.call(nfsProgram, nfsProcRmdir, dirOp(root, "dir"))
+	assert.Equal(t, uint32(nfs3ErrNotEmpty), r.uint32())
+
+	// Remove the file and directory
+	r = c.call(nfsProgram, nfsProcRemove, dirOp(subDir, "moved.txt"))
+	require.Equal(t, uint32(nfs3OK), r.uint32())
+	status, _, _ = c.getAttr(file)
+	assert.Equal(t, uint32(nfs3ErrStale), status)
+	r = c.call(nfsProgram, nfsProcRmdir, dirOp(root, "dir"))
+	require.Equal(t, uint32(nfs3OK), r.uint32())
+	assert.Equal(t, []string{".", ".."}, c.readDirPlus(root))
+
+	// Unsupported procedures
+	r = c.call(nfsProgram, nfsProcSymlink, nil)
+	assert.Equal(t, uint32(nfs3ErrNotSupp), r.uint32())
+}
+
+func TestNFS(t *testing.T) {
+	for _, cacheMode := range []vfscommon.CacheMode{vfscommon.CacheModeOff, vfscommon.CacheModeWrites} {
+		t.Run(cacheMode.String(), func(t *testing.T) {
+			testNFS(t, cacheMode)
+		})
+	}
+}
@@ -0,0 +1,277 @@
+//go:build !plan9
+// +build !plan9
+
+package nfs
+
+// ONC RPC version 2 as described in RFC 5531 using record marking
+// over TCP.
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/rclone/rclone/fs"
+)
+
+// RPC constants
+const (
+	rpcVersion = 2
+
+	msgCall  = 0
+	msgReply = 1
+
+	msgAccepted = 0
+	msgDenied   = 1
+
+	acceptSuccess      = 0
+	acceptProgUnavail  = 1
+	acceptProgMismatch = 2
+	acceptProcUnavail  = 3
+	acceptGarbageArgs  = 4
+	acceptSystemErr    = 5
+
+	rejectRPCMismatch = 0
+	rejectAuthError   = 1
+
+	authBadCred = 1
+	authTooWeak = 5
+
+	authNone = 0
+	authSys  = 1
+
+	maxAuthBody    = 400
+	maxRecordSize  = 4*1024*1024 + 64*1024 // largest write plus headers
+	lastFragment   = 1 << 31
+	maxConnWorkers = 16 // calls processed concurrently on each connection
+)
+
+// errRecordTooBig is returned if a client sends an over long record
+var errRecordTooBig = errors.New("RPC record too big")
+
+// authUnix is the AUTH_SYS credential sent by the client
+type authUnix struct {
+	machine string
+	uid     uint32
+	gid     uint32
+}
+
+// rpcCall is a decoded RPC call
+type rpcCall struct {
+	xid  uint32
+	prog uint32
+	vers uint32
+	proc uint32
+	cred authUnix // zero unless the flavor was AUTH_SYS
+	args *xdrReader
+	conn *rpcConn
+}
+
+// rpcProgram handles the calls for one RPC program
+type rpcProgram interface {
+	// versions returns the lowest and highest versions supported
+	versions() (low, high uint32)
+	// call runs the procedure writing the results to w. It should
+	// return errProcUnavail if the procedure isn't supported or
+	// errGarbageArgs if the arguments couldn't be decoded.
+	call(c *rpcCall, w *xdrWriter) error
+}
+
+// errProcUnavail is returned by programs for unknown procedures
+var errProcUnavail = errors.New("procedure unavailable")
+
+// rpcConn is a connection from an RPC client
+type rpcConn struct {
+	conn     net.Conn
+	what     string
+	programs map[uint32]rpcProgram
+	writeMu  sync.Mutex
+}
+
+// readRecord reads a record made of one or more fragments
+func readRecord(in io.Reader) ([]byte, error) {
+	var record []byte
+	for {
+		var header [4]byte
+		if _, err := io.ReadFull(in, header[:]); err != nil {
+			return nil, err
+		}
+		marker := binary.BigEndian.Uint32(header[:])
+		size := int(marker &^ lastFragment)
+		if len(record)+size > maxRecordSize {
+			return nil, errRecordTooBig
+		}
+		start := len(record)
+		record = append(record, make([]byte, size)...)
+		if _, err := io.ReadFull(in, record[start:]); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		if marker&lastFragment != 0 {
+			return record, nil
+		}
+	}
+}
+
+// writeRecord writes data as a single fragment record
+func (c *rpcConn) writeRecord(data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(data))|lastFragment)
+	_, err := c.conn.Write(append(header[:], data...))
+	return err
+}
+
+// serve reads calls from the connection until it is closed
+func (c *rpcConn) serve() {
+	defer func() {
+		_ = c.conn.Close()
+	}()
+	in := bufio.NewReader(c.conn)
+	workers := make(chan struct{}, maxConnWorkers)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		record, err := readRecord(in)
+		if err != nil {
+			if err != io.EOF {
+				fs.Debugf(c.what, "Closing connection: %v", err)
+			}
+			return
+		}
+		workers <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+			reply := c.handleRecord(record)
+			if reply == nil {
+				return
+			}
+			if err := c.writeRecord(reply); err != nil {
+				fs.Debugf(c.what, "Failed to write reply: %v", err)
+				_ = c.conn.Close()
+			}
+		}()
+	}
+}
+
+// readAuth reads an opaque_auth returning the AUTH_SYS credential if
+// that was the flavor.
+func readAuth(r *xdrReader) (flavor uint32, cred authUnix) {
+	flavor = r.uint32()
+	body := r.opaque(maxAuthBody)
+	if r.err != nil || flavor != authSys {
+		return flavor, cred
+	}
+	br := newXDRReader(body)
+	_ = br.uint32() // stamp
+	cred.machine = br.string(255)
+	cred.uid = br.uint32()
+	cred.gid = br.uint32()
+	if br.err != nil {
+		// Treat a badly formed credential as AUTH_NONE
+		return authNone, authUnix{}
+	}
+	return flavor, cred
+}
+
+// handleRecord decodes and runs a call returning the reply or nil
+// if the record should be ignored.
+func (c *rpcConn) handleRecord(record []byte) []byte {
+	r := newXDRReader(record)
+	call := &rpcCall{conn: c}
+	call.xid = r.uint32()
+	if r.uint32() != msgCall || r.err != nil {
+		return nil
+	}
+	w := &xdrWriter{}
+	w.uint32(call.xid)
+	w.uint32(msgReply)
+
+	rpcvers := r.uint32()
+	call.prog = r.uint32()
+	call.vers = r.uint32()
+	call.proc = r.uint32()
+	flavor, cred := readAuth(r)
+	_, _ = readAuth(r) // verifier
+	if r.err != nil {
+		return nil
+	}
+	call.cred = cred
+	call.args = r
+
+	if rpcvers != rpcVersion {
+		w.uint32(msgDenied)
+		w.uint32(rejectRPCMismatch)
+		w.uint32(rpcVersion)
+		w.uint32(rpcVersion)
+		return w.Bytes()
+	}
+	if flavor != authNone && flavor != authSys {
+		w.uint32(msgDenied)
+		w.uint32(rejectAuthError)
+		w.uint32(authTooWeak)
+		return w.Bytes()
+	}
+
+	// Accepted reply with a null verifier
+	w.uint32(msgAccepted)
+	w.uint32(authNone)
+	w.uint32(0)
+
+	prog, ok := c.programs[call.prog]
+	if !ok {
+		w.uint32(acceptProgUnavail)
+		return w.Bytes()
+	}
+	if low, high := prog.versions(); call.vers < low || call.vers > high {
+		w.uint32(acceptProgMismatch)
+		w.uint32(low)
+		w.uint32(high)
+		return w.Bytes()
+	}
+
+	results := &xdrWriter{}
+	err := prog.call(call, results)
+	if err == nil && call.args.err != nil {
+		err = errGarbageArgs
+	}
+	switch err {
+	case nil:
+		w.uint32(acceptSuccess)
+		_, _ = w.Write(results.Bytes())
+	case errProcUnavail:
+		w.uint32(acceptProcUnavail)
+	case errGarbageArgs:
+		w.uint32(acceptGarbageArgs)
+	default:
+		fs.Errorf(c.what, "%s: %v", procName(call), err)
+		w.uint32(acceptSystemErr)
+	}
+	return w.Bytes()
+}
+
+// procName describes the call for logging
+func procName(call *rpcCall) string {
+	switch call.prog {
+	case nfsProgram:
+		if int(call.proc) < len(nfsProcNames) {
+			return "NFS " + nfsProcNames[call.proc]
+		}
+	case mountProgram:
+		if int(call.proc) < len(mountProcNames) {
+			return "MOUNT " + mountProcNames[call.proc]
+		}
+	}
+	return fmt.Sprintf("program %d version %d procedure %d", call.prog, call.vers, call.proc)
+}
@@ -0,0 +1,162 @@
+//go:build !plan9
+// +build !plan9
+
+package nfs
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/cmd/serve/proxy"
+	"github.com/rclone/rclone/cmd/serve/proxy/proxyflags"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/vfs"
+	"github.com/rclone/rclone/vfs/vfsflags"
+)
+
+// server contains everything to run the server
+type server struct {
+	f        fs.Fs
+	ctx      context.Context // for global config
+	opt      Options
+	vfs      *vfs.VFS
+	proxy    *proxy.Proxy
+	handles  *handleMap
+	files    *openFiles
+	verifier [8]byte // write verifier - changes each time the server starts
+	listener net.Listener
+	mu       sync.Mutex
+	conns    map[net.Conn]struct{} // open connections
+	wg       sync.WaitGroup        // for the connections
+	closed   chan struct{}         // closed when the server is closed
+}
+
+// newServer makes a new server from the options
+func newServer(ctx context.Context, f fs.Fs, opt *Options) (*server, error) {
+	s := &server{
+		f:      f,
+		ctx:    ctx,
+		opt:    *opt,
+		conns:  make(map[net.Conn]struct{}),
+		closed: make(chan struct{}),
+	}
+	switch s.opt.HandleCache {
+	case handleCacheDisk, handleCacheMemory:
+	default:
+		return nil, fmt.Errorf("unknown --handle-cache %q - must be %q or %q", s.opt.HandleCache, handleCacheDisk, handleCacheMemory)
+	}
+	if proxyflags.Opt.AuthProxy != "" {
+		s.proxy = proxy.New(ctx, &proxyflags.Opt)
+	} else {
+		s.vfs = vfs.New(f, &vfsflags.Opt)
+	}
+	binary.BigEndian.PutUint64(s.verifier[:], uint64(time.Now().UnixNano()))
+	s.handles = newHandleMap(ctx, f, s.opt.HandleCache == handleCacheDisk)
+	s.files = newOpenFiles()
+	return s, nil
+}
+
+// getVFS gets the VFS for the export
+func (s *server) getVFS(export string) (*vfs.VFS, error) {
+	if s.proxy == nil {
+		if export != "" {
+			return nil, errors.New("unknown export")
+		}
+		return s.vfs, nil
+	}
+	// The NFS protocol doesn't pass a password so the auth proxy is
+	// called with an empty one.
+	VFS, _, err := s.proxy.Call(export, "", false)
+	return VFS, err
+}
+
+// serve starts the listener
+func (s *server) serve() (err error) {
+	s.listener, err = net.Listen("tcp", s.opt.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for connections: %w", err)
+	}
+	fs.Logf(nil, "NFS server listening on %v", s.Addr())
+	s.wg.Add(1)
+	go s.acceptConnections()
+	return nil
+}
+
+// Addr returns the address the server is listening on
+func (s *server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// acceptConnections accepts connections and serves them in a go routine
+func (s *server) acceptConnections() {
+	defer s.wg.Done()
+	programs := map[uint32]rpcProgram{
+		nfsProgram:   nfsProg{s: s},
+		mountProgram: mountProg{s: s},
+	}
+	for {
+		nConn, err := s.listener.Accept()
+		if err != nil {
+			select {
+			case <-s.closed:
+				return
+			default:
+			}
+			if strings.Contains(err.Error(), "use of closed network connection") {
+				return
+			}
+			fs.Errorf(nil, "Failed to accept incoming connection: %v", err)
+			continue
+		}
+		s.mu.Lock()
+		s.conns[nConn] = struct{}{}
+		s.mu.Unlock()
+		c := &rpcConn{
+			conn:     nConn,
+			what:     nConn.RemoteAddr().String(),
+			programs: programs,
+		}
+		fs.Debugf(c.what, "NFS connection opened")
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			c.serve()
+			s.mu.Lock()
+			delete(s.conns, nConn)
+			s.mu.Unlock()
+			fs.Debugf(c.what, "NFS connection closed")
+		}()
+	}
+}
+
+// Wait for the server to be closed
+func (s *server) Wait() {
+	<-s.closed
+	s.wg.Wait()
+}
+
+// Close the server, any open connections and the open files
+func (s *server) Close() error {
+	select {
+	case <-s.closed:
+		return nil
+	default:
+	}
+	close(s.closed)
+	err := s.listener.Close()
+	s.mu.Lock()
+	for nConn := range s.conns {
+		_ = nConn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	s.files.close()
+	s.handles.close()
+	return err
+}
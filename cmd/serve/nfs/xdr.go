@@ -0,0 +1,140 @@
+//go:build !plan9
+// +build !plan9
+
+package nfs
+
+// XDR encoding and decoding as described in RFC 4506
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// errGarbageArgs is returned when the arguments can't be decoded
+var errGarbageArgs = errors.New("can't decode arguments")
+
+// xdrReader decodes XDR data from a buffer
+//
+// The first error is sticky and all subsequent reads return zero
+// values.
+type xdrReader struct {
+	buf []byte
+	err error
+}
+
+// newXDRReader makes a reader of buf
+func newXDRReader(buf []byte) *xdrReader {
+	return &xdrReader{buf: buf}
+}
+
+// next returns the next n bytes from the buffer
+func (r *xdrReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.buf) {
+		r.err = errGarbageArgs
+		r.buf = nil
+		return nil
+	}
+	data := r.buf[:n]
+	r.buf = r.buf[n:]
+	return data
+}
+
+// uint32 reads an unsigned int
+func (r *xdrReader) uint32() uint32 {
+	data := r.next(4)
+	if data == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(data)
+}
+
+// uint64 reads an unsigned hyper
+func (r *xdrReader) uint64() uint64 {
+	data := r.next(8)
+	if data == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// bool reads a boolean
+func (r *xdrReader) bool() bool {
+	return r.uint32() != 0
+}
+
+// fixedOpaque reads n bytes of fixed length opaque data
+func (r *xdrReader) fixedOpaque(n int) []byte {
+	data := r.next(n)
+	r.next(pad(n))
+	return data
+}
+
+// opaque reads variable length opaque data of at most max bytes
+func (r *xdrReader) opaque(max int) []byte {
+	n := r.uint32()
+	if r.err == nil && n > uint32(max) {
+		r.err = errGarbageArgs
+		return nil
+	}
+	return r.fixedOpaque(int(n))
+}
+
+// string reads a string of at most max bytes
+func (r *xdrReader) string(max int) string {
+	return string(r.opaque(max))
+}
+
+// pad returns the number of padding bytes needed after n bytes
+func pad(n int) int {
+	return (4 - n%4) % 4
+}
+
+// xdrWriter encodes XDR data into a buffer
+type xdrWriter struct {
+	bytes.Buffer
+}
+
+// uint32 writes an unsigned int
+func (w *xdrWriter) uint32(v uint32) {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	_, _ = w.Write(buf[:])
+}
+
+// uint64 writes an unsigned hyper
+func (w *xdrWriter) uint64(v uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	_, _ = w.Write(buf[:])
+}
+
+// bool writes a boolean
+func (w *xdrWriter) bool(v bool) {
+	if v {
+		w.uint32(1)
+	} else {
+		w.uint32(0)
+	}
+}
+
+// fixedOpaque writes fixed length opaque data
+func (w *xdrWriter) fixedOpaque(data []byte) {
+	_, _ = w.Write(data)
+	var zeros [3]byte
+	_, _ = w.Write(zeros[:pad(len(data))])
+}
+
+// opaque writes variable length opaque data
+func (w *xdrWriter) opaque(data []byte) {
+	w.uint32(uint32(len(data)))
+	w.fixedOpaque(data)
+}
+
+// string writes a string
+func (w *xdrWriter) string(s string) {
+	w.opaque([]byte(s))
+}
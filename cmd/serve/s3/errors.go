@@ -0,0 +1,104 @@
+package s3
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/vfs"
+)
+
+// apiError is an error returned to the client in the S3 XML format
+type apiError struct {
+	Code       string // S3 error code
+	Message    string // human readable message
+	StatusCode int    // HTTP status to return
+}
+
+// Error satisfies the error interface
+func (e *apiError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// S3 errors - see https://docs.aws.amazon.com/AmazonS3/latest/API/ErrorResponses.html
+var (
+	errAccessDenied                      = &apiError{"AccessDenied", "Access Denied", http.StatusForbidden}
+	errAccessDeniedMissingDate           = &apiError{"AccessDenied", "AWS authentication requires a valid Date or x-amz-date header", http.StatusForbidden}
+	errAuthorizationHeaderMalformed      = &apiError{"AuthorizationHeaderMalformed", "The authorization header that you provided is not valid.", http.StatusBadRequest}
+	errAuthorizationQueryParametersError = &apiError{"AuthorizationQueryParametersError", "Error parsing the X-Amz-Credential parameter.", http.StatusBadRequest}
+	errBadDigest                         = &apiError{"BadDigest", "The Content-MD5 you specified did not match what was received.", http.StatusBadRequest}
+	errBucketAlreadyOwnedByYou           = &apiError{"BucketAlreadyOwnedByYou", "Your previous request to create the named bucket succeeded and you already own it.", http.StatusConflict}
+	errBucketNotEmpty                    = &apiError{"BucketNotEmpty", "The bucket that you tried to delete is not empty.", http.StatusConflict}
+	errIncompleteBody                    = &apiError{"IncompleteBody", "You did not provide the number of bytes specified by the Content-Length HTTP header.", http.StatusBadRequest}
+	errInternalError                     = &apiError{"InternalError", "We encountered an internal error. Please try again.", http.StatusInternalServerError}
+	errInvalidAccessKeyID                = &apiError{"InvalidAccessKeyId", "The AWS access key ID that you provided does not exist in our records.", http.StatusForbidden}
+	errInvalidArgument                   = &apiError{"InvalidArgument", "Invalid Argument", http.StatusBadRequest}
+	errInvalidBucketName                 = &apiError{"InvalidBucketName", "The specified bucket is not valid.", http.StatusBadRequest}
+	errInvalidDigest                     = &apiError{"InvalidDigest", "The Content-MD5 you specified is not valid.", http.StatusBadRequest}
+	errInvalidPart                       = &apiError{"InvalidPart", "One or more of the specified parts could not be found.", http.StatusBadRequest}
+	errInvalidPartOrder                  = &apiError{"InvalidPartOrder", "The list of parts was not in ascending order.", http.StatusBadRequest}
+	errInvalidRange                      = &apiError{"InvalidRange", "The requested range cannot be satisfied.", http.StatusRequestedRangeNotSatisfiable}
+	errInvalidRequest                    = &apiError{"InvalidRequest", "Invalid Request", http.StatusBadRequest}
+	errMalformedDate                     = &apiError{"AccessDenied", "X-Amz-Date must be in the ISO8601 Long Format \"yyyyMMdd'T'HHmmss'Z'\"", http.StatusForbidden}
+	errMalformedXML                      = &apiError{"MalformedXML", "The XML that you provided was not well formed or did not validate against our published schema.", http.StatusBadRequest}
+	errMethodNotAllowed                  = &apiError{"MethodNotAllowed", "The specified method is not allowed against this resource.", http.StatusMethodNotAllowed}
+	errMissingContentLength              = &apiError{"MissingContentLength", "You must provide the Content-Length HTTP header.", http.StatusLengthRequired}
+	errNoSuchBucket                      = &apiError{"NoSuchBucket", "The specified bucket does not exist.", http.StatusNotFound}
+	errNoSuchKey                         = &apiError{"NoSuchKey", "The specified key does not exist.", http.StatusNotFound}
+	errNoSuchUpload                      = &apiError{"NoSuchUpload", "The specified multipart upload does not exist.", http.StatusNotFound}
+	errNotImplemented                    = &apiError{"NotImplemented", "A header or query you provided implies functionality that is not implemented.", http.StatusNotImplemented}
+	errRequestExpired                    = &apiError{"AccessDenied", "Request has expired", http.StatusForbidden}
+	errRequestNotYetValid                = &apiError{"AccessDenied", "Request is not valid yet", http.StatusForbidden}
+	errRequestTimeTooSkewed              = &apiError{"RequestTimeTooSkewed", "The difference between the request time and the server's time is too large.", http.StatusForbidden}
+	errSignatureDoesNotMatch             = &apiError{"SignatureDoesNotMatch", "The request signature we calculated does not match the signature you provided.", http.StatusForbidden}
+	errSignatureVersionNotSupported      = &apiError{"InvalidRequest", "Only AWS Signature Version 4 is supported.", http.StatusBadRequest}
+	errXAmzContentSHA256Mismatch         = &apiError{"XAmzContentSHA256Mismatch", "The provided 'x-amz-content-sha256' header does not match what was computed.", http.StatusBadRequest}
+)
+
+// errorResponse is the XML body of an error
+type errorResponse struct {
+	XMLName   xml.Name `xml:"Error"`
+	Code      string
+	Message   string
+	Resource  string `xml:",omitempty"`
+	RequestID string `xml:"RequestId"`
+}
+
+// toAPIError converts err into an *apiError
+//
+// notFound is the error to use if the VFS returns ENOENT
+func toAPIError(err error, notFound *apiError) *apiError {
+	var e *apiError
+	switch {
+	case errors.As(err, &e):
+		return e
+	case errors.Is(err, vfs.ENOENT):
+		return notFound
+	case errors.Is(err, vfs.EROFS), errors.Is(err, vfs.EPERM):
+		return errAccessDenied
+	case errors.Is(err, vfs.ENOTEMPTY):
+		return errBucketNotEmpty
+	}
+	return errInternalError
+}
+
+// writeError writes err to the client as an S3 XML error
+func writeError(w http.ResponseWriter, r *http.Request, err error, notFound *apiError) {
+	e := toAPIError(err, notFound)
+	if e == errInternalError {
+		fs.Errorf(r.URL.Path, "%s %s failed: %v", r.Method, r.URL.Path, err)
+	} else {
+		fs.Debugf(r.URL.Path, "%s %s: %v", r.Method, r.URL.Path, err)
+	}
+	if r.Method == http.MethodHead {
+		w.WriteHeader(e.StatusCode)
+		return
+	}
+	writeXML(w, e.StatusCode, &errorResponse{
+		Code:      e.Code,
+		Message:   e.Message,
+		Resource:  r.URL.Path,
+		RequestID: w.Header().Get("x-amz-request-id"),
+	})
+}
@@ -0,0 +1,111 @@
+package s3
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/rclone/rclone/vfs"
+)
+
+// metaMtime is the user metadata key the s3 backend stores the
+// modification time in. This is stored as the modification time of
+// the file rather than in the metaStore.
+const metaMtime = "x-amz-meta-mtime"
+
+// Headers which are stored as object metadata. Any x-amz-meta-
+// headers are stored too.
+var metaHeaders = []string{
+	"cache-control",
+	"content-disposition",
+	"content-encoding",
+	"content-language",
+	"content-type",
+	"expires",
+	"x-amz-storage-class",
+	"x-amz-website-redirect-location",
+}
+
+// objectMeta is the metadata for an object, keyed on the lower case
+// header name
+type objectMeta map[string]string
+
+// metaFromHeader reads the object metadata from the request headers
+func metaFromHeader(h http.Header) objectMeta {
+	meta := objectMeta{}
+	for _, k := range metaHeaders {
+		if v := h.Get(k); v != "" {
+			meta[k] = v
+		}
+	}
+	for k, vs := range h {
+		k = strings.ToLower(k)
+		if strings.HasPrefix(k, "x-amz-meta-") && k != metaMtime && len(vs) > 0 {
+			meta[k] = vs[0]
+		}
+	}
+	return meta
+}
+
+// setHeaders writes the metadata into the response headers
+func (meta objectMeta) setHeaders(h http.Header) {
+	for k, v := range meta {
+		h.Set(k, v)
+	}
+}
+
+// metaStore is an in memory store of the object metadata which can't
+// be stored in the VFS
+type metaStore struct {
+	mu   sync.Mutex
+	meta map[string]objectMeta // path => metadata
+}
+
+// get returns the metadata for the path which may be nil
+func (m *metaStore) get(p string) objectMeta {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.meta[p]
+}
+
+// set the metadata for the path, removing it if meta is empty
+func (m *metaStore) set(p string, meta objectMeta) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(meta) == 0 {
+		delete(m.meta, p)
+	} else {
+		m.meta[p] = meta
+	}
+}
+
+// remove the metadata for the path
+func (m *metaStore) remove(p string) {
+	m.set(p, nil)
+}
+
+// removeDir removes the metadata for all paths in the directory
+func (m *metaStore) removeDir(dir string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	prefix := dir + "/"
+	for p := range m.meta {
+		if strings.HasPrefix(p, prefix) {
+			delete(m.meta, p)
+		}
+	}
+}
+
+// metaFor returns the metaStore for the VFS
+func (s *server) metaFor(VFS *vfs.VFS) *metaStore {
+	s.metaMu.Lock()
+	defer s.metaMu.Unlock()
+	m := s.meta[VFS]
+	if m == nil {
+		m = &metaStore{
+			meta: make(map[string]objectMeta),
+		}
+		s.meta[VFS] = m
+	}
+	return m
+}
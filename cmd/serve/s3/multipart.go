@@ -0,0 +1,585 @@
+package s3
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config"
+	"github.com/rclone/rclone/vfs"
+)
+
+const (
+	maxPartNumber = 10000 // maximum part number allowed
+	maxListParts  = 1000  // maximum number of parts to return in a listing
+)
+
+// part is a part of a multipart upload stored on disk
+type part struct {
+	number       int
+	etag         string // hex MD5 of the part
+	size         int64
+	lastModified time.Time
+}
+
+// upload is a multipart upload in progress
+type upload struct {
+	id        string
+	VFS       *vfs.VFS
+	bucket    string
+	key       string
+	meta      objectMeta
+	modTime   time.Time
+	initiated time.Time
+	dir       string // directory the parts are stored in
+
+	mu       sync.Mutex
+	parts    map[int]*part
+	complete bool // set once the upload is being completed
+}
+
+// partPath returns the file name for the part
+func (u *upload) partPath(number int) string {
+	return filepath.Join(u.dir, strconv.Itoa(number))
+}
+
+// uploads holds the multipart uploads in progress
+type uploads struct {
+	mu      sync.Mutex
+	dir     string             // directory for parts - made on first use
+	uploads map[string]*upload // upload ID => upload
+}
+
+// newUploads makes a new uploads
+func newUploads() *uploads {
+	return &uploads{
+		uploads: make(map[string]*upload),
+	}
+}
+
+// create a new upload
+func (us *uploads) create(VFS *vfs.VFS, bucket, key string, meta objectMeta, modTime time.Time) (*upload, error) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	if us.dir == "" {
+		cacheDir := filepath.Join(config.GetCacheDir(), "serve-s3")
+		err := os.MkdirAll(cacheDir, 0700)
+		if err != nil {
+			return nil, fmt.Errorf("failed to make cache directory: %w", err)
+		}
+		us.dir, err = ioutil.TempDir(cacheDir, "uploads-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to make uploads directory: %w", err)
+		}
+	}
+	var id [16]byte
+	_, err := rand.Read(id[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to make upload ID: %w", err)
+	}
+	u := &upload{
+		id:        hex.EncodeToString(id[:]),
+		VFS:       VFS,
+		bucket:    bucket,
+		key:       key,
+		meta:      meta,
+		modTime:   modTime,
+		initiated: time.Now(),
+		parts:     make(map[int]*part),
+	}
+	u.dir = filepath.Join(us.dir, u.id)
+	err = os.Mkdir(u.dir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make upload directory: %w", err)
+	}
+	us.uploads[u.id] = u
+	return u, nil
+}
+
+// get the upload checking it is for the VFS, bucket and key
+func (us *uploads) get(VFS *vfs.VFS, bucket, key, id string) (*upload, error) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	u, ok := us.uploads[id]
+	if !ok || u.VFS != VFS || u.bucket != bucket || u.key != key {
+		return nil, errNoSuchUpload
+	}
+	return u, nil
+}
+
+// remove the upload and its parts
+func (us *uploads) remove(u *upload) {
+	us.mu.Lock()
+	delete(us.uploads, u.id)
+	us.mu.Unlock()
+	err := os.RemoveAll(u.dir)
+	if err != nil {
+		fs.Errorf(nil, "Failed to remove parts of upload %s: %v", u.id, err)
+	}
+}
+
+// list returns the uploads in the bucket sorted by key then initiated time
+func (us *uploads) list(VFS *vfs.VFS, bucket string) []*upload {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	var list []*upload
+	for _, u := range us.uploads {
+		if u.VFS == VFS && u.bucket == bucket {
+			list = append(list, u)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].key != list[j].key {
+			return list[i].key < list[j].key
+		}
+		return list[i].initiated.Before(list[j].initiated)
+	})
+	return list
+}
+
+// close removes all the uploads in progress
+func (us *uploads) close() {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	if us.dir == "" {
+		return
+	}
+	err := os.RemoveAll(us.dir)
+	if err != nil {
+		fs.Errorf(nil, "Failed to remove multipart uploads: %v", err)
+	}
+	us.uploads = make(map[string]*upload)
+	us.dir = ""
+}
+
+// createMultipartUpload starts a new multipart upload
+func (s *server) createMultipartUpload(w http.ResponseWriter, r *http.Request, VFS *vfs.VFS, bucket, key string) {
+	if _, err := bucketDir(VFS, bucket); err != nil {
+		writeError(w, r, err, errNoSuchBucket)
+		return
+	}
+	if err := checkKey(key); err != nil || strings.HasSuffix(key, "/") {
+		writeError(w, r, errInvalidArgument, errNoSuchKey)
+		return
+	}
+	u, err := s.uploads.create(VFS, bucket, key, metaFromHeader(r.Header), mtimeFromHeader(r.Header))
+	if err != nil {
+		writeError(w, r, err, errNoSuchKey)
+		return
+	}
+	fs.Debugf(path.Join(bucket, key), "Created multipart upload %s", u.id)
+	writeXML(w, http.StatusOK, &initiateMultipartUploadResult{
+		Xmlns:    s3Namespace,
+		Bucket:   bucket,
+		Key:      key,
+		UploadID: u.id,
+	})
+}
+
+// parsePartNumber reads the partNumber from the query
+func parsePartNumber(r *http.Request) (int, error) {
+	n, err := strconv.Atoi(r.URL.Query().Get("partNumber"))
+	if err != nil || n < 1 || n > maxPartNumber {
+		return 0, errInvalidArgument
+	}
+	return n, nil
+}
+
+// parseCopyRange parses the X-Amz-Copy-Source-Range header which is
+// in the form "bytes=first-last"
+func parseCopyRange(v string, size int64) (offset, length int64, err error) {
+	if v == "" {
+		return 0, size, nil
+	}
+	if !strings.HasPrefix(v, "bytes=") {
+		return 0, 0, errInvalidRange
+	}
+	parts := strings.SplitN(strings.TrimPrefix(v, "bytes="), "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, errInvalidRange
+	}
+	first, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, errInvalidRange
+	}
+	last, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || first < 0 || last < first || last >= size {
+		return 0, 0, errInvalidRange
+	}
+	return first, last - first + 1, nil
+}
+
+// writePart writes in to the part returning it
+func (u *upload) writePart(number int, in io.Reader) (p *part, err error) {
+	out, err := ioutil.TempFile(u.dir, "part-")
+	if err != nil {
+		return nil, err
+	}
+	hasher := md5.New()
+	size, err := io.Copy(io.MultiWriter(out, hasher), in)
+	closeErr := out.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(out.Name(), u.partPath(number))
+	}
+	if err != nil {
+		_ = os.Remove(out.Name())
+		return nil, err
+	}
+	return &part{
+		number:       number,
+		etag:         hex.EncodeToString(hasher.Sum(nil)),
+		size:         size,
+		lastModified: time.Now(),
+	}, nil
+}
+
+// uploadPart uploads a part of a multipart upload with UploadPart or
+// UploadPartCopy
+func (s *server) uploadPart(w http.ResponseWriter, r *http.Request, VFS *vfs.VFS, bucket, key, uploadID string) {
+	u, err := s.uploads.get(VFS, bucket, key, uploadID)
+	if err != nil {
+		writeError(w, r, err, errNoSuchUpload)
+		return
+	}
+	number, err := parsePartNumber(r)
+	if err != nil {
+		writeError(w, r, err, errNoSuchUpload)
+		return
+	}
+
+	var in io.Reader = r.Body
+	copySource := r.Header.Get("X-Amz-Copy-Source") != ""
+	if copySource {
+		_, file, err := openCopySource(r, VFS)
+		if err != nil {
+			writeError(w, r, err, errNoSuchKey)
+			return
+		}
+		offset, length, err := parseCopyRange(r.Header.Get("X-Amz-Copy-Source-Range"), file.Size())
+		if err != nil {
+			writeError(w, r, err, errNoSuchKey)
+			return
+		}
+		fh, err := file.Open(os.O_RDONLY)
+		if err != nil {
+			writeError(w, r, err, errNoSuchKey)
+			return
+		}
+		defer func() {
+			_ = fh.Close()
+		}()
+		in = io.NewSectionReader(fh, offset, length)
+	}
+	wantMD5, err := contentMD5(r)
+	if err != nil {
+		writeError(w, r, err, errNoSuchUpload)
+		return
+	}
+
+	p, err := u.writePart(number, in)
+	if err != nil {
+		writeError(w, r, err, errNoSuchUpload)
+		return
+	}
+	if wantMD5 != nil && hex.EncodeToString(wantMD5) != p.etag {
+		_ = os.Remove(u.partPath(number))
+		writeError(w, r, errBadDigest, errNoSuchUpload)
+		return
+	}
+
+	u.mu.Lock()
+	if u.complete {
+		u.mu.Unlock()
+		writeError(w, r, errNoSuchUpload, errNoSuchUpload)
+		return
+	}
+	u.parts[number] = p
+	u.mu.Unlock()
+
+	etag := `"` + p.etag + `"`
+	if copySource {
+		writeXML(w, http.StatusOK, &copyPartResult{
+			Xmlns:        s3Namespace,
+			LastModified: formatTime(p.lastModified),
+			ETag:         etag,
+		})
+		return
+	}
+	w.Header().Set("ETag", etag)
+	w.WriteHeader(http.StatusOK)
+}
+
+// partsReader reads the parts of an upload one after the other
+type partsReader struct {
+	u     *upload
+	parts []*part
+	in    *os.File
+}
+
+// Read reads from the current part opening the next one when it is finished
+func (pr *partsReader) Read(p []byte) (n int, err error) {
+	for {
+		if pr.in == nil {
+			if len(pr.parts) == 0 {
+				return 0, io.EOF
+			}
+			pr.in, err = os.Open(pr.u.partPath(pr.parts[0].number))
+			if err != nil {
+				return 0, err
+			}
+			pr.parts = pr.parts[1:]
+		}
+		n, err = pr.in.Read(p)
+		if err == io.EOF {
+			_ = pr.in.Close()
+			pr.in = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+// Close closes any open part
+func (pr *partsReader) Close() error {
+	if pr.in == nil {
+		return nil
+	}
+	err := pr.in.Close()
+	pr.in = nil
+	return err
+}
+
+// completeMultipartUpload assembles the parts into the object
+func (s *server) completeMultipartUpload(w http.ResponseWriter, r *http.Request, VFS *vfs.VFS, bucket, key, uploadID string) {
+	u, err := s.uploads.get(VFS, bucket, key, uploadID)
+	if err != nil {
+		writeError(w, r, err, errNoSuchUpload)
+		return
+	}
+	var req completeMultipartUpload
+	err = xml.NewDecoder(io.LimitReader(r.Body, maxXMLBodySize)).Decode(&req)
+	if err != nil || len(req.Parts) == 0 {
+		writeError(w, r, errMalformedXML, errNoSuchUpload)
+		return
+	}
+
+	// Check the parts requested are the ones we have
+	u.mu.Lock()
+	if u.complete {
+		u.mu.Unlock()
+		writeError(w, r, errNoSuchUpload, errNoSuchUpload)
+		return
+	}
+	parts := make([]*part, 0, len(req.Parts))
+	hasher := md5.New()
+	for i, cp := range req.Parts {
+		if i > 0 && cp.PartNumber <= req.Parts[i-1].PartNumber {
+			u.mu.Unlock()
+			writeError(w, r, errInvalidPartOrder, errNoSuchUpload)
+			return
+		}
+		p, ok := u.parts[cp.PartNumber]
+		if !ok || strings.Trim(cp.ETag, `"`) != p.etag {
+			u.mu.Unlock()
+			writeError(w, r, errInvalidPart, errNoSuchUpload)
+			return
+		}
+		sum, _ := hex.DecodeString(p.etag)
+		_, _ = hasher.Write(sum)
+		parts = append(parts, p)
+	}
+	u.complete = true
+	u.mu.Unlock()
+
+	etag := fmt.Sprintf(`"%s-%d"`, hex.EncodeToString(hasher.Sum(nil)), len(parts))
+	p := path.Join(bucket, key)
+	s.longRunning(w, r, func() (interface{}, error) {
+		defer s.uploads.remove(u)
+		in := &partsReader{u: u, parts: parts}
+		err := writeFile(VFS, p, in, u.modTime)
+		_ = in.Close()
+		if err != nil {
+			return nil, err
+		}
+		s.metaFor(VFS).set(p, u.meta)
+		fs.Debugf(p, "Completed multipart upload %s with %d parts", u.id, len(parts))
+		return &completeMultipartUploadResult{
+			Xmlns:    s3Namespace,
+			Location: "/" + p,
+			Bucket:   bucket,
+			Key:      key,
+			ETag:     etag,
+		}, nil
+	})
+}
+
+// abortMultipartUpload removes an upload and its parts
+func (s *server) abortMultipartUpload(w http.ResponseWriter, r *http.Request, VFS *vfs.VFS, bucket, key, uploadID string) {
+	u, err := s.uploads.get(VFS, bucket, key, uploadID)
+	if err != nil {
+		writeError(w, r, err, errNoSuchUpload)
+		return
+	}
+	u.mu.Lock()
+	complete := u.complete
+	u.complete = true
+	u.mu.Unlock()
+	if !complete {
+		s.uploads.remove(u)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listParts lists the parts uploaded so far
+func (s *server) listParts(w http.ResponseWriter, r *http.Request, VFS *vfs.VFS, bucket, key, uploadID string) {
+	u, err := s.uploads.get(VFS, bucket, key, uploadID)
+	if err != nil {
+		writeError(w, r, err, errNoSuchUpload)
+		return
+	}
+	q := r.URL.Query()
+	maxParts := maxListParts
+	if v := q.Get("max-parts"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, r, errInvalidArgument, errNoSuchUpload)
+			return
+		}
+		if n < maxParts {
+			maxParts = n
+		}
+	}
+	marker := 0
+	if v := q.Get("part-number-marker"); v != "" {
+		marker, err = strconv.Atoi(v)
+		if err != nil {
+			writeError(w, r, errInvalidArgument, errNoSuchUpload)
+			return
+		}
+	}
+	u.mu.Lock()
+	numbers := make([]int, 0, len(u.parts))
+	for number := range u.parts {
+		if number > marker {
+			numbers = append(numbers, number)
+		}
+	}
+	sort.Ints(numbers)
+	result := listPartsResult{
+		Xmlns:            s3Namespace,
+		Bucket:           bucket,
+		Key:              key,
+		UploadID:         uploadID,
+		Initiator:        defaultOwner,
+		Owner:            defaultOwner,
+		StorageClass:     defaultStorage,
+		PartNumberMarker: marker,
+		MaxParts:         maxParts,
+		Parts:            []partInfo{},
+	}
+	for _, number := range numbers {
+		if len(result.Parts) >= maxParts {
+			result.IsTruncated = true
+			break
+		}
+		p := u.parts[number]
+		result.Parts = append(result.Parts, partInfo{
+			PartNumber:   p.number,
+			LastModified: formatTime(p.lastModified),
+			ETag:         `"` + p.etag + `"`,
+			Size:         p.size,
+		})
+		result.NextPartNumberMarker = p.number
+	}
+	u.mu.Unlock()
+	writeXML(w, http.StatusOK, &result)
+}
+
+// listMultipartUploads lists the uploads in progress in the bucket
+func (s *server) listMultipartUploads(w http.ResponseWriter, r *http.Request, VFS *vfs.VFS, bucket string) {
+	if _, err := bucketDir(VFS, bucket); err != nil {
+		writeError(w, r, err, errNoSuchBucket)
+		return
+	}
+	q := r.URL.Query()
+	maxUploads := maxListKeys
+	if v := q.Get("max-uploads"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, r, errInvalidArgument, errNoSuchBucket)
+			return
+		}
+		if n < maxUploads {
+			maxUploads = n
+		}
+	}
+	prefix := q.Get("prefix")
+	keyMarker := q.Get("key-marker")
+	uploadIDMarker := q.Get("upload-id-marker")
+	result := listMultipartUploadsResult{
+		Xmlns:          s3Namespace,
+		Bucket:         bucket,
+		KeyMarker:      keyMarker,
+		UploadIDMarker: uploadIDMarker,
+		Prefix:         prefix,
+		MaxUploads:     maxUploads,
+		Uploads:        []uploadInfo{},
+	}
+	// Skip uploads up to and including the markers. Uploads with the
+	// same key are sorted by initiated time so skip them until the
+	// upload ID marker has been seen.
+	seenUploadID := uploadIDMarker == ""
+	for _, u := range s.uploads.list(VFS, bucket) {
+		if !strings.HasPrefix(u.key, prefix) || u.key < keyMarker {
+			continue
+		}
+		if u.key == keyMarker {
+			if !seenUploadID {
+				seenUploadID = u.id == uploadIDMarker
+				continue
+			}
+			if uploadIDMarker == "" {
+				continue
+			}
+		}
+		if len(result.Uploads) >= maxUploads {
+			result.IsTruncated = true
+			break
+		}
+		result.Uploads = append(result.Uploads, uploadInfo{
+			Key:          u.key,
+			UploadID:     u.id,
+			Initiator:    defaultOwner,
+			Owner:        defaultOwner,
+			StorageClass: defaultStorage,
+			Initiated:    formatTime(u.initiated),
+		})
+		result.NextKeyMarker = u.key
+		result.NextUploadIDMarker = u.id
+	}
+	if !result.IsTruncated {
+		result.NextKeyMarker = ""
+		result.NextUploadIDMarker = ""
+	}
+	writeXML(w, http.StatusOK, &result)
+}
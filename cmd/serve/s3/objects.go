@@ -0,0 +1,993 @@
+package s3
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ncw/swift/v2"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/vfs"
+)
+
+const (
+	maxListKeys      = 1000            // maximum number of keys to return in a listing
+	maxXMLBodySize   = 4 * 1024 * 1024 // maximum size of XML request bodies
+	emptyMD5         = "d41d8cd98f00b204e9800998ecf8427e"
+	defaultStorage   = "STANDARD"
+	copyDirective    = "COPY"
+	replaceDirective = "REPLACE"
+)
+
+// Response headers which can be overridden by the query string of a
+// GetObject request
+var responseOverrides = map[string]string{
+	"response-cache-control":       "Cache-Control",
+	"response-content-disposition": "Content-Disposition",
+	"response-content-encoding":    "Content-Encoding",
+	"response-content-language":    "Content-Language",
+	"response-content-type":        "Content-Type",
+	"response-expires":             "Expires",
+}
+
+// checkBucketName checks the bucket name is usable as a directory
+func checkBucketName(bucket string) error {
+	if bucket == "" || bucket == "." || bucket == ".." || strings.ContainsAny(bucket, "/\\") {
+		return errInvalidBucketName
+	}
+	return nil
+}
+
+// checkKey checks the key can be stored in the VFS.
+//
+// Keys ending in "/" are directory markers.
+func checkKey(key string) error {
+	k := strings.TrimSuffix(key, "/")
+	if k == "" {
+		return errInvalidArgument
+	}
+	for _, segment := range strings.Split(k, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return errInvalidArgument
+		}
+	}
+	return nil
+}
+
+// bucketDir returns the directory for the bucket
+func bucketDir(VFS *vfs.VFS, bucket string) (*vfs.Dir, error) {
+	if err := checkBucketName(bucket); err != nil {
+		return nil, err
+	}
+	node, err := VFS.Stat(bucket)
+	if err == vfs.ENOENT {
+		return nil, errNoSuchBucket
+	} else if err != nil {
+		return nil, err
+	}
+	dir, ok := node.(*vfs.Dir)
+	if !ok {
+		return nil, errNoSuchBucket
+	}
+	return dir, nil
+}
+
+// mkdirAll makes the directory dir and any parents needed
+func mkdirAll(VFS *vfs.VFS, dir string) error {
+	if dir == "" || dir == "." || dir == "/" {
+		return nil
+	}
+	node, err := VFS.Stat(dir)
+	if err == nil {
+		if node.IsDir() {
+			return nil
+		}
+		return errInvalidRequest
+	}
+	if err != vfs.ENOENT {
+		return err
+	}
+	err = mkdirAll(VFS, path.Dir(dir))
+	if err != nil {
+		return err
+	}
+	return VFS.Mkdir(dir, 0777)
+}
+
+// pruneEmptyDirs removes the empty directories from dir upwards,
+// stopping at the bucket.
+func pruneEmptyDirs(VFS *vfs.VFS, bucket, dir string) {
+	for ; strings.HasPrefix(dir, bucket+"/"); dir = path.Dir(dir) {
+		node, err := VFS.Stat(dir)
+		if err != nil || !node.IsDir() {
+			return
+		}
+		entries, err := node.(*vfs.Dir).ReadDirAll()
+		if err != nil || len(entries) != 0 {
+			return
+		}
+		if err = node.Remove(); err != nil {
+			fs.Debugf(dir, "Failed to remove empty directory: %v", err)
+			return
+		}
+	}
+}
+
+// mtimeFromHeader reads the modification time from the metadata
+// headers returning a zero time if not found.
+func mtimeFromHeader(h http.Header) time.Time {
+	if v := h.Get(metaMtime); v != "" {
+		modTime, err := swift.FloatStringToTime(v)
+		if err == nil {
+			return modTime
+		}
+		fs.Debugf(nil, "Failed to parse %s %q: %v", metaMtime, v, err)
+	}
+	return time.Time{}
+}
+
+// etagHashType returns the hash to use for ETags in the VFS
+func (s *server) etagHashType(VFS *vfs.VFS) hash.Type {
+	if s.etagAuto {
+		return VFS.Fs().Hashes().GetOne()
+	}
+	return s.etagHash
+}
+
+// objectETag returns the quoted ETag for the file or "" if it
+// isn't available.
+func (s *server) objectETag(r *http.Request, VFS *vfs.VFS, file *vfs.File) string {
+	ht := s.etagHashType(VFS)
+	if ht == hash.None {
+		return ""
+	}
+	o, ok := file.DirEntry().(fs.Object)
+	if !ok {
+		return ""
+	}
+	sum, err := o.Hash(r.Context(), ht)
+	if err != nil || sum == "" {
+		return ""
+	}
+	return `"` + sum + `"`
+}
+
+// writeFile writes in to the file p in the VFS making any parent
+// directories needed.
+//
+// If modTime is not zero it is set as the modification time of the
+// file. If the write fails the file is removed.
+func writeFile(VFS *vfs.VFS, p string, in io.Reader, modTime time.Time) (err error) {
+	err = mkdirAll(VFS, path.Dir(p))
+	if err != nil {
+		return err
+	}
+	fh, err := VFS.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0777)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(fh, in)
+	if err == nil && !modTime.IsZero() {
+		err = fh.Node().SetModTime(modTime)
+	}
+	closeErr := fh.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// Don't leave a partial file behind
+		if removeErr := VFS.Remove(p); removeErr != nil && removeErr != vfs.ENOENT {
+			fs.Errorf(p, "Failed to remove partial upload: %v", removeErr)
+		}
+	}
+	return err
+}
+
+// contentMD5 reads the Content-MD5 header returning nil if not set
+func contentMD5(r *http.Request) ([]byte, error) {
+	v := r.Header.Get("Content-MD5")
+	if v == "" {
+		return nil, nil
+	}
+	want, err := base64.StdEncoding.DecodeString(v)
+	if err != nil || len(want) != md5.Size {
+		return nil, errInvalidDigest
+	}
+	return want, nil
+}
+
+// listBuckets lists the top level directories as buckets
+func (s *server) listBuckets(w http.ResponseWriter, r *http.Request, VFS *vfs.VFS) {
+	root, err := VFS.Root()
+	if err != nil {
+		writeError(w, r, err, errNoSuchBucket)
+		return
+	}
+	nodes, err := root.ReadDirAll()
+	if err != nil {
+		writeError(w, r, err, errNoSuchBucket)
+		return
+	}
+	result := listAllMyBucketsResult{
+		Xmlns:   s3Namespace,
+		Owner:   defaultOwner,
+		Buckets: []bucketInfo{},
+	}
+	for _, node := range nodes {
+		if node.IsDir() {
+			result.Buckets = append(result.Buckets, bucketInfo{
+				Name:         node.Name(),
+				CreationDate: formatTime(node.ModTime()),
+			})
+		}
+	}
+	writeXML(w, http.StatusOK, &result)
+}
+
+// headBucket checks the bucket exists
+func (s *server) headBucket(w http.ResponseWriter, r *http.Request, VFS *vfs.VFS, bucket string) {
+	if _, err := bucketDir(VFS, bucket); err != nil {
+		writeError(w, r, err, errNoSuchBucket)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// createBucket makes a new top level directory
+func (s *server) createBucket(w http.ResponseWriter, r *http.Request, VFS *vfs.VFS, bucket string) {
+	if err := checkBucketName(bucket); err != nil {
+		writeError(w, r, err, errNoSuchBucket)
+		return
+	}
+	if _, err := bucketDir(VFS, bucket); err == nil {
+		writeError(w, r, errBucketAlreadyOwnedByYou, errNoSuchBucket)
+		return
+	}
+	if err := VFS.Mkdir(bucket, 0777); err != nil {
+		writeError(w, r, err, errNoSuchBucket)
+		return
+	}
+	w.Header().Set("Location", "/"+bucket)
+	w.WriteHeader(http.StatusOK)
+}
+
+// deleteBucket removes an empty top level directory
+func (s *server) deleteBucket(w http.ResponseWriter, r *http.Request, VFS *vfs.VFS, bucket string) {
+	dir, err := bucketDir(VFS, bucket)
+	if err != nil {
+		writeError(w, r, err, errNoSuchBucket)
+		return
+	}
+	entries, err := dir.ReadDirAll()
+	if err != nil {
+		writeError(w, r, err, errNoSuchBucket)
+		return
+	}
+	if len(entries) != 0 {
+		writeError(w, r, errBucketNotEmpty, errNoSuchBucket)
+		return
+	}
+	if err = dir.Remove(); err != nil {
+		writeError(w, r, err, errNoSuchBucket)
+		return
+	}
+	s.metaFor(VFS).removeDir(bucket)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getBucketLocation returns the location of the bucket which is
+// always the default
+func (s *server) getBucketLocation(w http.ResponseWriter, r *http.Request, VFS *vfs.VFS, bucket string) {
+	if _, err := bucketDir(VFS, bucket); err != nil {
+		writeError(w, r, err, errNoSuchBucket)
+		return
+	}
+	writeXML(w, http.StatusOK, &locationConstraint{Xmlns: s3Namespace})
+}
+
+// getBucketVersioning returns an empty versioning configuration as
+// versioning isn't supported
+func (s *server) getBucketVersioning(w http.ResponseWriter, r *http.Request, VFS *vfs.VFS, bucket string) {
+	if _, err := bucketDir(VFS, bucket); err != nil {
+		writeError(w, r, err, errNoSuchBucket)
+		return
+	}
+	writeXML(w, http.StatusOK, &versioningConfiguration{Xmlns: s3Namespace})
+}
+
+// listEntry is an object or common prefix found when listing
+type listEntry struct {
+	key  string
+	file *vfs.File // nil for a common prefix
+}
+
+// errListFull is returned to stop the walk when the listing is full
+var errListFull = errors.New("listing full")
+
+// lister walks the directories of a bucket returning the keys in
+// lexical order.
+type lister struct {
+	prefix     string      // only return keys with this prefix
+	delimiter  string      // group keys into common prefixes with this
+	marker     string      // only return keys after this one
+	maxKeys    int         // maximum number of entries to return
+	entries    []listEntry // entries found
+	truncated  bool        // set if there were more entries
+	lastPrefix string      // last common prefix found
+}
+
+// add the entry to the listing returning errListFull if full
+func (l *lister) add(entry listEntry) error {
+	if len(l.entries) >= l.maxKeys {
+		l.truncated = true
+		return errListFull
+	}
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+// commonPrefix returns the common prefix of key if it has one
+func (l *lister) commonPrefix(key string) (string, bool) {
+	if l.delimiter == "" {
+		return "", false
+	}
+	i := strings.Index(key[len(l.prefix):], l.delimiter)
+	if i < 0 {
+		return "", false
+	}
+	return key[:len(l.prefix)+i+len(l.delimiter)], true
+}
+
+// addPrefix adds a common prefix to the listing if it is new
+func (l *lister) addPrefix(prefix string) error {
+	if prefix == l.lastPrefix {
+		return nil
+	}
+	l.lastPrefix = prefix
+	// If the marker is within the prefix there may be more keys
+	// after it, otherwise skip prefixes before the marker.
+	if prefix <= l.marker && !(len(l.marker) > len(prefix) && strings.HasPrefix(l.marker, prefix)) {
+		return nil
+	}
+	return l.add(listEntry{key: prefix})
+}
+
+// addFile adds a file to the listing
+func (l *lister) addFile(key string, file *vfs.File) error {
+	if !strings.HasPrefix(key, l.prefix) {
+		return nil
+	}
+	if prefix, ok := l.commonPrefix(key); ok {
+		return l.addPrefix(prefix)
+	}
+	if key <= l.marker {
+		return nil
+	}
+	return l.add(listEntry{key: key, file: file})
+}
+
+// walk the directory whose keys start with dirKey
+func (l *lister) walk(dir *vfs.Dir, dirKey string) error {
+	nodes, err := dir.ReadDirAll()
+	if err != nil {
+		return err
+	}
+	// Sort the directories as if they had a trailing "/" so the
+	// keys come out in lexical order.
+	type child struct {
+		key  string
+		node vfs.Node
+	}
+	children := make([]child, 0, len(nodes))
+	for _, node := range nodes {
+		key := dirKey + node.Name()
+		if node.IsDir() {
+			key += "/"
+		}
+		children = append(children, child{key: key, node: node})
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].key < children[j].key
+	})
+	for _, c := range children {
+		switch node := c.node.(type) {
+		case *vfs.File:
+			err = l.addFile(c.key, node)
+		case *vfs.Dir:
+			// Skip directories which can't contain the prefix
+			if !strings.HasPrefix(c.key, l.prefix) && !strings.HasPrefix(l.prefix, c.key) {
+				continue
+			}
+			// Skip directories whose keys are all before the marker
+			if c.key <= l.marker && !strings.HasPrefix(l.marker, c.key) {
+				continue
+			}
+			// If the directory is a common prefix then there is
+			// no need to read it
+			if strings.HasPrefix(c.key, l.prefix) {
+				if prefix, ok := l.commonPrefix(c.key); ok {
+					err = l.addPrefix(prefix)
+					break
+				}
+			}
+			err = l.walk(node, c.key)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// list the bucket
+func (l *lister) list(VFS *vfs.VFS, bucket string) error {
+	// Start from the deepest directory which contains the prefix
+	dirKey := ""
+	if i := strings.LastIndex(l.prefix, "/"); i >= 0 {
+		dirKey = l.prefix[:i+1]
+	}
+	node, err := VFS.Stat(path.Join(bucket, dirKey))
+	if err == vfs.ENOENT {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	dir, ok := node.(*vfs.Dir)
+	if !ok {
+		return nil
+	}
+	err = l.walk(dir, dirKey)
+	if err == errListFull {
+		err = nil
+	}
+	return err
+}
+
+// listObjects lists the objects in the bucket with ListObjects or
+// ListObjectsV2
+func (s *server) listObjects(w http.ResponseWriter, r *http.Request, VFS *vfs.VFS, bucket string) {
+	if _, err := bucketDir(VFS, bucket); err != nil {
+		writeError(w, r, err, errNoSuchBucket)
+		return
+	}
+	q := r.URL.Query()
+	v2 := q.Get("list-type") == "2"
+	maxKeys := maxListKeys
+	if v := q.Get("max-keys"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, r, errInvalidArgument, errNoSuchBucket)
+			return
+		}
+		if n < maxKeys {
+			maxKeys = n
+		}
+	}
+	encodingType := q.Get("encoding-type")
+	if encodingType != "" && encodingType != "url" {
+		writeError(w, r, errInvalidArgument, errNoSuchBucket)
+		return
+	}
+	encode := func(s string) string {
+		if encodingType == "url" {
+			return url.QueryEscape(s)
+		}
+		return s
+	}
+	l := lister{
+		prefix:    q.Get("prefix"),
+		delimiter: q.Get("delimiter"),
+		maxKeys:   maxKeys,
+	}
+	continuationToken := q.Get("continuation-token")
+	if v2 {
+		l.marker = q.Get("start-after")
+		if continuationToken != "" {
+			marker, err := base64.URLEncoding.DecodeString(continuationToken)
+			if err != nil {
+				writeError(w, r, errInvalidArgument, errNoSuchBucket)
+				return
+			}
+			l.marker = string(marker)
+		}
+	} else {
+		l.marker = q.Get("marker")
+	}
+	if err := l.list(VFS, bucket); err != nil {
+		writeError(w, r, err, errNoSuchBucket)
+		return
+	}
+
+	// Make the entries for the response
+	ht := s.etagHashType(VFS)
+	showETag := ht != hash.None && !VFS.Fs().Features().SlowHash
+	fetchOwner := !v2 || q.Get("fetch-owner") == "true"
+	contents := []object{}
+	prefixes := []commonPrefix{}
+	meta := s.metaFor(VFS)
+	for _, entry := range l.entries {
+		if entry.file == nil {
+			prefixes = append(prefixes, commonPrefix{Prefix: encode(entry.key)})
+			continue
+		}
+		storageClass := defaultStorage
+		if v := meta.get(path.Join(bucket, entry.key))["x-amz-storage-class"]; v != "" {
+			storageClass = v
+		}
+		obj := object{
+			Key:          encode(entry.key),
+			LastModified: formatTime(entry.file.ModTime()),
+			Size:         entry.file.Size(),
+			StorageClass: storageClass,
+		}
+		if showETag {
+			obj.ETag = s.objectETag(r, VFS, entry.file)
+		}
+		if fetchOwner {
+			obj.Owner = &defaultOwner
+		}
+		contents = append(contents, obj)
+	}
+	nextMarker := ""
+	if l.truncated && len(l.entries) > 0 {
+		nextMarker = l.entries[len(l.entries)-1].key
+	}
+	if encodingType != "" {
+		encodingType = "url"
+	}
+
+	if v2 {
+		result := listBucketResultV2{
+			Xmlns:             s3Namespace,
+			Name:              bucket,
+			Prefix:            encode(l.prefix),
+			StartAfter:        encode(q.Get("start-after")),
+			ContinuationToken: continuationToken,
+			KeyCount:          len(l.entries),
+			MaxKeys:           maxKeys,
+			Delimiter:         encode(l.delimiter),
+			IsTruncated:       l.truncated,
+			Contents:          contents,
+			CommonPrefixes:    prefixes,
+			EncodingType:      encodingType,
+		}
+		if nextMarker != "" {
+			result.NextContinuationToken = base64.URLEncoding.EncodeToString([]byte(nextMarker))
+		}
+		writeXML(w, http.StatusOK, &result)
+		return
+	}
+	result := listBucketResult{
+		Xmlns:          s3Namespace,
+		Name:           bucket,
+		Prefix:         encode(l.prefix),
+		Marker:         encode(l.marker),
+		NextMarker:     encode(nextMarker),
+		MaxKeys:        maxKeys,
+		Delimiter:      encode(l.delimiter),
+		IsTruncated:    l.truncated,
+		Contents:       contents,
+		CommonPrefixes: prefixes,
+		EncodingType:   encodingType,
+	}
+	writeXML(w, http.StatusOK, &result)
+}
+
+// setObjectHeaders sets the response headers describing the file
+func (s *server) setObjectHeaders(w http.ResponseWriter, r *http.Request, VFS *vfs.VFS, p string, file *vfs.File) {
+	h := w.Header()
+	s.metaFor(VFS).get(p).setHeaders(h)
+	if h.Get("Content-Type") == "" {
+		if o, ok := file.DirEntry().(fs.Object); ok {
+			h.Set("Content-Type", fs.MimeType(r.Context(), o))
+		} else {
+			h.Set("Content-Type", fs.MimeTypeFromName(p))
+		}
+	}
+	modTime := file.ModTime()
+	h.Set(metaMtime, swift.TimeToFloatString(modTime))
+	h.Set("Last-Modified", modTime.UTC().Format(http.TimeFormat))
+	h.Set("Accept-Ranges", "bytes")
+	if etag := s.objectETag(r, VFS, file); etag != "" {
+		h.Set("ETag", etag)
+	}
+}
+
+// getObject serves GetObject and HeadObject requests
+func (s *server) getObject(w http.ResponseWriter, r *http.Request, VFS *vfs.VFS, bucket, key string) {
+	if _, err := bucketDir(VFS, bucket); err != nil {
+		writeError(w, r, err, errNoSuchBucket)
+		return
+	}
+	if err := checkKey(key); err != nil {
+		writeError(w, r, errNoSuchKey, errNoSuchKey)
+		return
+	}
+	p := path.Join(bucket, key)
+	node, err := VFS.Stat(p)
+	if err != nil {
+		writeError(w, r, err, errNoSuchKey)
+		return
+	}
+	isMarker := strings.HasSuffix(key, "/")
+	if node.IsDir() != isMarker {
+		writeError(w, r, errNoSuchKey, errNoSuchKey)
+		return
+	}
+	if isMarker {
+		// Directory markers are empty objects
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Length", "0")
+		w.Header().Set("Last-Modified", node.ModTime().UTC().Format(http.TimeFormat))
+		w.Header().Set("ETag", `"`+emptyMD5+`"`)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	file := node.(*vfs.File)
+	s.setObjectHeaders(w, r, VFS, p, file)
+	q := r.URL.Query()
+	for param, header := range responseOverrides {
+		if v := q.Get(param); v != "" {
+			w.Header().Set(header, v)
+		}
+	}
+
+	in, err := file.Open(os.O_RDONLY)
+	if err != nil {
+		writeError(w, r, err, errNoSuchKey)
+		return
+	}
+	defer func() {
+		err := in.Close()
+		if err != nil {
+			fs.Errorf(p, "Failed to close file: %v", err)
+		}
+	}()
+
+	// ServeContent deals with Range and the conditional headers
+	http.ServeContent(w, r, "", file.ModTime(), in)
+}
+
+// putObject uploads an object into the VFS
+func (s *server) putObject(w http.ResponseWriter, r *http.Request, VFS *vfs.VFS, bucket, key string) {
+	if _, err := bucketDir(VFS, bucket); err != nil {
+		writeError(w, r, err, errNoSuchBucket)
+		return
+	}
+	if err := checkKey(key); err != nil {
+		writeError(w, r, err, errNoSuchKey)
+		return
+	}
+	wantMD5, err := contentMD5(r)
+	if err != nil {
+		writeError(w, r, err, errNoSuchKey)
+		return
+	}
+	p := path.Join(bucket, key)
+	if strings.HasSuffix(key, "/") {
+		// A directory marker so make the directory
+		if r.ContentLength > 0 {
+			writeError(w, r, errInvalidRequest, errNoSuchKey)
+			return
+		}
+		if err := mkdirAll(VFS, p); err != nil {
+			writeError(w, r, err, errNoSuchKey)
+			return
+		}
+		w.Header().Set("ETag", `"`+emptyMD5+`"`)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	hasher := md5.New()
+	err = writeFile(VFS, p, io.TeeReader(r.Body, hasher), mtimeFromHeader(r.Header))
+	if err != nil {
+		writeError(w, r, err, errNoSuchKey)
+		return
+	}
+	sum := hasher.Sum(nil)
+	if wantMD5 != nil && !bytes.Equal(sum, wantMD5) {
+		_ = VFS.Remove(p)
+		writeError(w, r, errBadDigest, errNoSuchKey)
+		return
+	}
+	s.metaFor(VFS).set(p, metaFromHeader(r.Header))
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum)+`"`)
+	w.WriteHeader(http.StatusOK)
+}
+
+// removeObject removes the object from the VFS along with any
+// directories left empty.
+func (s *server) removeObject(VFS *vfs.VFS, bucket, key string) error {
+	if err := checkKey(key); err != nil {
+		return vfs.ENOENT
+	}
+	p := path.Join(bucket, key)
+	node, err := VFS.Stat(p)
+	if err != nil {
+		return err
+	}
+	isMarker := strings.HasSuffix(key, "/")
+	if node.IsDir() != isMarker {
+		return vfs.ENOENT
+	}
+	if isMarker {
+		// Only remove directory markers if they are empty
+		entries, err := node.(*vfs.Dir).ReadDirAll()
+		if err != nil {
+			return err
+		}
+		if len(entries) != 0 {
+			return nil
+		}
+	}
+	err = node.Remove()
+	if err != nil {
+		return err
+	}
+	s.metaFor(VFS).remove(p)
+	pruneEmptyDirs(VFS, bucket, path.Dir(p))
+	return nil
+}
+
+// deleteObject deletes a single object
+func (s *server) deleteObject(w http.ResponseWriter, r *http.Request, VFS *vfs.VFS, bucket, key string) {
+	if _, err := bucketDir(VFS, bucket); err != nil {
+		writeError(w, r, err, errNoSuchBucket)
+		return
+	}
+	err := s.removeObject(VFS, bucket, key)
+	if err != nil && err != vfs.ENOENT {
+		writeError(w, r, err, errNoSuchKey)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deleteObjects deletes the objects listed in the request body
+func (s *server) deleteObjects(w http.ResponseWriter, r *http.Request, VFS *vfs.VFS, bucket string) {
+	if _, err := bucketDir(VFS, bucket); err != nil {
+		writeError(w, r, err, errNoSuchBucket)
+		return
+	}
+	var req deleteRequest
+	err := xml.NewDecoder(io.LimitReader(r.Body, maxXMLBodySize)).Decode(&req)
+	if err != nil || len(req.Objects) > maxListKeys {
+		writeError(w, r, errMalformedXML, errNoSuchBucket)
+		return
+	}
+	result := deleteResult{
+		Xmlns:   s3Namespace,
+		Deleted: []deletedObject{},
+		Error:   []deleteError{},
+	}
+	for _, obj := range req.Objects {
+		err := s.removeObject(VFS, bucket, obj.Key)
+		if err != nil && err != vfs.ENOENT {
+			e := toAPIError(err, errNoSuchKey)
+			fs.Errorf(path.Join(bucket, obj.Key), "Failed to delete: %v", err)
+			result.Error = append(result.Error, deleteError{
+				Key:     obj.Key,
+				Code:    e.Code,
+				Message: e.Message,
+			})
+		} else if !req.Quiet {
+			result.Deleted = append(result.Deleted, deletedObject{Key: obj.Key})
+		}
+	}
+	writeXML(w, http.StatusOK, &result)
+}
+
+// parseCopySource parses the X-Amz-Copy-Source header
+func parseCopySource(source string) (bucket, key string, err error) {
+	if i := strings.IndexByte(source, '?'); i >= 0 {
+		if !strings.HasPrefix(source[i+1:], "versionId=") {
+			return "", "", errInvalidArgument
+		}
+		source = source[:i]
+	}
+	source, err = url.PathUnescape(source)
+	if err != nil {
+		return "", "", errInvalidArgument
+	}
+	bucket, key = splitPath("/" + strings.TrimPrefix(source, "/"))
+	if bucket == "" || key == "" {
+		return "", "", errInvalidArgument
+	}
+	return bucket, key, nil
+}
+
+// openCopySource opens the file named in the X-Amz-Copy-Source header
+func openCopySource(r *http.Request, VFS *vfs.VFS) (p string, file *vfs.File, err error) {
+	bucket, key, err := parseCopySource(r.Header.Get("X-Amz-Copy-Source"))
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err = bucketDir(VFS, bucket); err != nil {
+		return "", nil, err
+	}
+	if err = checkKey(key); err != nil || strings.HasSuffix(key, "/") {
+		return "", nil, errNoSuchKey
+	}
+	p = path.Join(bucket, key)
+	node, err := VFS.Stat(p)
+	if err != nil {
+		return "", nil, toAPIError(err, errNoSuchKey)
+	}
+	file, ok := node.(*vfs.File)
+	if !ok {
+		return "", nil, errNoSuchKey
+	}
+	return p, file, nil
+}
+
+// copyObject copies an object within the VFS
+func (s *server) copyObject(w http.ResponseWriter, r *http.Request, VFS *vfs.VFS, bucket, key string) {
+	if _, err := bucketDir(VFS, bucket); err != nil {
+		writeError(w, r, err, errNoSuchBucket)
+		return
+	}
+	if err := checkKey(key); err != nil || strings.HasSuffix(key, "/") {
+		writeError(w, r, errInvalidArgument, errNoSuchKey)
+		return
+	}
+	srcPath, srcFile, err := openCopySource(r, VFS)
+	if err != nil {
+		writeError(w, r, err, errNoSuchKey)
+		return
+	}
+	dstPath := path.Join(bucket, key)
+	store := s.metaFor(VFS)
+
+	// Work out the metadata for the destination
+	var (
+		meta    objectMeta
+		modTime time.Time
+	)
+	directive := strings.ToUpper(r.Header.Get("X-Amz-Metadata-Directive"))
+	switch directive {
+	case "", copyDirective:
+		meta = objectMeta{}
+		for k, v := range store.get(srcPath) {
+			meta[k] = v
+		}
+		if v := r.Header.Get("X-Amz-Storage-Class"); v != "" {
+			meta["x-amz-storage-class"] = v
+		}
+		modTime = srcFile.ModTime()
+	case replaceDirective:
+		meta = metaFromHeader(r.Header)
+		modTime = mtimeFromHeader(r.Header)
+	default:
+		writeError(w, r, errInvalidArgument, errNoSuchKey)
+		return
+	}
+
+	if srcPath == dstPath {
+		// Copying an object onto itself is used to update the metadata
+		if directive != replaceDirective && r.Header.Get("X-Amz-Storage-Class") == "" {
+			writeError(w, r, errInvalidRequest, errNoSuchKey)
+			return
+		}
+		s.longRunning(w, r, func() (interface{}, error) {
+			if !modTime.IsZero() {
+				if err := srcFile.SetModTime(modTime); err != nil {
+					return nil, err
+				}
+			}
+			store.set(dstPath, meta)
+			return &copyObjectResult{
+				Xmlns:        s3Namespace,
+				LastModified: formatTime(srcFile.ModTime()),
+				ETag:         s.objectETag(r, VFS, srcFile),
+			}, nil
+		})
+		return
+	}
+
+	s.longRunning(w, r, func() (interface{}, error) {
+		in, err := srcFile.Open(os.O_RDONLY)
+		if err != nil {
+			return nil, err
+		}
+		hasher := md5.New()
+		err = writeFile(VFS, dstPath, io.TeeReader(in, hasher), modTime)
+		closeErr := in.Close()
+		if err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, err
+		}
+		store.set(dstPath, meta)
+		if modTime.IsZero() {
+			modTime = time.Now()
+		}
+		return &copyObjectResult{
+			Xmlns:        s3Namespace,
+			LastModified: formatTime(modTime),
+			ETag:         `"` + hex.EncodeToString(hasher.Sum(nil)) + `"`,
+		}, nil
+	})
+}
+
+// keepAliveInterval is how often to send whitespace to the client
+// during long running operations
+const keepAliveInterval = 10 * time.Second
+
+// longRunning runs fn which may take a long time, like S3 does for
+// CopyObject and CompleteMultipartUpload.
+//
+// It sends the 200 OK status then whitespace every keepAliveInterval
+// to stop the client timing out. The result of fn or an error is then
+// sent as XML in the body.
+func (s *server) longRunning(w http.ResponseWriter, r *http.Request, fn func() (interface{}, error)) {
+	type result struct {
+		v   interface{}
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		v, err := fn()
+		done <- result{v: v, err: err}
+	}()
+	flusher, _ := w.(http.Flusher)
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+	started := false
+	for {
+		select {
+		case res := <-done:
+			v := res.v
+			if res.err != nil {
+				if !started {
+					writeError(w, r, res.err, errNoSuchKey)
+					return
+				}
+				e := toAPIError(res.err, errNoSuchKey)
+				fs.Errorf(r.URL.Path, "%s %s failed: %v", r.Method, r.URL.Path, res.err)
+				v = &errorResponse{
+					Code:      e.Code,
+					Message:   e.Message,
+					Resource:  r.URL.Path,
+					RequestID: w.Header().Get("x-amz-request-id"),
+				}
+			}
+			if !started {
+				writeXML(w, http.StatusOK, v)
+				return
+			}
+			data, err := xml.Marshal(v)
+			if err == nil {
+				_, err = w.Write(data)
+			}
+			if err != nil {
+				fs.Debugf(r.URL.Path, "Failed to write response: %v", err)
+			}
+			return
+		case <-ticker.C:
+			if !started {
+				started = true
+				w.Header().Set("Content-Type", "application/xml")
+				w.WriteHeader(http.StatusOK)
+				_, _ = io.WriteString(w, xml.Header)
+			}
+			_, _ = io.WriteString(w, " ")
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+}
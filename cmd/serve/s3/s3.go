@@ -0,0 +1,166 @@
+// Package s3 implements an S3 compatible server to serve an rclone VFS
+package s3
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/cmd/serve/proxy"
+	"github.com/rclone/rclone/cmd/serve/proxy/proxyflags"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/flags"
+	"github.com/rclone/rclone/fs/hash"
+	httplib "github.com/rclone/rclone/lib/http"
+	"github.com/rclone/rclone/vfs"
+	"github.com/rclone/rclone/vfs/vfsflags"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Options contains options for the s3 server
+type Options struct {
+	HTTP     httplib.Options // options for the http server
+	AuthKeys []string        // access_key_id,secret_access_key pairs
+	EtagHash string          // hash to use for the ETag, "auto" or "" for none
+}
+
+// DefaultOpt is the default values used for Options
+var DefaultOpt = Options{
+	HTTP:     httplib.DefaultOpt,
+	EtagHash: "MD5",
+}
+
+// Opt is options set by command line flags
+var Opt = DefaultOpt
+
+// AddFlags adds flags for the s3 server
+func AddFlags(flagSet *pflag.FlagSet, Opt *Options) {
+	httplib.AddFlagsPrefix(flagSet, "", &Opt.HTTP)
+	flags.StringArrayVarP(flagSet, &Opt.AuthKeys, "auth-key", "", Opt.AuthKeys, "Set key pair for v4 authorization: access_key_id,secret_access_key")
+	flags.StringVarP(flagSet, &Opt.EtagHash, "etag-hash", "", Opt.EtagHash, "Which hash to use for the ETag, or auto or blank for off")
+}
+
+func init() {
+	vfsflags.AddFlags(Command.Flags())
+	proxyflags.AddFlags(Command.Flags())
+	AddFlags(Command.Flags(), &Opt)
+}
+
+// Command definition for cobra
+var Command = &cobra.Command{
+	Use:   "s3 remote:path",
+	Short: `Serve remote:path over s3.`,
+	Long: `Run a basic S3 compatible server to serve a remote over the S3
+protocol. This can be used with any S3 client, or you can make a
+remote of type s3 to read and write it.
+
+The top level directories of ` + "`remote:path`" + ` are served as
+buckets and the files within them as objects.  Creating a bucket makes
+a top level directory and removing an empty bucket removes it.
+
+You can use the filter flags (e.g. ` + "`--include`, `--exclude`" + `) to control what
+is served.
+
+The server will log errors.  Use ` + "`-v`" + ` to see access logs.
+
+` + "`--bwlimit`" + ` will be respected for file transfers.
+Use ` + "`--stats`" + ` to control the stats printing.
+
+### Setting up rclone for use with serve s3
+
+Use ` + "`--auth-key`" + ` to set the access key and secret key the
+clients must use, e.g.
+
+    rclone serve s3 --auth-key ACCESS_KEY_ID,SECRET_ACCESS_KEY remote:path
+
+This flag may be repeated to allow more than one key pair. If no
+` + "`--auth-key`" + ` is given then the server allows anonymous access and
+doesn't check request signatures.
+
+Only AWS Signature Version 4 is supported, either in the
+` + "`Authorization`" + ` header or as presigned URLs. Streaming uploads
+(` + "`aws-chunked`" + ` encoding) have their chunk signatures checked.
+
+Buckets are served in "path style" only, so clients must be
+configured to use path style requests, e.g. with an rclone remote like
+this
+
+    [serves3]
+    type = s3
+    provider = Rclone
+    endpoint = http://127.0.0.1:8080/
+    access_key_id = ACCESS_KEY_ID
+    secret_access_key = SECRET_ACCESS_KEY
+
+### Supported operations
+
+The following operations are supported
+
+- ListBuckets, CreateBucket, DeleteBucket, HeadBucket, GetBucketLocation
+- ListObjects, ListObjectsV2
+- GetObject (with Range), HeadObject, PutObject, DeleteObject, DeleteObjects
+- CopyObject
+- CreateMultipartUpload, UploadPart, UploadPartCopy, CompleteMultipartUpload,
+  AbortMultipartUpload, ListParts, ListMultipartUploads
+
+Versioning, ACLs, bucket policies and tagging are not supported.
+
+### Object metadata
+
+The modification time of objects is read from and stored as the
+modification time of the files, using the ` + "`X-Amz-Meta-Mtime`" + ` header
+in the same way as the s3 backend.
+
+Other metadata, such as ` + "`Content-Type`" + ` and ` + "`X-Amz-Meta-*`" + `
+headers, is only stored in memory and will be lost when the server is
+restarted. If no ` + "`Content-Type`" + ` was set then it is worked out from the
+file extension.
+
+Use ` + "`--etag-hash`" + ` to set the hash used for ETags. This is ` + "`MD5`" + `
+by default which is what S3 clients expect. Set it to ` + "`auto`" + ` to use
+whichever hash the remote supports or to blank to disable ETags. Note
+that ETags are only included in listings if the remote can read the
+hash quickly.
+
+### Multipart uploads
+
+Parts of multipart uploads are stored in the rclone cache directory
+until the upload is completed, at which point they are streamed into
+the VFS as one file.
+` + httplib.Help + vfs.Help + proxy.Help,
+	Run: func(command *cobra.Command, args []string) {
+		var f fs.Fs
+		if proxyflags.Opt.AuthProxy == "" {
+			cmd.CheckArgs(1, 1, command, args)
+			f = cmd.NewFsSrc(args)
+		} else {
+			cmd.CheckArgs(0, 0, command, args)
+		}
+		cmd.Run(false, false, command, func() error {
+			s, err := newServer(context.Background(), f, &Opt)
+			if err != nil {
+				return err
+			}
+			err = s.serve()
+			if err != nil {
+				return err
+			}
+			s.Wait()
+			return nil
+		})
+	},
+}
+
+// parseEtagHash parses the --etag-hash option returning whether
+// "auto" was set
+func parseEtagHash(etagHash string) (ht hash.Type, auto bool, err error) {
+	switch strings.ToLower(etagHash) {
+	case "":
+		return hash.None, false, nil
+	case "auto":
+		return hash.None, true, nil
+	}
+	err = ht.Set(etagHash)
+	return ht, false, err
+}
@@ -0,0 +1,70 @@
+// Serve s3 tests set up a server and run the integration tests
+// for the s3 remote against it.
+
+package s3
+
+import (
+	"context"
+	"testing"
+
+	_ "github.com/rclone/rclone/backend/local"
+	"github.com/rclone/rclone/cmd/serve/servetest"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/configmap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAccessKeyID     = "rclone"
+	testSecretAccessKey = "secretaccesskey"
+)
+
+// TestS3 runs the s3 server then runs the unit tests for the
+// s3 remote against it.
+func TestS3(t *testing.T) {
+	// Configure and start the server
+	start := func(f fs.Fs) (configmap.Simple, func()) {
+		opt := DefaultOpt
+		opt.HTTP.ListenAddr = "127.0.0.1:0"
+		opt.AuthKeys = []string{testAccessKeyID + "," + testSecretAccessKey}
+
+		s, err := newServer(context.Background(), f, &opt)
+		require.NoError(t, err)
+		require.NoError(t, s.serve())
+
+		// Config for the backend we'll use to connect to the server
+		config := configmap.Simple{
+			"type":              "s3",
+			"provider":          "Rclone",
+			"endpoint":          s.URL(),
+			"access_key_id":     testAccessKeyID,
+			"secret_access_key": testSecretAccessKey,
+		}
+
+		return config, func() {
+			assert.NoError(t, s.close())
+		}
+	}
+
+	servetest.Run(t, "s3", start)
+}
+
+func TestParseEtagHash(t *testing.T) {
+	for _, test := range []struct {
+		in       string
+		wantAuto bool
+		wantErr  bool
+	}{
+		{"", false, false},
+		{"auto", true, false},
+		{"AUTO", true, false},
+		{"MD5", false, false},
+		{"sha1", false, false},
+		{"potato", false, true},
+	} {
+		_, auto, err := parseEtagHash(test.in)
+		assert.Equal(t, test.wantAuto, auto, test.in)
+		assert.Equal(t, test.wantErr, err != nil, test.in)
+	}
+}
@@ -0,0 +1,315 @@
+package s3
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rclone/rclone/cmd/serve/proxy"
+	"github.com/rclone/rclone/cmd/serve/proxy/proxyflags"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+	httplib "github.com/rclone/rclone/lib/http"
+	"github.com/rclone/rclone/vfs"
+	"github.com/rclone/rclone/vfs/vfsflags"
+)
+
+// server contains everything to run the server
+type server struct {
+	f        fs.Fs
+	ctx      context.Context // for global config
+	opt      Options
+	vfs      *vfs.VFS          // nil if using the proxy
+	proxy    *proxy.Proxy      // nil if not using the proxy
+	keys     map[string]string // access key ID => secret access key
+	etagHash hash.Type         // hash to use for ETags
+	etagAuto bool              // set to use any hash the remote supports
+	listener net.Listener
+	srv      httplib.Server
+	uploads  *uploads // in progress multipart uploads
+	metaMu   sync.Mutex
+	meta     map[*vfs.VFS]*metaStore // in memory metadata for each VFS
+}
+
+// Make a new S3 server to serve the remote
+func newServer(ctx context.Context, f fs.Fs, opt *Options) (s *server, err error) {
+	s = &server{
+		f:    f,
+		ctx:  ctx,
+		opt:  *opt,
+		keys: make(map[string]string, len(opt.AuthKeys)),
+		meta: make(map[*vfs.VFS]*metaStore),
+	}
+	for _, pair := range opt.AuthKeys {
+		parts := strings.SplitN(pair, ",", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("--auth-key must be in the form access_key_id,secret_access_key: %q", pair)
+		}
+		s.keys[parts[0]] = parts[1]
+	}
+	s.etagHash, s.etagAuto, err = parseEtagHash(opt.EtagHash)
+	if err != nil {
+		return nil, fmt.Errorf("bad --etag-hash: %w", err)
+	}
+	if proxyflags.Opt.AuthProxy != "" {
+		if len(s.keys) == 0 {
+			return nil, errors.New("--auth-key must be set when using --auth-proxy")
+		}
+		s.proxy = proxy.New(ctx, &proxyflags.Opt)
+	} else {
+		s.vfs = vfs.New(f, &vfsflags.Opt)
+	}
+	s.uploads = newUploads()
+	return s, nil
+}
+
+// serve starts the server listening
+func (s *server) serve() (err error) {
+	s.listener, err = net.Listen("tcp", s.opt.HTTP.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %q: %w", s.opt.HTTP.ListenAddr, err)
+	}
+	listeners, tlsListeners := []net.Listener{s.listener}, []net.Listener{}
+	if s.useTLS() {
+		listeners, tlsListeners = tlsListeners, listeners
+	}
+	s.srv, err = httplib.NewServer(listeners, tlsListeners, s.opt.HTTP)
+	if err != nil {
+		_ = s.listener.Close()
+		return err
+	}
+	s.srv.Router().HandleFunc("/*", s.handler)
+	s.srv.Serve()
+	fs.Logf(s.f, "Serving S3 on %s", s.URL())
+	return nil
+}
+
+// useTLS returns true if the server should be using TLS
+func (s *server) useTLS() bool {
+	return s.opt.HTTP.SslKey != "" || len(s.opt.HTTP.SslKeyBody) > 0
+}
+
+// URL returns the URL of the running server
+func (s *server) URL() string {
+	scheme := "http"
+	if s.useTLS() {
+		scheme = "https"
+	}
+	baseURL := strings.Trim(s.opt.HTTP.BaseURL, "/")
+	if baseURL != "" {
+		baseURL = "/" + baseURL
+	}
+	return fmt.Sprintf("%s://%s%s/", scheme, s.listener.Addr().String(), baseURL)
+}
+
+// Wait blocks while the server is serving requests
+func (s *server) Wait() {
+	s.srv.Wait()
+}
+
+// close stops the server and removes any incomplete uploads
+func (s *server) close() error {
+	err := s.srv.Shutdown()
+	s.uploads.close()
+	return err
+}
+
+// newRequestID makes a random ID for each request
+func newRequestID() string {
+	var id [8]byte
+	_, _ = rand.Read(id[:])
+	return strings.ToUpper(hex.EncodeToString(id[:]))
+}
+
+// splitPath splits the request path into bucket and key
+func splitPath(p string) (bucket, key string) {
+	p = strings.TrimPrefix(p, "/")
+	i := strings.IndexByte(p, '/')
+	if i < 0 {
+		return p, ""
+	}
+	return p[:i], p[i+1:]
+}
+
+// authenticate checks the authorization of the request and returns
+// the VFS to use for it.
+//
+// It wraps the request body to decode and check the payload if
+// necessary.
+func (s *server) authenticate(r *http.Request) (VFS *vfs.VFS, err error) {
+	auth, err := getAuthInfo(r)
+	if err != nil {
+		return nil, err
+	}
+	var signingKey []byte
+	VFS = s.vfs
+	if len(s.keys) > 0 {
+		if auth == nil {
+			return nil, errAccessDenied
+		}
+		secretKey, ok := s.keys[auth.accessKey]
+		if !ok {
+			return nil, errInvalidAccessKeyID
+		}
+		signingKey, err = auth.verify(r, secretKey)
+		if err != nil {
+			return nil, err
+		}
+		if s.proxy != nil {
+			VFS, _, err = s.proxy.Call(auth.accessKey, secretKey, false)
+			if err != nil {
+				fs.Infof(nil, "proxy login failed: %v", err)
+				return nil, errAccessDenied
+			}
+		}
+	}
+
+	// Decode and check the payload
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	switch payloadHash {
+	case "", unsignedPayload:
+	case streamingPayload:
+		if auth == nil || auth.presigned {
+			signingKey = nil
+		}
+		r.Body = ioutil.NopCloser(newChunkedReader(r.Body, auth, signingKey))
+		s.setDecodedLength(r)
+	case streamingUnsignedTrailer:
+		r.Body = ioutil.NopCloser(newChunkedReader(r.Body, nil, nil))
+		s.setDecodedLength(r)
+	default:
+		want, err := hex.DecodeString(payloadHash)
+		if err != nil || len(want) != 32 {
+			return nil, errXAmzContentSHA256Mismatch
+		}
+		r.Body = ioutil.NopCloser(newCheckReader(r.Body, sha256.New(), want, errXAmzContentSHA256Mismatch))
+	}
+	return VFS, nil
+}
+
+// setDecodedLength sets the ContentLength of r from the
+// X-Amz-Decoded-Content-Length header for aws-chunked uploads.
+func (s *server) setDecodedLength(r *http.Request) {
+	r.ContentLength = -1
+	if v := r.Header.Get("X-Amz-Decoded-Content-Length"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err == nil && n >= 0 {
+			r.ContentLength = n
+		}
+	}
+}
+
+// Subresources which aren't supported
+var unsupportedSubresources = []string{
+	"accelerate", "acl", "analytics", "cors", "encryption", "inventory",
+	"legal-hold", "lifecycle", "logging", "metrics", "notification",
+	"object-lock", "ownershipControls", "policy", "publicAccessBlock",
+	"replication", "requestPayment", "restore", "retention", "select",
+	"tagging", "torrent", "versions", "website",
+}
+
+// handler reads incoming requests and dispatches them
+func (s *server) handler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("x-amz-request-id", newRequestID())
+	w.Header().Set("Server", "rclone/"+fs.Version)
+	bucket, key := splitPath(r.URL.Path)
+	fs.Infof(r.URL.Path, "%s: %s %s", r.RemoteAddr, r.Method, r.URL.RequestURI())
+
+	VFS, err := s.authenticate(r)
+	if err != nil {
+		writeError(w, r, err, errAccessDenied)
+		return
+	}
+
+	q := r.URL.Query()
+	for _, subresource := range unsupportedSubresources {
+		if _, found := q[subresource]; found {
+			writeError(w, r, errNotImplemented, errNotImplemented)
+			return
+		}
+	}
+
+	switch {
+	case bucket == "":
+		if r.Method == http.MethodGet {
+			s.listBuckets(w, r, VFS)
+			return
+		}
+	case key == "":
+		switch r.Method {
+		case http.MethodGet:
+			if _, found := q["uploads"]; found {
+				s.listMultipartUploads(w, r, VFS, bucket)
+			} else if _, found := q["location"]; found {
+				s.getBucketLocation(w, r, VFS, bucket)
+			} else if _, found := q["versioning"]; found {
+				s.getBucketVersioning(w, r, VFS, bucket)
+			} else {
+				s.listObjects(w, r, VFS, bucket)
+			}
+			return
+		case http.MethodHead:
+			s.headBucket(w, r, VFS, bucket)
+			return
+		case http.MethodPut:
+			s.createBucket(w, r, VFS, bucket)
+			return
+		case http.MethodDelete:
+			s.deleteBucket(w, r, VFS, bucket)
+			return
+		case http.MethodPost:
+			if _, found := q["delete"]; found {
+				s.deleteObjects(w, r, VFS, bucket)
+				return
+			}
+		}
+	default:
+		uploadID := q.Get("uploadId")
+		switch r.Method {
+		case http.MethodGet:
+			if uploadID != "" {
+				s.listParts(w, r, VFS, bucket, key, uploadID)
+			} else {
+				s.getObject(w, r, VFS, bucket, key)
+			}
+			return
+		case http.MethodHead:
+			s.getObject(w, r, VFS, bucket, key)
+			return
+		case http.MethodPut:
+			if uploadID != "" {
+				s.uploadPart(w, r, VFS, bucket, key, uploadID)
+			} else if r.Header.Get("X-Amz-Copy-Source") != "" {
+				s.copyObject(w, r, VFS, bucket, key)
+			} else {
+				s.putObject(w, r, VFS, bucket, key)
+			}
+			return
+		case http.MethodDelete:
+			if uploadID != "" {
+				s.abortMultipartUpload(w, r, VFS, bucket, key, uploadID)
+			} else {
+				s.deleteObject(w, r, VFS, bucket, key)
+			}
+			return
+		case http.MethodPost:
+			if _, found := q["uploads"]; found {
+				s.createMultipartUpload(w, r, VFS, bucket, key)
+				return
+			} else if uploadID != "" {
+				s.completeMultipartUpload(w, r, VFS, bucket, key, uploadID)
+				return
+			}
+		}
+	}
+	writeError(w, r, errMethodNotAllowed, errMethodNotAllowed)
+}
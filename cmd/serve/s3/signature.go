@@ -0,0 +1,587 @@
+package s3
+
+// AWS Signature Version 4 verification
+//
+// See https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-authenticating-requests.html
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	gohash "hash"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	signV4Algorithm          = "AWS4-HMAC-SHA256"
+	signV4ChunkAlgorithm     = "AWS4-HMAC-SHA256-PAYLOAD"
+	unsignedPayload          = "UNSIGNED-PAYLOAD"
+	streamingPayload         = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	streamingUnsignedTrailer = "STREAMING-UNSIGNED-PAYLOAD-TRAILER"
+	emptySHA256              = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	iso8601Format            = "20060102T150405Z"
+	yyyymmdd                 = "20060102"
+	maxClockSkew             = 15 * time.Minute
+	maxPresignExpiry         = 7 * 24 * time.Hour
+	maxChunkSize             = 64 * 1024 * 1024
+)
+
+// timeNow is used to read the current time - overridden in the tests
+var timeNow = time.Now
+
+// authInfo is the parsed contents of a V4 signed request, either from
+// the Authorization header or from the query string of a presigned
+// URL.
+type authInfo struct {
+	accessKey     string        // access key ID from the credential
+	date          string        // date from the credential scope (yyyymmdd)
+	region        string        // region from the credential scope
+	service       string        // service from the credential scope
+	signedHeaders []string      // lower case names of the signed headers
+	signature     string        // hex signature supplied by the client
+	amzDate       string        // timestamp used in the string to sign
+	timestamp     time.Time     // amzDate parsed
+	presigned     bool          // set if the auth was in the query string
+	expires       time.Duration // validity of a presigned URL
+}
+
+// scope returns the credential scope for the request
+func (a *authInfo) scope() string {
+	return strings.Join([]string{a.date, a.region, a.service, "aws4_request"}, "/")
+}
+
+// parseCredential parses a credential of the form
+// AKID/20130524/us-east-1/s3/aws4_request
+func (a *authInfo) parseCredential(credential string) error {
+	parts := strings.Split(credential, "/")
+	if len(parts) < 5 || parts[len(parts)-1] != "aws4_request" {
+		return errAuthorizationHeaderMalformed
+	}
+	// The access key may contain a "/" so join up the leading parts
+	n := len(parts)
+	a.accessKey = strings.Join(parts[:n-4], "/")
+	a.date, a.region, a.service = parts[n-4], parts[n-3], parts[n-2]
+	if a.service != "s3" {
+		return errAuthorizationHeaderMalformed
+	}
+	return nil
+}
+
+// parseSignedHeaders parses a ";" separated list of signed headers
+func (a *authInfo) parseSignedHeaders(signedHeaders string) error {
+	if signedHeaders == "" {
+		return errAuthorizationHeaderMalformed
+	}
+	a.signedHeaders = strings.Split(strings.ToLower(signedHeaders), ";")
+	sort.Strings(a.signedHeaders)
+	return nil
+}
+
+// parseAuthHeader parses the value of an Authorization header of the form
+//
+//	AWS4-HMAC-SHA256 Credential=AKID/20130524/us-east-1/s3/aws4_request, SignedHeaders=host;range;x-amz-date, Signature=fe5f80f77d5fa3beca038a248ff027d0445342fe2855ddc963176630326f1024
+func parseAuthHeader(header string) (*authInfo, error) {
+	if !strings.HasPrefix(header, signV4Algorithm+" ") {
+		if strings.HasPrefix(header, "AWS ") {
+			return nil, errSignatureVersionNotSupported
+		}
+		return nil, errAuthorizationHeaderMalformed
+	}
+	a := &authInfo{}
+	var haveCredential, haveSignedHeaders, haveSignature bool
+	for _, field := range strings.Split(header[len(signV4Algorithm)+1:], ",") {
+		field = strings.TrimSpace(field)
+		equals := strings.IndexByte(field, '=')
+		if equals < 0 {
+			return nil, errAuthorizationHeaderMalformed
+		}
+		key, value := field[:equals], strings.TrimSpace(field[equals+1:])
+		var err error
+		switch key {
+		case "Credential":
+			err = a.parseCredential(value)
+			haveCredential = true
+		case "SignedHeaders":
+			err = a.parseSignedHeaders(value)
+			haveSignedHeaders = true
+		case "Signature":
+			a.signature = value
+			haveSignature = true
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !haveCredential || !haveSignedHeaders || !haveSignature {
+		return nil, errAuthorizationHeaderMalformed
+	}
+	return a, nil
+}
+
+// parsePresigned parses the authorization parameters from the query
+// string of a presigned URL
+func parsePresigned(q url.Values) (*authInfo, error) {
+	if q.Get("X-Amz-Algorithm") != signV4Algorithm {
+		return nil, errAuthorizationQueryParametersError
+	}
+	a := &authInfo{
+		presigned: true,
+		signature: q.Get("X-Amz-Signature"),
+		amzDate:   q.Get("X-Amz-Date"),
+	}
+	if a.signature == "" || a.amzDate == "" {
+		return nil, errAuthorizationQueryParametersError
+	}
+	if err := a.parseCredential(q.Get("X-Amz-Credential")); err != nil {
+		return nil, errAuthorizationQueryParametersError
+	}
+	if err := a.parseSignedHeaders(q.Get("X-Amz-SignedHeaders")); err != nil {
+		return nil, errAuthorizationQueryParametersError
+	}
+	expires, err := strconv.ParseInt(q.Get("X-Amz-Expires"), 10, 64)
+	if err != nil || expires < 0 {
+		return nil, errAuthorizationQueryParametersError
+	}
+	a.expires = time.Duration(expires) * time.Second
+	if a.expires > maxPresignExpiry {
+		return nil, errAuthorizationQueryParametersError
+	}
+	return a, nil
+}
+
+// getAuthInfo reads the V4 authorization from the request
+//
+// It returns nil, nil if the request is anonymous.
+func getAuthInfo(r *http.Request) (a *authInfo, err error) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		a, err = parseAuthHeader(header)
+		if err != nil {
+			return nil, err
+		}
+		a.amzDate = r.Header.Get("X-Amz-Date")
+		if a.amzDate == "" {
+			// Fall back to the Date header if X-Amz-Date isn't present
+			t, err := http.ParseTime(r.Header.Get("Date"))
+			if err != nil {
+				return nil, errAccessDeniedMissingDate
+			}
+			a.amzDate = t.UTC().Format(iso8601Format)
+		}
+	} else if q := r.URL.Query(); q.Get("X-Amz-Algorithm") != "" {
+		a, err = parsePresigned(q)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, nil
+	}
+	a.timestamp, err = time.Parse(iso8601Format, a.amzDate)
+	if err != nil {
+		return nil, errMalformedDate
+	}
+	if a.timestamp.Format(yyyymmdd) != a.date {
+		return nil, errAuthorizationHeaderMalformed
+	}
+	return a, nil
+}
+
+// checkTime checks the time of the request is acceptable
+func (a *authInfo) checkTime() error {
+	now := timeNow()
+	if a.presigned {
+		if a.timestamp.After(now.Add(maxClockSkew)) {
+			return errRequestNotYetValid
+		}
+		if now.After(a.timestamp.Add(a.expires)) {
+			return errRequestExpired
+		}
+		return nil
+	}
+	skew := now.Sub(a.timestamp)
+	if skew > maxClockSkew || skew < -maxClockSkew {
+		return errRequestTimeTooSkewed
+	}
+	return nil
+}
+
+// payloadHash returns the value the client used as the hash of the
+// payload when signing the request
+func (a *authInfo) payloadHash(r *http.Request) string {
+	if a.presigned {
+		if v := r.URL.Query().Get("X-Amz-Content-Sha256"); v != "" {
+			return v
+		}
+		if v := r.Header.Get("X-Amz-Content-Sha256"); v != "" {
+			return v
+		}
+		return unsignedPayload
+	}
+	if v := r.Header.Get("X-Amz-Content-Sha256"); v != "" {
+		return v
+	}
+	return emptySHA256
+}
+
+// awsURIEncode encodes s as described in the AWS docs. All bytes
+// except the unreserved characters are percent encoded with upper
+// case hex digits. "/" is left alone unless encodeSlash is set.
+func awsURIEncode(s string, encodeSlash bool) string {
+	const hexDigits = "0123456789ABCDEF"
+	var out strings.Builder
+	out.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			out.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			out.WriteByte(c)
+		default:
+			out.WriteByte('%')
+			out.WriteByte(hexDigits[c>>4])
+			out.WriteByte(hexDigits[c&0x0F])
+		}
+	}
+	return out.String()
+}
+
+// canonicalURIs returns the candidate canonical URIs for the
+// request.
+//
+// The first is the path exactly as the client sent it, which is what
+// the AWS SDKs sign as they don't double encode S3 paths. The second
+// is the decoded path re-encoded with the AWS rules for clients which
+// send the path differently to the way they sign it.
+func canonicalURIs(r *http.Request) []string {
+	rawPath := r.RequestURI
+	if i := strings.IndexByte(rawPath, '?'); i >= 0 {
+		rawPath = rawPath[:i]
+	}
+	if u, err := url.ParseRequestURI(rawPath); err == nil && u.IsAbs() {
+		// request-target in absolute form
+		rawPath = u.EscapedPath()
+	}
+	if rawPath == "" {
+		rawPath = "/"
+	}
+	uris := []string{rawPath}
+	if decoded, err := url.PathUnescape(rawPath); err == nil {
+		if encoded := awsURIEncode(decoded, false); encoded != rawPath {
+			uris = append(uris, encoded)
+		}
+	}
+	return uris
+}
+
+// canonicalQuery returns the canonical query string for the request
+// leaving out the signature if presigned
+func canonicalQuery(rawQuery string, presigned bool) string {
+	type pair struct{ key, value string }
+	var pairs []pair
+	for _, item := range strings.Split(rawQuery, "&") {
+		if item == "" {
+			continue
+		}
+		key, value := item, ""
+		if i := strings.IndexByte(item, '='); i >= 0 {
+			key, value = item[:i], item[i+1:]
+		}
+		if k, err := url.QueryUnescape(key); err == nil {
+			key = k
+		}
+		if v, err := url.QueryUnescape(value); err == nil {
+			value = v
+		}
+		if presigned && key == "X-Amz-Signature" {
+			continue
+		}
+		pairs = append(pairs, pair{awsURIEncode(key, true), awsURIEncode(value, true)})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].key != pairs[j].key {
+			return pairs[i].key < pairs[j].key
+		}
+		return pairs[i].value < pairs[j].value
+	})
+	var out strings.Builder
+	for i, p := range pairs {
+		if i > 0 {
+			out.WriteByte('&')
+		}
+		out.WriteString(p.key)
+		out.WriteByte('=')
+		out.WriteString(p.value)
+	}
+	return out.String()
+}
+
+// canonicalHeaders returns the canonical headers for the request
+func (a *authInfo) canonicalHeaders(r *http.Request) string {
+	var out strings.Builder
+	for _, name := range a.signedHeaders {
+		var values []string
+		switch name {
+		case "host":
+			values = []string{r.Host}
+		case "content-length":
+			values = r.Header.Values(name)
+			if len(values) == 0 && r.ContentLength >= 0 {
+				values = []string{strconv.FormatInt(r.ContentLength, 10)}
+			}
+		case "transfer-encoding":
+			values = r.TransferEncoding
+		default:
+			values = r.Header.Values(name)
+		}
+		out.WriteString(name)
+		out.WriteByte(':')
+		for i, value := range values {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			out.WriteString(strings.Join(strings.Fields(value), " "))
+		}
+		out.WriteByte('\n')
+	}
+	return out.String()
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data with key
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// sha256Hex returns the hex encoded SHA256 of data
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// signingKey derives the signing key from the secret
+func (a *authInfo) signingKey(secretKey string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secretKey), a.date)
+	key = hmacSHA256(key, a.region)
+	key = hmacSHA256(key, a.service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+// sign calculates the signature of a canonical request
+func (a *authInfo) sign(key []byte, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		signV4Algorithm,
+		a.amzDate,
+		a.scope(),
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// verify checks the signature of the request against secretKey
+//
+// It returns the signing key so that streaming payloads can be
+// checked.
+func (a *authInfo) verify(r *http.Request, secretKey string) (key []byte, err error) {
+	if err = a.checkTime(); err != nil {
+		return nil, err
+	}
+	key = a.signingKey(secretKey)
+	query := canonicalQuery(r.URL.RawQuery, a.presigned)
+	headers := a.canonicalHeaders(r)
+	signedHeaders := strings.Join(a.signedHeaders, ";")
+	payloadHash := a.payloadHash(r)
+	for _, uri := range canonicalURIs(r) {
+		canonicalRequest := strings.Join([]string{
+			r.Method,
+			uri,
+			query,
+			headers,
+			signedHeaders,
+			payloadHash,
+		}, "\n")
+		if hmac.Equal([]byte(a.sign(key, canonicalRequest)), []byte(a.signature)) {
+			return key, nil
+		}
+	}
+	return nil, errSignatureDoesNotMatch
+}
+
+// checkReader checks the data read through it matches the hash
+// provided when EOF is reached.
+type checkReader struct {
+	in   io.Reader
+	hash gohash.Hash
+	want []byte
+	err  error // error to return on mismatch
+}
+
+// newCheckReader makes a reader which returns err at EOF if the hash
+// of the data read doesn't match want
+func newCheckReader(in io.Reader, hash gohash.Hash, want []byte, err error) io.Reader {
+	return &checkReader{
+		in:   in,
+		hash: hash,
+		want: want,
+		err:  err,
+	}
+}
+
+// Read bytes checking the hash at EOF
+func (c *checkReader) Read(p []byte) (n int, err error) {
+	n, err = c.in.Read(p)
+	_, _ = c.hash.Write(p[:n])
+	if err == io.EOF && !bytes.Equal(c.hash.Sum(nil), c.want) {
+		err = c.err
+	}
+	return n, err
+}
+
+// chunkedReader decodes an aws-chunked payload, checking the chunk
+// signatures if signingKey is set.
+//
+// Each chunk looks like this
+//
+//	hex(size);chunk-signature=signature\r\n
+//	data\r\n
+//
+// Unsigned chunks don't have the chunk-signature and the final zero
+// length chunk may be followed by trailing headers.
+type chunkedReader struct {
+	in         *bufio.Reader
+	auth       *authInfo // may be nil if not checking signatures
+	signingKey []byte    // nil if not checking signatures
+	prevSig    string    // signature of the previous chunk
+	chunk      []byte    // remaining data of the current chunk
+	buf        []byte    // buffer for chunk data
+	done       bool      // set when the last chunk has been read
+	err        error     // sticky error
+}
+
+// newChunkedReader decodes the aws-chunked data in in. If signingKey
+// is not nil then the chunk signatures are checked.
+func newChunkedReader(in io.Reader, auth *authInfo, signingKey []byte) io.Reader {
+	c := &chunkedReader{
+		in: bufio.NewReader(in),
+	}
+	if auth != nil && signingKey != nil {
+		c.auth = auth
+		c.signingKey = signingKey
+		c.prevSig = auth.signature
+	}
+	return c
+}
+
+// readLine reads a \r\n terminated line
+func (c *chunkedReader) readLine() (string, error) {
+	line, err := c.in.ReadString('\n')
+	if err == io.EOF {
+		return "", io.ErrUnexpectedEOF
+	} else if err != nil {
+		return "", err
+	}
+	if !strings.HasSuffix(line, "\r\n") {
+		return "", errIncompleteBody
+	}
+	return line[:len(line)-2], nil
+}
+
+// readChunk reads the next chunk into c.chunk
+func (c *chunkedReader) readChunk() error {
+	header, err := c.readLine()
+	if err != nil {
+		return err
+	}
+	sizeHex, signature := header, ""
+	if i := strings.IndexByte(header, ';'); i >= 0 {
+		sizeHex = header[:i]
+		for _, ext := range strings.Split(header[i+1:], ";") {
+			if strings.HasPrefix(ext, "chunk-signature=") {
+				signature = ext[len("chunk-signature="):]
+			}
+		}
+	}
+	size, err := strconv.ParseInt(strings.TrimSpace(sizeHex), 16, 64)
+	if err != nil || size < 0 {
+		return errIncompleteBody
+	}
+	if size > maxChunkSize {
+		return fmt.Errorf("chunk size %d too large: %w", size, errIncompleteBody)
+	}
+	if int64(cap(c.buf)) < size {
+		c.buf = make([]byte, size)
+	}
+	c.chunk = c.buf[:size]
+	if _, err = io.ReadFull(c.in, c.chunk); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	if size > 0 {
+		// data is followed by \r\n
+		if line, err := c.readLine(); err != nil {
+			return err
+		} else if line != "" {
+			return errIncompleteBody
+		}
+	}
+	if c.signingKey != nil {
+		stringToSign := strings.Join([]string{
+			signV4ChunkAlgorithm,
+			c.auth.amzDate,
+			c.auth.scope(),
+			c.prevSig,
+			emptySHA256,
+			sha256Hex(c.chunk),
+		}, "\n")
+		expected := hex.EncodeToString(hmacSHA256(c.signingKey, stringToSign))
+		if !hmac.Equal([]byte(expected), []byte(signature)) {
+			return errSignatureDoesNotMatch
+		}
+		c.prevSig = expected
+	}
+	if size == 0 {
+		// Read any trailing headers up to the final blank line
+		for {
+			line, err := c.readLine()
+			if err != nil {
+				return err
+			}
+			if line == "" {
+				break
+			}
+		}
+		c.done = true
+	}
+	return nil
+}
+
+// Read decoded data from the chunks
+func (c *chunkedReader) Read(p []byte) (n int, err error) {
+	for len(c.chunk) == 0 {
+		if c.err != nil {
+			return 0, c.err
+		}
+		if c.done {
+			return 0, io.EOF
+		}
+		if err := c.readChunk(); err != nil {
+			c.chunk = nil
+			c.err = err
+		}
+	}
+	n = copy(p, c.chunk)
+	c.chunk = c.chunk[n:]
+	return n, nil
+}
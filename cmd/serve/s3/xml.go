@@ -0,0 +1,254 @@
+package s3
+
+import (
+	"encoding/xml"
+	"net/http"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+)
+
+// XML namespace for S3 responses
+const s3Namespace = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+// Time format used in XML responses
+const xmlTimeFormat = "2006-01-02T15:04:05.000Z"
+
+// formatTime formats t for an XML response
+func formatTime(t time.Time) string {
+	return t.UTC().Format(xmlTimeFormat)
+}
+
+// owner of buckets and objects
+type owner struct {
+	ID          string
+	DisplayName string
+}
+
+// the owner of everything served
+var defaultOwner = owner{
+	ID:          "rclone",
+	DisplayName: "rclone",
+}
+
+// bucketInfo describes a bucket in a ListBuckets response
+type bucketInfo struct {
+	Name         string
+	CreationDate string
+}
+
+// listAllMyBucketsResult is the response to ListBuckets
+type listAllMyBucketsResult struct {
+	XMLName xml.Name `xml:"ListAllMyBucketsResult"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Owner   owner
+	Buckets []bucketInfo `xml:"Buckets>Bucket"`
+}
+
+// object describes an object in a listing
+type object struct {
+	Key          string
+	LastModified string
+	ETag         string `xml:",omitempty"`
+	Size         int64
+	StorageClass string
+	Owner        *owner `xml:",omitempty"`
+}
+
+// commonPrefix is a prefix found in a listing with a delimiter
+type commonPrefix struct {
+	Prefix string
+}
+
+// listBucketResult is the response to ListObjects
+type listBucketResult struct {
+	XMLName        xml.Name `xml:"ListBucketResult"`
+	Xmlns          string   `xml:"xmlns,attr"`
+	Name           string
+	Prefix         string
+	Marker         string
+	NextMarker     string `xml:",omitempty"`
+	MaxKeys        int
+	Delimiter      string `xml:",omitempty"`
+	IsTruncated    bool
+	Contents       []object
+	CommonPrefixes []commonPrefix
+	EncodingType   string `xml:",omitempty"`
+}
+
+// listBucketResultV2 is the response to ListObjectsV2
+type listBucketResultV2 struct {
+	XMLName               xml.Name `xml:"ListBucketResult"`
+	Xmlns                 string   `xml:"xmlns,attr"`
+	Name                  string
+	Prefix                string
+	StartAfter            string `xml:",omitempty"`
+	ContinuationToken     string `xml:",omitempty"`
+	NextContinuationToken string `xml:",omitempty"`
+	KeyCount              int
+	MaxKeys               int
+	Delimiter             string `xml:",omitempty"`
+	IsTruncated           bool
+	Contents              []object
+	CommonPrefixes        []commonPrefix
+	EncodingType          string `xml:",omitempty"`
+}
+
+// locationConstraint is the response to GetBucketLocation
+type locationConstraint struct {
+	XMLName  xml.Name `xml:"LocationConstraint"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Location string   `xml:",chardata"`
+}
+
+// versioningConfiguration is the response to GetBucketVersioning
+type versioningConfiguration struct {
+	XMLName xml.Name `xml:"VersioningConfiguration"`
+	Xmlns   string   `xml:"xmlns,attr"`
+}
+
+// copyObjectResult is the response to CopyObject
+type copyObjectResult struct {
+	XMLName      xml.Name `xml:"CopyObjectResult"`
+	Xmlns        string   `xml:"xmlns,attr"`
+	LastModified string
+	ETag         string
+}
+
+// copyPartResult is the response to UploadPartCopy
+type copyPartResult struct {
+	XMLName      xml.Name `xml:"CopyPartResult"`
+	Xmlns        string   `xml:"xmlns,attr"`
+	LastModified string
+	ETag         string
+}
+
+// deleteRequest is the body of a DeleteObjects request
+type deleteRequest struct {
+	XMLName xml.Name `xml:"Delete"`
+	Quiet   bool
+	Objects []struct {
+		Key       string
+		VersionID string `xml:"VersionId"`
+	} `xml:"Object"`
+}
+
+// deletedObject is a successfully deleted object
+type deletedObject struct {
+	Key string
+}
+
+// deleteError is an object which couldn't be deleted
+type deleteError struct {
+	Key     string
+	Code    string
+	Message string
+}
+
+// deleteResult is the response to DeleteObjects
+type deleteResult struct {
+	XMLName xml.Name `xml:"DeleteResult"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Deleted []deletedObject
+	Error   []deleteError
+}
+
+// initiateMultipartUploadResult is the response to CreateMultipartUpload
+type initiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Bucket   string
+	Key      string
+	UploadID string `xml:"UploadId"`
+}
+
+// completedPart is a part listed in a CompleteMultipartUpload request
+type completedPart struct {
+	PartNumber int
+	ETag       string
+}
+
+// completeMultipartUpload is the body of a CompleteMultipartUpload request
+type completeMultipartUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []completedPart `xml:"Part"`
+}
+
+// completeMultipartUploadResult is the response to CompleteMultipartUpload
+type completeMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"CompleteMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Location string
+	Bucket   string
+	Key      string
+	ETag     string
+}
+
+// partInfo describes an uploaded part in ListParts
+type partInfo struct {
+	PartNumber   int
+	LastModified string
+	ETag         string
+	Size         int64
+}
+
+// listPartsResult is the response to ListParts
+type listPartsResult struct {
+	XMLName              xml.Name `xml:"ListPartsResult"`
+	Xmlns                string   `xml:"xmlns,attr"`
+	Bucket               string
+	Key                  string
+	UploadID             string `xml:"UploadId"`
+	Initiator            owner
+	Owner                owner
+	StorageClass         string
+	PartNumberMarker     int
+	NextPartNumberMarker int
+	MaxParts             int
+	IsTruncated          bool
+	Parts                []partInfo `xml:"Part"`
+}
+
+// uploadInfo describes an upload in ListMultipartUploads
+type uploadInfo struct {
+	Key          string
+	UploadID     string `xml:"UploadId"`
+	Initiator    owner
+	Owner        owner
+	StorageClass string
+	Initiated    string
+}
+
+// listMultipartUploadsResult is the response to ListMultipartUploads
+type listMultipartUploadsResult struct {
+	XMLName            xml.Name `xml:"ListMultipartUploadsResult"`
+	Xmlns              string   `xml:"xmlns,attr"`
+	Bucket             string
+	KeyMarker          string
+	UploadIDMarker     string `xml:"UploadIdMarker"`
+	NextKeyMarker      string `xml:",omitempty"`
+	NextUploadIDMarker string `xml:"NextUploadIdMarker,omitempty"`
+	Prefix             string
+	MaxUploads         int
+	IsTruncated        bool
+	Uploads            []uploadInfo `xml:"Upload"`
+}
+
+// writeXML writes v to the client as XML with the status code given
+func writeXML(w http.ResponseWriter, status int, v interface{}) {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		fs.Errorf(nil, "Failed to marshal XML response: %v", err)
+		http.Error(w, "Failed to marshal XML response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, err = w.Write([]byte(xml.Header))
+	if err == nil {
+		_, err = w.Write(data)
+	}
+	if err != nil {
+		fs.Debugf(nil, "Failed to write XML response: %v", err)
+	}
+}
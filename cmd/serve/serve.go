@@ -8,7 +8,9 @@ import (
 	"github.com/rclone/rclone/cmd/serve/docker"
 	"github.com/rclone/rclone/cmd/serve/ftp"
 	"github.com/rclone/rclone/cmd/serve/http"
+	"github.com/rclone/rclone/cmd/serve/nfs"
 	"github.com/rclone/rclone/cmd/serve/restic"
+	"github.com/rclone/rclone/cmd/serve/s3"
 	"github.com/rclone/rclone/cmd/serve/sftp"
 	"github.com/rclone/rclone/cmd/serve/webdav"
 	"github.com/spf13/cobra"
@@ -31,6 +33,12 @@ func init() {
 	if sftp.Command != nil {
 		Command.AddCommand(sftp.Command)
 	}
+	if nfs.Command != nil {
+		Command.AddCommand(nfs.Command)
+	}
+	if s3.Command != nil {
+		Command.AddCommand(s3.Command)
+	}
 	if docker.Command != nil {
 		Command.AddCommand(docker.Command)
 	}
@@ -6,6 +6,7 @@ package sftp
 import (
 	"io"
 	"os"
+	"path"
 	"syscall"
 	"time"
 
@@ -30,8 +31,42 @@ func newVFSHandler(vfs *vfs.VFS) sftp.Handlers {
 	}
 }
 
+// maxSymlinks is the maximum number of symlinks resolve will follow
+const maxSymlinks = 40
+
+// resolve follows any symlinks at name returning the path they point
+// to.
+//
+// If the path doesn't exist it is returned with vfs.ENOENT so it can
+// be created.
+func (v vfsHandler) resolve(name string) (string, error) {
+	for i := 0; i < maxSymlinks; i++ {
+		node, err := v.Stat(name)
+		if err != nil {
+			return name, err
+		}
+		file, ok := node.(*vfs.File)
+		if !ok || !file.IsSymlink() {
+			return name, nil
+		}
+		target, err := file.Readlink()
+		if err != nil {
+			return name, err
+		}
+		if !path.IsAbs(target) {
+			target = path.Join(path.Dir(name), target)
+		}
+		name = target
+	}
+	return name, syscall.ELOOP
+}
+
 func (v vfsHandler) Fileread(r *sftp.Request) (io.ReaderAt, error) {
-	file, err := v.OpenFile(r.Filepath, os.O_RDONLY, 0777)
+	name, err := v.resolve(r.Filepath)
+	if err != nil {
+		return nil, err
+	}
+	file, err := v.OpenFile(name, os.O_RDONLY, 0777)
 	if err != nil {
 		return nil, err
 	}
@@ -39,7 +74,11 @@ func (v vfsHandler) Fileread(r *sftp.Request) (io.ReaderAt, error) {
 }
 
 func (v vfsHandler) Filewrite(r *sftp.Request) (io.WriterAt, error) {
-	file, err := v.OpenFile(r.Filepath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0777)
+	name, err := v.resolve(r.Filepath)
+	if err != nil && err != vfs.ENOENT {
+		return nil, err
+	}
+	file, err := v.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0777)
 	if err != nil {
 		return nil, err
 	}
@@ -74,15 +113,14 @@ func (v vfsHandler) Filecmd(r *sftp.Request) error {
 			return err
 		}
 	case "Symlink":
-		// FIXME
-		// _, err := v.fetch(r.Filepath)
-		// if err != nil {
-		// 	return err
-		// }
-		// link := newMemFile(r.Target, false)
-		// link.symlink = r.Filepath
-		// v.files[r.Target] = link
-		return sftp.ErrSshFxOpUnsupported
+		// r.Filepath is the target and r.Target is the link
+		err := v.Symlink(r.Filepath, r.Target)
+		if err == vfs.ENOSYS {
+			return sftp.ErrSshFxOpUnsupported
+		}
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -125,20 +163,46 @@ func (v vfsHandler) Filelist(r *sftp.Request) (l sftp.ListerAt, err error) {
 		}
 		return listerat(fis), nil
 	case "Stat":
-		node, err = v.Stat(r.Filepath)
+		name, err := v.resolve(r.Filepath)
+		if err != nil {
+			return nil, err
+		}
+		node, err = v.Stat(name)
 		if err != nil {
 			return nil, err
 		}
 		return listerat([]os.FileInfo{node}), nil
 	case "Readlink":
-		// FIXME
-		// if file.symlink != "" {
-		// 	file, err = v.fetch(file.symlink)
-		// 	if err != nil {
-		// 		return nil, err
-		// 	}
-		// }
-		// return listerat([]os.FileInfo{file}), nil
+		target, err := v.Readlink(r.Filepath)
+		if err != nil {
+			return nil, err
+		}
+		// The sftp library returns the Name of the FileInfo
+		return listerat([]os.FileInfo{linkInfo{target: target}}), nil
 	}
 	return nil, sftp.ErrSshFxOpUnsupported
 }
+
+// Lstat returns info about r.Filepath without following symlinks
+func (v vfsHandler) Lstat(r *sftp.Request) (sftp.ListerAt, error) {
+	node, err := v.Stat(r.Filepath)
+	if err != nil {
+		return nil, err
+	}
+	return listerat([]os.FileInfo{node}), nil
+}
+
+// Check interface
+var _ sftp.LstatFileLister = vfsHandler{}
+
+// linkInfo is an os.FileInfo for returning the target of a symlink
+type linkInfo struct {
+	target string
+}
+
+func (li linkInfo) Name() string       { return li.target }
+func (li linkInfo) Size() int64        { return int64(len(li.target)) }
+func (li linkInfo) Mode() os.FileMode  { return os.ModeSymlink | 0777 }
+func (li linkInfo) ModTime() time.Time { return time.Time{} }
+func (li linkInfo) IsDir() bool        { return false }
+func (li linkInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,353 @@
+package webdav
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/lib/kv"
+	"github.com/rclone/rclone/vfs"
+	"golang.org/x/net/webdav"
+)
+
+// Names of the lock systems for --lock-system
+const (
+	lockSystemMemory = "memory"
+	lockSystemCache  = "cache"
+)
+
+// lockKvFacility is the name of the key-value database used to keep
+// the locks in the cache directory
+const lockKvFacility = "webdavlocks"
+
+// lockVFSGroup is the VFS lock group of the WebDAV servers so they
+// can write the files locked by each other
+const lockVFSGroup = "webdav"
+
+// newLockSystem makes the lock system called name for f.
+//
+// VFS should be set if the same VFS serves all the requests.
+func newLockSystem(ctx context.Context, name string, f fs.Fs, VFS *vfs.VFS) (webdav.LockSystem, error) {
+	switch name {
+	case "", lockSystemMemory:
+		return webdav.NewMemLS(), nil
+	case lockSystemCache:
+		if VFS == nil {
+			return nil, errors.New("--lock-system cache can't be used with --auth-proxy")
+		}
+		return newCacheLockSystem(ctx, f, VFS)
+	}
+	return nil, fmt.Errorf("unknown --lock-system %q - use memory or cache", name)
+}
+
+// cachedLock is a WebDAV lock as stored in the database
+type cachedLock struct {
+	Root      string        // path of the locked resource from the root of the remote
+	Duration  time.Duration // negative if the lock doesn't expire
+	OwnerXML  string        // owner of the lock as sent by the client
+	ZeroDepth bool          // set if the lock doesn't cover the members of a collection
+	Expires   time.Time     // when the lock expires if not zero
+}
+
+// covers returns true if the lock covers the resource at name
+func (lk *cachedLock) covers(name string) bool {
+	if name == lk.Root {
+		return true
+	}
+	if lk.ZeroDepth {
+		return false
+	}
+	return lk.Root == "/" || strings.HasPrefix(name, lk.Root+"/")
+}
+
+// cacheLockSystem is a webdav.LockSystem which keeps the locks in a
+// key-value database in the cache directory.
+//
+// This means the locks survive a restart and are shared between
+// rclone processes serving the same remote with the same cache
+// directory.
+//
+// Each lock is also taken as a mandatory lock in the VFS so writes
+// from other rclone processes sharing the VFS locks fail.
+type cacheLockSystem struct {
+	mu   sync.Mutex
+	db   *kv.DB
+	root string          // root of the Fs
+	vfs  *vfs.VFS        // VFS to take the mandatory locks in
+	held map[string]bool // tokens of the locks held by Confirm in this process
+}
+
+// check interface
+var _ webdav.LockSystem = (*cacheLockSystem)(nil)
+
+// newCacheLockSystem makes a lock system for f keeping the locks in
+// the cache directory.
+func newCacheLockSystem(ctx context.Context, f fs.Fs, VFS *vfs.VFS) (*cacheLockSystem, error) {
+	if !kv.Supported() {
+		return nil, errors.New("--lock-system cache isn't supported on this OS")
+	}
+	db, err := kv.Start(ctx, lockKvFacility, f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock database: %w", err)
+	}
+	VFS.SetLockGroup(lockVFSGroup)
+	return &cacheLockSystem{
+		db:   db,
+		root: path.Join("/", f.Root()),
+		vfs:  VFS,
+		held: make(map[string]bool),
+	}, nil
+}
+
+// abs returns the path from the root of the remote of name, which is
+// relative to the root of the Fs.
+func (ls *cacheLockSystem) abs(name string) string {
+	return path.Join(ls.root, name)
+}
+
+// rel returns the path relative to the root of the Fs of name, which
+// is from the root of the remote.
+func (ls *cacheLockSystem) rel(name string) string {
+	if ls.root == "/" {
+		return name
+	}
+	return path.Join("/", strings.TrimPrefix(name, ls.root))
+}
+
+// update the locks in the database with fn. The locks which have
+// expired are removed first.
+func (ls *cacheLockSystem) update(now time.Time, fn func(locks map[string]*cachedLock) error) error {
+	return ls.db.Do(true, &kvUpdateLocks{now: now, fn: fn})
+}
+
+// lockOwner returns the owner of the VFS lock for token
+func lockOwner(token string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(token))
+	return h.Sum64()
+}
+
+// setVFSLock takes or updates the mandatory lock in the VFS for lk
+func (ls *cacheLockSystem) setVFSLock(token string, lk *cachedLock) error {
+	return ls.vfs.SetLock(context.Background(), ls.rel(lk.Root), vfs.Lock{
+		Owner:     lockOwner(token),
+		Type:      vfs.LockWrite,
+		End:       math.MaxUint64,
+		Flock:     true,
+		Mandatory: true,
+		Recursive: !lk.ZeroDepth,
+		Expires:   lk.Expires,
+	}, false)
+}
+
+// Confirm confirms that the caller can claim all of the locks
+// specified by the given conditions.
+func (ls *cacheLockSystem) Confirm(now time.Time, name0, name1 string, conditions ...webdav.Condition) (release func(), err error) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	var tokens []string
+	err = ls.update(now, func(locks map[string]*cachedLock) error {
+		for _, name := range []string{name0, name1} {
+			if name == "" {
+				continue
+			}
+			token := ls.lookup(locks, ls.abs(name), conditions)
+			if token == "" {
+				return webdav.ErrConfirmationFailed
+			}
+			if len(tokens) == 0 || tokens[0] != token {
+				tokens = append(tokens, token)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		ls.held[token] = true
+	}
+	return func() {
+		ls.mu.Lock()
+		defer ls.mu.Unlock()
+		for _, token := range tokens {
+			delete(ls.held, token)
+		}
+	}, nil
+}
+
+// lookup returns the token of the lock in conditions which covers
+// name and isn't held, or "" if there isn't one.
+func (ls *cacheLockSystem) lookup(locks map[string]*cachedLock, name string, conditions []webdav.Condition) string {
+	for _, c := range conditions {
+		lk := locks[c.Token]
+		if lk == nil || ls.held[c.Token] {
+			continue
+		}
+		if lk.covers(name) {
+			return c.Token
+		}
+	}
+	return ""
+}
+
+// Create creates a lock with the given details
+func (ls *cacheLockSystem) Create(now time.Time, details webdav.LockDetails) (token string, err error) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	lk := &cachedLock{
+		Root:      ls.abs(details.Root),
+		Duration:  details.Duration,
+		OwnerXML:  details.OwnerXML,
+		ZeroDepth: details.ZeroDepth,
+	}
+	if lk.Duration >= 0 {
+		lk.Expires = now.Add(lk.Duration)
+	}
+	token = "urn:uuid:" + uuid.New().String()
+	err = ls.update(now, func(locks map[string]*cachedLock) error {
+		for _, other := range locks {
+			if other.Root == lk.Root || (!other.ZeroDepth && other.covers(lk.Root)) || (!lk.ZeroDepth && lk.covers(other.Root)) {
+				return webdav.ErrLocked
+			}
+		}
+		locks[token] = lk
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	err = ls.setVFSLock(token, lk)
+	if err != nil {
+		removeErr := ls.update(now, func(locks map[string]*cachedLock) error {
+			delete(locks, token)
+			return nil
+		})
+		if removeErr != nil {
+			fs.Errorf(lk.Root, "webdav: failed to remove lock: %v", removeErr)
+		}
+		if err == vfs.EAGAIN {
+			return "", webdav.ErrLocked
+		}
+		return "", err
+	}
+	return token, nil
+}
+
+// Refresh refreshes the lock with the given token
+func (ls *cacheLockSystem) Refresh(now time.Time, token string, duration time.Duration) (details webdav.LockDetails, err error) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	var lk *cachedLock
+	err = ls.update(now, func(locks map[string]*cachedLock) error {
+		lk = locks[token]
+		if lk == nil {
+			return webdav.ErrNoSuchLock
+		}
+		if ls.held[token] {
+			return webdav.ErrLocked
+		}
+		lk.Duration = duration
+		lk.Expires = time.Time{}
+		if duration >= 0 {
+			lk.Expires = now.Add(duration)
+		}
+		return nil
+	})
+	if err != nil {
+		return details, err
+	}
+	err = ls.setVFSLock(token, lk)
+	if err != nil {
+		return details, err
+	}
+	return webdav.LockDetails{
+		Root:      ls.rel(lk.Root),
+		Duration:  lk.Duration,
+		OwnerXML:  lk.OwnerXML,
+		ZeroDepth: lk.ZeroDepth,
+	}, nil
+}
+
+// Unlock unlocks the lock with the given token
+func (ls *cacheLockSystem) Unlock(now time.Time, token string) (err error) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	var lk *cachedLock
+	err = ls.update(now, func(locks map[string]*cachedLock) error {
+		lk = locks[token]
+		if lk == nil {
+			return webdav.ErrNoSuchLock
+		}
+		if ls.held[token] {
+			return webdav.ErrLocked
+		}
+		delete(locks, token)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return ls.vfs.ReleaseLocks(ls.rel(lk.Root), lockOwner(token), true)
+}
+
+// kvUpdateLocks updates the locks in the database
+type kvUpdateLocks struct {
+	now time.Time
+	fn  func(locks map[string]*cachedLock) error
+}
+
+// Do the update
+func (op *kvUpdateLocks) Do(ctx context.Context, b kv.Bucket) error {
+	stored := map[string]string{}
+	locks := map[string]*cachedLock{}
+	err := b.ForEach(func(bkey, data []byte) error {
+		token := string(bkey)
+		stored[token] = string(data)
+		lk := new(cachedLock)
+		if err := json.Unmarshal(data, lk); err != nil {
+			fs.Errorf(nil, "webdav: removing corrupted lock %q: %v", token, err)
+			return nil
+		}
+		if lk.Expires.IsZero() || op.now.Before(lk.Expires) {
+			locks[token] = lk
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	err = op.fn(locks)
+	if err != nil {
+		return err
+	}
+	for token := range stored {
+		if _, found := locks[token]; !found {
+			if err = b.Delete([]byte(token)); err != nil {
+				return err
+			}
+		}
+	}
+	for token, lk := range locks {
+		data, err := json.Marshal(lk)
+		if err != nil {
+			return fmt.Errorf("failed to encode lock: %w", err)
+		}
+		if stored[token] == string(data) {
+			continue
+		}
+		if err = b.Put([]byte(token), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package webdav
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/rclone/rclone/backend/local"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config"
+	"github.com/rclone/rclone/lib/kv"
+	"github.com/rclone/rclone/vfs"
+	"github.com/rclone/rclone/vfs/vfscommon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/webdav"
+)
+
+func TestCacheLockSystem(t *testing.T) {
+	if !kv.Supported() {
+		t.Skip("cache lock system not supported")
+	}
+	oldCacheDir := config.GetCacheDir()
+	require.NoError(t, config.SetCacheDir(t.TempDir()))
+	defer func() {
+		_ = config.SetCacheDir(oldCacheDir)
+	}()
+	ctx := context.Background()
+	f, err := fs.NewFs(ctx, t.TempDir())
+	require.NoError(t, err)
+
+	// Two replicas of the server and a mount, all sharing their
+	// VFS locks. The options differ so the VFSes aren't shared.
+	newVFS := func(dirCacheTime time.Duration) *vfs.VFS {
+		opt := vfscommon.DefaultOpt
+		opt.SharedLocks = true
+		opt.DirCacheTime = dirCacheTime
+		VFS := vfs.New(f, &opt)
+		t.Cleanup(VFS.Shutdown)
+		return VFS
+	}
+	vfs1, vfs2, mount := newVFS(time.Minute), newVFS(2*time.Minute), newVFS(3*time.Minute)
+	ls1, err := newCacheLockSystem(ctx, f, vfs1)
+	require.NoError(t, err)
+	ls2, err := newCacheLockSystem(ctx, f, vfs2)
+	require.NoError(t, err)
+	write := func(VFS *vfs.VFS, name string) error {
+		fd, err := VFS.OpenFile(name, os.O_WRONLY|os.O_CREATE, 0777)
+		if err != nil {
+			return err
+		}
+		return fd.Close()
+	}
+
+	now := time.Now()
+	token, err := ls1.Create(now, webdav.LockDetails{Root: "/file", Duration: time.Minute, ZeroDepth: true})
+	require.NoError(t, err)
+
+	// The lock is seen by the other replica
+	_, err = ls2.Create(now, webdav.LockDetails{Root: "/file", Duration: -1, ZeroDepth: true})
+	assert.Equal(t, webdav.ErrLocked, err)
+	_, err = ls2.Create(now, webdav.LockDetails{Root: "/", Duration: -1})
+	assert.Equal(t, webdav.ErrLocked, err)
+	_, err = ls2.Confirm(now, "/file", "")
+	assert.Equal(t, webdav.ErrConfirmationFailed, err)
+	release, err := ls2.Confirm(now, "/file", "", webdav.Condition{Token: token})
+	require.NoError(t, err)
+	_, err = ls2.Refresh(now, token, time.Minute)
+	assert.Equal(t, webdav.ErrLocked, err)
+	release()
+	details, err := ls2.Refresh(now, token, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "/file", details.Root)
+
+	// The replicas can write the file but not the mount
+	require.NoError(t, write(vfs2, "file"))
+	assert.Equal(t, vfs.EAGAIN, write(mount, "file"))
+
+	// Unlocking on the other replica releases the locks
+	assert.Equal(t, webdav.ErrNoSuchLock, ls2.Unlock(now, "potato"))
+	require.NoError(t, ls2.Unlock(now, token))
+	require.NoError(t, write(mount, "file"))
+
+	// Locks taken by the mount stop WebDAV locks being taken
+	require.NoError(t, mount.SetLock(ctx, "dir", vfs.Lock{Owner: 1, Type: vfs.LockRead, Flock: true, End: 100}, false))
+	_, err = ls1.Create(now, webdav.LockDetails{Root: "/dir", Duration: time.Minute})
+	assert.Equal(t, webdav.ErrLocked, err)
+	require.NoError(t, mount.ReleaseLocks("dir", 1, true))
+
+	// Locks on directories cover their contents unless zero depth
+	require.NoError(t, mount.Mkdir("dir", 0777))
+	token, err = ls1.Create(now, webdav.LockDetails{Root: "/dir", Duration: time.Minute})
+	require.NoError(t, err)
+	_, err = ls2.Create(now, webdav.LockDetails{Root: "/dir/file", Duration: time.Minute, ZeroDepth: true})
+	assert.Equal(t, webdav.ErrLocked, err)
+	assert.Equal(t, vfs.EAGAIN, write(mount, "dir/file"))
+	release, err = ls2.Confirm(now, "/dir/file", "", webdav.Condition{Token: token})
+	require.NoError(t, err)
+	release()
+
+	require.NoError(t, ls1.Unlock(now, token))
+
+	// Expired locks are removed
+	token, err = ls1.Create(time.Now(), webdav.LockDetails{Root: "/dir", Duration: 10 * time.Millisecond})
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, write(mount, "dir/file"))
+	_, err = ls2.Create(time.Now(), webdav.LockDetails{Root: "/dir", Duration: time.Minute})
+	require.NoError(t, err)
+	assert.Equal(t, webdav.ErrNoSuchLock, ls1.Unlock(time.Now(), token))
+}
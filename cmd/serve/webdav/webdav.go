@@ -3,10 +3,12 @@ package webdav
 
 import (
 	"context"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -29,6 +31,7 @@ var (
 	hashName      string
 	hashType      = hash.None
 	disableGETDir = false
+	lockSystem    = lockSystemMemory
 )
 
 func init() {
@@ -38,6 +41,7 @@ func init() {
 	proxyflags.AddFlags(flagSet)
 	flags.StringVarP(flagSet, &hashName, "etag-hash", "", "", "Which hash to use for the ETag, or auto or blank for off")
 	flags.BoolVarP(flagSet, &disableGETDir, "disable-dir-list", "", false, "Disable HTML directory list on GET request for a directory")
+	flags.StringVarP(flagSet, &lockSystem, "lock-system", "", lockSystem, "Where to keep the WebDAV locks: memory or cache")
 }
 
 // Command definition for cobra
@@ -60,6 +64,45 @@ supported hash on the backend or you can use a named hash such as
 "MD5" or "SHA-1". Use the [hashsum](/commands/rclone_hashsum/) command
 to see the full list.
 
+#### --disable-dir-list
+
+A GET request for a directory returns an HTML listing of it unless
+this flag is set.
+
+The listing is returned as JSON instead if the request has an
+` + "`Accept: application/json`" + ` header or a ` + "`?format=json`" + ` query
+parameter. This is a list of the entries in the same format as
+[lsjson](/commands/rclone_lsjson/). Add ` + "`hash=true`" + ` to the query to
+include all the supported hashes of the files, or ` + "`hash-type=MD5`" + `
+to include just the named ones.
+
+#### --lock-system
+
+WebDAV clients, such as office suites, lock the files they are editing
+so that other clients can't change them. By default (` + "`memory`" + `)
+these locks are kept in memory so they are lost when rclone is
+restarted and aren't shared between several rclone servers.
+
+If this flag is set to ` + "`cache`" + ` then the locks are kept in a database
+in the cache directory instead. This means they survive a restart and
+are shared between rclone servers serving the same remote with the
+same cache directory, for example replicas behind a load balancer
+using shared storage for ` + "`--cache-dir`" + `.
+
+The locks are also taken in the VFS, so if ` + "`--vfs-shared-locks`" + ` is
+set then other rclone processes using the remote with the same cache
+directory and ` + "`--vfs-shared-locks`" + `, such as ` + "`rclone mount`" + `, can't
+change the files or directories locked by WebDAV clients. This can't
+be used with ` + "`--auth-proxy`" + `.
+
+#### Quota
+
+If the remote can report its usage, as shown by
+[about](/commands/rclone_about/), then the RFC 4331
+` + "`quota-available-bytes`" + ` and ` + "`quota-used-bytes`" + ` properties of
+directories are set from it so clients can show the free space. These
+are cached for ` + "`--dir-cache-time`" + `.
+
 ` + httplib.Help + vfs.Help + proxy.Help,
 	RunE: func(command *cobra.Command, args []string) error {
 		var f fs.Fs
@@ -82,8 +125,11 @@ to see the full list.
 			fs.Debugf(f, "Using hash %v for ETag", hashType)
 		}
 		cmd.Run(false, false, command, func() error {
-			s := newWebDAV(context.Background(), f, &httpflags.Opt)
-			err := s.serve()
+			s, err := newWebDAV(context.Background(), f, &httpflags.Opt)
+			if err != nil {
+				return err
+			}
+			err = s.serve()
 			if err != nil {
 				return err
 			}
@@ -119,7 +165,7 @@ type WebDAV struct {
 var _ webdav.FileSystem = (*WebDAV)(nil)
 
 // Make a new WebDAV to serve the remote
-func newWebDAV(ctx context.Context, f fs.Fs, opt *httplib.Options) *WebDAV {
+func newWebDAV(ctx context.Context, f fs.Fs, opt *httplib.Options) (*WebDAV, error) {
 	w := &WebDAV{
 		f:   f,
 		ctx: ctx,
@@ -133,15 +179,19 @@ func newWebDAV(ctx context.Context, f fs.Fs, opt *httplib.Options) *WebDAV {
 	} else {
 		w._vfs = vfs.New(f, &vfsflags.Opt)
 	}
+	ls, err := newLockSystem(ctx, lockSystem, f, w._vfs)
+	if err != nil {
+		return nil, err
+	}
 	w.Server = httplib.NewServer(http.HandlerFunc(w.handler), opt)
 	webdavHandler := &webdav.Handler{
 		Prefix:     w.Server.Opt.BaseURL,
 		FileSystem: w,
-		LockSystem: webdav.NewMemLS(),
+		LockSystem: ls,
 		Logger:     w.logRequest, // FIXME
 	}
 	w.webdavhandler = webdavHandler
-	return w
+	return w, nil
 }
 
 // Gets the VFS in use for this request
@@ -216,10 +266,11 @@ func (w *WebDAV) serveDir(rw http.ResponseWriter, r *http.Request, dirRemote str
 	// Make the entries for display
 	directory := serve.NewDirectory(dirRemote, w.HTMLTemplate)
 	for _, node := range dirEntries {
+		o, _ := node.DirEntry().(fs.Object)
 		if vfsflags.Opt.NoModTime {
-			directory.AddHTMLEntry(node.Path(), node.IsDir(), node.Size(), time.Time{})
+			directory.AddObjectEntry(node.Path(), node.IsDir(), node.Size(), time.Time{}, o)
 		} else {
-			directory.AddHTMLEntry(node.Path(), node.IsDir(), node.Size(), node.ModTime().UTC())
+			directory.AddObjectEntry(node.Path(), node.IsDir(), node.Size(), node.ModTime().UTC(), o)
 		}
 	}
 
@@ -336,6 +387,46 @@ func (h Handle) Readdir(count int) (fis []os.FileInfo, err error) {
 	return fis, nil
 }
 
+// Names of the RFC 4331 quota properties
+var (
+	quotaAvailableBytes = xml.Name{Space: "DAV:", Local: "quota-available-bytes"}
+	quotaUsedBytes      = xml.Name{Space: "DAV:", Local: "quota-used-bytes"}
+)
+
+// DeadProps returns the RFC 4331 quota properties of a directory if
+// the remote can report its usage
+func (h Handle) DeadProps() (map[xml.Name]webdav.Property, error) {
+	node := h.Handle.Node()
+	if !node.IsDir() || node.VFS().Fs().Features().About == nil {
+		return nil, nil
+	}
+	_, used, free := node.VFS().Statfs()
+	props := make(map[xml.Name]webdav.Property, 2)
+	for name, value := range map[xml.Name]int64{
+		quotaAvailableBytes: free,
+		quotaUsedBytes:      used,
+	} {
+		if value >= 0 {
+			props[name] = webdav.Property{
+				XMLName:  name,
+				InnerXML: []byte(strconv.FormatInt(value, 10)),
+			}
+		}
+	}
+	return props, nil
+}
+
+// Patch refuses to change any properties
+func (h Handle) Patch(patches []webdav.Proppatch) ([]webdav.Propstat, error) {
+	pstat := webdav.Propstat{Status: http.StatusForbidden}
+	for _, patch := range patches {
+		for _, prop := range patch.Props {
+			pstat.Props = append(pstat.Props, webdav.Property{XMLName: prop.XMLName})
+		}
+	}
+	return []webdav.Propstat{pstat}, nil
+}
+
 // Stat the handle
 func (h Handle) Stat() (fi os.FileInfo, err error) {
 	fi, err = h.Handle.Stat()
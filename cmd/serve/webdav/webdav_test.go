@@ -13,6 +13,7 @@ import (
 	"flag"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -40,9 +41,10 @@ const (
 
 // check interfaces
 var (
-	_ os.FileInfo         = FileInfo{nil}
-	_ webdav.ETager       = FileInfo{nil}
-	_ webdav.ContentTyper = FileInfo{nil}
+	_ os.FileInfo            = FileInfo{nil}
+	_ webdav.ETager          = FileInfo{nil}
+	_ webdav.ContentTyper    = FileInfo{nil}
+	_ webdav.DeadPropsHolder = Handle{nil}
 )
 
 // TestWebDav runs the webdav server then runs the unit tests for the
@@ -58,7 +60,8 @@ func TestWebDav(t *testing.T) {
 		hashType = hash.MD5
 
 		// Start the server
-		w := newWebDAV(context.Background(), f, &opt)
+		w, err := newWebDAV(context.Background(), f, &opt)
+		require.NoError(t, err)
 		assert.NoError(t, w.serve())
 
 		// Config for the backend we'll use to connect to the server
@@ -103,7 +106,8 @@ func TestHTTPFunction(t *testing.T) {
 	opt.Template = testTemplate
 
 	// Start the server
-	w := newWebDAV(context.Background(), f, &opt)
+	w, err := newWebDAV(context.Background(), f, &opt)
+	require.NoError(t, err)
 	assert.NoError(t, w.serve())
 	defer func() {
 		w.Close()
@@ -259,3 +263,25 @@ func HelpTestGET(t *testing.T, testURL string) {
 		checkGolden(t, test.Golden, body)
 	}
 }
+
+func TestQuota(t *testing.T) {
+	f, err := fs.NewFs(context.Background(), t.TempDir())
+	require.NoError(t, err)
+	if f.Features().About == nil {
+		t.Skip("About not supported")
+	}
+	opt := httplib.DefaultOpt
+	opt.ListenAddr = testBindAddress
+	w, err := newWebDAV(context.Background(), f, &opt)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("PROPFIND", "/", strings.NewReader(`<?xml version="1.0"?>
+<propfind xmlns="DAV:"><prop><quota-available-bytes/><quota-used-bytes/></prop></propfind>`))
+	req.Header.Set("Depth", "0")
+	rw := httptest.NewRecorder()
+	w.webdavhandler.ServeHTTP(rw, req)
+	assert.Equal(t, webdav.StatusMulti, rw.Code)
+	body := rw.Body.String()
+	assert.Regexp(t, `<D:quota-available-bytes>\d+</D:quota-available-bytes>`, body)
+	assert.Regexp(t, `<D:quota-used-bytes>\d+</D:quota-used-bytes>`, body)
+}
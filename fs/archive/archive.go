@@ -0,0 +1,44 @@
+// Package archive creates and extracts zip and tar archives on
+// remotes by streaming them.
+package archive
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Format is the type of an archive
+type Format string
+
+// Archive formats
+const (
+	FormatZip   Format = "zip"
+	FormatTar   Format = "tar"
+	FormatTarGz Format = "tar.gz"
+)
+
+// FormatFromName returns the Format of the archive from the extension
+// of name or format if it is set.
+func FormatFromName(name string, format string) (Format, error) {
+	switch strings.ToLower(format) {
+	case "":
+	case "zip":
+		return FormatZip, nil
+	case "tar":
+		return FormatTar, nil
+	case "tar.gz", "tgz":
+		return FormatTarGz, nil
+	default:
+		return "", fmt.Errorf("unknown archive format %q - use zip, tar or tar.gz", format)
+	}
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return FormatZip, nil
+	case strings.HasSuffix(lower, ".tar"):
+		return FormatTar, nil
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return FormatTarGz, nil
+	}
+	return "", fmt.Errorf("can't work out the archive format from %q - use --format", name)
+}
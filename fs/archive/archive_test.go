@@ -0,0 +1,194 @@
+package archive
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	_ "github.com/rclone/rclone/backend/local"
+	_ "github.com/rclone/rclone/backend/memory"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/filter"
+	"github.com/rclone/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatFromName(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		format string
+		want   Format
+		err    bool
+	}{
+		{"file.zip", "", FormatZip, false},
+		{"dir/FILE.TAR", "", FormatTar, false},
+		{"file.tar.gz", "", FormatTarGz, false},
+		{"file.tgz", "", FormatTarGz, false},
+		{"file.txt", "", "", true},
+		{"-", "", "", true},
+		{"-", "tgz", FormatTarGz, false},
+		{"file.zip", "tar", FormatTar, false},
+		{"file.zip", "rar", "", true},
+	} {
+		got, err := FormatFromName(test.name, test.format)
+		assert.Equal(t, test.want, got, test.name)
+		assert.Equal(t, test.err, err != nil, test.name)
+	}
+}
+
+func TestCleanName(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want string
+	}{
+		{"file", "file"},
+		{"./dir/file", "dir/file"},
+		{"/abs/file", "abs/file"},
+		{"dir/", "dir"},
+		{"../../etc/passwd", "etc/passwd"},
+		{"dir\\file", "dir/file"},
+		{".", ""},
+	} {
+		assert.Equal(t, test.want, cleanName(test.in), test.in)
+	}
+}
+
+var (
+	t1 = fstest.Time("2001-02-03T04:05:06Z")
+	t2 = fstest.Time("2011-12-25T12:59:58Z")
+)
+
+// writeItems writes the contents of items into f
+func writeItems(t *testing.T, f fs.Fs, contents map[string]string, items []fstest.Item) {
+	for _, item := range items {
+		path := filepath.Join(f.Root(), filepath.FromSlash(item.Path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0777))
+		require.NoError(t, os.WriteFile(path, []byte(contents[item.Path]), 0666))
+		require.NoError(t, os.Chtimes(path, item.ModTime, item.ModTime))
+	}
+}
+
+var testContents = map[string]string{
+	"file1.txt":         "hello world",
+	"dir/file2.txt":     "potato",
+	"dir/sub/file3.log": "log file",
+}
+
+// newSource makes a local directory with the test files in
+func newSource(t *testing.T) (fs.Fs, []fstest.Item) {
+	ctx := context.Background()
+	fsrc, err := fs.NewFs(ctx, t.TempDir())
+	require.NoError(t, err)
+	items := []fstest.Item{
+		fstest.NewItem("file1.txt", testContents["file1.txt"], t1),
+		fstest.NewItem("dir/file2.txt", testContents["dir/file2.txt"], t2),
+		fstest.NewItem("dir/sub/file3.log", testContents["dir/sub/file3.log"], t1),
+	}
+	writeItems(t, fsrc, testContents, items)
+	return fsrc, items
+}
+
+// roundTrip creates an archive of fsrc on a memory remote then
+// extracts it into a new local directory which is returned along with
+// the archive.
+func roundTrip(ctx context.Context, t *testing.T, fsrc fs.Fs, format Format) (fs.Fs, fs.Object) {
+	farchive, err := fs.NewFs(ctx, ":memory:"+t.Name())
+	require.NoError(t, err)
+	name := "test." + string(format)
+	require.NoError(t, Create(ctx, farchive, name, fsrc, "", format))
+	src, err := farchive.NewObject(ctx, name)
+	require.NoError(t, err)
+	assert.True(t, src.Size() > 0)
+	fdst, err := fs.NewFs(ctx, t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, Extract(ctx, fdst, "", src, format))
+	return fdst, src
+}
+
+func TestCreateExtract(t *testing.T) {
+	ctx := context.Background()
+	for _, format := range []Format{FormatZip, FormatTar, FormatTarGz} {
+		t.Run(string(format), func(t *testing.T) {
+			fsrc, items := newSource(t)
+			fdst, src := roundTrip(ctx, t, fsrc, format)
+			fstest.CheckListingWithPrecision(t, fdst, items, []string{"dir", "dir/sub"}, time.Second)
+
+			// Extracting again should overwrite changed files
+			changed := fstest.NewItem("dir/file2.txt", "changed potato", t1)
+			writeItems(t, fdst, map[string]string{changed.Path: "changed potato"}, []fstest.Item{changed})
+			require.NoError(t, Extract(ctx, fdst, "", src, format))
+			fstest.CheckListingWithPrecision(t, fdst, items, []string{"dir", "dir/sub"}, time.Second)
+
+			// Extracting into a subdirectory
+			require.NoError(t, Extract(ctx, fdst, "sub/dir", src, format))
+			var subItems []fstest.Item
+			for _, item := range items {
+				subItems = append(subItems, fstest.NewItem("sub/dir/"+item.Path, testContents[item.Path], item.ModTime))
+			}
+			fstest.CheckListingWithPrecision(t, fdst, append(subItems, items...), nil, time.Second)
+		})
+	}
+}
+
+func TestCreateExtractFilter(t *testing.T) {
+	ctx := context.Background()
+	for _, format := range []Format{FormatZip, FormatTarGz} {
+		t.Run(string(format), func(t *testing.T) {
+			fsrc, items := newSource(t)
+
+			// Filter on create
+			fi, err := filter.NewFilter(nil)
+			require.NoError(t, err)
+			require.NoError(t, fi.AddRule("- *.log"))
+			filterCtx := filter.ReplaceConfig(ctx, fi)
+			fdst, _ := roundTrip(filterCtx, t, fsrc, format)
+			fstest.CheckListingWithPrecision(t, fdst, items[:2], nil, time.Second)
+
+			// Filter on extract
+			farchive, err := fs.NewFs(ctx, ":memory:"+t.Name()+"-2")
+			require.NoError(t, err)
+			name := "test." + string(format)
+			require.NoError(t, Create(ctx, farchive, name, fsrc, "", format))
+			src, err := farchive.NewObject(ctx, name)
+			require.NoError(t, err)
+			fdst, err = fs.NewFs(ctx, t.TempDir())
+			require.NoError(t, err)
+			fi, err = filter.NewFilter(nil)
+			require.NoError(t, err)
+			require.NoError(t, fi.AddRule("- dir/**"))
+			require.NoError(t, Extract(filter.ReplaceConfig(ctx, fi), fdst, "", src, format))
+			fstest.CheckListingWithPrecision(t, fdst, items[:1], nil, time.Second)
+		})
+	}
+}
+
+func TestCreateExtractMetadata(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix file modes not supported on Windows")
+	}
+	ctx, ci := fs.AddConfig(context.Background())
+	ci.Metadata = true
+	for _, format := range []Format{FormatZip, FormatTar} {
+		t.Run(string(format), func(t *testing.T) {
+			fsrc, _ := newSource(t)
+			root := fsrc.Root()
+			require.NoError(t, os.Chmod(filepath.Join(root, "file1.txt"), 0640))
+			require.NoError(t, os.Chmod(filepath.Join(root, "dir", "sub"), 0750))
+			require.NoError(t, os.Chtimes(filepath.Join(root, "dir", "sub"), t2, t2))
+
+			fdst, _ := roundTrip(ctx, t, fsrc, format)
+
+			info, err := os.Stat(filepath.Join(fdst.Root(), "file1.txt"))
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+			info, err = os.Stat(filepath.Join(fdst.Root(), "dir", "sub"))
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0750), info.Mode().Perm())
+			assert.True(t, t2.Equal(info.ModTime()), "want %v got %v", t2, info.ModTime())
+		})
+	}
+}
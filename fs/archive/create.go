@@ -0,0 +1,241 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/fs/walk"
+)
+
+// Create makes an archive of format at dstFileName in fdst from the
+// contents of dir in fsrc.
+//
+// The archive is streamed to the destination with operations.Rcat.
+func Create(ctx context.Context, fdst fs.Fs, dstFileName string, fsrc fs.Fs, dir string, format Format) error {
+	pr, pw := io.Pipe()
+	writeErrChan := make(chan error, 1)
+	go func() {
+		err := Write(ctx, pw, fsrc, dir, format)
+		_ = pw.CloseWithError(err)
+		writeErrChan <- err
+	}()
+	_, err := operations.Rcat(ctx, fdst, dstFileName, pr, time.Now())
+	if err != nil {
+		// Stop the writer if it is still running
+		_ = pr.CloseWithError(err)
+	} else {
+		_ = pr.Close()
+	}
+	writeErr := <-writeErrChan
+	if writeErr != nil {
+		return writeErr
+	}
+	return err
+}
+
+// archiveWriter writes entries into an archive
+type archiveWriter interface {
+	// writeHeader starts a new entry and returns where to write its data
+	writeHeader(h *header) (io.Writer, error)
+	// Close finishes the archive
+	Close() error
+}
+
+// newArchiveWriter returns an archiveWriter for format writing to out
+func newArchiveWriter(out io.Writer, format Format) (archiveWriter, error) {
+	switch format {
+	case FormatZip:
+		return &zipWriter{zw: zip.NewWriter(out)}, nil
+	case FormatTar:
+		return &tarWriter{tw: tar.NewWriter(out)}, nil
+	case FormatTarGz:
+		gz := gzip.NewWriter(out)
+		return &tarWriter{tw: tar.NewWriter(gz), gz: gz}, nil
+	}
+	return nil, fmt.Errorf("unknown archive format %q", format)
+}
+
+// tarWriter writes tar and tar.gz archives
+type tarWriter struct {
+	tw *tar.Writer
+	gz *gzip.Writer // set if compressing
+}
+
+// writeHeader starts a new entry and returns where to write its data
+func (w *tarWriter) writeHeader(h *header) (io.Writer, error) {
+	hdr := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     h.name,
+		Size:     h.size,
+		Mode:     h.mode,
+		ModTime:  h.modTime,
+		// PAX format keeps the full precision of the times
+		Format: tar.FormatPAX,
+	}
+	if h.isDir {
+		hdr.Typeflag = tar.TypeDir
+		hdr.Name += "/"
+		hdr.Size = 0
+	}
+	if h.uid >= 0 {
+		hdr.Uid = h.uid
+	}
+	if h.gid >= 0 {
+		hdr.Gid = h.gid
+	}
+	return w.tw, w.tw.WriteHeader(hdr)
+}
+
+// Close finishes the archive
+func (w *tarWriter) Close() error {
+	err := w.tw.Close()
+	if w.gz != nil {
+		gzErr := w.gz.Close()
+		if err == nil {
+			err = gzErr
+		}
+	}
+	return err
+}
+
+// zipWriter writes zip archives
+type zipWriter struct {
+	zw *zip.Writer
+}
+
+// writeHeader starts a new entry and returns where to write its data
+func (w *zipWriter) writeHeader(h *header) (io.Writer, error) {
+	fh := &zip.FileHeader{
+		Name:     h.name,
+		Method:   zip.Deflate,
+		Modified: h.modTime,
+	}
+	mode := fileMode(h.mode)
+	if h.isDir {
+		fh.Name += "/"
+		fh.Method = zip.Store
+		mode |= os.ModeDir
+	}
+	fh.SetMode(mode)
+	return w.zw.CreateHeader(fh)
+}
+
+// Close finishes the archive
+func (w *zipWriter) Close() error {
+	return w.zw.Close()
+}
+
+// Write writes an archive of format to out from the contents of dir in
+// f.
+//
+// The filters in the context are used to choose which files to add.
+// Errors opening individual files are counted and logged and the file
+// is left out of the archive.
+func Write(ctx context.Context, out io.Writer, f fs.Fs, dir string, format Format) (err error) {
+	ci := fs.GetConfig(ctx)
+	aw, err := newArchiveWriter(out, format)
+	if err != nil {
+		return err
+	}
+	var entries fs.DirEntries
+	err = walk.ListR(ctx, f, dir, false, ci.MaxDepth, walk.ListAll, func(dirEntries fs.DirEntries) error {
+		entries = append(entries, dirEntries...)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list source: %w", err)
+	}
+	sort.Sort(entries)
+	prefix := ""
+	if dir != "" {
+		prefix = dir + "/"
+	}
+	for _, entry := range entries {
+		name := strings.TrimPrefix(entry.Remote(), prefix)
+		switch x := entry.(type) {
+		case fs.Directory:
+			err = writeDir(ctx, aw, name, x)
+		case fs.Object:
+			err = writeObject(ctx, aw, name, x, format)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return aw.Close()
+}
+
+// entryHeader makes the header for entry reading the metadata if
+// --metadata is in use.
+func entryHeader(ctx context.Context, name string, entry fs.DirEntry) *header {
+	_, isDir := entry.(fs.Directory)
+	h := newHeader(name, isDir, entry.Size(), entry.ModTime(ctx))
+	if fs.GetConfig(ctx).Metadata {
+		if do, ok := entry.(fs.Metadataer); ok {
+			metadata, err := do.Metadata(ctx)
+			if err != nil {
+				fs.Errorf(entry, "Failed to read metadata: %v", err)
+			} else {
+				h.setMetadata(metadata)
+			}
+		}
+	}
+	return h
+}
+
+// writeDir adds the directory to the archive
+func writeDir(ctx context.Context, aw archiveWriter, name string, dir fs.Directory) error {
+	_, err := aw.writeHeader(entryHeader(ctx, name, dir))
+	if err != nil {
+		return fmt.Errorf("failed to add directory %q to archive: %w", name, err)
+	}
+	return nil
+}
+
+// writeObject adds the object to the archive
+func writeObject(ctx context.Context, aw archiveWriter, name string, o fs.Object, format Format) (err error) {
+	ci := fs.GetConfig(ctx)
+	size := o.Size()
+	if size < 0 && format != FormatZip {
+		err = fs.CountError(errors.New("can't add file of unknown size to tar archive"))
+		fs.Errorf(o, "%v - skipping", err)
+		return nil
+	}
+	tr := accounting.Stats(ctx).NewTransfer(o)
+	defer func() {
+		tr.Done(ctx, err)
+	}()
+	in, err := operations.NewReOpen(ctx, o, ci.LowLevelRetries)
+	if err != nil {
+		// Nothing has been written yet so leave the file out
+		err = fs.CountError(err)
+		fs.Errorf(o, "Failed to open - skipping: %v", err)
+		return nil
+	}
+	in = tr.Account(ctx, in).WithBuffer()
+	defer fs.CheckClose(in, &err)
+	w, err := aw.writeHeader(entryHeader(ctx, name, o))
+	if err != nil {
+		return fmt.Errorf("failed to add %q to archive: %w", name, err)
+	}
+	n, err := io.Copy(w, in)
+	if err != nil {
+		return fmt.Errorf("failed to add %q to archive: %w", name, err)
+	}
+	if size >= 0 && n != size {
+		return fmt.Errorf("failed to add %q to archive: size changed from %d to %d while reading", name, size, n)
+	}
+	return nil
+}
@@ -0,0 +1,411 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fs/chunkedreader"
+	"github.com/rclone/rclone/fs/filter"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/operations"
+)
+
+// size of the first chunk read from zip archives - the chunks double
+// in size while reading sequentially
+const zipChunkSize = 1024 * 1024
+
+// Extract unpacks the archive src of format into dir in fdst.
+//
+// The filters in the context are used to choose which files to
+// extract. Errors uploading individual files are counted and logged
+// and extraction continues.
+func Extract(ctx context.Context, fdst fs.Fs, dir string, src fs.Object, format Format) error {
+	x := newExtractor(ctx, fdst, dir)
+	var err error
+	switch format {
+	case FormatZip:
+		err = x.extractZip(src)
+	case FormatTar:
+		err = x.extractTar(src, false)
+	case FormatTarGz:
+		err = x.extractTar(src, true)
+	default:
+		err = fmt.Errorf("unknown archive format %q", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to extract %v: %w", src, err)
+	}
+	return x.setDirMetadata()
+}
+
+// extractor holds the state while extracting an archive
+type extractor struct {
+	ctx         context.Context
+	ci          *fs.ConfigInfo
+	fi          *filter.Filter
+	fdst        fs.Fs
+	dir         string                     // directory in fdst to extract to
+	includeDir  func(string) (bool, error) // check directory filters
+	dirIncluded map[string]bool            // cache of directory filter results
+	dirs        map[string]*dirInfo        // directories in the archive by remote
+}
+
+// newExtractor makes a new extractor writing to dir in fdst
+func newExtractor(ctx context.Context, fdst fs.Fs, dir string) *extractor {
+	fi := filter.GetConfig(ctx)
+	if len(fi.Opt.ExcludeFile) > 0 {
+		fs.Logf(nil, "Ignoring --exclude-if-present when extracting")
+		fiCopy := *fi
+		fiCopy.Opt.ExcludeFile = nil
+		fi = &fiCopy
+	}
+	return &extractor{
+		ctx:         ctx,
+		ci:          fs.GetConfig(ctx),
+		fi:          fi,
+		fdst:        fdst,
+		dir:         dir,
+		includeDir:  fi.IncludeDirectory(ctx, nil),
+		dirIncluded: make(map[string]bool),
+		dirs:        make(map[string]*dirInfo),
+	}
+}
+
+// checkDir returns true if the directory and all its parents pass
+// the filters.
+func (x *extractor) checkDir(dir string) bool {
+	if dir == "" || dir == "." {
+		return true
+	}
+	if included, ok := x.dirIncluded[dir]; ok {
+		return included
+	}
+	included := x.checkDir(path.Dir(dir))
+	if included {
+		var err error
+		included, err = x.includeDir(dir)
+		if err != nil {
+			fs.Errorf(dir, "Failed to check directory filters: %v", err)
+			included = false
+		}
+	}
+	x.dirIncluded[dir] = included
+	return included
+}
+
+// tooDeep returns true if name is deeper than --max-depth
+func (x *extractor) tooDeep(name string) bool {
+	return x.ci.MaxDepth >= 0 && strings.Count(name, "/")+1 > x.ci.MaxDepth
+}
+
+// mkdir makes the directory for the archive entry described by h
+func (x *extractor) mkdir(h *header) error {
+	h.name = cleanName(h.name)
+	if h.name == "" || x.tooDeep(h.name) || !x.checkDir(h.name) {
+		return nil
+	}
+	remote := path.Join(x.dir, h.name)
+	err := operations.Mkdir(x.ctx, x.fdst, remote)
+	if err != nil {
+		return err
+	}
+	x.dirs[remote] = &dirInfo{
+		Dir:      fs.NewDir(remote, h.modTime),
+		metadata: h.metadata(),
+	}
+	return nil
+}
+
+// upload the archive entry described by h reading the data with open
+//
+// Errors uploading are counted and logged but not returned.
+func (x *extractor) upload(h *header, hashes map[hash.Type]string, open func() (io.ReadCloser, error)) (err error) {
+	h.name = cleanName(h.name)
+	if h.name == "" || x.tooDeep(h.name) {
+		return nil
+	}
+	if !x.checkDir(path.Dir(h.name)) || !x.fi.Include(h.name, h.size, h.modTime) {
+		fs.Debugf(h.name, "Excluded from extract")
+		return nil
+	}
+	ctx := x.ctx
+	remote := path.Join(x.dir, h.name)
+	info := &entryInfo{
+		fs:       x.fdst,
+		remote:   remote,
+		size:     h.size,
+		modTime:  h.modTime,
+		metadata: h.metadata(),
+		hashes:   hashes,
+	}
+	dst, err := x.fdst.NewObject(ctx, remote)
+	if err == nil {
+		if operations.Equal(ctx, info, dst) {
+			fs.Debugf(dst, "Unchanged skipping")
+			return nil
+		}
+	} else {
+		dst = nil
+	}
+	if operations.SkipDestructive(ctx, remote, "extract") {
+		return nil
+	}
+	tr := accounting.Stats(ctx).NewTransferRemoteSize(remote, h.size)
+	defer func() {
+		if err != nil {
+			err = fs.CountError(err)
+			fs.Errorf(remote, "Failed to extract: %v", err)
+		}
+		tr.Done(ctx, err)
+		err = nil
+	}()
+	in, err := open()
+	if err != nil {
+		return err
+	}
+	defer fs.CheckClose(in, &err)
+	body := tr.Account(ctx, in)
+	var options []fs.OpenOption
+	for _, option := range x.ci.UploadHeaders {
+		options = append(options, option)
+	}
+	if x.ci.MetadataSet != nil {
+		options = append(options, fs.MetadataOption(x.ci.MetadataSet))
+	}
+	if dst != nil {
+		err = dst.Update(ctx, body, info, options...)
+	} else {
+		dst, err = x.fdst.Put(ctx, body, info, options...)
+	}
+	if err != nil {
+		return err
+	}
+	if dst.Size() != h.size {
+		return fmt.Errorf("corrupted on transfer: sizes differ %d vs %d", h.size, dst.Size())
+	}
+	return nil
+}
+
+// setDirMetadata sets the modification times and metadata of the
+// directories made now that their contents have been written.
+func (x *extractor) setDirMetadata() error {
+	// Group the directories by parent so each parent is only listed once
+	byParent := map[string][]*dirInfo{}
+	for remote, dir := range x.dirs {
+		parent := path.Dir(remote)
+		if parent == "." {
+			parent = ""
+		}
+		byParent[parent] = append(byParent[parent], dir)
+	}
+	parents := make([]string, 0, len(byParent))
+	for parent := range byParent {
+		parents = append(parents, parent)
+	}
+	sort.Strings(parents)
+	var errCount int
+	for _, parent := range parents {
+		entries, err := x.fdst.List(x.ctx, parent)
+		if err != nil {
+			fs.Debugf(x.fdst, "Failed to list %q to set directory metadata: %v", parent, err)
+			continue
+		}
+		dsts := make(map[string]fs.Directory, len(entries))
+		for _, entry := range entries {
+			if dst, ok := entry.(fs.Directory); ok {
+				dsts[dst.Remote()] = dst
+			}
+		}
+		for _, src := range byParent[parent] {
+			dst := dsts[src.Remote()]
+			if dst == nil {
+				continue
+			}
+			if operations.CopyDirMetadata(x.ctx, x.fdst, x.fdst, dst, src) != nil {
+				errCount++
+			}
+		}
+	}
+	if errCount > 0 {
+		return fmt.Errorf("failed to set metadata on %d directories", errCount)
+	}
+	return nil
+}
+
+// extractTar extracts the tar archive src reading it as a stream
+func (x *extractor) extractTar(src fs.Object, compressed bool) (err error) {
+	in, err := operations.NewReOpen(x.ctx, src, x.ci.LowLevelRetries)
+	if err != nil {
+		return err
+	}
+	defer fs.CheckClose(in, &err)
+	var r io.Reader = in
+	if compressed {
+		gz, err := gzip.NewReader(in)
+		if err != nil {
+			return fmt.Errorf("failed to read gzip header: %w", err)
+		}
+		r = gz
+	}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar header: %w", err)
+		}
+		h := newHeader(hdr.Name, false, hdr.Size, hdr.ModTime)
+		h.mode = hdr.Mode & 07777
+		h.uid, h.gid = hdr.Uid, hdr.Gid
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			h.isDir = true
+			h.size = 0
+			err = x.mkdir(h)
+		case tar.TypeReg:
+			err = x.upload(h, nil, func() (io.ReadCloser, error) {
+				return io.NopCloser(tr), nil
+			})
+		default:
+			fs.Debugf(src, "Ignoring %q with unsupported type %q", hdr.Name, hdr.Typeflag)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractZip extracts the zip archive src.
+//
+// The central directory is read first then the files are read in the
+// order they are listed in it which is normally the order they are
+// stored.
+func (x *extractor) extractZip(src fs.Object) (err error) {
+	cr := chunkedreader.New(x.ctx, src, zipChunkSize, -1)
+	defer fs.CheckClose(cr, &err)
+	zr, err := zip.NewReader(&readerAt{r: cr, size: src.Size()}, src.Size())
+	if err != nil {
+		return fmt.Errorf("failed to read zip directory: %w", err)
+	}
+	for _, zf := range zr.File {
+		mode := zf.Mode()
+		h := newHeader(zf.Name, mode.IsDir() || strings.HasSuffix(zf.Name, "/"), int64(zf.UncompressedSize64), zf.Modified)
+		h.mode = unixPerm(mode)
+		if h.isDir {
+			h.size = 0
+			err = x.mkdir(h)
+		} else {
+			hashes := map[hash.Type]string{hash.CRC32: fmt.Sprintf("%08x", zf.CRC32)}
+			err = x.upload(h, hashes, zf.Open)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readerAt provides io.ReaderAt on a ChunkedReader. Sequential
+// reads carry on reading the open stream.
+type readerAt struct {
+	mu   sync.Mutex
+	r    *chunkedreader.ChunkedReader
+	size int64
+	pos  int64
+}
+
+// ReadAt reads len(p) bytes into p starting at offset off
+func (ra *readerAt) ReadAt(p []byte, off int64) (n int, err error) {
+	ra.mu.Lock()
+	defer ra.mu.Unlock()
+	if off >= ra.size {
+		return 0, io.EOF
+	}
+	if off != ra.pos {
+		_, err = ra.r.Seek(off, io.SeekStart)
+		if err != nil {
+			return 0, err
+		}
+		ra.pos = off
+	}
+	n, err = io.ReadFull(ra.r, p)
+	ra.pos += int64(n)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// dirInfo is a directory read from the archive
+type dirInfo struct {
+	*fs.Dir
+	metadata fs.Metadata
+}
+
+// Metadata returns the metadata of the directory
+func (d *dirInfo) Metadata(ctx context.Context) (fs.Metadata, error) {
+	return d.metadata, nil
+}
+
+// entryInfo describes a file read from the archive
+type entryInfo struct {
+	fs       fs.Info
+	remote   string
+	size     int64
+	modTime  time.Time
+	metadata fs.Metadata
+	hashes   map[hash.Type]string
+}
+
+// Fs returns the Fs the file is being extracted to
+func (i *entryInfo) Fs() fs.Info { return i.fs }
+
+// String returns a description of the file
+func (i *entryInfo) String() string { return i.remote }
+
+// Remote returns the remote path
+func (i *entryInfo) Remote() string { return i.remote }
+
+// ModTime returns the modification time of the file
+func (i *entryInfo) ModTime(ctx context.Context) time.Time { return i.modTime }
+
+// Size returns the size of the file
+func (i *entryInfo) Size() int64 { return i.size }
+
+// Storable returns whether the file can be stored
+func (i *entryInfo) Storable() bool { return true }
+
+// Hash returns the hash stored in the archive if there is one
+func (i *entryInfo) Hash(ctx context.Context, ht hash.Type) (string, error) {
+	if sum, ok := i.hashes[ht]; ok {
+		return sum, nil
+	}
+	return "", hash.ErrUnsupported
+}
+
+// Metadata returns the metadata stored in the archive
+func (i *entryInfo) Metadata(ctx context.Context) (fs.Metadata, error) {
+	return i.metadata, nil
+}
+
+// Check the interfaces are satisfied
+var (
+	_ fs.ObjectInfo = (*entryInfo)(nil)
+	_ fs.Metadataer = (*entryInfo)(nil)
+	_ fs.Metadataer = (*dirInfo)(nil)
+	_ io.ReaderAt   = (*readerAt)(nil)
+)
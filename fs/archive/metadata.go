@@ -0,0 +1,141 @@
+package archive
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+)
+
+// The metadata uses the same keys and formats as the local backend
+
+const metadataTimeFormat = time.RFC3339Nano
+
+// Default permissions for entries without a mode
+const (
+	defaultFileMode = 0644
+	defaultDirMode  = 0755
+)
+
+// header describes an entry in an archive
+type header struct {
+	name    string    // path in the archive
+	isDir   bool      // set if this is a directory
+	size    int64     // size of the file
+	modTime time.Time // modification time
+	mode    int64     // unix permission bits including setuid, setgid and sticky
+	uid     int       // owner or -1 if unknown
+	gid     int       // group or -1 if unknown
+}
+
+// newHeader makes a header for name with the defaults filled in
+func newHeader(name string, isDir bool, size int64, modTime time.Time) *header {
+	h := &header{
+		name:    name,
+		isDir:   isDir,
+		size:    size,
+		modTime: modTime,
+		mode:    defaultFileMode,
+		uid:     -1,
+		gid:     -1,
+	}
+	if isDir {
+		h.mode = defaultDirMode
+	}
+	return h
+}
+
+// setMetadata overrides the fields of h with any found in metadata
+func (h *header) setMetadata(metadata fs.Metadata) {
+	if value, ok := metadata["mode"]; ok {
+		mode, err := strconv.ParseInt(value, 8, 64)
+		if err != nil {
+			fs.Debugf(h.name, "Ignoring invalid mode %q in metadata: %v", value, err)
+		} else {
+			h.mode = mode & 07777
+		}
+	}
+	if value, ok := metadata["mtime"]; ok {
+		modTime, err := time.Parse(metadataTimeFormat, value)
+		if err != nil {
+			fs.Debugf(h.name, "Ignoring invalid mtime %q in metadata: %v", value, err)
+		} else {
+			h.modTime = modTime
+		}
+	}
+	for _, id := range []struct {
+		key string
+		p   *int
+	}{{"uid", &h.uid}, {"gid", &h.gid}} {
+		if value, ok := metadata[id.key]; ok {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				fs.Debugf(h.name, "Ignoring invalid %s %q in metadata: %v", id.key, value, err)
+			} else {
+				*id.p = n
+			}
+		}
+	}
+}
+
+// metadata returns the metadata for h
+func (h *header) metadata() fs.Metadata {
+	mode := h.mode & 07777
+	if h.isDir {
+		mode |= 0040000
+	} else {
+		mode |= 0100000
+	}
+	metadata := fs.Metadata{
+		"mode":  fmt.Sprintf("%0o", mode),
+		"mtime": h.modTime.Format(metadataTimeFormat),
+	}
+	if h.uid >= 0 {
+		metadata["uid"] = strconv.Itoa(h.uid)
+	}
+	if h.gid >= 0 {
+		metadata["gid"] = strconv.Itoa(h.gid)
+	}
+	return metadata
+}
+
+// unixPerm converts the permissions in mode into unix permission bits
+func unixPerm(mode os.FileMode) int64 {
+	perm := int64(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		perm |= 04000
+	}
+	if mode&os.ModeSetgid != 0 {
+		perm |= 02000
+	}
+	if mode&os.ModeSticky != 0 {
+		perm |= 01000
+	}
+	return perm
+}
+
+// fileMode converts unix permission bits into an os.FileMode
+func fileMode(perm int64) os.FileMode {
+	mode := os.FileMode(perm & 0777)
+	if perm&04000 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if perm&02000 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if perm&01000 != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode
+}
+
+// cleanName converts a name in an archive into a relative path
+// which can't refer above the root.
+func cleanName(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	return strings.Trim(path.Clean("/"+name), "/")
+}
@@ -76,9 +76,11 @@ type ConfigInfo struct {
 	IgnoreCaseSync          bool
 	NoTraverse              bool
 	CheckFirst              bool
+	Resume                  bool
 	NoCheckDest             bool
 	NoUnicodeNormalization  bool
 	NoUpdateModTime         bool
+	NoUpdateDirModTime      bool
 	DataRateUnit            string
 	CompareDest             []string
 	CopyDest                []string
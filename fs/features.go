@@ -29,6 +29,9 @@ type Features struct {
 	ReadMetadata            bool // can read metadata from objects
 	WriteMetadata           bool // can write metadata to objects
 	UserMetadata            bool // can read/write general purpose metadata
+	ReadDirMetadata         bool // can read metadata from directories
+	WriteDirMetadata        bool // can write metadata to directories
+	WriteDirSetModTime      bool // can set the modification time of directories
 
 	// Purge all files in the directory specified
 	//
@@ -311,6 +314,9 @@ func (ft *Features) Mask(ctx context.Context, f Fs) *Features {
 	ft.ReadMetadata = ft.ReadMetadata && mask.ReadMetadata
 	ft.WriteMetadata = ft.WriteMetadata && mask.WriteMetadata
 	ft.UserMetadata = ft.UserMetadata && mask.UserMetadata
+	ft.ReadDirMetadata = ft.ReadDirMetadata && mask.ReadDirMetadata
+	ft.WriteDirMetadata = ft.WriteDirMetadata && mask.WriteDirMetadata
+	ft.WriteDirSetModTime = ft.WriteDirSetModTime && mask.WriteDirSetModTime
 	ft.CanHaveEmptyDirectories = ft.CanHaveEmptyDirectories && mask.CanHaveEmptyDirectories
 	ft.BucketBased = ft.BucketBased && mask.BucketBased
 	ft.BucketBasedRootOK = ft.BucketBasedRootOK && mask.BucketBasedRootOK
@@ -1180,6 +1180,82 @@ func Mkdir(ctx context.Context, f fs.Fs, dir string) error {
 	return nil
 }
 
+// CopyDirMetadata copies the modification time and metadata of the
+// src directory to the dst directory in fdst if the backend supports
+// it.
+//
+// dst should have been read after any changes to its contents were
+// made otherwise its modification time will be out of date.
+func CopyDirMetadata(ctx context.Context, fdst, fsrc fs.Fs, dst, src fs.Directory) error {
+	ci := fs.GetConfig(ctx)
+	features := fdst.Features()
+	setModTime := features.WriteDirSetModTime && !ci.NoUpdateDirModTime
+	setMetadata := features.WriteDirMetadata && ci.Metadata
+	if !setModTime && !setMetadata {
+		return nil
+	}
+	srcModTime := src.ModTime(ctx)
+	if setModTime {
+		modifyWindow := fs.GetModifyWindow(ctx, fdst, fsrc)
+		if modifyWindow == fs.ModTimeNotSupported {
+			setModTime = false
+		} else {
+			dt := dst.ModTime(ctx).Sub(srcModTime)
+			if dt < modifyWindow && dt > -modifyWindow {
+				fs.Debugf(dst, "Directory modification time the same (differ by %s, within tolerance %s)", dt, modifyWindow)
+				setModTime = false
+			}
+		}
+	}
+	var metadata fs.Metadata
+	if setMetadata {
+		if do, ok := src.(fs.Metadataer); ok {
+			var err error
+			metadata, err = do.Metadata(ctx)
+			if err != nil {
+				err = fs.CountError(err)
+				fs.Errorf(src, "Failed to read directory metadata: %v", err)
+				return err
+			}
+		}
+		metadata.Merge(ci.MetadataSet)
+		if len(metadata) == 0 {
+			setMetadata = false
+		}
+	}
+	if !setModTime && !setMetadata {
+		return nil
+	}
+	if SkipDestructive(ctx, fs.LogDirName(fdst, dst.Remote()), "set directory modification time and metadata") {
+		return nil
+	}
+	if setMetadata {
+		if do, ok := dst.(fs.SetMetadataer); ok {
+			fs.Debugf(dst, "Setting directory metadata")
+			err := do.SetMetadata(ctx, metadata)
+			if err != nil {
+				err = fs.CountError(err)
+				fs.Errorf(dst, "Failed to set directory metadata: %v", err)
+				return err
+			}
+		}
+	}
+	// Set the modification time last as setting the metadata may
+	// have changed it
+	if setModTime {
+		if do, ok := dst.(fs.SetModTimer); ok {
+			fs.Debugf(dst, "Setting directory modification time to %v", srcModTime)
+			err := do.SetModTime(ctx, srcModTime)
+			if err != nil {
+				err = fs.CountError(err)
+				fs.Errorf(dst, "Failed to set directory modification time: %v", err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // TryRmdir removes a container but not if not empty.  It doesn't
 // count errors but may return one.
 func TryRmdir(ctx context.Context, f fs.Fs, dir string) error {
@@ -1449,9 +1525,18 @@ func Rcat(ctx context.Context, fdst fs.Fs, dstFileName string, in io.ReadCloser,
 }
 
 // PublicLink adds a "readable by anyone with link" permission on the given file or folder.
+//
+// If f doesn't support public links but share links are configured
+// in ctx then a share link served by rclone is returned instead.
 func PublicLink(ctx context.Context, f fs.Fs, remote string, expire fs.Duration, unlink bool) (string, error) {
 	doPublicLink := f.Features().PublicLink
 	if doPublicLink == nil {
+		if l := fs.GetShareLinker(ctx); l != nil && l.CanMakeLinks() {
+			if unlink {
+				return "", errors.New("share links can't be removed - change the share secret to revoke them")
+			}
+			return l.MakeLink(ctx, f, remote, expire)
+		}
 		return "", fmt.Errorf("%v doesn't support public links", f)
 	}
 	return doPublicLink(ctx, remote, expire, unlink)
@@ -374,6 +374,10 @@ Returns:
 
 - url - URL of the resource
 
+If the remote doesn't support public links and the rc server was
+started with ` + "`--rc-share-secret`" + ` then a share link served by the rc
+server is returned.
+
 See the [link](/commands/rclone_link/) command for more information on the above.
 `,
 	})
@@ -440,6 +444,7 @@ This returns info about the remote passed in;
                 "Purge": true,
                 "PutStream": true,
                 "PutUnchecked": false,
+                "ReadDirMetadata": true,
                 "ReadMetadata": true,
                 "ReadMimeType": false,
                 "ServerSideAcrossConfigs": false,
@@ -452,6 +457,8 @@ This returns info about the remote passed in;
                 "UserInfo": false,
                 "UserMetadata": true,
                 "WrapFs": false,
+                "WriteDirMetadata": true,
+                "WriteDirSetModTime": true,
                 "WriteMetadata": true,
                 "WriteMimeType": false
         },
@@ -197,8 +197,9 @@ func getAsync(ctx context.Context, in rc.Params) (context.Context, bool, error)
 	}
 	delete(in, "_async") // remove the async parameter after parsing
 	if isAsync {
-		// unlink this job from the current context
-		ctx = context.Background()
+		// unlink this job from the current context keeping the
+		// share link options
+		ctx = fs.CopyShareLinker(context.Background(), ctx)
 	}
 	return ctx, isAsync, nil
 }
@@ -30,6 +30,7 @@ type Options struct {
 	WebGUIFetchURL           string // set the default url for fetching webgui
 	AccessControlAllowOrigin string // set the access control for CORS configuration
 	EnableMetrics            bool   // set to disable prometheus metrics on /metrics
+	ShareSecret              string // set to serve share links signed with this secret
 	JobExpireDuration        time.Duration
 	JobExpireInterval        time.Duration
 }
@@ -32,6 +32,7 @@ import (
 	"github.com/rclone/rclone/fs/rc/rcflags"
 	"github.com/rclone/rclone/fs/rc/webgui"
 	"github.com/rclone/rclone/lib/http/serve"
+	"github.com/rclone/rclone/lib/http/share"
 	"github.com/rclone/rclone/lib/random"
 	"github.com/skratchdot/open-golang/open"
 )
@@ -60,7 +61,8 @@ func Start(ctx context.Context, opt *rc.Options) (*Server, error) {
 	if opt.Enabled {
 		// Serve on the DefaultServeMux so can have global registrations appear
 		s := newServer(ctx, opt, http.DefaultServeMux)
-		return s, s.Serve()
+		err := s.Serve()
+		return s, err
 	}
 	return nil, nil
 }
@@ -71,6 +73,7 @@ type Server struct {
 	ctx            context.Context // for global config
 	files          http.Handler
 	pluginsHandler http.Handler
+	share          *share.Server // checks share links if they are enabled
 	opt            *rc.Options
 }
 
@@ -118,6 +121,10 @@ func newServer(ctx context.Context, opt *rc.Options, mux *http.ServeMux) *Server
 		pluginsHandler = http.FileServer(http.Dir(webgui.PluginsPath))
 	}
 
+	if opt.ShareSecret != "" {
+		opt.HTTPOptions.PublicPrefix = share.Prefix
+	}
+
 	s := &Server{
 		Server:         httplib.NewServer(mux, &opt.HTTPOptions),
 		ctx:            ctx,
@@ -125,6 +132,9 @@ func newServer(ctx context.Context, opt *rc.Options, mux *http.ServeMux) *Server
 		files:          fileHandler,
 		pluginsHandler: pluginsHandler,
 	}
+	if opt.ShareSecret != "" {
+		s.share = share.NewServer(opt.ShareSecret)
+	}
 	mux.HandleFunc("/", s.handler)
 
 	return s
@@ -276,6 +286,14 @@ func (s *Server) handlePost(w http.ResponseWriter, r *http.Request, path string)
 		in["_response"] = w
 	}
 
+	if s.share != nil {
+		// Make operations/publiclink return share links served by this server
+		ctx = fs.WithShareLinker(ctx, &share.Options{
+			URL:    s.URL(),
+			Secret: s.opt.ShareSecret,
+		})
+	}
+
 	fs.Debugf(nil, "rc: %q: with parameters %+v", path, in)
 	job, out, err := jobs.NewJob(ctx, call.Fn, in)
 	if job != nil {
@@ -327,6 +345,15 @@ func (s *Server) serveRemote(w http.ResponseWriter, r *http.Request, path string
 	}
 	if path == "" || strings.HasSuffix(path, "/") {
 		path = strings.Trim(path, "/")
+		format, err := serve.ArchiveFormat(r)
+		if err != nil {
+			writeError(path, nil, w, err, http.StatusBadRequest)
+			return
+		}
+		if format != "" {
+			serve.Archive(w, r, f, path, format)
+			return
+		}
 		entries, err := list.DirSorted(r.Context(), f, false, path)
 		if err != nil {
 			writeError(path, nil, w, fmt.Errorf("failed to list directory: %w", err), http.StatusInternalServerError)
@@ -334,10 +361,12 @@ func (s *Server) serveRemote(w http.ResponseWriter, r *http.Request, path string
 		}
 		// Make the entries for display
 		directory := serve.NewDirectory(path, s.HTMLTemplate)
+		directory.Downloadable = true
 		for _, entry := range entries {
 			_, isDir := entry.(fs.Directory)
-			//directory.AddHTMLEntry(entry.Remote(), isDir, entry.Size(), entry.ModTime(r.Context()))
-			directory.AddHTMLEntry(entry.Remote(), isDir, entry.Size(), time.Time{})
+			o, _ := entry.(fs.Object)
+			//directory.AddObjectEntry(entry.Remote(), isDir, entry.Size(), entry.ModTime(r.Context()), o)
+			directory.AddObjectEntry(entry.Remote(), isDir, entry.Size(), time.Time{}, o)
 		}
 		sortParm := r.URL.Query().Get("sort")
 		orderParm := r.URL.Query().Get("order")
@@ -355,14 +384,38 @@ func (s *Server) serveRemote(w http.ResponseWriter, r *http.Request, path string
 	}
 }
 
+// serveShare serves the file or directory of a share link where
+// urlPath is the path after share.Prefix
+func (s *Server) serveShare(w http.ResponseWriter, r *http.Request, urlPath string) {
+	l, remote, ok := s.share.Check(w, r, urlPath)
+	if !ok {
+		return
+	}
+	isDir := l.Dir && strings.HasSuffix(urlPath, "/")
+	if r.Method == "GET" && (!isDir || r.URL.Query().Get("download") != "") && !s.share.Download(w, l) {
+		return
+	}
+	if isDir {
+		remote += "/"
+	}
+	s.serveRemote(w, r, remote, l.Fs)
+}
+
 // Match URLS of the form [fs]/remote
 var fsMatch = regexp.MustCompile(`^\[(.*?)\](.*)$`)
 
+// shareDir is the path of the share links
+var shareDir = strings.TrimPrefix(share.Prefix, "/")
+
 func (s *Server) handleGet(w http.ResponseWriter, r *http.Request, path string) {
 	// Look to see if this has an fs in the path
 	fsMatchResult := fsMatch.FindStringSubmatch(path)
 
 	switch {
+	case s.share != nil && strings.HasPrefix(path, shareDir):
+		// Serve share links
+		s.serveShare(w, r, path[len(shareDir):])
+		return
 	case fsMatchResult != nil && s.opt.Serve:
 		// Serve /[fs]/remote files
 		s.serveRemote(w, r, fsMatchResult[2], fsMatchResult[1])
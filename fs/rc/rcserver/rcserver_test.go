@@ -22,6 +22,7 @@ import (
 	"github.com/rclone/rclone/fs/accounting"
 	"github.com/rclone/rclone/fs/config/configfile"
 	"github.com/rclone/rclone/fs/rc"
+	"github.com/rclone/rclone/lib/http/share"
 )
 
 const (
@@ -323,6 +324,27 @@ func TestRemoteServing(t *testing.T) {
 			Status:   http.StatusPartialContent,
 			Range:    "bytes=8-12",
 			Expected: `file1`,
+		}, {
+			Name:   "dir-download",
+			URL:    remoteURL + "dir/?download=tar",
+			Status: http.StatusOK,
+			// There may be a PAX header first if the time has fractional seconds
+			Contains: regexp.MustCompile(`(?s)^(?:PaxHeaders\.0/file2\.txt\x00.*)?file2\.txt\x00.*this is dir/file2\.txt\n`),
+			Headers: map[string]string{
+				"Content-Type":        "application/x-tar",
+				"Content-Disposition": "attachment; filename=dir.tar",
+			},
+		}, {
+			Name:   "dir-download-bad-format",
+			URL:    remoteURL + "dir/?download=rar",
+			Status: http.StatusBadRequest,
+			Expected: `{
+	"error": "unknown archive format \"rar\" - use zip, tar or tar.gz",
+	"input": null,
+	"path": "dir",
+	"status": 400
+}
+`,
 		}, {
 			Name:   "bad-remote",
 			URL:    "[notfoundremote:]/",
@@ -781,3 +803,58 @@ func TestRCAsync(t *testing.T) {
 	opt.Files = ""
 	testServer(t, tests, &opt)
 }
+
+func TestShareLinks(t *testing.T) {
+	link := func(l *share.Link) string {
+		token, err := l.Sign("secret")
+		require.NoError(t, err)
+		return strings.TrimPrefix(l.URL("", token), "/")
+	}
+	dirLink := link(&share.Link{ID: "2", Fs: testFs, Path: "dir", Dir: true, MaxDownloads: 1})
+	tests := []testRun{{
+		Name:     "file",
+		URL:      link(&share.Link{ID: "1", Fs: testFs, Path: "file.txt"}),
+		Status:   http.StatusOK,
+		Expected: "this is file1.txt\n",
+	}, {
+		Name:     "dir",
+		URL:      dirLink,
+		Status:   http.StatusOK,
+		Contains: regexp.MustCompile(`<a href="file2.txt">file2.txt</a>`),
+	}, {
+		Name:     "dir-file",
+		URL:      dirLink + "file2.txt",
+		Status:   http.StatusOK,
+		Expected: "this is dir/file2.txt\n",
+	}, {
+		Name:     "dir-file-used-up",
+		URL:      dirLink + "file2.txt",
+		Status:   http.StatusGone,
+		Expected: "Share link download limit reached\n",
+	}, {
+		Name:     "expired",
+		URL:      link(&share.Link{ID: "3", Fs: testFs, Path: "file.txt", Expires: time.Now().Add(-time.Minute).Unix()}),
+		Status:   http.StatusGone,
+		Expected: "Share link has expired\n",
+	}, {
+		Name:     "bad",
+		URL:      ".share/rubbish/file.txt",
+		Status:   http.StatusNotFound,
+		Expected: "Not Found\n",
+	}, {
+		Name:        "publiclink",
+		URL:         "operations/publiclink",
+		Method:      "POST",
+		ContentType: "application/x-www-form-urlencoded",
+		Body:        "fs=" + testFs + "&remote=file.txt",
+		Status:      http.StatusOK,
+		Contains:    regexp.MustCompile(`"url": "http://localhost:5572/\.share/[^/"]+/file\.txt"`),
+	}}
+	opt := newTestOpt()
+	opt.NoAuth = true
+	opt.ShareSecret = "secret"
+	testServer(t, tests, &opt)
+
+	// The global options for making share links aren't changed
+	assert.False(t, share.Opt.Enabled())
+}
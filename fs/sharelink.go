@@ -0,0 +1,57 @@
+package fs
+
+import "context"
+
+// ShareLinker makes share links - links to files and directories
+// which rclone serves itself - for backends without public links of
+// their own.
+type ShareLinker interface {
+	// CanMakeLinks returns true if share links can be made
+	CanMakeLinks() bool
+
+	// MakeLink makes a share link for the file or directory at
+	// remote in f which expires after expire unless it is
+	// DurationOff.
+	MakeLink(ctx context.Context, f Fs, remote string, expire Duration) (string, error)
+}
+
+// ShareLinks makes share links if there isn't a ShareLinker in the
+// context.
+//
+// set in lib/http/share to avoid a circular import
+var ShareLinks ShareLinker
+
+type shareLinkerContextKeyType struct{}
+
+// Context key for the ShareLinker
+var shareLinkerContextKey = shareLinkerContextKeyType{}
+
+// GetShareLinker returns the ShareLinker in ctx or the global
+// ShareLinks if there isn't one. It returns nil if share links
+// aren't available.
+func GetShareLinker(ctx context.Context) ShareLinker {
+	if ctx != nil {
+		if l, ok := ctx.Value(shareLinkerContextKey).(ShareLinker); ok {
+			return l
+		}
+	}
+	return ShareLinks
+}
+
+// WithShareLinker returns a new context with l to make share links
+func WithShareLinker(ctx context.Context, l ShareLinker) context.Context {
+	return context.WithValue(ctx, shareLinkerContextKey, l)
+}
+
+// CopyShareLinker copies the ShareLinker (if any) from srcCtx into
+// dstCtx returning the new context.
+func CopyShareLinker(dstCtx, srcCtx context.Context) context.Context {
+	if srcCtx == nil {
+		return dstCtx
+	}
+	l := srcCtx.Value(shareLinkerContextKey)
+	if l == nil {
+		return dstCtx
+	}
+	return context.WithValue(dstCtx, shareLinkerContextKey, l)
+}
@@ -0,0 +1,39 @@
+package fs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testShareLinker is a ShareLinker for testing
+type testShareLinker struct {
+	name string
+}
+
+func (l *testShareLinker) CanMakeLinks() bool { return true }
+
+func (l *testShareLinker) MakeLink(ctx context.Context, f Fs, remote string, expire Duration) (string, error) {
+	return l.name + "/" + remote, nil
+}
+
+func TestShareLinkerContext(t *testing.T) {
+	oldShareLinks := ShareLinks
+	defer func() { ShareLinks = oldShareLinks }()
+	global := &testShareLinker{name: "global"}
+	ShareLinks = global
+
+	ctx := context.Background()
+	assert.Equal(t, global, GetShareLinker(ctx))
+	assert.Equal(t, global, GetShareLinker(nil)) //lint:ignore SA1012 we want to test passing a nil Context
+
+	l := &testShareLinker{name: "context"}
+	ctx = WithShareLinker(ctx, l)
+	assert.Equal(t, l, GetShareLinker(ctx))
+
+	newCtx := CopyShareLinker(context.Background(), ctx)
+	assert.Equal(t, l, GetShareLinker(newCtx))
+	newCtx = CopyShareLinker(context.Background(), context.Background())
+	assert.Equal(t, global, GetShareLinker(newCtx))
+}
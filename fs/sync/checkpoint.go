@@ -0,0 +1,305 @@
+package sync
+
+// The checkpoint journal records the objects which have been checked
+// or transferred so that an interrupted sync run with --resume can
+// skip them when it is restarted.
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/lib/kv"
+)
+
+const (
+	checkpointFacility   = "sync-checkpoint" // prefix of the kv database names
+	checkpointBatchSize  = 1000              // write the journal after this many records
+	checkpointBatchTime  = 5 * time.Second   // or after this long
+	checkpointConfigKey  = "\x00config"      // key holding the config hash - can't be a remote
+	checkpointRecordSep  = "\x00"            // separates the src and dst fingerprints in a record
+	checkpointHashLength = 16                // number of hex digits of hashes to use
+)
+
+// checkpoint is the journal of objects which are known to be in sync
+type checkpoint struct {
+	db       *kv.DB
+	checksum bool // set if the records include the hashes
+	mu       sync.Mutex
+	pending  map[string]string // records not yet written to the db
+	lastSave time.Time         // when pending was last written
+	skipped  int64             // number of objects skipped because they were in the journal
+}
+
+// checkpointHash returns a short hex hash of the strings passed in
+func checkpointHash(items ...string) string {
+	h := md5.New()
+	for _, item := range items {
+		_, _ = io.WriteString(h, item)
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:checkpointHashLength]
+}
+
+// checkpointFacility returns the name of the kv database for the
+// journal which is unique to the source and destination
+func (s *syncCopyMove) checkpointFacility() string {
+	return checkpointFacility + "-" + checkpointHash(fs.ConfigString(s.fsrc), fs.ConfigString(s.fdst))
+}
+
+// checkpointConfig returns a hash of the config which affects which
+// objects are considered in sync. If it changes the journal is
+// discarded.
+func (s *syncCopyMove) checkpointConfig() string {
+	ci, fi := s.ci, s.fi
+	config := struct {
+		DeleteMode      fs.DeleteMode
+		CheckSum        bool
+		SizeOnly        bool
+		IgnoreTimes     bool
+		IgnoreSize      bool
+		IgnoreChecksum  bool
+		IgnoreExisting  bool
+		IgnoreCaseSync  bool
+		UpdateOlder     bool
+		ModifyWindow    time.Duration
+		NoUpdateModTime bool
+		Immutable       bool
+		Metadata        bool
+		MetadataSet     fs.Metadata
+		CompareDest     []string
+		CopyDest        []string
+		BackupDir       string
+		Suffix          string
+		MaxDepth        int
+		Filter          interface{}
+		FilterFiles     []string
+	}{
+		DeleteMode:      s.deleteMode,
+		CheckSum:        ci.CheckSum,
+		SizeOnly:        ci.SizeOnly,
+		IgnoreTimes:     ci.IgnoreTimes,
+		IgnoreSize:      ci.IgnoreSize,
+		IgnoreChecksum:  ci.IgnoreChecksum,
+		IgnoreExisting:  ci.IgnoreExisting,
+		IgnoreCaseSync:  ci.IgnoreCaseSync,
+		UpdateOlder:     ci.UpdateOlder,
+		ModifyWindow:    ci.ModifyWindow,
+		NoUpdateModTime: ci.NoUpdateModTime,
+		Immutable:       ci.Immutable,
+		Metadata:        ci.Metadata,
+		MetadataSet:     ci.MetadataSet,
+		CompareDest:     ci.CompareDest,
+		CopyDest:        ci.CopyDest,
+		BackupDir:       ci.BackupDir,
+		Suffix:          ci.Suffix,
+		MaxDepth:        ci.MaxDepth,
+		Filter:          fi.Opt,
+	}
+	// Include the contents of any filter files so editing them
+	// invalidates the journal
+	for _, files := range [][]string{fi.Opt.FilterFrom, fi.Opt.ExcludeFrom, fi.Opt.IncludeFrom, fi.Opt.FilesFrom, fi.Opt.FilesFromRaw} {
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				// stdin or unreadable - can't tell if it has changed
+				data = []byte(time.Now().String())
+			}
+			config.FilterFiles = append(config.FilterFiles, checkpointHash(string(data)))
+		}
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		// shouldn't happen but make sure the journal isn't reused
+		return checkpointHash(time.Now().String())
+	}
+	return checkpointHash(string(data))
+}
+
+// kvGet reads the value of a key
+type kvGet struct {
+	key   string
+	value string
+	found bool
+}
+
+func (op *kvGet) Do(ctx context.Context, b kv.Bucket) error {
+	data := b.Get([]byte(op.key))
+	op.found = data != nil
+	op.value = string(data)
+	return nil
+}
+
+// kvPut writes a set of keys and values
+type kvPut struct {
+	items map[string]string
+}
+
+func (op *kvPut) Do(ctx context.Context, b kv.Bucket) error {
+	for key, value := range op.items {
+		if err := b.Put([]byte(key), []byte(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startCheckpoint opens the checkpoint journal for the sync,
+// discarding it if the config has changed since it was written.
+//
+// It returns nil if --resume isn't in use or the journal can't be
+// used.
+func (s *syncCopyMove) startCheckpoint(ctx context.Context) *checkpoint {
+	if !s.ci.Resume {
+		return nil
+	}
+	if s.DoMove {
+		fs.Logf(s.fdst, "Ignoring --resume with move as moved files are removed from the source")
+		return nil
+	}
+	if !kv.Supported() {
+		fs.Errorf(s.fdst, "Ignoring --resume as it isn't supported on this OS")
+		return nil
+	}
+	facility := s.checkpointFacility()
+	config := s.checkpointConfig()
+	db, err := kv.Start(ctx, facility, nil)
+	if err != nil {
+		fs.Errorf(s.fdst, "Ignoring --resume as the checkpoint journal couldn't be opened: %v", err)
+		return nil
+	}
+	get := &kvGet{key: checkpointConfigKey}
+	err = db.Do(false, get)
+	if err != nil && !errors.Is(err, kv.ErrEmpty) {
+		fs.Errorf(s.fdst, "Ignoring --resume as the checkpoint journal couldn't be read: %v", err)
+		_ = db.Stop(false)
+		return nil
+	}
+	switch {
+	case !get.found:
+		fs.Debugf(s.fdst, "Starting new checkpoint journal %q", db.Path())
+	case get.value != config:
+		fs.Infof(s.fdst, "Discarding checkpoint journal as the filters or flags have changed")
+		// Remove the database file and start again
+		_ = db.Stop(true)
+		db, err = kv.Start(ctx, facility, nil)
+		if err != nil {
+			fs.Errorf(s.fdst, "Ignoring --resume as the checkpoint journal couldn't be opened: %v", err)
+			return nil
+		}
+	default:
+		fs.Infof(s.fdst, "Resuming from checkpoint journal %q", db.Path())
+	}
+	err = db.Do(true, &kvPut{items: map[string]string{checkpointConfigKey: config}})
+	if err != nil {
+		fs.Errorf(s.fdst, "Ignoring --resume as the checkpoint journal couldn't be written: %v", err)
+		_ = db.Stop(false)
+		return nil
+	}
+	return &checkpoint{
+		db:       db,
+		checksum: s.ci.CheckSum,
+		pending:  make(map[string]string),
+		lastSave: time.Now(),
+	}
+}
+
+// makeRecord makes the journal record for a src and dst pair
+//
+// With --checksum the record includes the hashes even if they are
+// slow to read so a changed object isn't skipped.
+func (c *checkpoint) makeRecord(ctx context.Context, src, dst fs.ObjectInfo) string {
+	fast := !c.checksum
+	return fs.Fingerprint(ctx, src, fast) + checkpointRecordSep + fs.Fingerprint(ctx, dst, fast)
+}
+
+// done returns true if the journal shows src and dst were in sync and
+// neither has changed since.
+func (c *checkpoint) done(ctx context.Context, src, dst fs.Object) bool {
+	if c == nil || dst == nil {
+		return false
+	}
+	remote := src.Remote()
+	record := c.makeRecord(ctx, src, dst)
+	c.mu.Lock()
+	value, found := c.pending[remote]
+	c.mu.Unlock()
+	if !found {
+		get := &kvGet{key: remote}
+		err := c.db.Do(false, get)
+		if err != nil {
+			fs.Debugf(src, "Failed to read checkpoint journal: %v", err)
+			return false
+		}
+		value, found = get.value, get.found
+	}
+	if !found || value != record {
+		return false
+	}
+	c.mu.Lock()
+	c.skipped++
+	c.mu.Unlock()
+	return true
+}
+
+// record that src and dst are in sync
+func (c *checkpoint) record(ctx context.Context, src, dst fs.Object) {
+	if c == nil || dst == nil {
+		return
+	}
+	record := c.makeRecord(ctx, src, dst)
+	c.mu.Lock()
+	c.pending[src.Remote()] = record
+	var items map[string]string
+	if len(c.pending) >= checkpointBatchSize || time.Since(c.lastSave) >= checkpointBatchTime {
+		items = c.pending
+		c.pending = make(map[string]string)
+		c.lastSave = time.Now()
+	}
+	c.mu.Unlock()
+	if items != nil {
+		c.save(items)
+	}
+}
+
+// save writes items to the journal
+func (c *checkpoint) save(items map[string]string) {
+	err := c.db.Do(true, &kvPut{items: items})
+	if err != nil {
+		fs.Errorf(nil, "Failed to write checkpoint journal: %v", err)
+	}
+}
+
+// finish closes the journal. If the sync was successful the journal
+// is removed as it isn't needed any more, otherwise any pending
+// records are written out so a later run can resume from them.
+func (c *checkpoint) finish(f fs.Fs, success bool) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	items := c.pending
+	c.pending = make(map[string]string)
+	skipped := c.skipped
+	c.mu.Unlock()
+	if skipped > 0 {
+		fs.Infof(f, "Skipped %d files already in sync according to the checkpoint journal", skipped)
+	}
+	if success {
+		fs.Debugf(f, "Removing checkpoint journal as the sync completed")
+		_ = c.db.Stop(true)
+		return
+	}
+	if len(items) > 0 {
+		c.save(items)
+	}
+	fs.Infof(f, "Keeping checkpoint journal %q so the sync can be resumed with --resume", c.db.Path())
+	_ = c.db.Stop(false)
+}
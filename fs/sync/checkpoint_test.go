@@ -0,0 +1,141 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fstest"
+	"github.com/rclone/rclone/lib/kv"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpointResume(t *testing.T) {
+	if !kv.Supported() {
+		t.Skip("kv database not supported on this OS")
+	}
+	ctx := context.Background()
+	ctx, ci := fs.AddConfig(ctx)
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	if !r.Fremote.Features().SlowHash {
+		t.Skip("Need a remote which doesn't fingerprint with hashes")
+	}
+	ci.Resume = true
+	ci.CheckSum = true
+
+	file1 := r.WriteFile("sub dir/file1", "hello", t1)
+	file2 := r.WriteObject(ctx, "sub dir/file1", "hello", t1)
+	r.CheckLocalItems(t, file1)
+	r.CheckRemoteItems(t, file2)
+
+	s, err := newSyncCopyMove(ctx, r.Fremote, r.Flocal, fs.DeleteModeOff, false, false, false)
+	require.NoError(t, err)
+
+	// Hold a reference to the journal so it isn't removed when
+	// it is opened again in this test binary
+	db, err := kv.Start(ctx, s.checkpointFacility(), nil)
+	require.NoError(t, err)
+	defer func() { _ = db.Stop(true) }()
+
+	// Make a journal as if a sync was interrupted after checking file1
+	c := s.startCheckpoint(ctx)
+	require.NotNil(t, c)
+	src, err := r.Flocal.NewObject(ctx, "sub dir/file1")
+	require.NoError(t, err)
+	dst, err := r.Fremote.NewObject(ctx, "sub dir/file1")
+	require.NoError(t, err)
+	c.record(ctx, src, dst)
+	c.finish(r.Fremote, false)
+
+	// Change the contents of the destination without changing its
+	// size or modification time
+	file3 := r.WriteObject(ctx, "sub dir/file1", "HELLO", t1)
+	r.CheckRemoteItems(t, file3)
+
+	// The resumed sync should transfer the file as the hashes
+	// are in the journal with --checksum
+	err = CopyDir(ctx, r.Fremote, r.Flocal, false)
+	require.NoError(t, err)
+	r.CheckRemoteItems(t, file1)
+}
+
+func TestCheckpointSkip(t *testing.T) {
+	if !kv.Supported() {
+		t.Skip("kv database not supported on this OS")
+	}
+	ctx := context.Background()
+	ctx, ci := fs.AddConfig(ctx)
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	ci.Resume = true
+
+	// Make the destination differ so a real check would copy it
+	file1 := r.WriteFile("sub dir/file1", "hello", t1)
+	file2 := r.WriteObject(ctx, "sub dir/file1", "HELLO", t2)
+	r.CheckLocalItems(t, file1)
+	r.CheckRemoteItems(t, file2)
+
+	s, err := newSyncCopyMove(ctx, r.Fremote, r.Flocal, fs.DeleteModeOff, false, false, false)
+	require.NoError(t, err)
+	db, err := kv.Start(ctx, s.checkpointFacility(), nil)
+	require.NoError(t, err)
+	defer func() { _ = db.Stop(true) }()
+
+	// Record file1 as being in sync
+	c := s.startCheckpoint(ctx)
+	require.NotNil(t, c)
+	src, err := r.Flocal.NewObject(ctx, "sub dir/file1")
+	require.NoError(t, err)
+	dst, err := r.Fremote.NewObject(ctx, "sub dir/file1")
+	require.NoError(t, err)
+	c.record(ctx, src, dst)
+	c.finish(r.Fremote, false)
+
+	// The resumed sync should trust the journal and leave it alone
+	err = CopyDir(ctx, r.Fremote, r.Flocal, false)
+	require.NoError(t, err)
+	r.CheckRemoteItems(t, file2)
+}
+
+func TestCheckpointInvalidate(t *testing.T) {
+	if !kv.Supported() {
+		t.Skip("kv database not supported on this OS")
+	}
+	ctx := context.Background()
+	ctx, ci := fs.AddConfig(ctx)
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	if !r.Fremote.Features().SlowHash {
+		t.Skip("Need a remote which doesn't fingerprint with hashes")
+	}
+	ci.Resume = true
+	ci.CheckSum = true
+
+	file1 := r.WriteFile("file1", "hello", t1)
+	file2 := r.WriteObject(ctx, "file1", "HELLO", t1)
+
+	s, err := newSyncCopyMove(ctx, r.Fremote, r.Flocal, fs.DeleteModeOff, false, false, false)
+	require.NoError(t, err)
+	db, err := kv.Start(ctx, s.checkpointFacility(), nil)
+	require.NoError(t, err)
+	defer func() { _ = db.Stop(true) }()
+
+	// Record file1 as being in sync
+	c := s.startCheckpoint(ctx)
+	require.NotNil(t, c)
+	src, err := r.Flocal.NewObject(ctx, "file1")
+	require.NoError(t, err)
+	dst, err := r.Fremote.NewObject(ctx, "file1")
+	require.NoError(t, err)
+	c.record(ctx, src, dst)
+	c.finish(r.Fremote, false)
+	r.CheckRemoteItems(t, file2)
+
+	// Changing the flags invalidates the journal so the file is
+	// checked and transferred
+	ci.IgnoreCaseSync = true
+	err = CopyDir(ctx, r.Fremote, r.Flocal, false)
+	require.NoError(t, err)
+	r.CheckRemoteItems(t, file1)
+}
@@ -74,6 +74,10 @@ type syncCopyMove struct {
 	backupDir              fs.Fs                  // place to store overwrites/deletes
 	checkFirst             bool                   // if set run all the checkers before starting transfers
 	maxDurationEndTime     time.Time              // end time if --max-duration is set
+	setDirMetadata         bool                   // set if we should copy directory modtimes and metadata
+	dirsMu                 sync.Mutex             // protect dirs
+	dirs                   map[string]fs.DirEntry // src directories to copy modtimes and metadata from by dst remote
+	checkpoint             *checkpoint            // journal of objects in sync if --resume is set
 }
 
 type trackRenamesStrategy byte
@@ -126,6 +130,7 @@ func newSyncCopyMove(ctx context.Context, fdst, fsrc fs.Fs, deleteMode fs.Delete
 		modifyWindow:           fs.GetModifyWindow(ctx, fsrc, fdst),
 		trackRenamesCh:         make(chan fs.Object, ci.Checkers),
 		checkFirst:             ci.CheckFirst,
+		dirs:                   make(map[string]fs.DirEntry),
 	}
 	backlog := ci.MaxBacklog
 	if s.checkFirst {
@@ -220,6 +225,12 @@ func newSyncCopyMove(ctx context.Context, fdst, fsrc fs.Fs, deleteMode fs.Delete
 			s.noTraverse = false
 		}
 	}
+	// Copy directory modification times and metadata if the
+	// destination supports it
+	features := fdst.Features()
+	if (features.WriteDirSetModTime && !ci.NoUpdateDirModTime) || (features.WriteDirMetadata && ci.Metadata) {
+		s.setDirMetadata = s.deleteMode != fs.DeleteModeOnly
+	}
 	// Make Fs for --backup-dir if required
 	if ci.BackupDir != "" || ci.Suffix != "" {
 		var err error
@@ -330,7 +341,9 @@ func (s *syncCopyMove) pairChecker(in *pipe, out *pipe, fraction int, wg *sync.W
 		var err error
 		tr := accounting.Stats(s.ctx).NewCheckingTransfer(src)
 		// Check to see if can store this
-		if src.Storable() {
+		if s.checkpoint.done(s.ctx, src, pair.Dst) {
+			fs.Debugf(src, "Skipping as in sync according to the checkpoint journal")
+		} else if src.Storable() {
 			NoNeedTransfer, err := operations.CompareOrCopyDest(s.ctx, s.fdst, pair.Dst, pair.Src, s.compareCopyDest, s.backupDir)
 			if err != nil {
 				s.processError(err)
@@ -363,6 +376,9 @@ func (s *syncCopyMove) pairChecker(in *pipe, out *pipe, fraction int, wg *sync.W
 					}
 				}
 			} else {
+				if !NoNeedTransfer {
+					s.checkpoint.record(s.ctx, src, pair.Dst)
+				}
 				// If moving need to delete the files we don't need to copy
 				if s.DoMove {
 					// Delete src if no error on copy
@@ -410,10 +426,14 @@ func (s *syncCopyMove) pairCopyOrMove(ctx context.Context, in *pipe, fdst fs.Fs,
 			return
 		}
 		src := pair.Src
+		var dst fs.Object
 		if s.DoMove {
-			_, err = operations.Move(ctx, fdst, pair.Dst, src.Remote(), src)
+			dst, err = operations.Move(ctx, fdst, pair.Dst, src.Remote(), src)
 		} else {
-			_, err = operations.Copy(ctx, fdst, pair.Dst, src.Remote(), src)
+			dst, err = operations.Copy(ctx, fdst, pair.Dst, src.Remote(), src)
+		}
+		if err == nil {
+			s.checkpoint.record(ctx, src, dst)
 		}
 		s.processError(err)
 	}
@@ -630,6 +650,96 @@ func copyEmptyDirectories(ctx context.Context, f fs.Fs, entries map[string]fs.Di
 	return nil
 }
 
+// recordDir records the src directory so its modification time and
+// metadata can be copied to the dst directory remote once its
+// contents have been synced
+func (s *syncCopyMove) recordDir(remote string, src fs.Directory) {
+	if !s.setDirMetadata {
+		return
+	}
+	s.dirsMu.Lock()
+	s.dirs[remote] = src
+	s.dirsMu.Unlock()
+}
+
+// copyDirMetadata copies the modification times and metadata of the
+// recorded directories to the destination.
+//
+// This is done after all the transfers and deletions as these change
+// the modification times of the directories. The destination
+// directories are read again from their parents so they are up to
+// date and include any newly created ones.
+func (s *syncCopyMove) copyDirMetadata() error {
+	if len(s.dirs) == 0 {
+		return nil
+	}
+	if s.currentError() != nil && !s.ci.IgnoreErrors {
+		fs.Debugf(s.fdst, "Not copying directory modification times and metadata as there were errors")
+		return nil
+	}
+	// Group the directories by parent so each parent is only listed once
+	parents := make(map[string][]string)
+	for remote := range s.dirs {
+		parent := path.Dir(remote)
+		if parent == "." {
+			parent = ""
+		}
+		parents[parent] = append(parents[parent], remote)
+	}
+	var (
+		wg      sync.WaitGroup
+		tokens  = make(chan struct{}, s.ci.Checkers)
+		errMu   sync.Mutex
+		lastErr error
+		okCount int
+	)
+	for parent, remotes := range parents {
+		parent, remotes := parent, remotes
+		if s.aborting() {
+			break
+		}
+		tokens <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-tokens
+				wg.Done()
+			}()
+			entries, err := s.fdst.List(s.ctx, parent)
+			if err != nil {
+				if err != fs.ErrorDirNotFound {
+					fs.Errorf(fs.LogDirName(s.fdst, parent), "Failed to list directory to set directory metadata: %v", err)
+				}
+				return
+			}
+			dstDirs := make(map[string]fs.Directory, len(entries))
+			entries.ForDir(func(dir fs.Directory) {
+				dstDirs[dir.Remote()] = dir
+			})
+			for _, remote := range remotes {
+				dst, found := dstDirs[remote]
+				if !found {
+					// not created, e.g. empty and --create-empty-src-dirs not set
+					continue
+				}
+				err := operations.CopyDirMetadata(s.ctx, s.fdst, s.fsrc, dst, s.dirs[remote].(fs.Directory))
+				errMu.Lock()
+				if err != nil {
+					lastErr = err
+				} else {
+					okCount++
+				}
+				errMu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if okCount > 0 {
+		fs.Debugf(s.fdst, "checked modification times and metadata of %d directories", okCount)
+	}
+	return lastErr
+}
+
 func (s *syncCopyMove) srcParentDirCheck(entry fs.DirEntry) {
 	// If we are moving files then we don't want to remove directories with files in them
 	// from the srcEmptyDirs as we are about to move them making the directory empty.
@@ -840,6 +950,11 @@ func (s *syncCopyMove) run() error {
 		return nil
 	}
 
+	// Open the checkpoint journal if required
+	if s.deleteMode != fs.DeleteModeOnly {
+		s.checkpoint = s.startCheckpoint(s.ctx)
+	}
+
 	// Start background checking and transferring pipeline
 	s.startCheckers()
 	s.startRenamers()
@@ -910,6 +1025,10 @@ func (s *syncCopyMove) run() error {
 		}
 	}
 
+	// Copy the directory modification times and metadata now
+	// their contents are complete
+	s.processError(s.copyDirMetadata())
+
 	// Delete empty fsrc subdirectories
 	// if DoMove and --delete-empty-src-dirs flag is set
 	if s.DoMove && s.deleteEmptySrcDirs {
@@ -927,6 +1046,9 @@ func (s *syncCopyMove) run() error {
 		s.processError(errorMaxDurationReached)
 	}
 
+	// Remove the checkpoint journal if successful otherwise save it
+	s.checkpoint.finish(s.fdst, s.currentError() == nil)
+
 	// Print nothing to transfer message if there were no transfers and no errors
 	if s.deleteMode != fs.DeleteModeOnly && accounting.Stats(s.ctx).GetTransfers() == 0 && s.currentError() == nil {
 		fs.Infof(nil, "There was nothing to transfer")
@@ -1018,6 +1140,7 @@ func (s *syncCopyMove) SrcOnly(src fs.DirEntry) (recurse bool) {
 		s.srcParentDirCheck(src)
 		s.srcEmptyDirs[src.Remote()] = src
 		s.srcEmptyDirsMu.Unlock()
+		s.recordDir(src.Remote(), x)
 		return true
 	default:
 		panic("Bad object in DirEntries")
@@ -1052,6 +1175,7 @@ func (s *syncCopyMove) Match(ctx context.Context, dst, src fs.DirEntry) (recurse
 		// Do the same thing to the entire contents of the directory
 		_, ok := dst.(fs.Directory)
 		if ok {
+			s.recordDir(dst.Remote(), srcX)
 			// Only record matched (src & dst) empty dirs when performing move
 			if s.DoMove {
 				// Record the src directory for deletion
@@ -236,6 +236,91 @@ func TestMoveEmptyDirectories(t *testing.T) {
 	)
 }
 
+// setDirModTime sets the modification time of the directory dir in
+// the root of f
+func setDirModTime(ctx context.Context, t *testing.T, f fs.Fs, dir string, modTime time.Time) {
+	entries, err := f.List(ctx, "")
+	require.NoError(t, err)
+	for _, entry := range entries {
+		if entry.Remote() == dir {
+			do, ok := entry.(fs.SetModTimer)
+			require.True(t, ok, "directory doesn't support SetModTime")
+			require.NoError(t, do.SetModTime(ctx, modTime))
+			return
+		}
+	}
+	t.Fatalf("directory %q not found", dir)
+}
+
+// checkDirModTimes checks the modification times of the directories
+// in the root of f
+func checkDirModTimes(ctx context.Context, t *testing.T, f fs.Fs, want map[string]time.Time, precision time.Duration) {
+	entries, err := f.List(ctx, "")
+	require.NoError(t, err)
+	found := 0
+	entries.ForDir(func(dir fs.Directory) {
+		modTime, ok := want[dir.Remote()]
+		if ok {
+			fstest.AssertTimeEqualWithPrecision(t, dir.Remote(), modTime, dir.ModTime(ctx), precision)
+			found++
+		}
+	})
+	assert.Equal(t, len(want), found)
+}
+
+func testCopyDirModTime(t *testing.T, noUpdateDirModTime bool) {
+	ctx := context.Background()
+	ctx, ci := fs.AddConfig(ctx)
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	if !r.Flocal.Features().WriteDirSetModTime || !r.Fremote.Features().WriteDirSetModTime {
+		t.Skip("Can't set directory modification times")
+	}
+	ci.NoUpdateDirModTime = noUpdateDirModTime
+	file1 := r.WriteFile("sub dir/hello world", "hello world", t1)
+	err := operations.Mkdir(ctx, r.Flocal, "sub dir2")
+	require.NoError(t, err)
+	r.Mkdir(ctx, r.Fremote)
+	setDirModTime(ctx, t, r.Flocal, "sub dir", t2)
+	setDirModTime(ctx, t, r.Flocal, "sub dir2", t3)
+
+	err = CopyDir(ctx, r.Fremote, r.Flocal, true)
+	require.NoError(t, err)
+
+	r.CheckRemoteListing(
+		t,
+		[]fstest.Item{
+			file1,
+		},
+		[]string{
+			"sub dir",
+			"sub dir2",
+		},
+	)
+	if noUpdateDirModTime {
+		entries, err := r.Fremote.List(ctx, "")
+		require.NoError(t, err)
+		entries.ForDir(func(dir fs.Directory) {
+			_, ok := fstest.CheckTimeEqualWithPrecision(t2, dir.ModTime(ctx), r.Fremote.Precision())
+			assert.False(t, ok, "modification time of %q shouldn't have been copied", dir.Remote())
+		})
+		return
+	}
+	want := map[string]time.Time{
+		"sub dir": t2,
+	}
+	if r.Fremote.Features().CanHaveEmptyDirectories {
+		want["sub dir2"] = t3
+	}
+	checkDirModTimes(ctx, t, r.Fremote, want, fs.GetModifyWindow(ctx, r.Fremote))
+}
+
+// Test copy directory modification times
+func TestCopyDirModTime(t *testing.T) { testCopyDirModTime(t, false) }
+
+// Test copy directory modification times with --no-update-dir-modtime
+func TestCopyDirModTimeWithNoUpdateDirModTime(t *testing.T) { testCopyDirModTime(t, true) }
+
 // Test sync empty directories
 func TestSyncEmptyDirectories(t *testing.T) {
 	ctx := context.Background()
@@ -183,7 +183,7 @@ type GetTierer interface {
 	GetTier() string
 }
 
-// Metadataer is an optional interface for Object
+// Metadataer is an optional interface for Object and Directory
 type Metadataer interface {
 	// Metadata returns metadata for an object
 	//
@@ -191,6 +191,21 @@ type Metadataer interface {
 	Metadata(ctx context.Context) (Metadata, error)
 }
 
+// SetModTimer is an optional interface for Directory
+type SetModTimer interface {
+	// SetModTime sets the modification time of the Directory
+	SetModTime(ctx context.Context, t time.Time) error
+}
+
+// SetMetadataer is an optional interface for Object and Directory
+type SetMetadataer interface {
+	// SetMetadata sets the metadata of the Object or Directory
+	//
+	// Only the keys in metadata are changed and any which can't
+	// be set are ignored
+	SetMetadata(ctx context.Context, metadata Metadata) error
+}
+
 // FullObjectInfo contains all the read-only optional interfaces
 //
 // Use for checking making wrapping ObjectInfos implement everything
@@ -216,6 +231,16 @@ type FullObject interface {
 	Metadataer
 }
 
+// FullDirectory contains all the optional interfaces for Directory
+//
+// Use for checking making wrapping Directories implement everything
+type FullDirectory interface {
+	Directory
+	Metadataer
+	SetModTimer
+	SetMetadataer
+}
+
 // ObjectOptionalInterfaces returns the names of supported and
 // unsupported optional interfaces for an Object
 func ObjectOptionalInterfaces(o Object) (supported, unsupported []string) {
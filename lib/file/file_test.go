@@ -167,3 +167,43 @@ func TestIsReserved(t *testing.T) {
 	require.Error(t, IsReserved("test."))
 	require.Error(t, IsReserved("test "))
 }
+
+// Test punching a hole in a file reads back as zeros
+func TestPunchHole(t *testing.T) {
+	if !PunchHoleImplemented {
+		t.Skip("PunchHole not implemented on this OS")
+	}
+	dir := t.TempDir()
+	fd, err := OpenFile(path.Join(dir, "sparse"), os.O_CREATE|os.O_RDWR, 0600)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, fd.Close())
+	}()
+	require.NoError(t, SetSparse(fd))
+
+	const blockSize = 64 * 1024
+	data := make([]byte, 3*blockSize)
+	for i := range data {
+		data[i] = 'x'
+	}
+	_, err = fd.Write(data)
+	require.NoError(t, err)
+
+	err = PunchHole(fd, blockSize, blockSize)
+	if err == ErrPunchHoleUnsupported {
+		t.Skip("PunchHole not supported on this file system")
+	}
+	require.NoError(t, err)
+
+	fi, err := fd.Stat()
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), fi.Size(), "size must be unchanged")
+
+	got := make([]byte, len(data))
+	_, err = fd.ReadAt(got, 0)
+	require.NoError(t, err)
+	for i := range data[blockSize : 2*blockSize] {
+		data[blockSize+i] = 0
+	}
+	assert.Equal(t, data, got)
+}
@@ -22,3 +22,13 @@ const SetSparseImplemented = false
 func SetSparse(out *os.File) error {
 	return nil
 }
+
+// PunchHoleImplemented is a constant indicating whether the
+// implementation of PunchHole actually does anything.
+const PunchHoleImplemented = false
+
+// PunchHole deallocates size bytes of the file from offset leaving
+// the file size unchanged. The range reads back as zeros afterwards.
+func PunchHole(out *os.File, offset, size int64) error {
+	return ErrPunchHoleUnsupported
+}
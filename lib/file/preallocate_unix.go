@@ -71,3 +71,25 @@ const SetSparseImplemented = false
 func SetSparse(out *os.File) error {
 	return nil
 }
+
+// PunchHoleImplemented is a constant indicating whether the
+// implementation of PunchHole actually does anything.
+const PunchHoleImplemented = true
+
+// PunchHole deallocates size bytes of the file from offset leaving
+// the file size unchanged. The range reads back as zeros afterwards.
+func PunchHole(out *os.File, offset, size int64) (err error) {
+	if size <= 0 {
+		return nil
+	}
+	for {
+		err = unix.Fallocate(int(out.Fd()), unix.FALLOC_FL_KEEP_SIZE|unix.FALLOC_FL_PUNCH_HOLE, offset, size)
+		if err != syscall.EINTR {
+			break
+		}
+	}
+	if err == unix.ENOTSUP || err == unix.EOPNOTSUPP {
+		return ErrPunchHoleUnsupported
+	}
+	return err
+}
@@ -92,7 +92,8 @@ func PreAllocate(size int64, out *os.File) error {
 }
 
 const (
-	FSCTL_SET_SPARSE = 0x000900c4
+	FSCTL_SET_SPARSE    = 0x000900c4
+	FSCTL_SET_ZERO_DATA = 0x000980c8
 )
 
 // SetSparseImplemented is a constant indicating whether the
@@ -108,3 +109,37 @@ func SetSparse(out *os.File) error {
 	}
 	return nil
 }
+
+// PunchHoleImplemented is a constant indicating whether the
+// implementation of PunchHole actually does anything.
+const PunchHoleImplemented = true
+
+// fileZeroDataInformation is the input to FSCTL_SET_ZERO_DATA
+type fileZeroDataInformation struct {
+	FileOffset      int64
+	BeyondFinalZero int64
+}
+
+// PunchHole deallocates size bytes of the file from offset leaving
+// the file size unchanged. The range reads back as zeros afterwards.
+//
+// The file must have been made sparse with SetSparse for the space
+// to be released.
+func PunchHole(out *os.File, offset, size int64) error {
+	if size <= 0 {
+		return nil
+	}
+	var bytesReturned uint32
+	in := fileZeroDataInformation{
+		FileOffset:      offset,
+		BeyondFinalZero: offset + size,
+	}
+	err := syscall.DeviceIoControl(syscall.Handle(out.Fd()), FSCTL_SET_ZERO_DATA, (*byte)(unsafe.Pointer(&in)), uint32(unsafe.Sizeof(in)), nil, 0, &bytesReturned, nil)
+	if err == windows.ERROR_INVALID_FUNCTION || err == windows.ERROR_NOT_SUPPORTED {
+		return ErrPunchHoleUnsupported
+	}
+	if err != nil {
+		return fmt.Errorf("DeviceIoControl FSCTL_SET_ZERO_DATA: %w", err)
+	}
+	return nil
+}
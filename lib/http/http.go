@@ -91,6 +91,8 @@ type Server interface {
 	Router() chi.Router
 	Route(pattern string, fn func(r chi.Router)) chi.Router
 	Mount(pattern string, h http.Handler)
+	Serve()
+	Wait()
 	Shutdown() error
 }
 
@@ -228,6 +230,7 @@ func NewServer(listeners, tlsListeners []net.Listener, opt Options) (Server, err
 	return &server{addrs, tlsAddrs, listeners, tlsListeners, httpServer, router, wg, useSSL}, nil
 }
 
+// Serve starts the server serving requests on its listeners
 func (s *server) Serve() {
 	serve := func(l net.Listener, tls bool) {
 		defer s.closing.Done()
@@ -0,0 +1,79 @@
+package serve
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/archive"
+)
+
+// archiveContentTypes are the Content-Type headers for each archive
+// format
+var archiveContentTypes = map[archive.Format]string{
+	archive.FormatZip:   "application/zip",
+	archive.FormatTar:   "application/x-tar",
+	archive.FormatTarGz: "application/gzip",
+}
+
+// ArchiveFormat returns the archive format asked for with the
+// download query parameter of r, or "" if there isn't one.
+func ArchiveFormat(r *http.Request) (archive.Format, error) {
+	download := r.URL.Query().Get("download")
+	if download == "" {
+		return "", nil
+	}
+	return archive.FormatFromName("", download)
+}
+
+// wroteWriter notes whether anything has been written
+type wroteWriter struct {
+	w     io.Writer
+	wrote bool
+}
+
+// Write to the underlying writer
+func (ww *wroteWriter) Write(p []byte) (int, error) {
+	ww.wrote = true
+	return ww.w.Write(p)
+}
+
+// Archive serves the contents of dir in f as an archive of format via
+// HEAD or GET.
+//
+// The archive is made as it is sent so nothing is buffered on disk.
+// The filters in the context of r choose which files are included.
+func Archive(w http.ResponseWriter, r *http.Request, f fs.Fs, dir string, format archive.Format) {
+	if r.Method != "HEAD" && r.Method != "GET" {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	name := path.Base(dir)
+	if dir == "" {
+		name = f.Name()
+	}
+	w.Header().Set("Content-Type", archiveContentTypes[format])
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": name + "." + string(format),
+	}))
+	if r.Method == "HEAD" {
+		return
+	}
+	fs.Infof(dir, "%s: Serving directory as %s archive", r.RemoteAddr, format)
+	out := &wroteWriter{w: w}
+	err := archive.Write(r.Context(), out, f, dir, format)
+	if err != nil {
+		if out.wrote {
+			// The archive is part sent so log the error and abort
+			// the response so the client doesn't get a truncated
+			// archive which looks complete
+			Error(dir, nil, "Failed to write archive", err)
+			panic(http.ErrAbortHandler)
+		} else {
+			w.Header().Del("Content-Disposition")
+			Error(dir, w, "Failed to write archive", err)
+		}
+	}
+}
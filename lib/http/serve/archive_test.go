@@ -0,0 +1,54 @@
+package serve
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rclone/rclone/fs/archive"
+	"github.com/rclone/rclone/fstest/mockfs"
+	"github.com/rclone/rclone/fstest/mockobject"
+	"github.com/stretchr/testify/assert"
+)
+
+// wrongSizeObject claims to be bigger than its contents
+type wrongSizeObject struct {
+	*mockobject.ContentMockObject
+}
+
+// Size returns the wrong size of the object
+func (o wrongSizeObject) Size() int64 {
+	return o.ContentMockObject.Size() + 100
+}
+
+func TestArchiveGET(t *testing.T) {
+	ctx := context.Background()
+	f := mockfs.NewFs(ctx, "test", "root")
+	o := mockobject.New("file.txt").WithContent([]byte("hello"), mockobject.SeekModeNone)
+	o.SetFs(f)
+	f.AddObject(o)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "http://example.com/?download=tar", nil)
+	Archive(w, r, f, "", archive.FormatTar)
+	resp := w.Result()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-tar", resp.Header.Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=test.tar`, resp.Header.Get("Content-Disposition"))
+	assert.Contains(t, w.Body.String(), "hello")
+}
+
+func TestArchiveAbort(t *testing.T) {
+	ctx := context.Background()
+	f := mockfs.NewFs(ctx, "test", "root")
+	o := mockobject.New("file.txt").WithContent([]byte("hello"), mockobject.SeekModeNone)
+	o.SetFs(f)
+	f.AddObject(wrongSizeObject{o})
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "http://example.com/?download=tar", nil)
+	// The archive has been started so the response must be aborted
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		Archive(w, r, f, "", archive.FormatTar)
+	})
+	assert.NotEqual(t, 0, w.Body.Len())
+}
@@ -2,23 +2,29 @@ package serve
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"html/template"
+	"mime"
 	"net/http"
 	"net/url"
 	"path"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/operations"
 	"github.com/rclone/rclone/lib/rest"
 )
 
 // DirEntry is a directory entry
 type DirEntry struct {
 	remote  string
+	object  fs.Object // the object for a file if known
 	URL     string
 	Leaf    string
 	IsDir   bool
@@ -37,6 +43,8 @@ type Directory struct {
 	Breadcrumb   []Crumb
 	Sort         string
 	Order        string
+	Writable     bool // set if files can be uploaded and deleted
+	Downloadable bool // set if the directory can be downloaded as an archive
 }
 
 // Crumb is a breadcrumb entry
@@ -86,6 +94,13 @@ func (d *Directory) SetQuery(queryParams url.Values) *Directory {
 
 // AddHTMLEntry adds an entry to that directory
 func (d *Directory) AddHTMLEntry(remote string, isDir bool, size int64, modTime time.Time) {
+	d.AddObjectEntry(remote, isDir, size, modTime, nil)
+}
+
+// AddObjectEntry adds an entry to that directory with the object it
+// is for, which may be nil. The object is used to read the hashes of
+// the file for JSON listings.
+func (d *Directory) AddObjectEntry(remote string, isDir bool, size int64, modTime time.Time, o fs.Object) {
 	leaf := path.Base(remote)
 	if leaf == "." {
 		leaf = ""
@@ -97,6 +112,7 @@ func (d *Directory) AddHTMLEntry(remote string, isDir bool, size int64, modTime
 	}
 	d.Entries = append(d.Entries, DirEntry{
 		remote:  remote,
+		object:  o,
 		URL:     rest.URLPathEscape(urlRemote) + d.Query,
 		Leaf:    leaf,
 		IsDir:   isDir,
@@ -221,7 +237,122 @@ const (
 	sortByTime         = "time"
 )
 
+// WantsJSON returns true if the request asks for the listing as JSON
+// with a format=json query parameter or an Accept header.
+//
+// The Accept header must give application/json a q weight at least as
+// high as text/html. Wildcards such as */* count for neither.
+func WantsJSON(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "json" {
+		return true
+	}
+	var jsonWeight, htmlWeight float64
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		weight := 1.0
+		if q, ok := params["q"]; ok {
+			weight, err = strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+		}
+		switch mediaType {
+		case "application/json":
+			jsonWeight = weight
+		case "text/html":
+			htmlWeight = weight
+		}
+	}
+	return jsonWeight > 0 && jsonWeight >= htmlWeight
+}
+
+// jsonHashTypes returns the hash types asked for in the query of r
+// with hash=true for all the supported hashes or hash-type=TYPE.
+func jsonHashTypes(r *http.Request) (hashTypes []hash.Type, all bool, err error) {
+	query := r.URL.Query()
+	if showHash := query.Get("hash"); showHash != "" {
+		all, err = strconv.ParseBool(showHash)
+		if err != nil {
+			return nil, false, fmt.Errorf("bad hash parameter: %w", err)
+		}
+	}
+	for _, names := range query["hash-type"] {
+		for _, name := range strings.Split(names, ",") {
+			var hashType hash.Type
+			err = hashType.Set(name)
+			if err != nil {
+				return nil, false, err
+			}
+			hashTypes = append(hashTypes, hashType)
+		}
+	}
+	return hashTypes, all && len(hashTypes) == 0, nil
+}
+
+// serveJSON serves the directory as a JSON list of the entries in
+// the same format as lsjson, with the paths relative to the directory
+func (d *Directory) serveJSON(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	hashTypes, allHashes, err := jsonHashTypes(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	prefix := strings.Trim(d.DirRemote, "/") + "/"
+	items := make([]operations.ListJSONItem, 0, len(d.Entries))
+	for _, entry := range d.Entries {
+		item := operations.ListJSONItem{
+			Path:    strings.TrimPrefix(strings.TrimLeft(entry.remote, "/"), prefix),
+			Name:    path.Base(entry.remote),
+			Size:    entry.Size,
+			ModTime: operations.Timestamp{When: entry.ModTime, Format: time.RFC3339Nano},
+			IsDir:   entry.IsDir,
+		}
+		switch {
+		case entry.IsDir:
+			item.MimeType = "inode/directory"
+		case entry.object != nil:
+			item.MimeType = fs.MimeType(ctx, entry.object)
+		default:
+			item.MimeType = fs.MimeTypeFromName(entry.remote)
+		}
+		if o := entry.object; o != nil && (allHashes || len(hashTypes) > 0) {
+			types := hashTypes
+			if allHashes {
+				types = o.Fs().Hashes().Array()
+			}
+			item.Hashes = make(map[string]string)
+			for _, hashType := range types {
+				sum, err := o.Hash(ctx, hashType)
+				if err != nil {
+					fs.Errorf(o, "Failed to read hash: %v", err)
+				} else if sum != "" {
+					item.Hashes[hashType.String()] = sum
+				}
+			}
+		}
+		items = append(items, item)
+	}
+	buf := &bytes.Buffer{}
+	err = json.NewEncoder(buf).Encode(items)
+	if err != nil {
+		Error(d.DirRemote, w, "Failed to encode JSON", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, err = buf.WriteTo(w)
+	if err != nil {
+		Error(d.DirRemote, nil, "Failed to drain JSON buffer", err)
+	}
+}
+
 // Serve serves a directory
+//
+// The listing is rendered with the HTML template unless the request
+// asks for JSON, see WantsJSON.
 func (d *Directory) Serve(w http.ResponseWriter, r *http.Request) {
 	// Account the transfer
 	tr := accounting.Stats(r.Context()).NewTransferRemoteSize(d.DirRemote, -1)
@@ -229,6 +360,11 @@ func (d *Directory) Serve(w http.ResponseWriter, r *http.Request) {
 
 	fs.Infof(d.DirRemote, "%s: Serving directory", r.RemoteAddr)
 
+	if WantsJSON(r) {
+		d.serveJSON(w, r)
+		return
+	}
+
 	buf := &bytes.Buffer{}
 	err := d.HTMLTemplate.Execute(buf, d)
 	if err != nil {
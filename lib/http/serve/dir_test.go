@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/rclone/rclone/cmd/serve/http/data"
+	"github.com/rclone/rclone/fstest/mockobject"
 )
 
 func GetTemplate(t *testing.T) *template.Template {
@@ -123,3 +124,61 @@ func TestServe(t *testing.T) {
 </html>
 `, string(body))
 }
+
+func TestWantsJSON(t *testing.T) {
+	for _, test := range []struct {
+		url    string
+		accept string
+		want   bool
+	}{
+		{"/dir/", "", false},
+		{"/dir/", "text/html,application/xhtml+xml,*/*;q=0.8", false},
+		{"/dir/", "application/json", true},
+		{"/dir/", "text/plain, application/json; q=0.5", true},
+		{"/dir/", "text/html, application/json;q=0.1", false},
+		{"/dir/", "text/html;q=0.5, application/json", true},
+		{"/dir/", "application/json;q=0", false},
+		{"/dir/", "application/json, text/html", true},
+		{"/dir/?format=json", "", true},
+		{"/dir/?format=html", "", false},
+	} {
+		r := httptest.NewRequest("GET", "http://example.com"+test.url, nil)
+		if test.accept != "" {
+			r.Header.Set("Accept", test.accept)
+		}
+		assert.Equal(t, test.want, WantsJSON(r), test)
+	}
+}
+
+func TestServeJSON(t *testing.T) {
+	modTime := time.Date(2001, 2, 3, 4, 5, 6, 7, time.UTC)
+	d := NewDirectory("aDirectory", GetTemplate(t))
+	o := mockobject.New("aDirectory/file.txt").WithContent([]byte("hello"), mockobject.SeekModeNone)
+	d.AddObjectEntry("aDirectory/file.txt", false, 5, modTime, o)
+	d.AddHTMLEntry("aDirectory/dir", true, -1, modTime)
+
+	serveJSON := func(url string) (int, string) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", url, nil)
+		r.Header.Set("Accept", "application/json")
+		d.Serve(w, r)
+		resp := w.Result()
+		body, _ := ioutil.ReadAll(resp.Body)
+		if resp.StatusCode == http.StatusOK {
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		}
+		return resp.StatusCode, string(body)
+	}
+
+	status, body := serveJSON("http://example.com/aDirectory/")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `[{"Path":"file.txt","Name":"file.txt","Size":5,"MimeType":"text/plain; charset=utf-8","ModTime":"2001-02-03T04:05:06.000000007Z","IsDir":false},{"Path":"dir","Name":"dir","Size":-1,"MimeType":"inode/directory","ModTime":"2001-02-03T04:05:06.000000007Z","IsDir":true}]
+`, body)
+
+	status, body = serveJSON("http://example.com/aDirectory/?hash-type=MD5")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `"Hashes":{"md5":"5d41402abc4b2a76b9719d911017c592"}`)
+
+	status, _ = serveJSON("http://example.com/aDirectory/?hash-type=potato")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
@@ -0,0 +1,99 @@
+package share
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+)
+
+// realm is the authentication realm of links with passwords
+const realm = "rclone share"
+
+// Server checks the share links in the requests to a server
+type Server struct {
+	secret    string
+	mu        sync.Mutex
+	downloads map[string]*downloads // downloads of the links with limits by ID
+}
+
+// downloads counts the downloads of a link
+type downloads struct {
+	n       int   // number of downloads
+	expires int64 // when the link expires or 0
+}
+
+// NewServer makes a Server checking links encrypted with secret
+func NewServer(secret string) *Server {
+	return &Server{
+		secret:    secret,
+		downloads: make(map[string]*downloads),
+	}
+}
+
+// Check checks the share link of r, where urlPath is the path of the
+// request after Prefix.
+//
+// It returns the link and the path in the link's Fs which was asked
+// for. If it returns false then the link isn't valid and the error
+// response has already been sent.
+func (s *Server) Check(w http.ResponseWriter, r *http.Request, urlPath string) (l *Link, remote string, ok bool) {
+	token, name := urlPath, ""
+	if i := strings.IndexByte(urlPath, '/'); i >= 0 {
+		token, name = urlPath[:i], urlPath[i+1:]
+	}
+	l, err := Verify(s.secret, token, time.Now())
+	if err == ErrExpired {
+		http.Error(w, "Share link has expired", http.StatusGone)
+		return nil, "", false
+	} else if err != nil {
+		fs.Infof(urlPath, "%s: Invalid share link", r.RemoteAddr)
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return nil, "", false
+	}
+	if l.Password != "" {
+		_, pass, _ := r.BasicAuth()
+		if !l.CheckPassword(s.secret, pass) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="`+realm+`"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return nil, "", false
+		}
+	}
+	remote, ok = l.Lookup(name)
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return nil, "", false
+	}
+	return l, remote, true
+}
+
+// Download counts a download using l.
+//
+// If it returns false then the link has been used up and the error
+// response has already been sent.
+func (s *Server) Download(w http.ResponseWriter, l *Link) bool {
+	if l.MaxDownloads <= 0 {
+		return true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().Unix()
+	for id, d := range s.downloads {
+		if d.expires != 0 && d.expires <= now {
+			delete(s.downloads, id)
+		}
+	}
+	d := s.downloads[l.ID]
+	if d == nil {
+		d = &downloads{expires: l.Expires}
+		s.downloads[l.ID] = d
+	}
+	if d.n >= l.MaxDownloads {
+		http.Error(w, "Share link download limit reached", http.StatusGone)
+		return false
+	}
+	d.n++
+	return true
+}
@@ -0,0 +1,320 @@
+// Package share makes and checks share links - encrypted, expiring
+// URLs for files and directories which rclone serves itself.
+//
+// A share link carries everything needed to check it so the server
+// doesn't need to store anything apart from the secret used to
+// encrypt it.
+package share
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/flags"
+	"github.com/rclone/rclone/fs/fspath"
+	"github.com/rclone/rclone/lib/random"
+	"github.com/rclone/rclone/lib/rest"
+	"github.com/spf13/pflag"
+)
+
+// Prefix is the URL path of the share links from the root of the
+// server
+const Prefix = "/.share/"
+
+// Errors returned by Verify
+var (
+	ErrInvalid = errors.New("invalid share link")
+	ErrExpired = errors.New("share link has expired")
+)
+
+// Help contains text describing share links to add to the command
+// help.
+var Help = `
+#### Share links
+
+If ` + "`--share-secret`" + ` is set then the server will serve share links.
+These are URLs of the form ` + "`/.share/TOKEN/name`" + ` which can be used to
+download a file, or browse and download a directory, without the
+server's authentication.
+
+The token is encrypted with the secret and holds the path, when the
+link expires, and optionally a limit on the number of downloads and a
+password. The path can't be read from the link without the secret.
+The server checks it without storing anything so links can be made by
+any rclone which knows the secret. Links can't be revoked
+one by one - change the secret to revoke all of them.
+
+Use ` + "`rclone link`" + ` with ` + "`--share-url`" + ` set to the URL of the
+server and ` + "`--share-secret`" + ` to make share links for remotes without
+public links of their own.
+
+If a link has a password then it must be supplied as the password of
+HTTP basic authentication with any user name. The number of downloads
+of a link is only counted by the running server so it is reset when it
+restarts.
+`
+
+// Options contains options for making and serving share links
+type Options struct {
+	URL          string // URL of the server serving the share links
+	Secret       string // secret to sign the share links with
+	MaxDownloads int    // maximum number of downloads of each link or 0 for unlimited
+	Password     string // password needed to use the links if set
+}
+
+// Opt is options set by command line flags
+var Opt Options
+
+// AddFlagsPrefix adds flags for share links
+func AddFlagsPrefix(flagSet *pflag.FlagSet, prefix string, Opt *Options) {
+	flags.StringVarP(flagSet, &Opt.URL, prefix+"share-url", "", Opt.URL, "URL of the server serving share links")
+	flags.StringVarP(flagSet, &Opt.Secret, prefix+"share-secret", "", Opt.Secret, "Secret to encrypt share links with - if not set share links are disabled")
+	flags.IntVarP(flagSet, &Opt.MaxDownloads, prefix+"share-max-downloads", "", Opt.MaxDownloads, "Maximum number of downloads of each share link (0 for unlimited)")
+	flags.StringVarP(flagSet, &Opt.Password, prefix+"share-password", "", Opt.Password, "Password needed to use share links")
+}
+
+// AddFlags adds flags for share links
+func AddFlags(flagSet *pflag.FlagSet) {
+	AddFlagsPrefix(flagSet, "", &Opt)
+}
+
+// Enabled returns true if share links can be made and served
+func (opt *Options) Enabled() bool {
+	return opt.Secret != ""
+}
+
+// CanMakeLinks returns true if share links can be made, which needs
+// the URL of the server as well as the secret
+func (opt *Options) CanMakeLinks() bool {
+	return opt.Enabled() && opt.URL != ""
+}
+
+// Make share links with the options set by the command line flags
+// unless there are options in the context
+func init() {
+	fs.ShareLinks = &Opt
+}
+
+// Link is the contents of a share link
+type Link struct {
+	ID           string `json:"i"`           // random ID of the link
+	Fs           string `json:"f"`           // config string of the remote the link is in
+	Path         string `json:"p,omitempty"` // path of the file or directory in Fs
+	Dir          bool   `json:"d,omitempty"` // set if Path is a directory
+	Expires      int64  `json:"e,omitempty"` // unix time the link expires or 0 if it doesn't
+	MaxDownloads int    `json:"n,omitempty"` // maximum number of downloads or 0 for unlimited
+	Password     string `json:"w,omitempty"` // signature of the password if one is needed
+}
+
+// sign returns the signature of data with secret
+func sign(secret string, data ...string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	for _, s := range data {
+		_, _ = mac.Write([]byte(s))
+		_, _ = mac.Write([]byte{0})
+	}
+	return mac.Sum(nil)
+}
+
+// passwordSignature returns the signature of password stored in the
+// link. It is signed with the secret so it can't be checked without
+// it.
+func passwordSignature(secret, password string) string {
+	return hex.EncodeToString(sign(secret, "password", password))
+}
+
+// SetPassword sets the password needed to use the link
+func (l *Link) SetPassword(secret, password string) {
+	l.Password = ""
+	if password != "" {
+		l.Password = passwordSignature(secret, password)
+	}
+}
+
+// CheckPassword returns true if password is right for the link
+func (l *Link) CheckPassword(secret, password string) bool {
+	if l.Password == "" {
+		return true
+	}
+	return hmac.Equal([]byte(l.Password), []byte(passwordSignature(secret, password)))
+}
+
+// newAEAD returns the cipher used to encrypt and authenticate links
+// with a key made from secret
+func newAEAD(secret string) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(sign(secret, "link key"))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Sign returns the token for the link encrypted with secret
+//
+// Nothing in the link can be read or changed without the secret.
+func (l *Link) Sign(secret string) (string, error) {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode share link: %w", err)
+	}
+	aead, err := newAEAD(secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to make share link cipher: %w", err)
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(data)+aead.Overhead())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to make share link nonce: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, data, nil)), nil
+}
+
+// Verify checks token was encrypted with secret and hasn't expired at
+// now and returns the link it holds.
+func Verify(secret, token string, now time.Time) (*Link, error) {
+	if secret == "" {
+		return nil, ErrInvalid
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+	aead, err := newAEAD(secret)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, ErrInvalid
+	}
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	data, err := aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+	l := new(Link)
+	if err = json.Unmarshal(data, l); err != nil {
+		return nil, ErrInvalid
+	}
+	if l.Expires != 0 && !now.Before(time.Unix(l.Expires, 0)) {
+		return nil, ErrExpired
+	}
+	return l, nil
+}
+
+// leaf returns the name of the file or directory the link is for
+func (l *Link) leaf() string {
+	leaf := path.Base(l.Path)
+	if l.Path == "" || leaf == "/" || leaf == "." {
+		return ""
+	}
+	return leaf
+}
+
+// Lookup returns the path in Fs of the file or directory at name in
+// the link, which is the part of the URL after the token.
+//
+// For a directory this is relative to the directory. For a file it
+// may be empty or the leaf name of the file. It returns false if
+// name isn't in the link.
+func (l *Link) Lookup(name string) (remote string, ok bool) {
+	name = strings.Trim(path.Clean("/"+name), "/")
+	if !l.Dir {
+		if name != "" && name != l.leaf() {
+			return "", false
+		}
+		return l.Path, true
+	}
+	return strings.Trim(path.Join(l.Path, name), "/"), true
+}
+
+// Rel returns remote, which is a path in Fs, relative to root which
+// is the config string of the Fs doing the serving. It returns false
+// if remote isn't in root.
+func (l *Link) Rel(root, remote string) (string, bool) {
+	full := fspath.JoinRootPath(l.Fs, remote)
+	if full == root {
+		return "", true
+	}
+	prefix := root
+	if !strings.HasSuffix(prefix, "/") && !strings.HasSuffix(prefix, ":") {
+		prefix += "/"
+	}
+	if !strings.HasPrefix(full, prefix) {
+		return "", false
+	}
+	return strings.Trim(full[len(prefix):], "/"), true
+}
+
+// URL returns the share link for token at baseURL, the URL of the
+// root of the server.
+func (l *Link) URL(baseURL, token string) string {
+	u := strings.TrimRight(baseURL, "/") + Prefix + url.PathEscape(token) + "/"
+	if !l.Dir {
+		u += rest.URLPathEscape(l.leaf())
+	}
+	return u
+}
+
+// New makes a link for the file or directory at remote in f
+//
+// The link expires after expire unless it is fs.DurationOff.
+func (opt *Options) New(ctx context.Context, f fs.Fs, remote string, expire fs.Duration) (*Link, error) {
+	remote = strings.Trim(remote, "/")
+	isDir := remote == ""
+	if !isDir {
+		_, err := f.NewObject(ctx, remote)
+		if err == fs.ErrorObjectNotFound || err == fs.ErrorIsDir || err == fs.ErrorNotAFile {
+			_, err = f.List(ctx, remote)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find %q: %w", remote, err)
+			}
+			isDir = true
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to find %q: %w", remote, err)
+		}
+	}
+	l := &Link{
+		ID:           random.String(12),
+		Fs:           fs.ConfigString(f),
+		Path:         remote,
+		Dir:          isDir,
+		MaxDownloads: opt.MaxDownloads,
+	}
+	if expire != fs.DurationOff {
+		l.Expires = time.Now().Add(time.Duration(expire)).Unix()
+	}
+	l.SetPassword(opt.Secret, opt.Password)
+	return l, nil
+}
+
+// MakeLink makes a share link for the file or directory at remote in
+// f served by the server at opt.URL.
+//
+// The link expires after expire unless it is fs.DurationOff.
+func (opt *Options) MakeLink(ctx context.Context, f fs.Fs, remote string, expire fs.Duration) (string, error) {
+	if !opt.CanMakeLinks() {
+		return "", errors.New("need --share-url and --share-secret to make share links")
+	}
+	l, err := opt.New(ctx, f, remote, expire)
+	if err != nil {
+		return "", err
+	}
+	token, err := l.Sign(opt.Secret)
+	if err != nil {
+		return "", err
+	}
+	return l.URL(opt.URL, token), nil
+}
+
+// Check interface
+var _ fs.ShareLinker = (*Options)(nil)
@@ -0,0 +1,174 @@
+package share
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerify(t *testing.T) {
+	now := time.Now()
+	l := &Link{ID: "id", Fs: "remote:dir", Path: "file.txt", Expires: now.Add(time.Hour).Unix(), MaxDownloads: 3}
+	token, err := l.Sign("secret")
+	require.NoError(t, err)
+
+	got, err := Verify("secret", token, now)
+	require.NoError(t, err)
+	assert.Equal(t, l, got)
+
+	// The contents of the link can't be read from the token
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "remote:dir")
+	assert.NotContains(t, string(data), "file.txt")
+	again, err := l.Sign("secret")
+	require.NoError(t, err)
+	assert.NotEqual(t, token, again)
+
+	// The wrong secret or a changed token are rejected
+	_, err = Verify("other", token, now)
+	assert.Equal(t, ErrInvalid, err)
+	_, err = Verify("", token, now)
+	assert.Equal(t, ErrInvalid, err)
+	data[len(data)/2] ^= 1
+	_, err = Verify("secret", base64.RawURLEncoding.EncodeToString(data), now)
+	assert.Equal(t, ErrInvalid, err)
+	_, err = Verify("secret", token[:len(token)-4], now)
+	assert.Equal(t, ErrInvalid, err)
+	_, err = Verify("secret", "rubbish", now)
+	assert.Equal(t, ErrInvalid, err)
+	_, err = Verify("secret", "", now)
+	assert.Equal(t, ErrInvalid, err)
+
+	// Expired links are rejected
+	_, err = Verify("secret", token, now.Add(2*time.Hour))
+	assert.Equal(t, ErrExpired, err)
+}
+
+func TestPassword(t *testing.T) {
+	l := &Link{}
+	assert.True(t, l.CheckPassword("secret", ""))
+	l.SetPassword("secret", "potato")
+	assert.NotContains(t, l.Password, "potato")
+	assert.True(t, l.CheckPassword("secret", "potato"))
+	assert.False(t, l.CheckPassword("secret", "carrot"))
+	assert.False(t, l.CheckPassword("other", "potato"))
+}
+
+func TestShareLinker(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, &Opt, fs.GetShareLinker(ctx))
+	opt := &Options{Secret: "secret"}
+	assert.False(t, opt.CanMakeLinks())
+	opt.URL = "http://host/"
+	assert.True(t, opt.CanMakeLinks())
+	ctx = fs.WithShareLinker(ctx, opt)
+	assert.Equal(t, opt, fs.GetShareLinker(ctx))
+}
+
+func TestLookup(t *testing.T) {
+	file := &Link{Path: "dir/file.txt"}
+	dir := &Link{Path: "dir", Dir: true}
+	root := &Link{Dir: true}
+	for _, test := range []struct {
+		l      *Link
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{file, "", "dir/file.txt", true},
+		{file, "file.txt", "dir/file.txt", true},
+		{file, "other.txt", "", false},
+		{dir, "", "dir", true},
+		{dir, "sub/file.txt", "dir/sub/file.txt", true},
+		{dir, "../secret.txt", "dir/secret.txt", true},
+		{root, "sub/", "sub", true},
+	} {
+		got, ok := test.l.Lookup(test.name)
+		assert.Equal(t, test.wantOK, ok, test.name)
+		assert.Equal(t, test.want, got, test.name)
+	}
+}
+
+func TestRel(t *testing.T) {
+	for _, test := range []struct {
+		fs     string
+		root   string
+		remote string
+		want   string
+		wantOK bool
+	}{
+		{"remote:dir", "remote:", "file.txt", "dir/file.txt", true},
+		{"remote:dir", "remote:dir", "file.txt", "file.txt", true},
+		{"remote:dir", "remote:dir", "", "", true},
+		{"remote:dir", "remote:di", "file.txt", "", false},
+		{"remote:dir", "other:", "file.txt", "", false},
+		{"/tmp/files", "/tmp", "a/b.txt", "files/a/b.txt", true},
+	} {
+		got, ok := (&Link{Fs: test.fs}).Rel(test.root, test.remote)
+		assert.Equal(t, test.wantOK, ok, test)
+		assert.Equal(t, test.want, got, test)
+	}
+}
+
+func TestURL(t *testing.T) {
+	assert.Equal(t, "http://host/base/.share/token/file%20name.txt", (&Link{Path: "dir/file name.txt"}).URL("http://host/base/", "token"))
+	assert.Equal(t, "http://host/.share/token/", (&Link{Path: "dir", Dir: true}).URL("http://host", "token"))
+}
+
+func TestServer(t *testing.T) {
+	s := NewServer("secret")
+	check := func(l *Link, urlPath, pass string) (int, string) {
+		token, err := l.Sign("secret")
+		require.NoError(t, err)
+		r := httptest.NewRequest("GET", "http://host"+Prefix+token+urlPath, nil)
+		if pass != "" {
+			r.SetBasicAuth("user", pass)
+		}
+		w := httptest.NewRecorder()
+		_, remote, ok := s.Check(w, r, token+urlPath)
+		if !ok {
+			return w.Code, ""
+		}
+		return http.StatusOK, remote
+	}
+
+	l := &Link{ID: "1", Path: "dir", Dir: true}
+	status, remote := check(l, "/sub/file.txt", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "dir/sub/file.txt", remote)
+
+	l = &Link{ID: "2", Path: "file.txt"}
+	status, _ = check(l, "/other.txt", "")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	l = &Link{ID: "3", Path: "file.txt", Expires: time.Now().Add(-time.Minute).Unix()}
+	status, _ = check(l, "/file.txt", "")
+	assert.Equal(t, http.StatusGone, status)
+
+	l = &Link{ID: "4", Path: "file.txt"}
+	l.SetPassword("secret", "potato")
+	status, _ = check(l, "/file.txt", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, _ = check(l, "/file.txt", "carrot")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, _ = check(l, "/file.txt", "potato")
+	assert.Equal(t, http.StatusOK, status)
+
+	// The downloads of each link are counted
+	l = &Link{ID: "5", Path: "file.txt", MaxDownloads: 2}
+	for i := 0; i < 2; i++ {
+		assert.True(t, s.Download(httptest.NewRecorder(), l))
+	}
+	w := httptest.NewRecorder()
+	assert.False(t, s.Download(w, l))
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.True(t, s.Download(httptest.NewRecorder(), &Link{ID: "6", MaxDownloads: 1}))
+}
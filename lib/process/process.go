@@ -0,0 +1,2 @@
+// Package process provides utilities for dealing with OS processes
+package process
@@ -0,0 +1,15 @@
+//go:build !windows && !plan9 && !js
+// +build !windows,!plan9,!js
+
+package process
+
+import (
+	"syscall"
+)
+
+// Exists returns false if the process with the given pid is
+// known not to be running on this host.
+func Exists(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err != syscall.ESRCH
+}
@@ -0,0 +1,19 @@
+//go:build windows
+// +build windows
+
+package process
+
+import (
+	"os"
+)
+
+// Exists returns false if the process with the given pid is
+// known not to be running on this host.
+func Exists(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = p.Release()
+	return true
+}
@@ -296,6 +296,65 @@ func TestRangeInsertRandom(t *testing.T) {
 	}
 }
 
+func TestRangeRemove(t *testing.T) {
+	for _, test := range []struct {
+		rs   Ranges
+		r    Range
+		want Ranges
+	}{
+		{
+			rs:   Ranges(nil),
+			r:    Range{Pos: 1, Size: 1},
+			want: Ranges(nil),
+		},
+		{
+			rs:   Ranges{{Pos: 1, Size: 5}},
+			r:    Range{Pos: 1, Size: 0},
+			want: Ranges{{Pos: 1, Size: 5}},
+		},
+		{
+			rs:   Ranges{{Pos: 1, Size: 5}},
+			r:    Range{Pos: 0, Size: 10},
+			want: Ranges(nil),
+		},
+		{
+			rs:   Ranges{{Pos: 1, Size: 5}},
+			r:    Range{Pos: 6, Size: 10},
+			want: Ranges{{Pos: 1, Size: 5}},
+		},
+		{
+			rs: Ranges{{Pos: 1, Size: 5}},
+			r:  Range{Pos: 2, Size: 2},
+			want: Ranges{
+				{Pos: 1, Size: 1},
+				{Pos: 4, Size: 2},
+			},
+		},
+		{
+			rs: Ranges{
+				{Pos: 1, Size: 2},
+				{Pos: 11, Size: 2},
+				{Pos: 21, Size: 2},
+				{Pos: 31, Size: 2},
+				{Pos: 41, Size: 2},
+			},
+			r: Range{Pos: 12, Size: 20},
+			want: Ranges{
+				{Pos: 1, Size: 2},
+				{Pos: 11, Size: 1},
+				{Pos: 32, Size: 1},
+				{Pos: 41, Size: 2},
+			},
+		},
+	} {
+		got := append(Ranges(nil), test.rs...)
+		got.Remove(test.r)
+		what := fmt.Sprintf("test rs=%v, r=%v", test.rs, test.r)
+		assert.Equal(t, test.want, got, what)
+		checkRanges(t, got, what)
+	}
+}
+
 func TestRangeFind(t *testing.T) {
 	for _, test := range []struct {
 		rs          Ranges
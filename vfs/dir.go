@@ -372,6 +372,9 @@ func (d *Dir) rename(newParent *Dir, fsDir fs.Directory) {
 func (d *Dir) addObject(node Node) {
 	d.mu.Lock()
 	leaf := node.Name()
+	if f, ok := node.(*File); ok {
+		leaf = f.rawName()
+	}
 	d.items[leaf] = node
 	if d.virtual == nil {
 		d.virtual = make(map[string]vState)
@@ -450,11 +453,20 @@ func (d *Dir) _readDir() error {
 	} else {
 		return nil
 	}
+	hasListing := len(d.items) != 0 || !d.read.IsZero()
+	if hasListing && d.vfs.offline.IsOffline() {
+		// Use the old listing until the remote is back
+		return nil
+	}
 	entries, err := list.DirSorted(context.TODO(), d.f, false, d.path)
 	if err == fs.ErrorDirNotFound {
 		// We treat directory not found as empty because we
 		// create directories on the fly
 	} else if err != nil {
+		if d.vfs.offline.Check(err) && hasListing {
+			fs.Debugf(d.path, "Remote is offline so using the old directory listing")
+			return nil
+		}
 		return err
 	}
 
@@ -705,6 +717,14 @@ func (d *Dir) stat(leaf string) (Node, error) {
 	}
 	item, ok := d.items[leaf]
 
+	// look for a symlink with this name
+	if !ok && d.vfs.Opt.Links {
+		item, ok = d.items[leaf+vfscommon.LinkSuffix]
+		if ok && !item.IsFile() {
+			item, ok = nil, false
+		}
+	}
+
 	if !ok && d.vfs.Opt.CaseInsensitive {
 		leafLower := strings.ToLower(leaf)
 		for name, node := range d.items {
@@ -866,12 +886,58 @@ func (d *Dir) Create(name string, flags int) (*File, error) {
 	return newFile(d, d.Path(), nil, name), nil
 }
 
+// Symlink makes a new symlink called name pointing to target
+//
+// The symlink is stored on the remote as a file called name with
+// vfscommon.LinkSuffix on the end containing the target. This needs
+// --vfs-links to be set.
+func (d *Dir) Symlink(target, name string) (*File, error) {
+	if !d.vfs.Opt.Links {
+		return nil, ENOSYS
+	}
+	if d.vfs.Opt.ReadOnly {
+		return nil, EROFS
+	}
+	_, err := d.stat(name)
+	switch err {
+	case ENOENT:
+		// not found, carry on
+	case nil:
+		return nil, EEXIST
+	default:
+		fs.Errorf(d, "Dir.Symlink stat failed: %v", err)
+		return nil, err
+	}
+	file, err := d.Create(name+vfscommon.LinkSuffix, os.O_WRONLY|os.O_CREATE|os.O_EXCL)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := file.Open(os.O_WRONLY | os.O_CREATE | os.O_TRUNC)
+	if err != nil {
+		fs.Errorf(d, "Dir.Symlink failed to open %q: %v", name, err)
+		return nil, err
+	}
+	_, err = fd.WriteString(target)
+	closeErr := fd.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fs.Errorf(d, "Dir.Symlink failed to write %q: %v", name, err)
+		return nil, err
+	}
+	return file, nil
+}
+
 // Mkdir creates a new directory
 func (d *Dir) Mkdir(name string) (*Dir, error) {
 	if d.vfs.Opt.ReadOnly {
 		return nil, EROFS
 	}
 	path := path.Join(d.path, name)
+	if err := d.vfs.locks.checkWrite(path, false); err != nil {
+		return nil, err
+	}
 	node, err := d.stat(name)
 	switch err {
 	case ENOENT:
@@ -905,6 +971,9 @@ func (d *Dir) Remove() error {
 	if d.vfs.Opt.ReadOnly {
 		return EROFS
 	}
+	if err := d.vfs.locks.checkWrite(d.path, false); err != nil {
+		return err
+	}
 	// Check directory is empty first
 	empty, err := d.isEmpty()
 	if err != nil {
@@ -933,6 +1002,9 @@ func (d *Dir) RemoveAll() error {
 	if d.vfs.Opt.ReadOnly {
 		return EROFS
 	}
+	if err := d.vfs.locks.checkWrite(d.path, true); err != nil {
+		return err
+	}
 	// Remove contents of the directory
 	nodes, err := d.ReadDirAll()
 	if err != nil {
@@ -984,6 +1056,18 @@ func (d *Dir) Rename(oldName, newName string, destDir *Dir) error {
 		fs.Errorf(oldPath, "Dir.Rename error: %v", err)
 		return err
 	}
+	if err = d.vfs.locks.checkWrite(oldPath, oldNode.IsDir()); err != nil {
+		return err
+	}
+	if err = d.vfs.locks.checkWrite(newPath, false); err != nil {
+		return err
+	}
+	// symlinks keep their suffix on the remote
+	if oldFile, ok := oldNode.(*File); ok && oldFile.IsSymlink() {
+		oldName = oldFile.rawName()
+		newName += vfscommon.LinkSuffix
+		newPath += vfscommon.LinkSuffix
+	}
 	switch x := oldNode.DirEntry().(type) {
 	case nil:
 		if oldFile, ok := oldNode.(*File); ok {
@@ -2,15 +2,19 @@ package vfs
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"os"
 	"sort"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/operations"
 	"github.com/rclone/rclone/fstest"
+	"github.com/rclone/rclone/vfs/vfscommon"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -333,6 +337,83 @@ func TestDirReadDirAll(t *testing.T) {
 	})
 }
 
+// unreachableFs is an Fs which can be made to fail as if the remote
+// couldn't be reached
+type unreachableFs struct {
+	fs.Fs
+	mu  sync.Mutex
+	err error
+}
+
+func (f *unreachableFs) setErr(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func (f *unreachableFs) getErr() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.err
+}
+
+func (f *unreachableFs) List(ctx context.Context, dir string) (fs.DirEntries, error) {
+	if err := f.getErr(); err != nil {
+		return nil, err
+	}
+	return f.Fs.List(ctx, dir)
+}
+
+func (f *unreachableFs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	if err := f.getErr(); err != nil {
+		return nil, err
+	}
+	return f.Fs.NewObject(ctx, remote)
+}
+
+func TestDirReadDirAllOffline(t *testing.T) {
+	oldOfflineProbeInterval := vfscommon.OfflineProbeInterval
+	vfscommon.OfflineProbeInterval = 10 * time.Millisecond
+	defer func() { vfscommon.OfflineProbeInterval = oldOfflineProbeInterval }()
+
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	ctx := context.Background()
+	f := &unreachableFs{Fs: r.Fremote}
+	opt := vfscommon.DefaultOpt
+	opt.Offline = true
+	opt.DirCacheTime = time.Millisecond
+	vfs := New(f, &opt)
+	defer cleanupVFS(t, vfs)
+
+	file1 := r.WriteObject(ctx, "dir/file1", "file1 contents", t1)
+	r.CheckRemoteItems(t, file1)
+
+	node, err := vfs.Stat("dir")
+	require.NoError(t, err)
+	dir := node.(*Dir)
+	checkListing(t, dir, []string{"file1,14,false"})
+	assert.Equal(t, false, vfs.Stats()["offline"])
+
+	// Make the remote unreachable and change it
+	f.setErr(&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connect: network is unreachable")})
+	r.WriteObject(ctx, "dir/file2", "file2- contents", t2)
+	time.Sleep(2 * opt.DirCacheTime)
+
+	// Check the old listing is served while offline
+	checkListing(t, dir, []string{"file1,14,false"})
+	assert.True(t, vfs.offline.IsOffline())
+	assert.Equal(t, true, vfs.Stats()["offline"])
+	checkListing(t, dir, []string{"file1,14,false"})
+
+	// Check the new listing is served when back online
+	f.setErr(nil)
+	assert.Eventually(t, func() bool {
+		return !vfs.offline.IsOffline()
+	}, 10*time.Second, 10*time.Millisecond)
+	checkListing(t, dir, []string{"file1,14,false", "file2,15,false"})
+}
+
 func TestDirOpen(t *testing.T) {
 	_, _, dir, _, cleanup := dirCreate(t)
 	defer cleanup()
@@ -517,6 +598,55 @@ func TestDirRemoveName(t *testing.T) {
 	assert.Equal(t, EROFS, err)
 }
 
+func TestDirSymlink(t *testing.T) {
+	r, vfs, dir, file1, cleanup := dirCreate(t)
+	defer cleanup()
+
+	_, err := dir.Symlink("file1", "link")
+	assert.Equal(t, ENOSYS, err)
+
+	vfs.Opt.Links = true
+	link, err := dir.Symlink("file1", "link")
+	require.NoError(t, err)
+	assert.True(t, link.IsSymlink())
+	assert.Equal(t, "link", link.Name())
+	checkListing(t, dir, []string{"file1,14,false", "link,5,false"})
+
+	node, err := dir.Stat("link")
+	require.NoError(t, err)
+	assert.Equal(t, link, node)
+	target, err := link.Readlink()
+	require.NoError(t, err)
+	assert.Equal(t, "file1", target)
+
+	// check the underlying r.Fremote
+	item := fstest.NewItem("dir/link"+vfscommon.LinkSuffix, "file1", link.ModTime())
+	fstest.CheckListingWithPrecision(t, r.Fremote, []fstest.Item{file1, item}, []string{"dir"}, r.Fremote.Precision())
+
+	_, err = dir.Symlink("file2", "link")
+	assert.Equal(t, EEXIST, err)
+	_, err = dir.Symlink("file2", "file1")
+	assert.Equal(t, EEXIST, err)
+
+	// Rename the link
+	err = dir.Rename("link", "link2", dir)
+	require.NoError(t, err)
+	checkListing(t, dir, []string{"file1,14,false", "link2,5,false"})
+	item.Path = "dir/link2" + vfscommon.LinkSuffix
+	fstest.CheckListingWithPrecision(t, r.Fremote, []fstest.Item{file1, item}, []string{"dir"}, r.Fremote.Precision())
+
+	// Remove the link
+	err = dir.RemoveName("link2")
+	require.NoError(t, err)
+	checkListing(t, dir, []string{"file1,14,false"})
+	fstest.CheckListingWithPrecision(t, r.Fremote, []fstest.Item{file1}, []string{"dir"}, r.Fremote.Precision())
+
+	// read only check
+	vfs.Opt.ReadOnly = true
+	_, err = dir.Symlink("file1", "link3")
+	assert.Equal(t, EROFS, err)
+}
+
 func TestDirRename(t *testing.T) {
 	r, vfs, dir, file1, cleanup := dirCreate(t)
 	defer cleanup()
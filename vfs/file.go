@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -47,6 +49,7 @@ type File struct {
 	writers          []Handle                        // writers for this file
 	nwriters         int32                           // len(writers) which is read/updated with atomic
 	pendingModTime   time.Time                       // will be applied once o becomes available, i.e. after file was written
+	pendingMetadata  fs.Metadata                     // will be applied once o becomes available, i.e. after file was written
 	pendingRenameFun func(ctx context.Context) error // will be run/renamed after all writers close
 	appendMode       bool                            // file was opened with O_APPEND
 	sys              atomic.Value                    // user defined info to be attached here
@@ -93,6 +96,9 @@ func (f *File) IsDir() bool {
 func (f *File) Mode() (mode os.FileMode) {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
+	if f._isSymlink() {
+		return os.ModeSymlink | 0777
+	}
 	mode = f.d.vfs.Opt.FilePerms
 	if f.appendMode {
 		mode |= os.ModeAppend
@@ -104,9 +110,60 @@ func (f *File) Mode() (mode os.FileMode) {
 func (f *File) Name() (name string) {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
+	if f._isSymlink() {
+		return strings.TrimSuffix(f.leaf, vfscommon.LinkSuffix)
+	}
 	return f.leaf
 }
 
+// rawName returns the leaf name of the object on the remote, which
+// includes the vfscommon.LinkSuffix for symlinks
+func (f *File) rawName() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.leaf
+}
+
+// _isSymlink returns true if the file is a symlink
+//
+// call with the lock held
+func (f *File) _isSymlink() bool {
+	return f.d.vfs.Opt.Links && strings.HasSuffix(f.leaf, vfscommon.LinkSuffix)
+}
+
+// IsSymlink returns true if the file is a symlink
+//
+// Symlinks are stored on the remote as files with
+// vfscommon.LinkSuffix on the end of their name containing the link
+// target. They are only translated if --vfs-links is set.
+func (f *File) IsSymlink() bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f._isSymlink()
+}
+
+// Readlink returns the target of the symlink
+//
+// It returns EINVAL if the file isn't a symlink.
+func (f *File) Readlink() (target string, err error) {
+	if !f.IsSymlink() {
+		return "", EINVAL
+	}
+	fd, err := f.Open(os.O_RDONLY)
+	if err != nil {
+		return "", err
+	}
+	buf, err := ioutil.ReadAll(fd)
+	closeErr := fd.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read symlink: %w", err)
+	}
+	return string(buf), nil
+}
+
 // _path returns the full path of the file
 // use when lock is held
 func (f *File) _path() string {
@@ -432,6 +489,78 @@ func (f *File) applyPendingModTime() error {
 	return f._applyPendingModTime()
 }
 
+// Metadata returns the metadata of the file
+//
+// This includes any metadata set which is waiting for the file to be
+// uploaded. It returns nil if there is no metadata.
+func (f *File) Metadata() (metadata fs.Metadata, err error) {
+	f.mu.RLock()
+	o := f.o
+	pending := f.pendingMetadata
+	f.mu.RUnlock()
+	if o != nil {
+		m, err := fs.GetMetadata(context.TODO(), o)
+		if err != nil {
+			return nil, err
+		}
+		metadata.Merge(m)
+	}
+	metadata.Merge(pending)
+	return metadata, nil
+}
+
+// SetMetadata sets the metadata of the file
+//
+// Only the keys in metadata are changed. If the file is being
+// written then the metadata will be set when it has been uploaded.
+//
+// It returns ENOSYS if the backend can't set metadata.
+func (f *File) SetMetadata(metadata fs.Metadata) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.d.vfs.Opt.ReadOnly {
+		return EROFS
+	}
+	if !f.d.f.Features().WriteMetadata {
+		return ENOSYS
+	}
+
+	f.pendingMetadata.Merge(metadata)
+
+	// Only update the metadata when there are no writers, setObject will do it
+	if !f._writingInProgress() {
+		return f._applyPendingMetadata()
+	}
+
+	// queue up for later, hoping f.o becomes available
+	return nil
+}
+
+// Apply pending metadata
+// Call with the mutex held
+func (f *File) _applyPendingMetadata() error {
+	if len(f.pendingMetadata) == 0 {
+		return nil
+	}
+	defer func() { f.pendingMetadata = nil }()
+
+	if f.o == nil {
+		return errors.New("cannot apply metadata, file object is not available")
+	}
+	do, ok := f.o.(fs.SetMetadataer)
+	if !ok {
+		fs.Debugf(f.o, "Can't set metadata on this object")
+		return ENOSYS
+	}
+	err := do.SetMetadata(context.TODO(), f.pendingMetadata)
+	if err != nil {
+		fs.Errorf(f.o, "Failed to apply pending metadata: %v", err)
+		return err
+	}
+	fs.Debugf(f.o, "Applied pending metadata OK")
+	return nil
+}
+
 // _writingInProgress returns true of there are any open writers
 // Call with read lock held
 func (f *File) _writingInProgress() bool {
@@ -455,6 +584,7 @@ func (f *File) setObject(o fs.Object) {
 	f.mu.Lock()
 	f.o = o
 	_ = f._applyPendingModTime()
+	_ = f._applyPendingMetadata()
 	d := f.d
 	f.mu.Unlock()
 
@@ -580,6 +710,9 @@ func (f *File) Remove() (err error) {
 	if d.vfs.Opt.ReadOnly {
 		return EROFS
 	}
+	if err = d.vfs.locks.checkWrite(f.Path(), false); err != nil {
+		return err
+	}
 
 	// Remove the object from the cache
 	wasWriting := false
@@ -589,7 +722,7 @@ func (f *File) Remove() (err error) {
 
 	// Remove the item from the directory listing
 	// called with File.mu released
-	d.delObject(f.Name())
+	d.delObject(f.rawName())
 
 	f.muRW.Lock() // muRW must be locked before mu to avoid
 	f.mu.Lock()   // deadlock in RWFileHandle.openPending and .close
@@ -702,6 +835,11 @@ func (f *File) Open(flags int) (fd Handle, err error) {
 	f.mu.RLock()
 	d := f.d
 	f.mu.RUnlock()
+	if write {
+		if err = d.vfs.locks.checkWrite(f.Path(), false); err != nil {
+			return nil, err
+		}
+	}
 	CacheMode := d.vfs.Opt.CacheMode
 	if CacheMode >= vfscommon.CacheModeMinimal && (d.vfs.cache.InUse(f.Path()) || d.vfs.cache.Exists(f.Path())) {
 		fd, err = f.openRW(flags)
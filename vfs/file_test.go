@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/operations"
@@ -419,3 +420,92 @@ func TestFileRename(t *testing.T) {
 		})
 	}
 }
+
+func TestFileSymlink(t *testing.T) {
+	opt := vfscommon.DefaultOpt
+	opt.Links = true
+	r, vfs, cleanup := newTestVFSOpt(t, &opt)
+	defer cleanup()
+
+	file1 := r.WriteObject(context.Background(), "dir/file1", "file1 contents", t1)
+	link := r.WriteObject(context.Background(), "dir/link"+vfscommon.LinkSuffix, "file1", t1)
+	r.CheckRemoteItems(t, file1, link)
+
+	node, err := vfs.Stat("dir/link")
+	require.NoError(t, err)
+	file := node.(*File)
+
+	assert.True(t, file.IsSymlink())
+	assert.Equal(t, "link", file.Name())
+	assert.Equal(t, "dir/link"+vfscommon.LinkSuffix, file.Path())
+	assert.Equal(t, os.ModeSymlink|0777, file.Mode())
+
+	target, err := file.Readlink()
+	require.NoError(t, err)
+	assert.Equal(t, "file1", target)
+
+	target, err = vfs.Readlink("dir/link")
+	require.NoError(t, err)
+	assert.Equal(t, "file1", target)
+
+	// The name on the remote can be used too
+	node, err = vfs.Stat("dir/link" + vfscommon.LinkSuffix)
+	require.NoError(t, err)
+	assert.Equal(t, file, node)
+
+	// Regular files aren't links
+	node, err = vfs.Stat("dir/file1")
+	require.NoError(t, err)
+	assert.False(t, node.(*File).IsSymlink())
+	assert.Equal(t, vfs.Opt.FilePerms, node.Mode())
+	_, err = vfs.Readlink("dir/file1")
+	assert.Equal(t, EINVAL, err)
+	_, err = vfs.Readlink("dir")
+	assert.Equal(t, EINVAL, err)
+
+	// Without --vfs-links the link is a regular file
+	vfs.Opt.Links = false
+	assert.False(t, file.IsSymlink())
+	assert.Equal(t, "link"+vfscommon.LinkSuffix, file.Name())
+	_, err = file.Readlink()
+	assert.Equal(t, EINVAL, err)
+}
+
+func TestFileMetadata(t *testing.T) {
+	r, vfs, file, _, cleanup := fileCreate(t, vfscommon.CacheModeOff)
+	defer cleanup()
+	ctx := context.Background()
+
+	features := r.Fremote.Features()
+	if !features.ReadMetadata || !features.WriteMetadata {
+		t.Skip("metadata not supported")
+	}
+
+	metadata, err := file.Metadata()
+	require.NoError(t, err)
+	assert.Equal(t, t1.Format(time.RFC3339Nano), metadata["mtime"])
+
+	err = file.SetMetadata(fs.Metadata{"mtime": t2.Format(time.RFC3339Nano)})
+	require.NoError(t, err)
+	metadata, err = file.Metadata()
+	require.NoError(t, err)
+	assert.Equal(t, t2.Format(time.RFC3339Nano), metadata["mtime"])
+	fstest.AssertTimeEqualWithPrecision(t, "mtime", t2, file.getObject().ModTime(ctx), r.Fremote.Precision())
+
+	// Metadata set while writing is applied after the upload
+	fd, err := file.Open(os.O_WRONLY | os.O_TRUNC)
+	require.NoError(t, err)
+	err = file.SetMetadata(fs.Metadata{"mtime": t3.Format(time.RFC3339Nano)})
+	require.NoError(t, err)
+	metadata, err = file.Metadata()
+	require.NoError(t, err)
+	assert.Equal(t, t3.Format(time.RFC3339Nano), metadata["mtime"])
+	_, err = fd.Write([]byte("hello"))
+	require.NoError(t, err)
+	require.NoError(t, fd.Close())
+	fstest.AssertTimeEqualWithPrecision(t, "mtime", t3, file.getObject().ModTime(ctx), r.Fremote.Precision())
+
+	vfs.Opt.ReadOnly = true
+	err = file.SetMetadata(fs.Metadata{"mtime": t1.Format(time.RFC3339Nano)})
+	assert.Equal(t, EROFS, err)
+}
@@ -54,6 +54,27 @@ Or individual files or directories:
 
     rclone rc vfs/forget file=path/to/file dir=path/to/dir
 
+#### Persisting the directory cache
+
+Normally the directory cache starts empty each time rclone is started,
+so the whole remote needs listing again before tools which scan it
+are fast. Using the !--vfs-persist-dir-cache! flag rclone saves the
+directory cache in the !vfsDir! directory under !--cache-dir!
+periodically and when it exits.
+
+    --vfs-persist-dir-cache     Save the directory cache in the cache directory for use after a restart
+
+When rclone starts again it serves the directories from the saved
+cache straight away while re-reading them from the remote in the
+background, so changes made while rclone wasn't running are picked up
+shortly after it starts. After that the usual !--dir-cache-time! and
+!--poll-interval! rules apply.
+
+The saved cache is dropped if the remote has been reconfigured or
+rclone's format for the saved cache changes. Files listed in the saved
+cache which have since been removed from the remote will give errors
+when opened until their directory has been re-read.
+
 ### VFS File Buffering
 
 The !--buffer-size! flag determines the amount of memory,
@@ -110,6 +131,16 @@ for two reasons.  Firstly because it is only checked every
 !--vfs-cache-poll-interval!.  Secondly because open files cannot be
 evicted from the cache.
 
+When the cache is over !--vfs-cache-max-size! or files are older than
+!--vfs-cache-max-age!, rclone evicts the least recently accessed
+16 MiB chunks of files which aren't in use rather than whole files,
+where the operating system supports punching holes in files (Linux
+and Windows). This means the parts of large files which are read
+often, such as the index of a media file, stay in the cache while the
+rest of the file is evicted. Files which have had all their chunks
+evicted are removed from the cache. On other systems whole files are
+evicted, least recently accessed first.
+
 You **should not** run two copies of rclone using the same VFS cache
 with the same or overlapping remotes if using !--vfs-cache-mode > off!.
 This can potentially cause data corruption if you do. You can work
@@ -216,6 +247,49 @@ Note that if you change the value of this flag, the fingerprints of
 the files in the cache may be invalidated and the files will need to
 be downloaded again.
 
+#### Offline mode
+
+If the !--vfs-offline! flag is set then rclone keeps working from the
+cache when the remote can't be reached, for example when the network
+is down.
+
+    --vfs-offline               Serve from the cache and queue uploads while the remote is unreachable
+    --vfs-conflict ConflictMode What to do if a file changed on the remote while it was cached - local|remote|both (default local)
+
+When an operation on the remote fails because it can't be reached
+(DNS failures, connection refused, no route to host and timeouts)
+rclone marks the remote as offline. Errors returned by the remote,
+such as HTTP 5xx responses, and connections dropped part way through
+a transfer don't count as the remote was reached. While it is
+offline:
+
+- directories are served from the directory cache, however old it is
+- files which are in the cache can be read and written as normal
+- uploads of modified files are queued and not retried
+
+Rclone checks every 15 seconds to see if the remote can be reached
+again. When it can, the queued uploads are started in the order the
+files were modified, and directories are re-read as usual. The upload
+queue is kept in the cache directory, so uploads queued while offline
+are done even if rclone is restarted.
+
+Queuing uploads needs !--vfs-cache-mode writes! or !full!. Operations
+which aren't cached - making, renaming and removing files and
+directories, and reading files which aren't in the cache - will still
+return errors while the remote is offline.
+
+When a file is uploaded from the cache rclone can check whether the
+file on the remote has been modified since it was cached, using the
+fingerprint described above. The !--vfs-conflict! flag controls what
+happens if it has:
+
+- !local! - upload the local changes, overwriting the remote (the default, which doesn't check)
+- !remote! - keep the remote file and discard the local changes
+- !both! - keep the remote file and upload the local changes as !name.conflict-YYYYMMDD-HHMMSS.ext!
+
+Whether the remote is offline is shown as !offline! in the output of
+the !vfs/stats! remote control command.
+
 ### VFS Chunked Reading
 
 When rclone reads files from a remote it reads them in chunks. This
@@ -307,6 +381,43 @@ If the flag is not provided on the command line, then its default value depends
 on the operating system where rclone runs: "true" on Windows and macOS, "false"
 otherwise. If the flag is provided without a value, then it is "true".
 
+### Symlinks
+
+By default the VFS does not support symlinks. If the !--vfs-links!
+flag is set then symlinks are translated to and from regular files on
+the remote with a !.rclonelink! extension containing the target of the
+link. This is the same format that the local backend uses with its
+!--links! flag, so a tree containing symlinks can be copied to a
+remote with !rclone copy -l! and mounted with !--vfs-links! and the
+symlinks will be shown as symlinks.
+
+    --vfs-links     Translate symlinks to/from regular files with a '.rclonelink' extension
+
+With this flag set, a file called !link.rclonelink! on the remote will
+be shown as a symlink called !link!, and creating a symlink called
+!link! will create !link.rclonelink! on the remote.
+
+Note that the VFS doesn't follow symlinks itself. When mounting, the
+operating system follows them. !rclone serve sftp! follows a symlink
+which is the last part of a path, but not symlinks to directories in
+the middle of a path (eg !dir-link/file!). The other !rclone serve!
+commands show symlinks as files containing the link target.
+
+### VFS File Locking
+
+When mounting, advisory file locks (!flock! and !fcntl!) are held in
+the VFS. These are normally private to the rclone process. With
+!--vfs-shared-locks! they are kept in a database in the cache
+directory so that other rclone processes using the same remote see
+them too.
+
+Shared locks also include the locks of WebDAV clients of !rclone serve
+webdav --lock-system cache!. Files and directories locked this way
+can't be changed by other rclone processes, which get the error
+"Resource temporarily unavailable".
+
+    --vfs-shared-locks    Share file locks with other rclone processes using the cache directory
+
 ### VFS Disk Options
 
 This flag allows you to manually set the statistics about the filing system.
@@ -0,0 +1,565 @@
+package vfs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/lib/kv"
+	"github.com/rclone/rclone/lib/process"
+	"github.com/rclone/rclone/lib/random"
+)
+
+// LockType is the type of an advisory lock
+type LockType byte
+
+// Types of lock
+const (
+	LockUnlock LockType = iota // no lock - used to release a lock
+	LockRead                   // shared lock
+	LockWrite                  // exclusive lock
+)
+
+// Lock describes an advisory lock on a range of bytes of a File.
+//
+// These are used to implement POSIX (fcntl) and BSD (flock) locks
+// which only conflict with locks of the same kind. BSD locks should
+// cover the whole file.
+//
+// A Mandatory lock also makes writes to the file, or to anything in
+// the directory if it is Recursive, fail with EAGAIN for users of
+// the VFS which don't hold it. These are used by servers with their
+// own locking, such as WebDAV, and are only enforced in other rclone
+// processes sharing the locks with --vfs-shared-locks.
+type Lock struct {
+	Owner     uint64    // identifies the holder of the lock, eg the FUSE lock owner
+	PID       uint32    // process ID of the holder - for information only
+	Type      LockType  // type of the lock
+	Start     uint64    // first byte of the range locked
+	End       uint64    // last byte of the range locked
+	Flock     bool      // set for a BSD lock
+	Mandatory bool      // set to make writes by others fail
+	Recursive bool      // set if a mandatory lock covers everything under the path
+	Expires   time.Time // when the lock is released if not zero
+}
+
+// lockKvFacility is the name of the key-value database used to share
+// locks between rclone processes
+const lockKvFacility = "vfslocks"
+
+// lockPollInterval is how often a waiter checks for locks released
+// by other rclone processes
+const lockPollInterval = 250 * time.Millisecond
+
+// heldLock is a Lock held in the lock table
+type heldLock struct {
+	Lock
+	Instance  string // identifies the lock table which holds the lock
+	Group     string // lock group of the lock table, see VFS.SetLockGroup
+	RclonePID int    // process ID of the rclone holding the lock
+}
+
+// overlaps returns true if the range of l overlaps that of lk
+func (l *heldLock) overlaps(lk *heldLock) bool {
+	return l.Start <= lk.End && lk.Start <= l.End
+}
+
+// sameHolder returns true if l and lk are held by the same lock
+// table or lock group
+func (l *heldLock) sameHolder(lk *heldLock) bool {
+	return l.Instance == lk.Instance || (l.Group != "" && l.Group == lk.Group)
+}
+
+// sameOwner returns true if l and lk have the same owner
+func (l *heldLock) sameOwner(lk *heldLock) bool {
+	return l.sameHolder(lk) && l.Owner == lk.Owner
+}
+
+// conflicts returns true if l would prevent lk being taken
+func (l *heldLock) conflicts(lk *heldLock) bool {
+	return lk.Type != LockUnlock &&
+		l.Flock == lk.Flock &&
+		!l.sameOwner(lk) &&
+		l.overlaps(lk) &&
+		(l.Type == LockWrite || lk.Type == LockWrite)
+}
+
+// expired returns true if l has passed its expiry time
+func (l *heldLock) expired(now time.Time) bool {
+	return !l.Expires.IsZero() && now.After(l.Expires)
+}
+
+// stale returns true if l is held by an rclone process which is no
+// longer running
+func (l *heldLock) stale(instance string) bool {
+	if l.Instance == instance || l.RclonePID <= 0 || l.RclonePID == os.Getpid() {
+		return false
+	}
+	return !process.Exists(l.RclonePID)
+}
+
+// applyLock returns the locks with lk applied.
+//
+// Any locks of the same owner and kind in the range of lk are removed
+// or trimmed, then lk is added unless it is an unlock.
+func applyLock(locks []heldLock, lk heldLock) (out []heldLock) {
+	for _, l := range locks {
+		if l.Flock != lk.Flock || !l.sameOwner(&lk) || !l.overlaps(&lk) {
+			out = append(out, l)
+			continue
+		}
+		if l.Start < lk.Start {
+			before := l
+			before.End = lk.Start - 1
+			out = append(out, before)
+		}
+		if l.End > lk.End {
+			after := l
+			after.Start = lk.End + 1
+			out = append(out, after)
+		}
+	}
+	if lk.Type != LockUnlock {
+		out = append(out, lk)
+	}
+	return out
+}
+
+// lockTable holds the advisory locks of the files in the VFS
+//
+// The locks are kept in memory unless they are shared with other
+// rclone processes, in which case they are kept in a key-value
+// database in the cache directory.
+type lockTable struct {
+	mu       sync.Mutex
+	root     string                // root of the Fs to make the keys for the locks
+	instance string                // identifies the locks held by this table
+	group    string                // lock group of this table
+	locks    map[string][]heldLock // locks held by this table indexed by path
+	db       *kv.DB                // database of shared locks or nil
+	changed  chan struct{}         // closed when the locks change
+}
+
+// newLockTable makes a new lock table for f, sharing the locks with
+// other rclone processes if shared is set and it is supported.
+func newLockTable(ctx context.Context, f fs.Fs, shared bool) *lockTable {
+	lt := &lockTable{
+		root:     f.Root(),
+		instance: random.String(16),
+		locks:    make(map[string][]heldLock),
+		changed:  make(chan struct{}),
+	}
+	if !shared {
+		return lt
+	}
+	if !kv.Supported() {
+		fs.Logf(f, "vfs: locks can't be shared on this OS")
+		return lt
+	}
+	db, err := kv.Start(ctx, lockKvFacility, f)
+	if err != nil {
+		fs.Errorf(f, "vfs: failed to open lock database - locks won't be shared: %v", err)
+		return lt
+	}
+	lt.db = db
+	return lt
+}
+
+// key returns the database key for the locks of name
+func (lt *lockTable) key(name string) string {
+	return path.Join("/", lt.root, name)
+}
+
+// liveLocks returns the locks which haven't expired and aren't held
+// by a stopped rclone
+func liveLocks(locks []heldLock, instance string) (out []heldLock) {
+	now := time.Now()
+	for _, l := range locks {
+		if !l.expired(now) && !l.stale(instance) {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// update the locks of name with fn, signalling waiters if they are
+// changed
+func (lt *lockTable) update(name string, fn func(locks []heldLock) ([]heldLock, error)) (err error) {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	var locks []heldLock
+	if lt.db != nil {
+		op := &kvUpdateLocks{
+			key:      lt.key(name),
+			instance: lt.instance,
+			fn:       fn,
+		}
+		err = lt.db.Do(true, op)
+		locks = op.locks
+	} else {
+		locks, err = fn(liveLocks(lt.locks[name], lt.instance))
+	}
+	if err != nil {
+		return err
+	}
+	var held []heldLock
+	for _, l := range locks {
+		if l.Instance == lt.instance {
+			held = append(held, l)
+		}
+	}
+	if len(held) == 0 {
+		delete(lt.locks, name)
+	} else {
+		lt.locks[name] = held
+	}
+	close(lt.changed)
+	lt.changed = make(chan struct{})
+	return nil
+}
+
+// waitChanged returns a channel which is closed when the locks may
+// have changed.
+func (lt *lockTable) waitChanged() (changed <-chan struct{}, poll <-chan time.Time) {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	if lt.db != nil {
+		poll = time.After(lockPollInterval)
+	}
+	return lt.changed, poll
+}
+
+// setLock sets, changes or releases the lock lk on name.
+//
+// If the lock conflicts with another lock then it returns EAGAIN
+// unless wait is set in which case it waits for the other lock to be
+// released or ctx to be cancelled.
+func (lt *lockTable) setLock(ctx context.Context, name string, lk Lock, wait bool) error {
+	if lk.Start > lk.End {
+		return EINVAL
+	}
+	lt.mu.Lock()
+	newLock := heldLock{
+		Lock:      lk,
+		Instance:  lt.instance,
+		Group:     lt.group,
+		RclonePID: os.Getpid(),
+	}
+	lt.mu.Unlock()
+	for {
+		changed, poll := lt.waitChanged()
+		err := lt.update(name, func(locks []heldLock) ([]heldLock, error) {
+			for i := range locks {
+				if locks[i].conflicts(&newLock) {
+					return nil, EAGAIN
+				}
+			}
+			return applyLock(locks, newLock), nil
+		})
+		if err != EAGAIN || !wait {
+			return err
+		}
+		select {
+		case <-changed:
+		case <-poll:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// getLock returns a lock which would prevent lk being taken on name
+// or lk with Type LockUnlock if there isn't one.
+func (lt *lockTable) getLock(name string, lk Lock) (Lock, error) {
+	lt.mu.Lock()
+	query := heldLock{
+		Lock:     lk,
+		Instance: lt.instance,
+		Group:    lt.group,
+	}
+	lt.mu.Unlock()
+	out := lk
+	out.Type = LockUnlock
+	err := lt.update(name, func(locks []heldLock) ([]heldLock, error) {
+		for i := range locks {
+			if locks[i].conflicts(&query) {
+				out = locks[i].Lock
+				break
+			}
+		}
+		return locks, nil
+	})
+	return out, err
+}
+
+// releaseOwner releases all the locks of the kind given by flock held
+// by owner on name
+func (lt *lockTable) releaseOwner(name string, owner uint64, flock bool) error {
+	// This is called on every close so avoid the update unless needed.
+	// Locks of the lock group may be held by other lock tables so
+	// these must always be looked up.
+	lt.mu.Lock()
+	found := lt.group != "" && lt.db != nil
+	for _, l := range lt.locks[name] {
+		if l.Owner == owner && l.Flock == flock {
+			found = true
+			break
+		}
+	}
+	release := heldLock{
+		Lock:     Lock{Owner: owner},
+		Instance: lt.instance,
+		Group:    lt.group,
+	}
+	lt.mu.Unlock()
+	if !found {
+		return nil
+	}
+	return lt.update(name, func(locks []heldLock) (out []heldLock, err error) {
+		for _, l := range locks {
+			if !l.sameOwner(&release) || l.Flock != flock {
+				out = append(out, l)
+			}
+		}
+		return out, nil
+	})
+}
+
+// checkWrite returns EAGAIN if name, or anything under it if tree is
+// set, is covered by a mandatory lock which isn't ours.
+func (lt *lockTable) checkWrite(name string, tree bool) error {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	if lt.db == nil {
+		// All the locks are held by this table
+		return nil
+	}
+	err := lt.db.Do(false, &kvCheckWrite{
+		lt:   lt,
+		name: name,
+		tree: tree,
+	})
+	if err == kv.ErrEmpty {
+		// No locks have been stored yet
+		return nil
+	}
+	return err
+}
+
+// shutdown releases all the locks held and closes the database
+func (lt *lockTable) shutdown() {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	lt.locks = make(map[string][]heldLock)
+	if lt.db == nil {
+		return
+	}
+	err := lt.db.Do(true, &kvReleaseLocks{instance: lt.instance})
+	if err != nil {
+		fs.Errorf(nil, "vfs: failed to release shared locks: %v", err)
+	}
+	_ = lt.db.Stop(false)
+	lt.db = nil
+}
+
+// kvUpdateLocks updates the locks of a file in the database
+type kvUpdateLocks struct {
+	key      string
+	instance string
+	fn       func(locks []heldLock) ([]heldLock, error)
+	locks    []heldLock // the locks after the update
+}
+
+// Do the update
+func (op *kvUpdateLocks) Do(ctx context.Context, b kv.Bucket) error {
+	locks, err := op.fn(kvGetLocks(b, op.key, op.instance))
+	if err != nil {
+		return err
+	}
+	op.locks = locks
+	if len(locks) == 0 {
+		return b.Delete([]byte(op.key))
+	}
+	data, err := json.Marshal(locks)
+	if err != nil {
+		return fmt.Errorf("failed to encode locks: %w", err)
+	}
+	return b.Put([]byte(op.key), data)
+}
+
+// kvGetLocks reads the live locks stored at key
+func kvGetLocks(b kv.Bucket, key string, instance string) []heldLock {
+	return kvDecodeLocks(key, b.Get([]byte(key)), instance)
+}
+
+// kvDecodeLocks decodes the live locks in data stored at key
+func kvDecodeLocks(key string, data []byte, instance string) []heldLock {
+	if data == nil {
+		return nil
+	}
+	var stored []heldLock
+	if err := json.Unmarshal(data, &stored); err != nil {
+		fs.Errorf(key, "vfs: ignoring corrupted shared locks: %v", err)
+	}
+	return liveLocks(stored, instance)
+}
+
+// kvCheckWrite checks whether a path may be written
+type kvCheckWrite struct {
+	lt   *lockTable
+	name string
+	tree bool
+}
+
+// foreign returns true if a mandatory lock in locks isn't held by
+// the lock table. If exact isn't set only recursive locks are
+// considered.
+func (op *kvCheckWrite) foreign(locks []heldLock, exact bool) bool {
+	ours := heldLock{
+		Instance: op.lt.instance,
+		Group:    op.lt.group,
+	}
+	for i := range locks {
+		l := &locks[i]
+		if l.Mandatory && (exact || l.Recursive) && !l.sameHolder(&ours) {
+			return true
+		}
+	}
+	return false
+}
+
+// Do the check
+func (op *kvCheckWrite) Do(ctx context.Context, b kv.Bucket) error {
+	// Check the path and the recursive locks of its parents
+	name := op.name
+	for exact := true; ; exact = false {
+		key := op.lt.key(name)
+		if op.foreign(kvGetLocks(b, key, op.lt.instance), exact) {
+			return EAGAIN
+		}
+		if name == "" {
+			break
+		}
+		name = path.Dir(name)
+		if name == "." {
+			name = ""
+		}
+	}
+	if !op.tree {
+		return nil
+	}
+	// Check everything under the path
+	prefix := op.lt.key(op.name)
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	c := b.Cursor()
+	for k, v := c.Seek([]byte(prefix)); k != nil && strings.HasPrefix(string(k), prefix); k, v = c.Next() {
+		if op.foreign(kvDecodeLocks(string(k), v, op.lt.instance), true) {
+			return EAGAIN
+		}
+	}
+	return nil
+}
+
+// kvReleaseLocks releases all the locks held by an instance in the
+// database
+type kvReleaseLocks struct {
+	instance string
+}
+
+// Do the release
+func (op *kvReleaseLocks) Do(ctx context.Context, b kv.Bucket) error {
+	updates := map[string][]heldLock{}
+	err := b.ForEach(func(bkey, data []byte) error {
+		var stored, locks []heldLock
+		if err := json.Unmarshal(data, &stored); err != nil {
+			return nil
+		}
+		for _, l := range stored {
+			if l.Instance != op.instance {
+				locks = append(locks, l)
+			}
+		}
+		if len(locks) != len(stored) {
+			updates[string(bkey)] = locks
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for key, locks := range updates {
+		if len(locks) == 0 {
+			err = b.Delete([]byte(key))
+		} else {
+			var data []byte
+			data, err = json.Marshal(locks)
+			if err == nil {
+				err = b.Put([]byte(key), data)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetLock sets, changes or releases the advisory lock lk on the file.
+//
+// If lk conflicts with a lock held by a different owner it returns
+// EAGAIN, or if wait is set it waits for the lock to be released or
+// ctx to be cancelled.
+func (f *File) SetLock(ctx context.Context, lk Lock, wait bool) error {
+	return f.VFS().locks.setLock(ctx, f.Path(), lk, wait)
+}
+
+// GetLock returns a lock held on the file which conflicts with lk, or
+// lk with Type LockUnlock if lk could be taken.
+func (f *File) GetLock(lk Lock) (Lock, error) {
+	return f.VFS().locks.getLock(f.Path(), lk)
+}
+
+// ReleaseLocks releases all the POSIX locks, or BSD locks if flock
+// is set, held by owner on the file.
+func (f *File) ReleaseLocks(owner uint64, flock bool) error {
+	return f.VFS().locks.releaseOwner(f.Path(), owner, flock)
+}
+
+// lockName returns the name of the path in the lock table
+func lockName(name string) string {
+	name = path.Clean("/" + name)
+	return strings.TrimPrefix(name, "/")
+}
+
+// SetLock sets, changes or releases the lock lk on the file or
+// directory at name, which needn't exist, as File.SetLock does.
+func (vfs *VFS) SetLock(ctx context.Context, name string, lk Lock, wait bool) error {
+	return vfs.locks.setLock(ctx, lockName(name), lk, wait)
+}
+
+// ReleaseLocks releases all the POSIX locks, or BSD locks if flock
+// is set, held by owner on the file or directory at name.
+func (vfs *VFS) ReleaseLocks(name string, owner uint64, flock bool) error {
+	return vfs.locks.releaseOwner(lockName(name), owner, flock)
+}
+
+// SetLockGroup sets the lock group of the VFS.
+//
+// The VFSes in a lock group, which may be in different rclone
+// processes sharing their locks, hold locks together so they may
+// write paths with mandatory locks held by each other and release
+// each other's locks. This is for replicas of a server which checks
+// its own locks.
+func (vfs *VFS) SetLockGroup(group string) {
+	vfs.locks.mu.Lock()
+	vfs.locks.group = group
+	vfs.locks.mu.Unlock()
+}
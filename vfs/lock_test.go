@@ -0,0 +1,210 @@
+package vfs
+
+import (
+	"context"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/vfs/vfscommon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyLock(t *testing.T) {
+	lock := func(owner uint64, typ LockType, start, end uint64) heldLock {
+		return heldLock{Lock: Lock{Owner: owner, Type: typ, Start: start, End: end}}
+	}
+	locks := []heldLock{lock(1, LockWrite, 0, 99), lock(2, LockRead, 200, 299)}
+
+	// Unlocking the middle of a range splits it
+	got := applyLock(locks, lock(1, LockUnlock, 10, 19))
+	assert.Equal(t, []heldLock{lock(1, LockWrite, 0, 9), lock(1, LockWrite, 20, 99), lock(2, LockRead, 200, 299)}, got)
+
+	// Changing the type of part of a range replaces that part
+	got = applyLock(locks, lock(1, LockRead, 50, 149))
+	assert.Equal(t, []heldLock{lock(1, LockWrite, 0, 49), lock(2, LockRead, 200, 299), lock(1, LockRead, 50, 149)}, got)
+
+	// Other owners and BSD locks are left alone
+	flock := lock(1, LockUnlock, 0, math.MaxUint64)
+	flock.Flock = true
+	got = applyLock(locks, flock)
+	assert.Equal(t, locks, got)
+	got = applyLock(locks, lock(3, LockUnlock, 0, math.MaxUint64))
+	assert.Equal(t, locks, got)
+}
+
+// Make a file for the lock tests
+func lockTestFile(t *testing.T, vfs *VFS) *File {
+	fd, err := vfs.OpenFile("file1", os.O_WRONLY|os.O_CREATE, 0777)
+	require.NoError(t, err)
+	require.NoError(t, fd.Close())
+	node, err := vfs.Stat("file1")
+	require.NoError(t, err)
+	return node.(*File)
+}
+
+func TestFileLock(t *testing.T) {
+	_, vfs, cleanup := newTestVFS(t)
+	defer cleanup()
+	file := lockTestFile(t, vfs)
+	ctx := context.Background()
+
+	// Read locks are shared
+	require.NoError(t, file.SetLock(ctx, Lock{Owner: 1, Type: LockRead, Start: 0, End: 99}, false))
+	require.NoError(t, file.SetLock(ctx, Lock{Owner: 2, Type: LockRead, Start: 50, End: 149}, false))
+
+	// Write locks conflict
+	err := file.SetLock(ctx, Lock{Owner: 2, Type: LockWrite, Start: 0, End: 9}, false)
+	assert.Equal(t, EAGAIN, err)
+	lock, err := file.GetLock(Lock{Owner: 2, Type: LockWrite, Start: 0, End: 9})
+	require.NoError(t, err)
+	assert.Equal(t, Lock{Owner: 1, Type: LockRead, Start: 0, End: 99}, lock)
+
+	// But not outside the range of other locks
+	require.NoError(t, file.SetLock(ctx, Lock{Owner: 2, Type: LockWrite, Start: 100, End: 199}, false))
+	lock, err = file.GetLock(Lock{Owner: 1, Type: LockRead, Start: 200, End: 299})
+	require.NoError(t, err)
+	assert.Equal(t, LockUnlock, lock.Type)
+
+	// BSD locks don't conflict with POSIX locks
+	require.NoError(t, file.SetLock(ctx, Lock{Owner: 3, Type: LockWrite, Start: 0, End: math.MaxUint64, Flock: true}, false))
+	err = file.SetLock(ctx, Lock{Owner: 4, Type: LockRead, Start: 0, End: math.MaxUint64, Flock: true}, false)
+	assert.Equal(t, EAGAIN, err)
+
+	// A waiting lock is taken when the conflicting lock is released
+	done := make(chan error)
+	go func() {
+		done <- file.SetLock(ctx, Lock{Owner: 2, Type: LockWrite, Start: 0, End: 9}, true)
+	}()
+	select {
+	case err = <-done:
+		t.Fatalf("lock taken too early: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	require.NoError(t, file.ReleaseLocks(1, false))
+	require.NoError(t, <-done)
+
+	// A waiting lock gives up when the context is cancelled
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	err = file.SetLock(ctx, Lock{Owner: 1, Type: LockRead, Start: 0, End: 9}, true)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// Releasing the BSD lock leaves the POSIX locks
+	require.NoError(t, file.ReleaseLocks(3, true))
+	require.NoError(t, file.SetLock(context.Background(), Lock{Owner: 4, Type: LockRead, Start: 0, End: math.MaxUint64, Flock: true}, false))
+	assert.Len(t, vfs.locks.locks["file1"], 4)
+
+	// Shutting down releases the locks
+	vfs.locks.shutdown()
+	assert.Len(t, vfs.locks.locks, 0)
+}
+
+func TestFileLockShared(t *testing.T) {
+	setTestCacheDir(t)
+	opt := vfscommon.DefaultOpt
+	opt.SharedLocks = true
+	r, vfs, cleanup := newTestVFSOpt(t, &opt)
+	defer cleanup()
+	if vfs.locks.db == nil {
+		t.Skip("shared locks not supported")
+	}
+	file := lockTestFile(t, vfs)
+	ctx := context.Background()
+
+	// Another lock table for the same remote sees the locks
+	other := newLockTable(ctx, r.Fremote, true)
+	defer other.shutdown()
+	require.NoError(t, file.SetLock(ctx, Lock{Owner: 1, PID: 42, Type: LockWrite, Start: 0, End: 99}, false))
+	err := other.setLock(ctx, "file1", Lock{Owner: 1, Type: LockRead, Start: 50, End: 59}, false)
+	assert.Equal(t, EAGAIN, err)
+	lock, err := other.getLock("file1", Lock{Owner: 1, Type: LockRead, Start: 50, End: 59})
+	require.NoError(t, err)
+	assert.Equal(t, Lock{Owner: 1, PID: 42, Type: LockWrite, Start: 0, End: 99}, lock)
+
+	// Waiting for a lock released by the other table
+	done := make(chan error)
+	go func() {
+		done <- other.setLock(ctx, "file1", Lock{Owner: 1, Type: LockRead, Start: 50, End: 59}, true)
+	}()
+	require.NoError(t, file.ReleaseLocks(1, false))
+	require.NoError(t, <-done)
+
+	// Locks of a stopped rclone are ignored
+	err = other.update("file2", func(locks []heldLock) ([]heldLock, error) {
+		return append(locks, heldLock{
+			Lock:      Lock{Owner: 1, Type: LockWrite, Start: 0, End: 99},
+			Instance:  "dead",
+			RclonePID: math.MaxInt32,
+		}), nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, vfs.locks.setLock(ctx, "file2", Lock{Owner: 1, Type: LockWrite, Start: 0, End: 99}, false))
+}
+
+func TestMandatoryLock(t *testing.T) {
+	setTestCacheDir(t)
+	opt := vfscommon.DefaultOpt
+	opt.SharedLocks = true
+	r, vfs, cleanup := newTestVFSOpt(t, &opt)
+	defer cleanup()
+	if vfs.locks.db == nil {
+		t.Skip("shared locks not supported")
+	}
+	// Different options so the VFS isn't shared
+	otherOpt := opt
+	otherOpt.NoModTime = true
+	other := New(r.Fremote, &otherOpt)
+	defer other.Shutdown()
+	lockTestFile(t, vfs)
+	require.NoError(t, vfs.Mkdir("dir", 0777))
+	ctx := context.Background()
+	mandatory := func(owner uint64, recursive bool) Lock {
+		return Lock{Owner: owner, Type: LockWrite, End: math.MaxUint64, Flock: true, Mandatory: true, Recursive: recursive}
+	}
+	write := func(v *VFS, name string) error {
+		fd, err := v.OpenFile(name, os.O_WRONLY|os.O_CREATE, 0777)
+		if err != nil {
+			return err
+		}
+		return fd.Close()
+	}
+
+	// Writes by the holder are allowed but not by others
+	require.NoError(t, vfs.SetLock(ctx, "file1", mandatory(1, false), false))
+	require.NoError(t, write(vfs, "file1"))
+	assert.Equal(t, EAGAIN, write(other, "file1"))
+	assert.Equal(t, EAGAIN, other.Remove("file1"))
+	fd, err := other.OpenFile("file1", os.O_RDONLY, 0)
+	require.NoError(t, err)
+	require.NoError(t, fd.Close())
+
+	// A recursive lock on a directory covers its contents
+	require.NoError(t, vfs.SetLock(ctx, "/dir/", mandatory(2, true), false))
+	assert.Equal(t, EAGAIN, write(other, "dir/file2"))
+	assert.Equal(t, EAGAIN, other.Mkdir("dir/sub", 0777))
+	require.NoError(t, vfs.ReleaseLocks("dir", 2, true))
+	require.NoError(t, write(other, "dir/file2"))
+
+	// A lock on a file in a directory stops it being renamed
+	require.NoError(t, vfs.SetLock(ctx, "dir/file2", mandatory(2, false), false))
+	assert.Equal(t, EAGAIN, other.Rename("dir", "dir2"))
+	require.NoError(t, vfs.ReleaseLocks("dir/file2", 2, true))
+
+	// The VFSes in a lock group share their locks
+	vfs.SetLockGroup("group")
+	other.SetLockGroup("group")
+	require.NoError(t, vfs.SetLock(ctx, "dir/file2", mandatory(3, false), false))
+	require.NoError(t, write(other, "dir/file2"))
+	require.NoError(t, other.ReleaseLocks("dir/file2", 3, true))
+	other.SetLockGroup("")
+	require.NoError(t, write(other, "dir/file2"))
+
+	// Expired locks are ignored
+	lk := mandatory(4, false)
+	lk.Expires = time.Now().Add(-time.Second)
+	require.NoError(t, vfs.SetLock(ctx, "dir/file2", lk, false))
+	require.NoError(t, write(other, "dir/file2"))
+}
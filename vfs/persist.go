@@ -0,0 +1,492 @@
+package vfs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config"
+	"github.com/rclone/rclone/fs/dirtree"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/list"
+	"github.com/rclone/rclone/lib/encoder"
+	"github.com/rclone/rclone/lib/file"
+)
+
+// persistVersion is the version of the persisted directory cache
+// format. Increase it if the format changes incompatibly.
+const persistVersion = 1
+
+// minPersistInterval is the minimum time between saves of the
+// directory cache
+const minPersistInterval = time.Minute
+
+// persistedDirCache is the on disk format of the directory cache
+type persistedDirCache struct {
+	Version int                         // version of the format
+	Fs      string                      // fs.ConfigString of the remote
+	Remote  string                      // description of the remote
+	Hash    string                      // type of the hashes stored
+	Saved   time.Time                   // when the directory cache was saved
+	Dirs    map[string][]persistedEntry // listings of directories by path
+}
+
+// persistedEntry is a single directory entry in the persisted
+// directory cache
+type persistedEntry struct {
+	Name    string
+	Dir     bool      `json:",omitempty"`
+	Size    int64     `json:",omitempty"`
+	ModTime time.Time `json:",omitempty"`
+	Hash    string    `json:",omitempty"`
+}
+
+// persistPath returns the OS path of the persisted directory cache
+// in the cache directory
+func (vfs *VFS) persistPath() string {
+	root := vfs.f.Root()
+	if runtime.GOOS == "windows" {
+		root = strings.TrimPrefix(root, `//?/`)
+	}
+	relativePath := encoder.OS.FromStandardPath(vfs.f.Name() + "/" + root)
+	return filepath.Join(config.GetCacheDir(), "vfsDir", filepath.FromSlash(relativePath)) + ".json"
+}
+
+// persistHashType returns the hash type to persist with the objects
+// or hash.None if hashes are too expensive to read.
+func (vfs *VFS) persistHashType() hash.Type {
+	if vfs.f.Features().SlowHash {
+		return hash.None
+	}
+	return vfs.f.Hashes().GetOne()
+}
+
+// newPersistedDirCache makes an empty persisted directory cache
+// describing the remote, used to check the persisted state is still
+// for the same remote.
+func (vfs *VFS) newPersistedDirCache() *persistedDirCache {
+	return &persistedDirCache{
+		Version: persistVersion,
+		Fs:      fs.ConfigString(vfs.f),
+		Remote:  vfs.f.String(),
+		Hash:    vfs.persistHashType().String(),
+		Dirs:    make(map[string][]persistedEntry),
+	}
+}
+
+// saveDirCache saves the directories which have been read so they can
+// be used by the next VFS on the same remote
+func (vfs *VFS) saveDirCache() error {
+	ctx := context.TODO()
+	pdc := vfs.newPersistedDirCache()
+	pdc.Saved = time.Now()
+	hashType := vfs.persistHashType()
+	slowModTime := vfs.f.Features().SlowModTime
+	vfs.root.walk(func(d *Dir) {
+		// NB d.mu is held by walk() here
+		if d.read.IsZero() {
+			return
+		}
+		entries := make([]persistedEntry, 0, len(d.items))
+		for name, node := range d.items {
+			switch d.virtual[name] {
+			case vAddFile, vAddDir:
+				// not in the remote listing yet
+				continue
+			}
+			switch x := node.(type) {
+			case *Dir:
+				entries = append(entries, persistedEntry{Name: name, Dir: true, ModTime: x.ModTime()})
+			case *File:
+				o := x.getObject()
+				if o == nil {
+					continue
+				}
+				entry := persistedEntry{Name: name, Size: o.Size()}
+				if !slowModTime {
+					entry.ModTime = o.ModTime(ctx)
+				}
+				if hashType != hash.None {
+					entry.Hash, _ = o.Hash(ctx, hashType)
+				}
+				entries = append(entries, entry)
+			}
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+		pdc.Dirs[d.path] = entries
+	})
+
+	buf, err := json.Marshal(pdc)
+	if err != nil {
+		return fmt.Errorf("failed to encode directory cache: %w", err)
+	}
+	osPath := vfs.persistPath()
+	err = file.MkdirAll(filepath.Dir(osPath), 0700)
+	if err != nil {
+		return fmt.Errorf("failed to make directory cache directory: %w", err)
+	}
+	// Write to a temporary file and rename it so an interrupted
+	// save can't leave a corrupted directory cache
+	tmpPath := osPath + ".tmp"
+	err = ioutil.WriteFile(tmpPath, buf, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write directory cache: %w", err)
+	}
+	err = os.Rename(tmpPath, osPath)
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write directory cache: %w", err)
+	}
+	fs.Debugf(vfs.f, "Saved %d directories to the persisted directory cache", len(pdc.Dirs))
+	return nil
+}
+
+// removeDirCache removes the persisted directory cache
+func (vfs *VFS) removeDirCache() {
+	err := os.Remove(vfs.persistPath())
+	if err != nil && !os.IsNotExist(err) {
+		fs.Errorf(vfs.f, "Failed to remove persisted directory cache: %v", err)
+	}
+}
+
+// readDirCache reads the persisted directory cache returning nil if
+// there isn't one or it can't be used with this remote.
+func (vfs *VFS) readDirCache() *persistedDirCache {
+	buf, err := ioutil.ReadFile(vfs.persistPath())
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		fs.Errorf(vfs.f, "Failed to read persisted directory cache: %v", err)
+		return nil
+	}
+	var pdc persistedDirCache
+	err = json.Unmarshal(buf, &pdc)
+	if err != nil {
+		fs.Errorf(vfs.f, "Dropping corrupted persisted directory cache: %v", err)
+		vfs.removeDirCache()
+		return nil
+	}
+	want := vfs.newPersistedDirCache()
+	if pdc.Version != want.Version || pdc.Fs != want.Fs || pdc.Remote != want.Remote || pdc.Hash != want.Hash {
+		fs.Infof(vfs.f, "Dropping persisted directory cache as the remote has changed")
+		vfs.removeDirCache()
+		return nil
+	}
+	return &pdc
+}
+
+// loadDirCache loads the persisted directory cache into the directory
+// tree, returning the paths of the directories loaded.
+//
+// The directories are treated as freshly read so they are served
+// straight away and should be revalidated.
+func (vfs *VFS) loadDirCache() (dirPaths []string) {
+	pdc := vfs.readDirCache()
+	if pdc == nil {
+		return nil
+	}
+	hashType := vfs.persistHashType()
+	dirTree := dirtree.New()
+	for dirPath, entries := range pdc.Dirs {
+		dirEntries := make(fs.DirEntries, 0, len(entries))
+		for _, entry := range entries {
+			remote := path.Join(dirPath, entry.Name)
+			if entry.Dir {
+				dirEntries = append(dirEntries, fs.NewDir(remote, entry.ModTime))
+			} else {
+				dirEntries = append(dirEntries, &persistedObject{
+					f:        vfs.f,
+					remote:   remote,
+					size:     entry.Size,
+					modTime:  entry.ModTime,
+					hashType: hashType,
+					hash:     entry.Hash,
+				})
+			}
+		}
+		dirTree[dirPath] = dirEntries
+		dirPaths = append(dirPaths, dirPath)
+	}
+	if _, found := dirTree[""]; !found {
+		// Nothing can be loaded without the root
+		return nil
+	}
+
+	when := time.Now()
+	d := vfs.root
+	d.mu.Lock()
+	err := d._readDirFromDirTree(dirTree, when)
+	d.mu.Unlock()
+	if err != nil {
+		fs.Errorf(vfs.f, "Failed to load persisted directory cache: %v", err)
+		vfs.root.ForgetAll()
+		return nil
+	}
+	// Only the directories which were persisted have been read
+	vfs.root.walk(func(d *Dir) {
+		if _, found := dirTree[d.path]; found {
+			d.read = when
+		} else {
+			d.read = time.Time{}
+		}
+	})
+	fs.Infof(vfs.f, "Loaded %d directories saved %v from the persisted directory cache", len(dirPaths), pdc.Saved.Format(time.RFC3339))
+	sort.Strings(dirPaths)
+	return dirPaths
+}
+
+// startPersistDirCache loads the persisted directory cache, then
+// revalidates it and saves it periodically in the background until
+// the VFS is shut down.
+func (vfs *VFS) startPersistDirCache() {
+	dirPaths := vfs.loadDirCache()
+	ctx, cancel := context.WithCancel(context.Background())
+	vfs.cancelPersist = cancel
+	vfs.persistDone.Add(1)
+	go func() {
+		defer vfs.persistDone.Done()
+		vfs.revalidateDirCache(ctx, dirPaths)
+		interval := vfs.Opt.DirCacheTime
+		if interval < minPersistInterval {
+			interval = minPersistInterval
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := vfs.saveDirCache(); err != nil {
+					fs.Errorf(vfs.f, "Failed to save persisted directory cache: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// stopPersistDirCache stops the background revalidation and saves
+// the directory cache
+func (vfs *VFS) stopPersistDirCache() {
+	if vfs.cancelPersist == nil {
+		return
+	}
+	vfs.cancelPersist()
+	vfs.persistDone.Wait()
+	vfs.cancelPersist = nil
+	if err := vfs.saveDirCache(); err != nil {
+		fs.Errorf(vfs.f, "Failed to save persisted directory cache: %v", err)
+	}
+}
+
+// revalidateDirCache re-reads the directories loaded from the
+// persisted directory cache so changes to the remote made while the
+// VFS wasn't running are found.
+//
+// dirPaths should be sorted so parents are read before their
+// children.
+func (vfs *VFS) revalidateDirCache(ctx context.Context, dirPaths []string) {
+	if len(dirPaths) == 0 {
+		return
+	}
+	start := time.Now()
+	for _, dirPath := range dirPaths {
+		if ctx.Err() != nil {
+			return
+		}
+		if vfs.offline.IsOffline() {
+			fs.Infof(vfs.f, "Stopped revalidating persisted directory cache as the remote is offline")
+			return
+		}
+		dir, ok := vfs.root.cachedNode(dirPath).(*Dir)
+		if !ok {
+			// removed by revalidating its parent
+			continue
+		}
+		err := dir.revalidate(ctx, start)
+		if err != nil {
+			fs.Errorf(dir, "Failed to revalidate persisted directory cache: %v", err)
+		}
+	}
+	fs.Infof(vfs.f, "Revalidated persisted directory cache in %v", time.Since(start))
+}
+
+// revalidate re-reads the directory unless it has been read since
+// the time passed in. The directory isn't locked while it is being
+// listed so the old listing can be used in the meantime.
+func (d *Dir) revalidate(ctx context.Context, since time.Time) error {
+	d.mu.RLock()
+	f, dirPath, read := d.f, d.path, d.read
+	d.mu.RUnlock()
+	if read.After(since) {
+		return nil
+	}
+	when := time.Now()
+	entries, err := list.DirSorted(ctx, f, false, dirPath)
+	if err == fs.ErrorDirNotFound {
+		// We treat directory not found as empty because we
+		// create directories on the fly
+	} else if err != nil {
+		d.vfs.offline.Check(err)
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	err = d._readDirFromEntries(entries, nil, time.Time{})
+	if err != nil {
+		return err
+	}
+	d.read = when
+	return nil
+}
+
+// persistedObject is an fs.Object loaded from the persisted directory
+// cache. It describes the object as it was when it was saved and
+// finds the real object if it is used for anything else, after which
+// the real object is used for everything. It is replaced by the real
+// object when its directory is revalidated.
+type persistedObject struct {
+	f        fs.Fs
+	remote   string
+	size     int64
+	modTime  time.Time
+	hashType hash.Type
+	hash     string
+
+	mu sync.Mutex
+	o  fs.Object // the real object once found
+}
+
+// object finds the real object
+func (o *persistedObject) object(ctx context.Context) (fs.Object, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.o == nil {
+		obj, err := o.f.NewObject(ctx, o.remote)
+		if err != nil {
+			return nil, err
+		}
+		o.o = obj
+	}
+	return o.o, nil
+}
+
+// found returns the real object or nil if it hasn't been found yet
+func (o *persistedObject) found() fs.Object {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.o
+}
+
+// Fs returns the parent Fs
+func (o *persistedObject) Fs() fs.Info {
+	return o.f
+}
+
+// String returns a description of the Object
+func (o *persistedObject) String() string {
+	if o == nil {
+		return "<nil>"
+	}
+	return o.remote
+}
+
+// Remote returns the remote path
+func (o *persistedObject) Remote() string {
+	return o.remote
+}
+
+// ModTime returns the modification time of the object
+func (o *persistedObject) ModTime(ctx context.Context) time.Time {
+	if obj := o.found(); obj != nil {
+		return obj.ModTime(ctx)
+	}
+	if !o.modTime.IsZero() {
+		return o.modTime
+	}
+	obj, err := o.object(ctx)
+	if err != nil {
+		fs.Debugf(o, "Failed to read modification time: %v", err)
+		return time.Time{}
+	}
+	return obj.ModTime(ctx)
+}
+
+// Size returns the size of the object
+func (o *persistedObject) Size() int64 {
+	if obj := o.found(); obj != nil {
+		return obj.Size()
+	}
+	return o.size
+}
+
+// Hash returns the selected checksum of the object
+func (o *persistedObject) Hash(ctx context.Context, ht hash.Type) (string, error) {
+	if o.found() == nil && ht == o.hashType && o.hash != "" {
+		return o.hash, nil
+	}
+	obj, err := o.object(ctx)
+	if err != nil {
+		return "", err
+	}
+	return obj.Hash(ctx, ht)
+}
+
+// Storable returns whether the object is storable
+func (o *persistedObject) Storable() bool {
+	return true
+}
+
+// SetModTime sets the modification time of the object
+func (o *persistedObject) SetModTime(ctx context.Context, t time.Time) error {
+	obj, err := o.object(ctx)
+	if err != nil {
+		return err
+	}
+	return obj.SetModTime(ctx, t)
+}
+
+// Open opens the object for reading
+func (o *persistedObject) Open(ctx context.Context, options ...fs.OpenOption) (io.ReadCloser, error) {
+	obj, err := o.object(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return obj.Open(ctx, options...)
+}
+
+// Update the object with the contents of the io.Reader
+func (o *persistedObject) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	obj, err := o.object(ctx)
+	if err != nil {
+		return err
+	}
+	return obj.Update(ctx, in, src, options...)
+}
+
+// Remove the object
+func (o *persistedObject) Remove(ctx context.Context) error {
+	obj, err := o.object(ctx)
+	if errors.Is(err, fs.ErrorObjectNotFound) {
+		// already gone
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return obj.Remove(ctx)
+}
+
+// Check the interfaces are satisfied
+var _ fs.Object = (*persistedObject)(nil)
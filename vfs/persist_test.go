@@ -0,0 +1,157 @@
+package vfs
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/fs/config"
+	"github.com/rclone/rclone/fstest"
+	"github.com/rclone/rclone/vfs/vfscommon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Use a temporary cache directory for the test
+func setTestCacheDir(t *testing.T) {
+	oldCacheDir := config.GetCacheDir()
+	require.NoError(t, config.SetCacheDir(t.TempDir()))
+	t.Cleanup(func() {
+		_ = config.SetCacheDir(oldCacheDir)
+	})
+}
+
+// Check the names in the directory and return its nodes by name
+func checkDirNames(t *testing.T, vfs *VFS, dirPath string, want []string) map[string]Node {
+	node, err := vfs.Stat(dirPath)
+	require.NoError(t, err)
+	items, err := node.(*Dir).ReadDirAll()
+	require.NoError(t, err)
+	var names []string
+	nodes := map[string]Node{}
+	for _, item := range items {
+		names = append(names, item.Name())
+		nodes[item.Name()] = item
+	}
+	assert.Equal(t, want, names)
+	return nodes
+}
+
+func TestPersistDirCache(t *testing.T) {
+	setTestCacheDir(t)
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	ctx := context.Background()
+
+	file1 := r.WriteObject(ctx, "dir/file1", "file1 contents", t1)
+	file2 := r.WriteObject(ctx, "file2", "file2 contents", t2)
+	r.CheckRemoteItems(t, file1, file2)
+
+	// Read the directories and save them
+	opt := vfscommon.DefaultOpt
+	opt.PersistDirCache = true
+	vfs := New(r.Fremote, &opt)
+	checkDirNames(t, vfs, "", []string{"dir", "file2"})
+	checkDirNames(t, vfs, "dir", []string{"file1"})
+	vfs.Shutdown()
+	assert.FileExists(t, vfs.persistPath())
+
+	// Change the remote while the VFS isn't running
+	file3 := r.WriteObject(ctx, "dir/file3", "file3 contents", t3)
+	r.CheckRemoteListing(t, []fstest.Item{file1, file2, file3}, []string{"dir"})
+	o2, err := r.Fremote.NewObject(ctx, "file2")
+	require.NoError(t, err)
+	require.NoError(t, o2.Remove(ctx))
+
+	// Load the directory cache without revalidating it
+	vfs = New(r.Fremote, nil)
+	defer vfs.Shutdown()
+	dirPaths := vfs.loadDirCache()
+	assert.Equal(t, []string{"", "dir"}, dirPaths)
+
+	// Check the old listing is served
+	nodes := checkDirNames(t, vfs, "", []string{"dir", "file2"})
+	assert.Equal(t, int64(len("file2 contents")), nodes["file2"].Size())
+	nodes = checkDirNames(t, vfs, "dir", []string{"file1"})
+	file := nodes["file1"].(*File)
+	require.IsType(t, &persistedObject{}, file.getObject())
+	assert.Equal(t, int64(len("file1 contents")), file.Size())
+	assert.True(t, file.ModTime().Equal(t1))
+
+	// Check files in the old listing can be read
+	fd, err := vfs.OpenFile("dir/file1", 0, 0)
+	require.NoError(t, err)
+	buf, err := ioutil.ReadAll(fd)
+	require.NoError(t, err)
+	require.NoError(t, fd.Close())
+	assert.Equal(t, "file1 contents", string(buf))
+
+	// Revalidate and check the new listing is served
+	vfs.revalidateDirCache(ctx, dirPaths)
+	checkDirNames(t, vfs, "", []string{"dir"})
+	nodes = checkDirNames(t, vfs, "dir", []string{"file1", "file3"})
+	_, isPersisted := file.getObject().(*persistedObject)
+	assert.False(t, isPersisted, "persisted object not replaced")
+	assert.Equal(t, file, nodes["file1"], "file node reused")
+}
+
+func TestPersistDirCacheRevalidateInBackground(t *testing.T) {
+	setTestCacheDir(t)
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	ctx := context.Background()
+
+	file1 := r.WriteObject(ctx, "file1", "file1 contents", t1)
+	r.CheckRemoteItems(t, file1)
+
+	opt := vfscommon.DefaultOpt
+	opt.PersistDirCache = true
+	vfs := New(r.Fremote, &opt)
+	checkDirNames(t, vfs, "", []string{"file1"})
+	vfs.Shutdown()
+
+	r.WriteObject(ctx, "file2", "file2 contents", t2)
+
+	vfs = New(r.Fremote, &opt)
+	defer vfs.Shutdown()
+	assert.Eventually(t, func() bool {
+		node, err := vfs.Stat("file2")
+		return err == nil && node.Size() == int64(len("file2 contents"))
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestPersistDirCacheDropped(t *testing.T) {
+	setTestCacheDir(t)
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	ctx := context.Background()
+
+	file1 := r.WriteObject(ctx, "file1", "file1 contents", t1)
+	r.CheckRemoteItems(t, file1)
+
+	vfs := New(r.Fremote, nil)
+	defer vfs.Shutdown()
+	checkDirNames(t, vfs, "", []string{"file1"})
+	require.NoError(t, vfs.saveDirCache())
+
+	// Pretend the directory cache was saved for a different remote
+	buf, err := ioutil.ReadFile(vfs.persistPath())
+	require.NoError(t, err)
+	var pdc persistedDirCache
+	require.NoError(t, json.Unmarshal(buf, &pdc))
+	pdc.Remote = "a different remote"
+	buf, err = json.Marshal(pdc)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(vfs.persistPath(), buf, 0600))
+
+	vfs.FlushDirCache()
+	assert.Nil(t, vfs.loadDirCache())
+	assert.NoFileExists(t, vfs.persistPath())
+
+	// A corrupted directory cache is dropped too
+	require.NoError(t, ioutil.WriteFile(vfs.persistPath(), []byte("{"), 0600))
+	assert.Nil(t, vfs.loadDirCache())
+	assert.NoFileExists(t, vfs.persistPath())
+}
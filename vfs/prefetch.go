@@ -0,0 +1,193 @@
+package vfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fs/filter"
+	"github.com/rclone/rclone/vfs/vfscommon"
+)
+
+// PrefetchOpt describes what to prefetch into the VFS cache
+type PrefetchOpt struct {
+	Paths     []string // paths of files or directories to prefetch - the root if empty
+	Offset    int64    // offset in each file to start at - from the end if negative
+	Count     int64    // number of bytes to prefetch from each file - all if negative
+	Transfers int      // number of files to prefetch at once - --transfers if 0
+}
+
+// PrefetchStats describes what was prefetched
+type PrefetchStats struct {
+	Files  int64 // number of files prefetched
+	Bytes  int64 // number of bytes in the ranges prefetched
+	Errors int64 // number of files which failed
+}
+
+// Prefetch downloads files into the VFS cache so they can be read
+// without waiting for the remote.
+//
+// The directories in opt.Paths are walked recursively. Files found
+// are filtered with the filter in ctx so the usual filtering rules,
+// including --min-size and --max-size, can be used to choose what is
+// fetched. The byte range set by opt.Offset and opt.Count is fetched
+// from each file.
+//
+// The data is fetched with the cache downloaders, and the files being
+// fetched are shown as checks in the stats for ctx.
+//
+// This needs --vfs-cache-mode full.
+func (vfs *VFS) Prefetch(ctx context.Context, opt PrefetchOpt) (stats PrefetchStats, err error) {
+	if vfs.cache == nil || vfs.Opt.CacheMode < vfscommon.CacheModeFull {
+		return stats, errors.New("prefetch needs --vfs-cache-mode full")
+	}
+	if opt.Transfers <= 0 {
+		opt.Transfers = fs.GetConfig(ctx).Transfers
+	}
+	paths := opt.Paths
+	if len(paths) == 0 {
+		paths = []string{""}
+	}
+	fi := filter.GetConfig(ctx)
+
+	var (
+		mu      sync.Mutex
+		lastErr error
+		wg      sync.WaitGroup
+		files   = make(chan *File, opt.Transfers)
+	)
+	for i := 0; i < opt.Transfers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range files {
+				if ctx.Err() != nil {
+					continue
+				}
+				n, err := vfs.prefetchFile(ctx, file, opt.Offset, opt.Count)
+				mu.Lock()
+				if err != nil {
+					fs.Errorf(file.Path(), "Failed to prefetch: %v", err)
+					stats.Errors++
+					lastErr = err
+				} else {
+					stats.Files++
+					stats.Bytes += n
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, path := range paths {
+		err = vfs.prefetchWalk(ctx, fi, path, files)
+		if err != nil {
+			break
+		}
+	}
+	close(files)
+	wg.Wait()
+	if err != nil {
+		return stats, err
+	}
+	if lastErr != nil {
+		return stats, fmt.Errorf("failed to prefetch %d files: last error: %w", stats.Errors, lastErr)
+	}
+	fs.Infof(vfs.f, "Prefetched %d files (%v) into the VFS cache", stats.Files, fs.SizeSuffix(stats.Bytes))
+	return stats, nil
+}
+
+// prefetchWalk sends the files in path which pass the filter to files
+func (vfs *VFS) prefetchWalk(ctx context.Context, fi *filter.Filter, path string, files chan<- *File) error {
+	node, err := vfs.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to find %q to prefetch: %w", path, err)
+	}
+	switch x := node.(type) {
+	case *File:
+		return prefetchSend(ctx, fi, x, files)
+	case *Dir:
+		return prefetchWalkDir(ctx, fi, x, files)
+	}
+	return nil
+}
+
+// prefetchWalkDir recursively sends the files in d which pass the
+// filter to files
+func prefetchWalkDir(ctx context.Context, fi *filter.Filter, d *Dir, files chan<- *File) error {
+	nodes, err := d.ReadDirAll()
+	if err != nil {
+		return fmt.Errorf("failed to list %q to prefetch: %w", d.Path(), err)
+	}
+	includeDirectory := fi.IncludeDirectory(ctx, d.f)
+	for _, node := range nodes {
+		switch x := node.(type) {
+		case *File:
+			err = prefetchSend(ctx, fi, x, files)
+		case *Dir:
+			var include bool
+			include, err = includeDirectory(x.Path())
+			if err == nil && include {
+				err = prefetchWalkDir(ctx, fi, x, files)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prefetchSend sends file to files if it passes the filter
+func prefetchSend(ctx context.Context, fi *filter.Filter, file *File, files chan<- *File) error {
+	if !fi.Include(file.Path(), file.Size(), file.ModTime()) {
+		return nil
+	}
+	select {
+	case files <- file:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+// prefetchFile fetches count bytes at offset of file into the cache
+// returning the number of bytes in the range fetched.
+func (vfs *VFS) prefetchFile(ctx context.Context, file *File, offset, count int64) (n int64, err error) {
+	o := file.getObject()
+	if o == nil {
+		// The file is being written so is already in the cache
+		return 0, nil
+	}
+	size := o.Size()
+	if offset < 0 {
+		offset += size
+		if offset < 0 {
+			offset = 0
+		}
+	}
+	if count < 0 || offset+count > size {
+		count = size - offset
+	}
+	if count <= 0 {
+		return 0, nil
+	}
+	tr := accounting.Stats(ctx).NewCheckingTransfer(o)
+	defer func() {
+		tr.Done(ctx, err)
+	}()
+	item := vfs.cache.Item(file.Path())
+	err = item.Open(o)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open cache file: %w", err)
+	}
+	n, err = item.Prefetch(offset, count)
+	closeErr := item.Close(nil)
+	if err == nil {
+		err = closeErr
+	}
+	return n, err
+}
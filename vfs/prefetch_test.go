@@ -0,0 +1,104 @@
+package vfs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rclone/rclone/fs/filter"
+	"github.com/rclone/rclone/fstest"
+	"github.com/rclone/rclone/lib/ranges"
+	"github.com/rclone/rclone/vfs/vfscommon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Create a VFS with files for prefetching
+func prefetchCreate(t *testing.T) (r *fstest.Run, vfs *VFS, cleanup func()) {
+	setTestCacheDir(t)
+	opt := vfscommon.DefaultOpt
+	opt.CacheMode = vfscommon.CacheModeFull
+	opt.CachePollInterval = 0
+	r, vfs, cleanup = newTestVFSOpt(t, &opt)
+
+	ctx := context.Background()
+	file1 := r.WriteObject(ctx, "dir/file1.txt", "file1 contents which are quite long", t1)
+	file2 := r.WriteObject(ctx, "dir/sub/file2.mp4", "file2 contents which are quite long", t2)
+	file3 := r.WriteObject(ctx, "other/file3.mp4", "file3 contents which are quite long", t3)
+	r.CheckRemoteItems(t, file1, file2, file3)
+	return r, vfs, cleanup
+}
+
+// Check the range of the file is in the cache
+func checkPrefetched(t *testing.T, vfs *VFS, name string, r ranges.Range) {
+	require.True(t, vfs.cache.Exists(name), name)
+	assert.True(t, vfs.cache.Item(name).HasRange(r), name)
+}
+
+func TestPrefetchNeedsCacheModeFull(t *testing.T) {
+	_, vfs, cleanup := newTestVFS(t)
+	defer cleanup()
+
+	_, err := vfs.Prefetch(context.Background(), PrefetchOpt{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "needs --vfs-cache-mode full")
+}
+
+func TestPrefetch(t *testing.T) {
+	_, vfs, cleanup := prefetchCreate(t)
+	defer cleanup()
+
+	stats, err := vfs.Prefetch(context.Background(), PrefetchOpt{
+		Paths: []string{"dir"},
+		Count: -1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, PrefetchStats{Files: 2, Bytes: 70}, stats)
+	checkPrefetched(t, vfs, "dir/file1.txt", ranges.Range{Pos: 0, Size: 35})
+	checkPrefetched(t, vfs, "dir/sub/file2.mp4", ranges.Range{Pos: 0, Size: 35})
+	assert.False(t, vfs.cache.Exists("other/file3.mp4"))
+
+	// Check the files are closed
+	assert.Equal(t, 0, vfs.cache.TotalInUse())
+
+	// Check a missing path
+	_, err = vfs.Prefetch(context.Background(), PrefetchOpt{
+		Paths: []string{"potato"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to find")
+}
+
+func TestPrefetchFilterAndRange(t *testing.T) {
+	_, vfs, cleanup := prefetchCreate(t)
+	defer cleanup()
+
+	fi, err := filter.NewFilter(&filter.Opt{
+		FilterRule: []string{"+ *.mp4", "- **"},
+		MinAge:     filter.DefaultOpt.MinAge,
+		MaxAge:     filter.DefaultOpt.MaxAge,
+		MinSize:    filter.DefaultOpt.MinSize,
+		MaxSize:    filter.DefaultOpt.MaxSize,
+	})
+	require.NoError(t, err)
+	ctx := filter.ReplaceConfig(context.Background(), fi)
+
+	// The last 10 bytes of every video
+	stats, err := vfs.Prefetch(ctx, PrefetchOpt{
+		Offset:    -10,
+		Count:     100,
+		Transfers: 1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, PrefetchStats{Files: 2, Bytes: 20}, stats)
+	checkPrefetched(t, vfs, "dir/sub/file2.mp4", ranges.Range{Pos: 25, Size: 10})
+	checkPrefetched(t, vfs, "other/file3.mp4", ranges.Range{Pos: 25, Size: 10})
+	assert.False(t, vfs.cache.Exists("dir/file1.txt"))
+
+	// Ranges outside the file are skipped
+	stats, err = vfs.Prefetch(ctx, PrefetchOpt{
+		Offset: 100,
+		Count:  10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, PrefetchStats{Files: 2}, stats)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -227,6 +228,125 @@ func rcForget(ctx context.Context, in rc.Params) (out rc.Params, err error) {
 	return out, nil
 }
 
+func init() {
+	rc.Add(rc.Call{
+		Path:  "vfs/prefetch",
+		Fn:    rcPrefetch,
+		Title: "Prefetch files into the VFS cache.",
+		Help: `
+This downloads files into the VFS cache so they can be read without
+waiting for the remote. It needs --vfs-cache-mode full.
+
+If no paths are passed in then it will prefetch every file.
+
+    rclone rc vfs/prefetch
+
+Otherwise pass files or dirs in as path=path. Any parameter key
+starting with path will prefetch that file or directory, and the
+contents of directories are prefetched recursively, e.g.
+
+    rclone rc vfs/prefetch path=projects/current path2=docs/index.html
+
+Use the _filter parameter to choose which files are prefetched, e.g.
+to prefetch only the files smaller than 100 MiB
+
+    rclone rc vfs/prefetch path=projects/current _filter='{"MaxSize":"100M"}'
+
+These optional parameters can also be passed in
+
+- offset - the offset in each file to start at, from the end of the file if negative
+- count - the number of bytes to fetch from each file, the rest of the file if not set
+- transfers - the number of files to fetch at once, --transfers if not set
+
+The offset and count may use size suffixes, so to prefetch the first
+4 MiB of every video
+
+    rclone rc vfs/prefetch count=4M _filter='{"IncludeRule":["*.mp4","*.mkv"]}'
+
+The files being prefetched are shown as checks in core/stats and the
+data being downloaded into the cache is shown as transfers. A large
+prefetch should be run with _async=true and monitored with job/status
+and core/stats - the checks are in the stats group job/ID.
+
+It returns the number of files prefetched, the number of bytes in the
+ranges prefetched and the number of files which failed.
+
+    {
+        "files": 12,
+        "bytes": 50331648,
+        "errors": 0
+    }
+` + getVFSHelp,
+	})
+}
+
+// getSize reads a size with an optional size suffix
+func getSize(k string, v interface{}) (int64, error) {
+	switch x := v.(type) {
+	case string:
+		var size fs.SizeSuffix
+		if strings.HasPrefix(x, "-") {
+			err := size.Set(x[1:])
+			return -int64(size), err
+		}
+		err := size.Set(x)
+		return int64(size), err
+	case float64:
+		return int64(x), nil
+	case int:
+		return int64(x), nil
+	case int64:
+		return x, nil
+	}
+	return 0, fmt.Errorf("value must be a size %q=%v", k, v)
+}
+
+func rcPrefetch(ctx context.Context, in rc.Params) (out rc.Params, err error) {
+	vfs, err := getVFS(in)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := PrefetchOpt{
+		Count: -1,
+	}
+	for k, v := range in {
+		switch {
+		case strings.HasPrefix(k, "path"):
+			path, ok := v.(string)
+			if !ok {
+				return out, fmt.Errorf("value must be string %q=%v", k, v)
+			}
+			opt.Paths = append(opt.Paths, strings.Trim(path, "/"))
+		case k == "offset":
+			opt.Offset, err = getSize(k, v)
+		case k == "count":
+			opt.Count, err = getSize(k, v)
+		case k == "transfers":
+			var transfers int64
+			transfers, err = in.GetInt64(k)
+			opt.Transfers = int(transfers)
+		default:
+			return out, fmt.Errorf("unknown key %q", k)
+		}
+		if err != nil {
+			return out, fmt.Errorf("invalid value %q=%v: %w", k, v, err)
+		}
+	}
+	sort.Strings(opt.Paths)
+
+	stats, err := vfs.Prefetch(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	out = rc.Params{
+		"files":  stats.Files,
+		"bytes":  stats.Bytes,
+		"errors": stats.Errors,
+	}
+	return out, nil
+}
+
 func getDuration(k string, v interface{}) (time.Duration, error) {
 	s, ok := v.(string)
 	if !ok {
@@ -417,6 +537,8 @@ This returns stats for the selected VFS.
             "dirs": 1,
             "files": 0
         },
+        // Whether the remote is unreachable - only present if --vfs-offline
+        "offline": false,
         // Options as returned by options/get
         "opt": {
             "CacheMaxAge": 3600000000000,
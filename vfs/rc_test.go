@@ -92,6 +92,27 @@ func TestRcRefresh(t *testing.T) {
 	// FIXME needs more tests
 }
 
+func TestRcPrefetch(t *testing.T) {
+	r, vfs, cleanup, call := rcNewRun(t, "vfs/prefetch")
+	defer cleanup()
+	_ = vfs
+
+	in := rc.Params{"fs": fs.ConfigString(r.Fremote), "potato": "1"}
+	_, err := call.Fn(context.Background(), in)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown key "potato"`)
+
+	in = rc.Params{"fs": fs.ConfigString(r.Fremote), "count": "potato"}
+	_, err = call.Fn(context.Background(), in)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid value "count"`)
+
+	in = rc.Params{"fs": fs.ConfigString(r.Fremote), "path": "dir", "offset": "-1M", "count": 4096.0}
+	_, err = call.Fn(context.Background(), in)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "needs --vfs-cache-mode full")
+}
+
 func TestRcPollInterval(t *testing.T) {
 	r, vfs, cleanup, call := rcNewRun(t, "vfs/poll-interval")
 	defer cleanup()
@@ -168,7 +168,12 @@ type VFS struct {
 	usageTime   time.Time
 	usage       *fs.Usage
 	pollChan    chan time.Duration
-	inUse       int32 // count of number of opens accessed with atomic
+	inUse       int32              // count of number of opens accessed with atomic
+	offline     *vfscommon.Offline // tracks whether the remote is reachable if set
+	locks       *lockTable         // advisory locks held on files
+
+	cancelPersist context.CancelFunc // stops the persisted directory cache
+	persistDone   sync.WaitGroup     // wait for the persisted directory cache to stop
 }
 
 // Keep track of active VFS keyed on fs.ConfigString(f)
@@ -213,6 +218,19 @@ func New(f fs.Fs, opt *vfscommon.Options) *VFS {
 	// Create root directory
 	vfs.root = newDir(vfs, f, nil, fsDir)
 
+	// Make the table of advisory locks
+	vfs.locks = newLockTable(context.TODO(), f, vfs.Opt.SharedLocks)
+
+	// Track whether the remote is reachable
+	if vfs.Opt.Offline {
+		vfs.offline = vfscommon.NewOffline(f)
+	}
+
+	// Load the directory cache before any changes are notified
+	if vfs.Opt.PersistDirCache {
+		vfs.startPersistDirCache()
+	}
+
 	// Start polling function
 	features := vfs.f.Features()
 	if do := features.ChangeNotify; do != nil {
@@ -261,6 +279,9 @@ func (vfs *VFS) Stats() (out rc.Params) {
 	if vfs.cache != nil {
 		out["diskCache"] = vfs.cache.Stats()
 	}
+	if vfs.offline != nil {
+		out["offline"] = vfs.offline.IsOffline()
+	}
 	return out
 }
 
@@ -289,7 +310,7 @@ func (vfs *VFS) SetCacheMode(cacheMode vfscommon.CacheMode) {
 	vfs.cache = nil
 	if cacheMode > vfscommon.CacheModeOff {
 		ctx, cancel := context.WithCancel(context.Background())
-		cache, err := vfscache.New(ctx, vfs.f, &vfs.Opt, vfs.AddVirtual) // FIXME pass on context or get from Opt?
+		cache, err := vfscache.New(ctx, vfs.f, &vfs.Opt, vfs.AddVirtual, vfs.offline) // FIXME pass on context or get from Opt?
 		if err != nil {
 			fs.Errorf(nil, "Failed to create vfs cache - disabling: %v", err)
 			vfs.Opt.CacheMode = vfscommon.CacheModeOff
@@ -330,7 +351,10 @@ func (vfs *VFS) Shutdown() {
 	}
 	activeMu.Unlock()
 
+	vfs.stopPersistDirCache()
 	vfs.shutdownCache()
+	vfs.offline.Shutdown()
+	vfs.locks.shutdown()
 }
 
 // CleanUp deletes the contents of the on disk cache
@@ -669,6 +693,34 @@ func (vfs *VFS) Mkdir(name string, perm os.FileMode) error {
 	return nil
 }
 
+// Symlink creates linkPath as a symbolic link to target.
+//
+// This needs --vfs-links to be set.
+func (vfs *VFS) Symlink(target, linkPath string) error {
+	dir, leaf, err := vfs.StatParent(linkPath)
+	if err != nil {
+		return err
+	}
+	_, err = dir.Symlink(target, leaf)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Readlink returns the target of the named symbolic link.
+func (vfs *VFS) Readlink(name string) (string, error) {
+	node, err := vfs.Stat(name)
+	if err != nil {
+		return "", err
+	}
+	file, ok := node.(*File)
+	if !ok {
+		return "", EINVAL
+	}
+	return file.Readlink()
+}
+
 // ReadDir reads the directory named by dirname and returns
 // a list of directory entries sorted by filename.
 func (vfs *VFS) ReadDir(dirname string) ([]os.FileInfo, error) {
@@ -50,6 +50,7 @@ type Cache struct {
 	hashOption *fs.HashesOption     // corresponding OpenOption
 	writeback  *writeback.WriteBack // holds Items for writeback
 	avFn       AddVirtualFn         // if set, can be called to add dir entries
+	offline    *vfscommon.Offline   // if set, tracks whether the remote is reachable
 
 	mu            sync.Mutex       // protects the following variables
 	cond          *sync.Cond       // cond lock for synchronous cache cleaning
@@ -75,7 +76,10 @@ type AddVirtualFn func(remote string, size int64, isDir bool) error
 //
 // This starts background goroutines which can be cancelled with the
 // context passed in.
-func New(ctx context.Context, fremote fs.Fs, opt *vfscommon.Options, avFn AddVirtualFn) (*Cache, error) {
+//
+// If offline is not nil, uploads are paused while the remote is
+// offline.
+func New(ctx context.Context, fremote fs.Fs, opt *vfscommon.Options, avFn AddVirtualFn, offline *vfscommon.Offline) (*Cache, error) {
 	// Get cache root path.
 	// We need it in two variants: OS path as an absolute path with UNC prefix,
 	// OS-specific path separators, and encoded with OS-specific encoder. Standard path
@@ -126,6 +130,17 @@ func New(ctx context.Context, fremote fs.Fs, opt *vfscommon.Options, avFn AddVir
 		hashOption: hashOption,
 		writeback:  writeback.New(ctx, opt),
 		avFn:       avFn,
+		offline:    offline,
+	}
+	offline.OnChange(func(isOffline bool) {
+		if isOffline {
+			c.writeback.Pause()
+		} else {
+			c.writeback.Resume()
+		}
+	})
+	if offline.IsOffline() {
+		c.writeback.Pause()
 	}
 
 	// load in the cache and metadata off disk
@@ -519,6 +534,7 @@ func (c *Cache) walk(dir string, fn func(osPath string, fi os.FileInfo, name str
 // to find any new items iterating the metadata but it will clear up
 // orphan files.
 func (c *Cache) reload(ctx context.Context) error {
+	var items Items
 	for _, dir := range []string{c.root, c.metaRoot} {
 		err := c.walk(dir, func(osPath string, fi os.FileInfo, name string) error {
 			if fi.IsDir() {
@@ -526,10 +542,7 @@ func (c *Cache) reload(ctx context.Context) error {
 			}
 			item, found := c.get(name)
 			if !found {
-				err := item.reload(ctx)
-				if err != nil {
-					fs.Errorf(name, "vfs cache: failed to reload item: %v", err)
-				}
+				items = append(items, item)
 			}
 			return nil
 		})
@@ -537,6 +550,17 @@ func (c *Cache) reload(ctx context.Context) error {
 			return fmt.Errorf("failed to walk cache %q: %w", dir, err)
 		}
 	}
+	// Reload in the order the items were queued for upload so
+	// the uploads are done in the same order
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].getQueued().Before(items[j].getQueued())
+	})
+	for _, item := range items {
+		err := item.reload(ctx)
+		if err != nil {
+			fs.Errorf(item.GetName(), "vfs cache: failed to reload item: %v", err)
+		}
+	}
 	return nil
 }
 
@@ -653,13 +677,80 @@ func (c *Cache) purgeClean(quota int64) {
 	c.cond.Broadcast()
 }
 
-// purgeOld gets rid of any files that are over age
+// evictChunks evicts chunks of the cache files of items which are
+// not in use in least recently accessed order until done returns true
+//
+// done is called with the access time of the next chunk to evict and
+// the space freed so far. It returns the items which had chunks
+// evicted.
+//
+// call with c.mu held
+func (c *Cache) evictChunks(items Items, done func(atime time.Time, freed int64) bool) (evicted Items) {
+	if !file.PunchHoleImplemented {
+		return nil
+	}
+	var chunks []cacheChunk
+	for _, item := range items {
+		chunks = append(chunks, item.evictableChunks()...)
+	}
+	sort.Slice(chunks, func(i, j int) bool {
+		return chunks[i].atime.Before(chunks[j].atime)
+	})
+
+	// Choose the chunks to evict
+	var (
+		freed int64
+		evict = map[*Item][]int64{}
+		order []*Item
+	)
+	for _, chunk := range chunks {
+		if done(chunk.atime, freed) {
+			break
+		}
+		if _, found := evict[chunk.item]; !found {
+			order = append(order, chunk.item)
+		}
+		evict[chunk.item] = append(evict[chunk.item], chunk.n)
+		freed += chunk.size
+	}
+
+	// Evict them
+	for _, item := range order {
+		spaceFreed, err := item.evictChunks(evict[item])
+		c.used -= spaceFreed
+		if spaceFreed > 0 {
+			evicted = append(evicted, item)
+		}
+		if errors.Is(err, file.ErrPunchHoleUnsupported) {
+			fs.Debugf(nil, "vfs cache: can't evict chunks as the cache file system doesn't support it")
+			return evicted
+		}
+		if err != nil {
+			fs.Errorf(item.GetName(), "vfs cache: failed to evict chunks: %v", err)
+		}
+	}
+	return evicted
+}
+
+// purgeOld gets rid of any files that are over age and any chunks of
+// files not in use which are over age
 func (c *Cache) purgeOld(maxAge time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	// cutoff := time.Now().Add(-maxAge)
+	var items Items
 	for _, item := range c.item {
 		c.removeNotInUse(item, maxAge, false)
+		if _, found := c.item[item.name]; found {
+			items = append(items, item)
+		}
+	}
+	cutoff := time.Now().Add(-maxAge)
+	evicted := c.evictChunks(items, func(atime time.Time, freed int64) bool {
+		return atime.After(cutoff)
+	})
+	// Remove any files which have had all their chunks evicted
+	for _, item := range evicted {
+		c.removeNotInUse(item, 0, true)
 	}
 	if c.used < int64(c.opt.CacheMaxSize) {
 		c.outOfSpace = false
@@ -715,9 +806,17 @@ func (c *Cache) purgeOverQuota(quota int64) {
 		}
 	}
 
+	// Evict the least recently accessed chunks of those files
+	// first so parts of large files can stay cached
+	used := c.used
+	c.evictChunks(items, func(atime time.Time, freed int64) bool {
+		return used-freed < quota
+	})
+
 	sort.Sort(items)
 
-	// Remove items until the quota is OK
+	// Remove items until the quota is OK and remove any emptied by
+	// evicting chunks
 	for _, item := range items {
 		c.removeNotInUse(item, 0, c.used <= quota)
 	}
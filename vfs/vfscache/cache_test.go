@@ -2,15 +2,20 @@ package vfscache
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	_ "github.com/rclone/rclone/backend/local" // import the local backend
 	"github.com/rclone/rclone/fstest"
+	"github.com/rclone/rclone/lib/file"
+	"github.com/rclone/rclone/lib/ranges"
 	"github.com/rclone/rclone/vfs/vfscommon"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -87,7 +92,7 @@ func newTestCacheOpt(t *testing.T, opt vfscommon.Options) (r *fstest.Run, c *Cac
 	ctx, cancel := context.WithCancel(context.Background())
 
 	avInfos = nil
-	c, err := New(ctx, r.Fremote, &opt, addVirtual)
+	c, err := New(ctx, r.Fremote, &opt, addVirtual, nil)
 	require.NoError(t, err)
 
 	cleanup = func() {
@@ -426,6 +431,109 @@ func TestCachePurgeOverQuota(t *testing.T) {
 	assert.Equal(t, []string(nil), itemAsString(c))
 }
 
+func TestCacheEvictChunks(t *testing.T) {
+	if !file.PunchHoleImplemented {
+		t.Skip("hole punching not implemented on this OS")
+	}
+	oldCacheChunkSize := cacheChunkSize
+	cacheChunkSize = 4096
+	defer func() { cacheChunkSize = oldCacheChunkSize }()
+
+	_, c, cleanup := newTestCache(t)
+	defer cleanup()
+
+	// Make a file of 4 chunks
+	contents := strings.Repeat("x", int(4*cacheChunkSize))
+	potato := c.Item("potato")
+	itemWrite(t, potato, contents)
+	require.NoError(t, potato.Close(nil))
+
+	// Make chunks 0 and 2 the coldest
+	t0 := time.Now()
+	potato.mu.Lock()
+	potato.info.ChunkATime[0] = t0.Add(-40 * time.Second)
+	potato.info.ChunkATime[1] = t0.Add(-10 * time.Second)
+	potato.info.ChunkATime[2] = t0.Add(-30 * time.Second)
+	potato.info.ChunkATime[3] = t0.Add(-20 * time.Second)
+	potato.mu.Unlock()
+
+	// Check the coldest chunks are evicted to get below quota
+	c.purgeOverQuota(3 * cacheChunkSize)
+	assert.Equal(t, 2*cacheChunkSize, c.used)
+	assert.Equal(t, []string{
+		fmt.Sprintf(`name="potato" opens=0 size=%d space=%d`, 4*cacheChunkSize, 2*cacheChunkSize),
+	}, itemSpaceAsString(c))
+	assert.Equal(t, ranges.Ranges{
+		{Pos: cacheChunkSize, Size: cacheChunkSize},
+		{Pos: 3 * cacheChunkSize, Size: cacheChunkSize},
+	}, potato.info.Rs)
+
+	// Check the cold chunks were zeroed and the hot chunks kept
+	buf, err := ioutil.ReadFile(c.toOSPath("potato"))
+	require.NoError(t, err)
+	zeroChunk := string(make([]byte, cacheChunkSize))
+	assert.Equal(t, zeroChunk+contents[:cacheChunkSize]+zeroChunk+contents[:cacheChunkSize], string(buf))
+
+	// Check chunks over age are evicted and the file removed when empty
+	potato.mu.Lock()
+	potato.info.ChunkATime[1] = t0.Add(-10 * time.Second)
+	potato.info.ChunkATime[3] = t0.Add(-20 * time.Second)
+	potato.mu.Unlock()
+	c.purgeOld(15 * time.Second)
+	assert.Equal(t, []string{
+		fmt.Sprintf(`name="potato" opens=0 size=%d space=%d`, 4*cacheChunkSize, cacheChunkSize),
+	}, itemSpaceAsString(c))
+	c.purgeOld(5 * time.Second)
+	assert.Equal(t, []string(nil), itemSpaceAsString(c))
+	assert.Equal(t, int64(0), c.used)
+}
+
+func TestCacheEvictChunksPartial(t *testing.T) {
+	if !file.PunchHoleImplemented {
+		t.Skip("hole punching not implemented on this OS")
+	}
+	oldCacheChunkSize := cacheChunkSize
+	cacheChunkSize = 4096
+	defer func() { cacheChunkSize = oldCacheChunkSize }()
+
+	// Fail punching the second hole
+	punched := 0
+	oldPunchHole := punchHole
+	punchHole = func(out *os.File, offset, size int64) error {
+		if punched == 1 {
+			return errors.New("punch hole failed")
+		}
+		punched++
+		return oldPunchHole(out, offset, size)
+	}
+	defer func() { punchHole = oldPunchHole }()
+
+	_, c, cleanup := newTestCache(t)
+	defer cleanup()
+
+	// Make a file of 4 chunks with chunks 0 and 2 the coldest
+	contents := strings.Repeat("x", int(4*cacheChunkSize))
+	potato := c.Item("potato")
+	itemWrite(t, potato, contents)
+	require.NoError(t, potato.Close(nil))
+	t0 := time.Now()
+	potato.mu.Lock()
+	potato.info.ChunkATime[0] = t0.Add(-40 * time.Second)
+	potato.info.ChunkATime[1] = t0.Add(-10 * time.Second)
+	potato.info.ChunkATime[2] = t0.Add(-30 * time.Second)
+	potato.info.ChunkATime[3] = t0.Add(-20 * time.Second)
+	potato.mu.Unlock()
+
+	// Check only the chunk which was punched is counted and
+	// marked as missing
+	c.purgeOverQuota(3 * cacheChunkSize)
+	assert.Equal(t, 1, punched)
+	assert.Equal(t, 3*cacheChunkSize, c.used)
+	assert.Equal(t, ranges.Ranges{
+		{Pos: cacheChunkSize, Size: 3 * cacheChunkSize},
+	}, potato.info.Rs)
+}
+
 // test reset clean files
 func TestCachePurgeClean(t *testing.T) {
 	r, c, cleanup := newItemTestCache(t)
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,6 +19,7 @@ import (
 	"github.com/rclone/rclone/lib/ranges"
 	"github.com/rclone/rclone/vfs/vfscache/downloaders"
 	"github.com/rclone/rclone/vfs/vfscache/writeback"
+	"github.com/rclone/rclone/vfs/vfscommon"
 )
 
 // NB as Cache and Item are tightly linked it is necessary to have a
@@ -72,12 +75,32 @@ type Item struct {
 
 // Info is persisted to backing store
 type Info struct {
-	ModTime     time.Time     // last time file was modified
-	ATime       time.Time     // last time file was accessed
-	Size        int64         // size of the file
-	Rs          ranges.Ranges // which parts of the file are present
-	Fingerprint string        // fingerprint of remote object
-	Dirty       bool          // set if the backing file has been modified
+	ModTime     time.Time           // last time file was modified
+	ATime       time.Time           // last time file was accessed
+	Size        int64               // size of the file
+	Rs          ranges.Ranges       // which parts of the file are present
+	Fingerprint string              // fingerprint of remote object
+	Dirty       bool                // set if the backing file has been modified
+	ChunkSize   int64               // size of the chunks in ChunkATime
+	ChunkATime  map[int64]time.Time // last time each chunk of the file was accessed by chunk number
+	Queued      time.Time           // when the file was first queued for upload, zero if not queued
+}
+
+// cacheChunkSize is the size of the chunks of the cache file which
+// have their access times tracked so they can be evicted
+// individually when the cache is over quota.
+var cacheChunkSize int64 = 16 * 1024 * 1024
+
+// punchHole is used to evict the chunks - it can be overridden in
+// the tests
+var punchHole = file.PunchHole
+
+// cacheChunk is a chunk of a cache file which could be evicted
+type cacheChunk struct {
+	item  *Item
+	n     int64     // chunk number
+	atime time.Time // last time the chunk was accessed
+	size  int64     // number of bytes of the chunk in the cache file
 }
 
 // Items are a slice of *Item ordered by ATime
@@ -179,6 +202,13 @@ func (item *Item) getATime() time.Time {
 	return item.info.ATime
 }
 
+// getQueued returns the time the item was first queued for upload
+func (item *Item) getQueued() time.Time {
+	item.mu.Lock()
+	defer item.mu.Unlock()
+	return item.info.Queued
+}
+
 // getDiskSize returns the size on disk (approximately) of the item
 //
 // We return the sizes of the chunks we have fetched, however there is
@@ -564,6 +594,69 @@ func (item *Item) open(o fs.Object) (err error) {
 	return err
 }
 
+// findConflict checks whether the remote object has been modified
+// since the item with fingerprint was cached, returning the remote
+// object if it has, or nil if the local changes can be uploaded.
+func (c *Cache) findConflict(ctx context.Context, name string, fingerprint string) (fs.Object, error) {
+	remoteObj, err := c.fremote.NewObject(ctx, name)
+	if err == fs.ErrorObjectNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to check remote object for conflicts: %w", err)
+	}
+	remoteFingerprint := fs.Fingerprint(ctx, remoteObj, c.opt.FastFingerprint)
+	if remoteFingerprint == fingerprint {
+		return nil, nil
+	}
+	fs.Debugf(name, "vfs cache: conflict: remote fingerprint %q != cached fingerprint %q", remoteFingerprint, fingerprint)
+	return remoteObj, nil
+}
+
+// resolveConflict resolves a conflict between the local changes in
+// cacheObj and remoteObj, which has been modified on the remote since
+// it was cached, according to the conflict mode.
+//
+// The remote object is always kept.
+func (c *Cache) resolveConflict(ctx context.Context, name string, remoteObj, cacheObj fs.Object) error {
+	if c.opt.Conflict != vfscommon.ConflictModeBoth {
+		fs.Logf(name, "vfs cache: conflict: remote modified since file was cached - discarding local changes")
+		return nil
+	}
+	newName := conflictName(name, time.Now())
+	_, err := operations.Copy(ctx, c.fremote, nil, newName, cacheObj)
+	if err != nil {
+		return fmt.Errorf("failed to upload conflicting file as %q: %w", newName, err)
+	}
+	fs.Logf(name, "vfs cache: conflict: remote modified since file was cached - local changes uploaded as %q", newName)
+	if err := c.AddVirtual(newName, cacheObj.Size(), false); err != nil {
+		fs.Debugf(newName, "vfs cache: failed to add conflicting file to directory listing: %v", err)
+	}
+	return nil
+}
+
+// conflictName returns the name to upload local changes which
+// conflict with the remote as at time t.
+func conflictName(name string, t time.Time) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + ".conflict-" + t.Format("20060102-150405") + ext
+}
+
+// _discardChanges discards the cached data in favour of remoteObj
+// after a conflict so the remote object is read when it is next
+// needed.
+//
+// call with lock held
+func (item *Item) _discardChanges(remoteObj fs.Object) {
+	item.o = remoteObj
+	item.info.Rs = nil
+	item.info.ChunkATime = nil
+	item.info.Size = remoteObj.Size()
+	item._updateFingerprint()
+	if item.opens == 0 {
+		item._removeFile("local changes discarded after conflict")
+	}
+}
+
 // Store stores the local cache file to the remote object, returning
 // the new remote object. objOld is the old object if known.
 //
@@ -579,18 +672,34 @@ func (item *Item) _store(ctx context.Context, storeFn StoreFn) (err error) {
 
 	// Object has disappeared if cacheObj == nil
 	if cacheObj != nil {
-		o, name := item.o, item.name
+		o, name, fingerprint := item.o, item.name, item.info.Fingerprint
 		item.mu.Unlock()
-		o, err := operations.Copy(ctx, item.c.fremote, o, name, cacheObj)
+		var remoteObj fs.Object
+		if item.c.opt.Conflict != vfscommon.ConflictModeLocal {
+			remoteObj, err = item.c.findConflict(ctx, name, fingerprint)
+		}
+		if err == nil {
+			if remoteObj != nil {
+				err = item.c.resolveConflict(ctx, name, remoteObj, cacheObj)
+			} else {
+				o, err = operations.Copy(ctx, item.c.fremote, o, name, cacheObj)
+			}
+		}
 		item.mu.Lock()
 		if err != nil {
+			item.c.offline.Check(err)
 			return fmt.Errorf("vfs cache: failed to transfer file from cache to remote: %w", err)
 		}
-		item.o = o
-		item._updateFingerprint()
+		if remoteObj != nil {
+			item._discardChanges(remoteObj)
+		} else {
+			item.o = o
+			item._updateFingerprint()
+		}
 	}
 
 	item.info.Dirty = false
+	item.info.Queued = time.Time{}
 	err = item._save()
 	if err != nil {
 		fs.Errorf(item.name, "vfs cache: failed to write metadata file: %v", err)
@@ -622,7 +731,7 @@ func (item *Item) Close(storeFn StoreFn) (err error) {
 	defer item.postAccess()
 	var (
 		downloaders   *downloaders.Downloaders
-		syncWriteBack = item.c.opt.WriteBack <= 0
+		syncWriteBack = item.c.opt.WriteBack <= 0 && !item.c.offline.IsOffline()
 	)
 	item.mu.Lock()
 	defer item.mu.Unlock()
@@ -702,6 +811,10 @@ func (item *Item) Close(storeFn StoreFn) (err error) {
 	// upload the file to backing store if changed
 	if item.info.Dirty {
 		fs.Infof(item.name, "vfs cache: queuing for upload in %v", item.c.opt.WriteBack)
+		if item.info.Queued.IsZero() {
+			item.info.Queued = time.Now()
+			checkErr(item._save())
+		}
 		if syncWriteBack {
 			// do synchronous writeback
 			checkErr(item._store(context.Background(), storeFn))
@@ -789,7 +902,9 @@ func (item *Item) _checkObject(o fs.Object) error {
 		if item.info.Fingerprint != "" {
 			// remote object && local object
 			if remoteFingerprint != item.info.Fingerprint {
-				if !item.info.Dirty {
+				if item.c.offline.IsOffline() {
+					fs.Debugf(item.name, "vfs cache: remote is offline so keeping cached entry (remote fingerprint %q != cached fingerprint %q)", remoteFingerprint, item.info.Fingerprint)
+				} else if !item.info.Dirty {
 					fs.Debugf(item.name, "vfs cache: removing cached entry as stale (remote fingerprint %q != cached fingerprint %q)", remoteFingerprint, item.info.Fingerprint)
 					item._remove("stale (remote is different)")
 				} else {
@@ -1160,6 +1275,130 @@ func (item *Item) _ensure(offset, size int64) (err error) {
 func (item *Item) _written(offset, size int64) {
 	// defer log.Trace(item.name, "offset=%d, size=%d", offset, size)("")
 	item.info.Rs.Insert(ranges.Range{Pos: offset, Size: size})
+	item._accessed(offset, size)
+}
+
+// _accessed records the chunks in (offset, size) as accessed now
+//
+// call with lock held
+func (item *Item) _accessed(offset, size int64) {
+	if size <= 0 {
+		return
+	}
+	if item.info.ChunkSize != cacheChunkSize || item.info.ChunkATime == nil {
+		item.info.ChunkSize = cacheChunkSize
+		item.info.ChunkATime = make(map[int64]time.Time)
+	}
+	now := time.Now()
+	for n := offset / cacheChunkSize; n*cacheChunkSize < offset+size; n++ {
+		item.info.ChunkATime[n] = now
+	}
+}
+
+// evictableChunks returns the chunks present in the cache file
+// which could be evicted, or nil if the item is in use.
+//
+// Chunks without an access time recorded have the access time of
+// the item.
+func (item *Item) evictableChunks() (chunks []cacheChunk) {
+	item.mu.Lock()
+	defer item.mu.Unlock()
+	if item.opens != 0 || item.info.Dirty {
+		return nil
+	}
+	for _, r := range item.info.Rs {
+		for n := r.Pos / cacheChunkSize; n*cacheChunkSize < r.End(); n++ {
+			if len(chunks) > 0 && chunks[len(chunks)-1].n == n {
+				// already counted this chunk from the previous range
+				continue
+			}
+			atime := item.info.ATime
+			if t, ok := item.info.ChunkATime[n]; ok && item.info.ChunkSize == cacheChunkSize {
+				atime = t
+			}
+			chunks = append(chunks, cacheChunk{
+				item:  item,
+				n:     n,
+				atime: atime,
+				size:  item.info.Rs.Intersection(ranges.Range{Pos: n * cacheChunkSize, Size: cacheChunkSize}).Size(),
+			})
+		}
+	}
+	return chunks
+}
+
+// evictChunks removes the chunks numbered ns from the cache file by
+// punching holes in it, returning the space freed.
+//
+// It does nothing if the item has come into use since the chunks
+// were chosen.
+func (item *Item) evictChunks(ns []int64) (spaceFreed int64, err error) {
+	item.mu.Lock()
+	defer item.mu.Unlock()
+	if item.opens != 0 || item.info.Dirty {
+		return 0, nil
+	}
+
+	oldRs := append(ranges.Ranges(nil), item.info.Rs...)
+	oldSize := oldRs.Size()
+	var holes []ranges.Range
+	for _, n := range ns {
+		r := ranges.Range{Pos: n * cacheChunkSize, Size: cacheChunkSize}
+		before := item.info.Rs.Size()
+		item.info.Rs.Remove(r)
+		if item.info.Rs.Size() != before {
+			holes = append(holes, r)
+		}
+		delete(item.info.ChunkATime, n)
+	}
+	spaceFreed = oldSize - item.info.Rs.Size()
+	if spaceFreed == 0 {
+		return 0, nil
+	}
+
+	// Save the metadata before punching the holes so a crash can't
+	// leave zeroed parts of the file marked as present
+	restore := func() {
+		item.info.Rs = oldRs
+		if err := item._save(); err != nil {
+			fs.Errorf(item.name, "vfs cache: failed to restore metadata after evicting chunks: %v", err)
+		}
+	}
+	err = item._save()
+	if err != nil {
+		restore()
+		return 0, err
+	}
+	osPath := item.c.toOSPath(item.name) // No locking in Cache
+	fd, err := file.OpenFile(osPath, os.O_WRONLY, 0600)
+	if err != nil {
+		restore()
+		return 0, fmt.Errorf("vfs cache: evict chunks: failed to open cache file: %w", err)
+	}
+	defer fs.CheckClose(fd, &err)
+	for i, r := range holes {
+		err = punchHole(fd, r.Pos, r.Size)
+		if err != nil {
+			if i == 0 {
+				// Nothing has been zeroed yet so the old ranges are still good
+				restore()
+				return 0, err
+			}
+			// Mark the chunks which haven't been zeroed as present
+			// again and only count the space of those which have
+			for _, hole := range holes[i:] {
+				for _, present := range oldRs.Intersection(hole) {
+					item.info.Rs.Insert(present)
+				}
+			}
+			if saveErr := item._save(); saveErr != nil {
+				fs.Errorf(item.name, "vfs cache: failed to save metadata after evicting chunks: %v", saveErr)
+			}
+			return oldSize - item.info.Rs.Size(), err
+		}
+	}
+	fs.Infof(item.name, "vfs cache: evicted %d chunks freeing %d bytes", len(holes), spaceFreed)
+	return spaceFreed, nil
 }
 
 // update the fingerprint of the object if any
@@ -1261,11 +1500,40 @@ func (item *Item) readAt(b []byte, off int64) (n int, err error) {
 	}
 
 	item.info.ATime = time.Now()
+	item._accessed(off, int64(len(b)))
 	// Do the reading with Item.mu unlocked and cache protected by preAccess
 	n, err = item.fd.ReadAt(b, off)
 	return n, err
 }
 
+// Prefetch makes sure the size bytes at off are in the cache file,
+// downloading them if necessary.
+//
+// It returns the number of bytes in the range, which may be less than
+// size if the range runs off the end of the file.
+func (item *Item) Prefetch(off, size int64) (n int64, err error) {
+	item.preAccess()
+	defer item.postAccess()
+	item.mu.Lock()
+	defer item.mu.Unlock()
+	if item.fd == nil {
+		return 0, errors.New("vfs cache item Prefetch: internal error: didn't Open file")
+	}
+	if off < 0 || off >= item.info.Size || size <= 0 {
+		return 0, nil
+	}
+	if off+size > item.info.Size {
+		size = item.info.Size - off
+	}
+	err = item._ensure(off, size)
+	if err != nil {
+		return 0, err
+	}
+	item.info.ATime = time.Now()
+	item._accessed(off, size)
+	return size, nil
+}
+
 // WriteAt bytes to the file at off
 func (item *Item) WriteAt(b []byte, off int64) (n int, err error) {
 	item.preAccess()
@@ -4,6 +4,7 @@ package vfscache
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -14,8 +15,10 @@ import (
 	"time"
 
 	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/fserrors"
 	"github.com/rclone/rclone/fstest"
 	"github.com/rclone/rclone/lib/random"
+	"github.com/rclone/rclone/lib/ranges"
 	"github.com/rclone/rclone/lib/readers"
 	"github.com/rclone/rclone/vfs/vfscommon"
 	"github.com/stretchr/testify/assert"
@@ -219,6 +222,37 @@ func TestItemReadAt(t *testing.T) {
 	require.NoError(t, item.Close(nil))
 }
 
+func TestItemPrefetch(t *testing.T) {
+	r, c, cleanup := newItemTestCache(t)
+	defer cleanup()
+
+	_, obj, item := newFile(t, r, c, "existing")
+
+	// Prefetch when not open
+	_, err := item.Prefetch(0, 10)
+	assert.Error(t, err)
+
+	require.NoError(t, item.Open(obj))
+
+	n, err := item.Prefetch(10, 20)
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), n)
+	assert.True(t, item.HasRange(ranges.Range{Pos: 10, Size: 20}))
+
+	// Prefetch off the end of the file
+	n, err = item.Prefetch(90, 50)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), n)
+	assert.True(t, item.HasRange(ranges.Range{Pos: 90, Size: 10}))
+
+	n, err = item.Prefetch(100, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+
+	require.NoError(t, item.Close(nil))
+	assert.Equal(t, false, item.IsDirty())
+}
+
 func TestItemWriteAtNew(t *testing.T) {
 	r, c, cleanup := newItemTestCache(t)
 	defer cleanup()
@@ -450,6 +484,90 @@ func TestItemReloadCacheStale(t *testing.T) {
 	checkObject(t, r, "existing", "HELLO"+contents2[5:])
 }
 
+func TestItemConflict(t *testing.T) {
+	for _, conflict := range []vfscommon.ConflictMode{
+		vfscommon.ConflictModeLocal,
+		vfscommon.ConflictModeRemote,
+		vfscommon.ConflictModeBoth,
+	} {
+		t.Run(conflict.String(), func(t *testing.T) {
+			opt := vfscommon.DefaultOpt
+			opt.CachePollInterval = 0
+			opt.WriteBack = 0
+			opt.Conflict = conflict
+			r, c, cleanup := newTestCacheOpt(t, opt)
+			defer cleanup()
+
+			contents, obj, item := newFile(t, r, c, "existing.txt")
+			require.NoError(t, item.Open(obj))
+
+			// Read the whole file then write to it to make it dirty
+			buf := make([]byte, len(contents))
+			_, err := item.ReadAt(buf, 0)
+			require.NoError(t, err)
+			_, err = item.WriteAt([]byte("HELLO"), 0)
+			require.NoError(t, err)
+			local := "HELLO" + contents[5:]
+
+			// Update the remote while the file is open
+			remote := r.WriteObject(context.Background(), "existing.txt", "remote contents", time.Now())
+
+			require.NoError(t, item.Close(nil))
+			assert.Equal(t, false, item.IsDirty())
+
+			if conflict == vfscommon.ConflictModeLocal {
+				checkObject(t, r, "existing.txt", local)
+				assert.Nil(t, avInfos)
+				return
+			}
+
+			// The remote is kept and the local changes discarded
+			checkObject(t, r, "existing.txt", "remote contents")
+			assertPathNotExist(t, c.toOSPath("existing.txt"))
+			size, err := item.GetSize()
+			require.NoError(t, err)
+			assert.Equal(t, remote.Size, size)
+
+			entries, err := r.Fremote.List(context.Background(), "")
+			require.NoError(t, err)
+			if conflict == vfscommon.ConflictModeRemote {
+				assert.Equal(t, 1, len(entries))
+				assert.Nil(t, avInfos)
+				return
+			}
+
+			// The local changes are uploaded as a conflict copy
+			require.Equal(t, 2, len(entries))
+			var conflictRemote string
+			for _, entry := range entries {
+				if entry.Remote() != "existing.txt" {
+					conflictRemote = entry.Remote()
+				}
+			}
+			assert.Regexp(t, `^existing\.conflict-\d{8}-\d{6}\.txt$`, conflictRemote)
+			checkObject(t, r, conflictRemote, local)
+			assert.Equal(t, []avInfo{
+				{Remote: conflictRemote, Size: int64(len(local)), IsDir: false},
+			}, avInfos)
+		})
+	}
+}
+
+func TestConflictName(t *testing.T) {
+	when := time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC)
+	for _, test := range []struct {
+		in   string
+		want string
+	}{
+		{"file.txt", "file.conflict-20210203-040506.txt"},
+		{"file", "file.conflict-20210203-040506"},
+		{"dir/file.tar.gz", "dir/file.tar.conflict-20210203-040506.gz"},
+		{"dir.d/file", "dir.d/file.conflict-20210203-040506"},
+	} {
+		assert.Equal(t, test.want, conflictName(test.in, when), test.in)
+	}
+}
+
 func TestItemReadWrite(t *testing.T) {
 	r, c, cleanup := newItemTestCache(t)
 	defer cleanup()
@@ -597,3 +715,32 @@ func TestItemReadWrite(t *testing.T) {
 		assert.False(t, item.remove(fileName))
 	})
 }
+
+func TestItemRetriableErrorNotOffline(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	opt := vfscommon.DefaultOpt
+	opt.CachePollInterval = 0
+	opt.WriteBack = 0
+	offline := vfscommon.NewOffline(r.Fremote)
+	defer offline.Shutdown()
+	c, err := New(ctx, r.Fremote, &opt, addVirtual, offline)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, c.CleanUp()) }()
+
+	// A retriable HTTP error means the remote was reached so it
+	// doesn't go offline
+	httpErr := errors.New("stream error: stream ID 3; REFUSED_STREAM")
+	require.True(t, fserrors.ShouldRetry(httpErr))
+	assert.False(t, offline.Check(httpErr))
+	assert.False(t, offline.IsOffline())
+
+	// Check the writeback isn't paused so the file is uploaded on close
+	item, _ := c.get("potato")
+	itemWrite(t, item, "hello")
+	require.NoError(t, item.Close(nil))
+	checkObject(t, r, "potato", "hello")
+}
@@ -34,6 +34,7 @@ type WriteBack struct {
 	timer   *time.Timer               // next scheduled time for the uploader
 	expiry  time.Time                 // time the next item expires or IsZero
 	uploads int                       // number of uploads in progress
+	paused  bool                      // set if uploads are paused
 
 	// read and written with atomic
 	id Handle // id of the last writeBackItem created
@@ -216,6 +217,10 @@ func (wb *WriteBack) _stopTimer() {
 
 // reset the timer which runs the expiries
 func (wb *WriteBack) _resetTimer() {
+	if wb.paused {
+		wb._stopTimer()
+		return
+	}
 	wbItem := wb._peekItem()
 	if wbItem == nil {
 		wb._stopTimer()
@@ -427,7 +432,7 @@ func (wb *WriteBack) processItems(ctx context.Context) {
 	wb.mu.Lock()
 	defer wb.mu.Unlock()
 
-	if wb.ctx.Err() != nil {
+	if wb.ctx.Err() != nil || wb.paused {
 		return
 	}
 
@@ -457,6 +462,44 @@ func (wb *WriteBack) processItems(ctx context.Context) {
 	}
 }
 
+// Pause stops any more uploads starting until Resume is called.
+//
+// Items can still be added to the queue while it is paused.
+func (wb *WriteBack) Pause() {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	if wb.paused {
+		return
+	}
+	fs.Debugf(nil, "vfs cache: pausing writeback")
+	wb.paused = true
+	wb._stopTimer()
+}
+
+// Resume starts uploads again after Pause.
+//
+// Items which have failed to upload are retried straight away rather
+// than waiting for their retry delay. They are retried in the order
+// they were first queued.
+func (wb *WriteBack) Resume() {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	if !wb.paused {
+		return
+	}
+	fs.Debugf(nil, "vfs cache: resuming writeback")
+	wb.paused = false
+	now := time.Now()
+	for _, wbItem := range wb.items {
+		if wbItem.tries > 0 {
+			wbItem.expiry = now
+			wbItem.delay = wb.opt.WriteBack
+		}
+	}
+	heap.Init(&wb.items)
+	wb._resetTimer()
+}
+
 // Stats return the number of uploads in progress and queued
 func (wb *WriteBack) Stats() (uploadsInProgress, uploadsQueued int) {
 	wb.mu.Lock()
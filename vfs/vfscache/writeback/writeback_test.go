@@ -347,6 +347,44 @@ func TestWriteBackAddFailRetry(t *testing.T) {
 	checkNotInLookup(t, wb, wbItem)
 }
 
+// Now test uploads being paused and resumed
+func TestWriteBackPauseResume(t *testing.T) {
+	wb, cancel := newTestWriteBack(t)
+	defer cancel()
+
+	pi := newPutItem(t)
+
+	id := wb.Add(0, "one", true, pi.put)
+	wbItem := wb.lookup[id]
+
+	<-pi.started
+	wb.Pause()
+	pi.finish(errors.New("transfer failed BOOM"))
+	waitUntilNoTransfers(t, wb)
+	checkOnHeap(t, wb, wbItem)
+	checkInLookup(t, wb, wbItem)
+
+	// check the retry doesn't start while paused
+	select {
+	case <-pi.started:
+		t.Fatal("upload started while paused")
+	case <-time.After(4 * wb.opt.WriteBack):
+	}
+
+	// check the retry starts straight away when resumed
+	wb.Resume()
+	select {
+	case <-pi.started:
+	case <-time.After(wb.opt.WriteBack):
+		t.Fatal("upload not retried when resumed")
+	}
+	checkNotOnHeap(t, wb, wbItem)
+
+	pi.finish(nil) // transfer successful
+	waitUntilNoTransfers(t, wb)
+	checkNotInLookup(t, wb, wbItem)
+}
+
 // Now test the upload being cancelled by another upload being added
 func TestWriteBackAddUpdate(t *testing.T) {
 	wb, cancel := newTestWriteBack(t)
@@ -0,0 +1,59 @@
+package vfscommon
+
+import (
+	"fmt"
+
+	"github.com/rclone/rclone/fs"
+)
+
+// ConflictMode controls what happens when a file is uploaded from the
+// cache but the remote has been modified since it was cached
+type ConflictMode byte
+
+// ConflictMode options
+const (
+	ConflictModeLocal  ConflictMode = iota // overwrite the remote with the local changes
+	ConflictModeRemote                     // keep the remote and discard the local changes
+	ConflictModeBoth                       // keep the remote and upload the local changes as a conflict copy
+)
+
+var conflictModeToString = []string{
+	ConflictModeLocal:  "local",
+	ConflictModeRemote: "remote",
+	ConflictModeBoth:   "both",
+}
+
+// String turns a ConflictMode into a string
+func (l ConflictMode) String() string {
+	if l >= ConflictMode(len(conflictModeToString)) {
+		return fmt.Sprintf("ConflictMode(%d)", l)
+	}
+	return conflictModeToString[l]
+}
+
+// Set a ConflictMode
+func (l *ConflictMode) Set(s string) error {
+	for n, name := range conflictModeToString {
+		if s != "" && name == s {
+			*l = ConflictMode(n)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown conflict mode %q", s)
+}
+
+// Type of the value
+func (l *ConflictMode) Type() string {
+	return "ConflictMode"
+}
+
+// UnmarshalJSON makes sure the value can be parsed as a string or integer in JSON
+func (l *ConflictMode) UnmarshalJSON(in []byte) error {
+	return fs.UnmarshalJSONFlag(in, l, func(i int64) error {
+		if i < 0 || i >= int64(len(conflictModeToString)) {
+			return fmt.Errorf("unknown conflict mode %d", i)
+		}
+		*l = ConflictMode(i)
+		return nil
+	})
+}
@@ -0,0 +1,59 @@
+package vfscommon
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+// Check ConflictMode it satisfies the pflag interface
+var _ pflag.Value = (*ConflictMode)(nil)
+
+// Check ConflictMode it satisfies the json.Unmarshaller interface
+var _ json.Unmarshaler = (*ConflictMode)(nil)
+
+func TestConflictModeString(t *testing.T) {
+	assert.Equal(t, "local", ConflictModeLocal.String())
+	assert.Equal(t, "both", ConflictModeBoth.String())
+	assert.Equal(t, "ConflictMode(17)", ConflictMode(17).String())
+}
+
+func TestConflictModeSet(t *testing.T) {
+	var m ConflictMode
+
+	err := m.Set("remote")
+	assert.NoError(t, err)
+	assert.Equal(t, ConflictModeRemote, m)
+
+	err = m.Set("potato")
+	assert.Error(t, err, "Unknown conflict mode")
+
+	err = m.Set("")
+	assert.Error(t, err, "Unknown conflict mode")
+}
+
+func TestConflictModeType(t *testing.T) {
+	var m ConflictMode
+	assert.Equal(t, "ConflictMode", m.Type())
+}
+
+func TestConflictModeUnmarshalJSON(t *testing.T) {
+	var m ConflictMode
+
+	err := json.Unmarshal([]byte(`"both"`), &m)
+	assert.NoError(t, err)
+	assert.Equal(t, ConflictModeBoth, m)
+
+	err = json.Unmarshal([]byte(`"potato"`), &m)
+	assert.Error(t, err, "Unknown conflict mode")
+
+	err = json.Unmarshal([]byte(strconv.Itoa(int(ConflictModeRemote))), &m)
+	assert.NoError(t, err)
+	assert.Equal(t, ConflictModeRemote, m)
+
+	err = json.Unmarshal([]byte("99"), &m)
+	assert.Error(t, err, "Unknown conflict mode")
+}
@@ -0,0 +1,179 @@
+package vfscommon
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+)
+
+// OfflineProbeInterval is how often an offline remote is checked to
+// see if it is reachable again
+var OfflineProbeInterval = 15 * time.Second
+
+// offlineProbeName is the name of the object looked up to check
+// whether the remote is reachable. It doesn't need to exist.
+const offlineProbeName = ".rclone-offline-probe"
+
+// IsOfflineError returns true if err shows the remote couldn't be
+// reached, rather than the remote returning an error.
+//
+// Only errors reaching the remote count - failing to dial it, which
+// includes the connection being refused and there being no route to
+// it, failing to look up its name and timing out. Errors such as a
+// retriable HTTP status or a connection dropped during a transfer
+// don't as the remote could be reached.
+func IsOfflineError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var (
+		dnsErr *net.DNSError
+		opErr  *net.OpError
+		netErr net.Error
+	)
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// Offline keeps track of whether the remote is reachable.
+//
+// The remote goes offline when an operation fails with an error for
+// which IsOfflineError is true. While it is offline it is probed
+// periodically and it comes back online when a probe succeeds.
+//
+// A nil *Offline is always online.
+type Offline struct {
+	f        fs.Fs
+	mu       sync.Mutex
+	offline  bool               // set if the remote is offline
+	since    time.Time          // when the remote went offline
+	onChange []func(bool)       // called when the state changes
+	cancel   context.CancelFunc // stop the prober
+	wg       sync.WaitGroup     // wait for the prober to stop
+}
+
+// NewOffline makes a new online Offline for the remote
+func NewOffline(f fs.Fs) *Offline {
+	return &Offline{
+		f: f,
+	}
+}
+
+// IsOffline returns true if the remote is offline
+func (o *Offline) IsOffline() bool {
+	if o == nil {
+		return false
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.offline
+}
+
+// OnChange adds fn to be called with the new state when the remote
+// goes offline or comes back online.
+func (o *Offline) OnChange(fn func(offline bool)) {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.onChange = append(o.onChange, fn)
+}
+
+// Check looks at the error from an operation on the remote and marks
+// the remote offline if it shows the remote couldn't be reached.
+//
+// It returns true if the remote is offline.
+func (o *Offline) Check(err error) bool {
+	if o == nil {
+		return false
+	}
+	if !IsOfflineError(err) {
+		return o.IsOffline()
+	}
+	o.set(true, err)
+	return true
+}
+
+// set the offline state, calling the OnChange functions if it
+// changed.
+func (o *Offline) set(offline bool, err error) {
+	o.mu.Lock()
+	if o.offline == offline {
+		o.mu.Unlock()
+		return
+	}
+	o.offline = offline
+	if offline {
+		o.since = time.Now()
+		fs.Logf(o.f, "vfs: remote is unreachable - going offline: %v", err)
+		ctx, cancel := context.WithCancel(context.Background())
+		o.cancel = cancel
+		o.wg.Add(1)
+		go o.prober(ctx)
+	} else {
+		fs.Logf(o.f, "vfs: remote is reachable again after %v - going online", time.Since(o.since).Truncate(time.Second))
+		if o.cancel != nil {
+			o.cancel()
+			o.cancel = nil
+		}
+	}
+	onChange := make([]func(bool), len(o.onChange))
+	copy(onChange, o.onChange)
+	o.mu.Unlock()
+	for _, fn := range onChange {
+		fn(offline)
+	}
+}
+
+// probe returns true if the remote can be reached
+func (o *Offline) probe(ctx context.Context) bool {
+	_, err := o.f.NewObject(ctx, offlineProbeName)
+	if IsOfflineError(err) {
+		fs.Debugf(o.f, "vfs: remote is still unreachable: %v", err)
+		return false
+	}
+	return true
+}
+
+// prober checks the remote periodically until it can be reached
+func (o *Offline) prober(ctx context.Context) {
+	defer o.wg.Done()
+	ticker := time.NewTicker(OfflineProbeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if o.probe(ctx) {
+				o.set(false, nil)
+				return
+			}
+		}
+	}
+}
+
+// Shutdown stops probing the remote
+func (o *Offline) Shutdown() {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	cancel := o.cancel
+	o.cancel = nil
+	o.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	o.wg.Wait()
+}
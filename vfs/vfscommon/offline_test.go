@@ -0,0 +1,134 @@
+package vfscommon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/fserrors"
+	"github.com/rclone/rclone/fstest/mockfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dialErr is an error returned when the remote can't be reached
+var dialErr = &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connect: network is unreachable")}
+
+// timeoutErr is a network error which timed out
+type timeoutErr struct{}
+
+func (timeoutErr) Error() string   { return "i/o timeout" }
+func (timeoutErr) Timeout() bool   { return true }
+func (timeoutErr) Temporary() bool { return true }
+
+// refusedErr returns the error from connecting to a port nothing is
+// listening on
+func refusedErr(t *testing.T) error {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+	conn, err := net.Dial("tcp", addr)
+	if err == nil {
+		_ = conn.Close()
+	}
+	require.Error(t, err)
+	return err
+}
+
+func TestIsOfflineError(t *testing.T) {
+	for _, test := range []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{context.Canceled, false},
+		{errors.New("potato"), false},
+		{fs.ErrorObjectNotFound, false},
+		{dialErr, true},
+		{fmt.Errorf("wrapped: %w", dialErr), true},
+		{&net.DNSError{Err: "no such host", Name: "example.com"}, true},
+		{refusedErr(t), true},
+		{&net.OpError{Op: "read", Net: "tcp", Err: timeoutErr{}}, true},
+		{context.DeadlineExceeded, true},
+		{io.ErrUnexpectedEOF, false},
+		{&net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}, false},
+		{fserrors.RetryErrorf("HTTP error 503 (503 Service Unavailable)"), false},
+		{errors.New("stream error: stream ID 3; INTERNAL_ERROR"), false},
+	} {
+		assert.Equal(t, test.want, IsOfflineError(test.err), fmt.Sprint(test.err))
+	}
+}
+
+// unreachableFs is an Fs whose NewObject can be made to fail
+type unreachableFs struct {
+	fs.Fs
+	mu  sync.Mutex
+	err error
+}
+
+func (f *unreachableFs) setErr(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func (f *unreachableFs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	return nil, fs.ErrorObjectNotFound
+}
+
+func TestOffline(t *testing.T) {
+	oldOfflineProbeInterval := OfflineProbeInterval
+	OfflineProbeInterval = 10 * time.Millisecond
+	defer func() { OfflineProbeInterval = oldOfflineProbeInterval }()
+
+	f := &unreachableFs{Fs: mockfs.NewFs(context.Background(), "mock", "/")}
+	o := NewOffline(f)
+	defer o.Shutdown()
+	changes := make(chan bool, 2)
+	o.OnChange(func(offline bool) { changes <- offline })
+
+	// Other errors don't make it go offline
+	assert.False(t, o.IsOffline())
+	assert.False(t, o.Check(nil))
+	assert.False(t, o.Check(errors.New("potato")))
+	assert.False(t, o.IsOffline())
+
+	// Go offline and stay offline while the probe fails
+	f.setErr(dialErr)
+	assert.True(t, o.Check(dialErr))
+	assert.True(t, o.IsOffline())
+	assert.Equal(t, true, <-changes)
+	assert.True(t, o.Check(dialErr))
+	time.Sleep(5 * OfflineProbeInterval)
+	assert.True(t, o.IsOffline())
+
+	// Come back online when the probe succeeds
+	f.setErr(nil)
+	select {
+	case offline := <-changes:
+		assert.Equal(t, false, offline)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting to come back online")
+	}
+	assert.False(t, o.IsOffline())
+	assert.Equal(t, 0, len(changes))
+
+	// A nil Offline is always online
+	var nilOffline *Offline
+	assert.False(t, nilOffline.IsOffline())
+	assert.False(t, nilOffline.Check(dialErr))
+	nilOffline.OnChange(func(bool) {})
+	nilOffline.Shutdown()
+}
@@ -15,6 +15,7 @@ type Options struct {
 	ReadOnly           bool          // if set VFS is read only
 	NoModTime          bool          // don't read mod times for files
 	DirCacheTime       time.Duration // how long to consider directory listing cache valid
+	PersistDirCache    bool          // if set save the directory cache for use after a restart
 	PollInterval       time.Duration
 	Umask              int
 	UID                uint32
@@ -35,6 +36,10 @@ type Options struct {
 	UsedIsSize         bool          // if true, use the `rclone size` algorithm for Used size
 	FastFingerprint    bool          // if set use fast fingerprints
 	DiskSpaceTotalSize fs.SizeSuffix
+	Offline            bool         // if set serve from the cache and queue writes when the remote is unreachable
+	Conflict           ConflictMode // what to do when uploading a file modified on the remote since it was cached
+	Links              bool         // if set translate symlinks to and from files with LinkSuffix
+	SharedLocks        bool         // if set share advisory locks with other rclone processes
 }
 
 // DefaultOpt is the default values uses for Opt
@@ -63,8 +68,17 @@ var DefaultOpt = Options{
 	ReadAhead:          0 * fs.Mebi,
 	UsedIsSize:         false,
 	DiskSpaceTotalSize: -1,
+	Offline:            false,
+	Conflict:           ConflictModeLocal,
+	Links:              false,
+	SharedLocks:        false,
 }
 
+// LinkSuffix is the suffix added to the names of files on the remote
+// which hold symlinks when Links is set. This is the same convention
+// the local backend uses with --links.
+const LinkSuffix = ".rclonelink"
+
 // Init the options, making sure everything is withing range
 func (opt *Options) Init() {
 	// Mask the permissions with the umask
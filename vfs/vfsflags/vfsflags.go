@@ -22,6 +22,7 @@ func AddFlags(flagSet *pflag.FlagSet) {
 	flags.BoolVarP(flagSet, &Opt.NoChecksum, "no-checksum", "", Opt.NoChecksum, "Don't compare checksums on up/download")
 	flags.BoolVarP(flagSet, &Opt.NoSeek, "no-seek", "", Opt.NoSeek, "Don't allow seeking in files")
 	flags.DurationVarP(flagSet, &Opt.DirCacheTime, "dir-cache-time", "", Opt.DirCacheTime, "Time to cache directory entries for")
+	flags.BoolVarP(flagSet, &Opt.PersistDirCache, "vfs-persist-dir-cache", "", Opt.PersistDirCache, "Save the directory cache in the cache directory for use after a restart")
 	flags.DurationVarP(flagSet, &Opt.PollInterval, "poll-interval", "", Opt.PollInterval, "Time to wait between polling for changes, must be smaller than dir-cache-time and only on supported remotes (set 0 to disable)")
 	flags.BoolVarP(flagSet, &Opt.ReadOnly, "read-only", "", Opt.ReadOnly, "Only allow read-only access")
 	flags.FVarP(flagSet, &Opt.CacheMode, "vfs-cache-mode", "", "Cache mode off|minimal|writes|full")
@@ -40,5 +41,9 @@ func AddFlags(flagSet *pflag.FlagSet) {
 	flags.BoolVarP(flagSet, &Opt.UsedIsSize, "vfs-used-is-size", "", Opt.UsedIsSize, "Use the `rclone size` algorithm for Used size")
 	flags.BoolVarP(flagSet, &Opt.FastFingerprint, "vfs-fast-fingerprint", "", Opt.FastFingerprint, "Use fast (less accurate) fingerprints for change detection")
 	flags.FVarP(flagSet, &Opt.DiskSpaceTotalSize, "vfs-disk-space-total-size", "", "Specify the total space of disk")
+	flags.BoolVarP(flagSet, &Opt.Offline, "vfs-offline", "", Opt.Offline, "Serve from the cache and queue writes when the remote is unreachable")
+	flags.FVarP(flagSet, &Opt.Conflict, "vfs-conflict", "", "What to do if a file was modified on the remote before its local changes are uploaded local|remote|both")
+	flags.BoolVarP(flagSet, &Opt.Links, "vfs-links", "", Opt.Links, "Translate symlinks to/from regular files with a '"+vfscommon.LinkSuffix+"' extension")
+	flags.BoolVarP(flagSet, &Opt.SharedLocks, "vfs-shared-locks", "", Opt.SharedLocks, "Share file locks with other rclone processes using the cache directory")
 	platformFlags(flagSet)
 }